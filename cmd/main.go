@@ -3,9 +3,11 @@ package main
 import (
 	"errors"
 	"log"
+	"net/http"
 	"os"
 	"tonclient/internal/config"
 	"tonclient/internal/database"
+	"tonclient/internal/handlers"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 	"tonclient/internal/services"
@@ -72,6 +74,18 @@ func run() {
 	log.Println("Operation repository initialized")
 	refr := repositories.NewReferralRepository(db.Db)
 	log.Println("Referral repository initialized")
+	rtr := repositories.NewRewardTierRepository(db.Db)
+	log.Println("Reward tier repository initialized")
+	pwr := repositories.NewPoolWhitelistRepository(db.Db)
+	log.Println("Pool whitelist repository initialized")
+	tir := repositories.NewTxIntentRepository(db.Db)
+	log.Println("Tx intent repository initialized")
+	fpr := repositories.NewFailedPayoutRepository(db.Db)
+	log.Println("Failed payout repository initialized")
+	anr := repositories.NewAnnouncementRepository(db.Db)
+	log.Println("Announcement repository initialized")
+	nsr := repositories.NewNotificationSettingRepository(db.Db)
+	log.Println("Notification setting repository initialized")
 
 	log.Println("Repository initialized")
 
@@ -80,7 +94,7 @@ func run() {
 	log.Println("User service initialized")
 	ts := services.NewTelegramService(tr, us)
 	log.Println("Telegram service initialized")
-	ps := services.NewPoolService(pr, us)
+	ps := services.NewPoolService(pr, rtr, pwr, us)
 	log.Println("Pool service initialized")
 	ss := services.NewStakeService(sr, us, ps)
 	log.Println("Stake service initialized")
@@ -88,7 +102,13 @@ func run() {
 	log.Println("WalletTon service initialized")
 	opS := services.NewOperationService(or)
 	log.Println("Operation service initialized")
-	rs := services.NewReferalService(refr)
+	rs := services.NewReferalService(refr, us, ws)
+	as := services.NewAdminService(us, ps, ss, fpr)
+	log.Println("Admin service initialized")
+	ans := services.NewAnnouncementService(ps, anr)
+	log.Println("Announcement service initialized")
+	ns := services.NewNotificationService(nsr)
+	log.Println("Notification service initialized")
 
 	aws, err := services.NewAdminWalletService(
 		config.LoadTonConfig(),
@@ -103,13 +123,54 @@ func run() {
 	log.Println("AdminWallet service initialized")
 	tcs := services.NewTonConnectService(redis.Cli, aws)
 	log.Println("Ton connect service initialized")
+	is := services.NewTxIntentService(tir, aws)
+	tcs.TrackIntents(is)
+	log.Println("Tx intent service initialized")
 
 	log.Println("Service initialized")
 
 	tokenBot := os.Getenv("TELEGRAM_BOT_TOKEN")
 
+	// API и манифест могут раздаваться как с одного адреса, так и с разных
+	servers := make(map[string]*http.ServeMux)
+	muxFor := func(addr string) *http.ServeMux {
+		if _, ok := servers[addr]; !ok {
+			servers[addr] = http.NewServeMux()
+		}
+		return servers[addr]
+	}
+
+	apiConfig := config.LoadApiConfig()
+	if apiConfig.Addr != "" {
+		mux := muxFor(apiConfig.Addr)
+		api := handlers.NewApi(ps, ss, us, opS, apiConfig.Token, apiConfig.Origins)
+		api.Register(mux)
+		handlers.NewWebApp(api, ps, ss, us, ts, ws, aws, as, tcs, opS, tokenBot, tonbot.EnqueuePayout, apiConfig.WebAppOrigins).Register(mux)
+	}
+
+	manifestConfig := config.LoadManifestConfig()
+	if manifestConfig.Addr == "" {
+		manifestConfig.Addr = apiConfig.Addr
+	}
+	if manifestConfig.Addr != "" {
+		manifest, err := handlers.NewManifest(manifestConfig)
+		if err != nil {
+			logger.Fatalf("Failed to load TON Connect manifest: %v", err)
+		}
+		manifest.Register(muxFor(manifestConfig.Addr))
+	}
+
+	for addr, mux := range servers {
+		go func() {
+			logger.Infoln("HTTP server listening on", addr)
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				logger.Fatalf("Failed to start HTTP server: %v", err)
+			}
+		}()
+	}
+
 	logger.Infoln("Telegram bot starting:", tokenBot)
-	tgbot := tonbot.NewTgBot(tokenBot, us, ts, ps, aws, ss, ws, tcs, opS, rs)
+	tgbot := tonbot.NewTgBot(tokenBot, us, ts, ps, aws, ss, ws, tcs, opS, rs, is, as, ans, ns)
 
 	transaction := make(chan models.SubmitTransaction)
 
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,10 +15,20 @@ import (
 const (
 	CONFIG_TON_TESTNET_URL string = "https://ton-blockchain.github.io/testnet-global.config.json"
 	CONFIG_TON_MAINNET_URL string = "https://ton-blockchain.github.io/global.config.json"
+
+	// адрес, под которым ston.fi отдает цену нативного TON
+	TON_NATIVE_ADDR string = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	USDT_DECIMALS   int    = 6
+
+	MANIFEST_FILE string = "tonconnect-manifest.json"
+
+	WALLETS_LIST_URL  string = "https://raw.githubusercontent.com/ton-blockchain/wallets-list/main/wallets-v2.json"
+	WALLETS_LIST_FILE string = "tonconnect-wallets.json"
 )
 
 var WALLET_SEED []string
 var COMMISSION_AMOUNT float64
+var USDT_JETTON_MASTER = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
 
 var log = InitLogger()
 
@@ -32,6 +46,80 @@ type RedisConfig struct {
 	Db       int
 }
 
+type ApiConfig struct {
+	Addr  string
+	Token string
+	// Origins адреса веб-дашборда, которым доступен /api/v1. "*" - любой адрес
+	Origins []string
+	// WebAppOrigins адреса, с которых Mini App обращается к /webapp/v1. "*" - любой адрес
+	WebAppOrigins []string
+}
+
+// ManifestConfig манифест TON Connect и связанные с ним файлы.
+// Пустые значения берутся из tonconnect-manifest.json
+type ManifestConfig struct {
+	Addr        string
+	PublicUrl   string
+	AppUrl      string
+	Name        string
+	IconUrl     string
+	IconFile    string
+	TermsUrl    string
+	TermsFile   string
+	PrivacyUrl  string
+	PrivacyFile string
+}
+
+// WalletsRegistryConfig реестр кошельков TON Connect: адрес списка, локальный файл на случай
+// недоступности реестра, время кэширования и необязательный фильтр по app_name
+type WalletsRegistryConfig struct {
+	Url  string
+	File string
+	Ttl  time.Duration
+	Only []string
+}
+
+// TonConnectSessionConfig хранение сессий TON Connect: время жизни без использования и ключ шифрования
+type TonConnectSessionConfig struct {
+	Ttl time.Duration
+	Key []byte
+}
+
+// CommissionConfig политика комиссии за стейк по умолчанию и скидка приглашенным пользователям в %
+type CommissionConfig struct {
+	Asset            string
+	Type             string
+	Value            float64
+	ReferralDiscount float64
+}
+
+// ReferralConfig реферальная программа: процент награды для каждого уровня приглашения,
+// от чего он считается (stake или commission) и порог выплаты в токенах платформы
+type ReferralConfig struct {
+	Levels    []float64
+	Base      string
+	Threshold float64
+	Mode      string  // once - награда один раз за приглашенного, every - за каждый стейк
+	MinStake  float64 // минимальная сумма стейка в токенах платформы
+	MinHold   uint    // минимальный срок стейка в днях
+}
+
+// TelegramSenderConfig лимиты исходящих сообщений Bot API
+type TelegramSenderConfig struct {
+	GlobalRate   int           // сообщений в секунду на весь бот
+	ChatInterval time.Duration // интервал между сообщениями в один чат
+	ChatBurst    int           // сообщений в один чат подряд без ожидания
+	Retries      int           // повторов при временных ошибках
+	Workers      int           // обработчиков очереди уведомлений
+	QueueSize    int
+}
+
+// PriceWatchConfig проверка цены активных стейков относительно порога страховки
+type PriceWatchConfig struct {
+	Interval   time.Duration
+	NearMargin float64 // за сколько процентных пунктов до порога предупреждать
+}
+
 type TonClientConfig struct {
 	Seed                []string
 	WalletAddr          string
@@ -50,6 +138,10 @@ func InitConfig() error {
 		COMMISSION_AMOUNT = 5
 	}
 
+	if usdt := os.Getenv("JETTON_CONTRACT_USDT"); usdt != "" {
+		USDT_JETTON_MASTER = usdt
+	}
+
 	return nil
 }
 
@@ -89,3 +181,248 @@ func LoadRedisConfig() *RedisConfig {
 		Db:       convDb,
 	}
 }
+
+// LoadApiConfig настройки HTTP API. Пустой API_ADDR - сервер не запускается
+func LoadApiConfig() *ApiConfig {
+	return &ApiConfig{
+		Addr:          os.Getenv("API_ADDR"),
+		Token:         os.Getenv("API_TOKEN"),
+		Origins:       envList("API_ALLOWED_ORIGINS"),
+		WebAppOrigins: envList("WEBAPP_ALLOWED_ORIGINS"),
+	}
+}
+
+// LoadManifestConfig настройки раздачи манифеста. Пустой MANIFEST_ADDR - манифест раздается на адресе API
+func LoadManifestConfig() *ManifestConfig {
+	return &ManifestConfig{
+		Addr:        os.Getenv("MANIFEST_ADDR"),
+		PublicUrl:   strings.TrimSuffix(os.Getenv("MANIFEST_PUBLIC_URL"), "/"),
+		AppUrl:      os.Getenv("MANIFEST_APP_URL"),
+		Name:        os.Getenv("MANIFEST_APP_NAME"),
+		IconUrl:     os.Getenv("MANIFEST_ICON_URL"),
+		IconFile:    os.Getenv("MANIFEST_ICON_FILE"),
+		TermsUrl:    os.Getenv("MANIFEST_TERMS_URL"),
+		TermsFile:   os.Getenv("MANIFEST_TERMS_FILE"),
+		PrivacyUrl:  os.Getenv("MANIFEST_PRIVACY_URL"),
+		PrivacyFile: os.Getenv("MANIFEST_PRIVACY_FILE"),
+	}
+}
+
+// ManifestUrl адрес манифеста для кошельков: MANIFEST_URL или манифест, раздаваемый самим приложением
+func ManifestUrl() string {
+	if u := os.Getenv("MANIFEST_URL"); u != "" {
+		return u
+	}
+	if u := strings.TrimSuffix(os.Getenv("MANIFEST_PUBLIC_URL"), "/"); u != "" {
+		return u + "/tonconnect-manifest.json"
+	}
+	return ""
+}
+
+// TonProofDomains домены, для которых принимается ton_proof: TON_PROOF_DOMAINS через запятую,
+// иначе домен приложения из MANIFEST_APP_URL или url манифеста
+func TonProofDomains() []string {
+	if v := os.Getenv("TON_PROOF_DOMAINS"); v != "" {
+		domains := make([]string, 0)
+		for _, d := range strings.Split(v, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		return domains
+	}
+
+	appUrl := os.Getenv("MANIFEST_APP_URL")
+	if appUrl == "" {
+		var manifest struct {
+			Url string `json:"url"`
+		}
+		if data, err := os.ReadFile(MANIFEST_FILE); err == nil && json.Unmarshal(data, &manifest) == nil {
+			appUrl = manifest.Url
+		}
+	}
+
+	u, err := url.Parse(appUrl)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return []string{u.Host}
+}
+
+// LoadCommissionConfig COMMISSION_STAKE_ASSET (jetton, ton, stake), COMMISSION_STAKE_TYPE (fixed, percent),
+// COMMISSION_STAKE_AMOUNT и COMMISSION_REFERRAL_DISCOUNT. По умолчанию 1 токен платформы без скидки
+func LoadCommissionConfig() *CommissionConfig {
+	cfg := &CommissionConfig{
+		Asset: strings.ToLower(firstEnv("COMMISSION_STAKE_ASSET", "jetton")),
+		Type:  strings.ToLower(firstEnv("COMMISSION_STAKE_TYPE", "fixed")),
+		Value: 1,
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("COMMISSION_STAKE_AMOUNT"), 64); err == nil && v >= 0 {
+		cfg.Value = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("COMMISSION_REFERRAL_DISCOUNT"), 64); err == nil && v > 0 {
+		cfg.ReferralDiscount = min(v, 100)
+	}
+	return cfg
+}
+
+// LoadReferralConfig REFERRAL_LEVELS - проценты уровней через запятую (по умолчанию REFERAL_BONUS для одного уровня),
+// REFERRAL_REWARD_BASE - stake или commission, REFERRAL_PAYOUT_THRESHOLD - минимальная сумма выплаты,
+// REFERRAL_REWARD_MODE - once или every, REFERRAL_MIN_STAKE и REFERRAL_MIN_HOLD_DAYS - условия начисления
+func LoadReferralConfig() *ReferralConfig {
+	cfg := &ReferralConfig{
+		Base:      strings.ToLower(firstEnv("REFERRAL_REWARD_BASE", "stake")),
+		Threshold: 10,
+		Mode:      strings.ToLower(firstEnv("REFERRAL_REWARD_MODE", "once")),
+	}
+	for _, level := range strings.Split(firstEnv("REFERRAL_LEVELS", firstEnv("REFERAL_BONUS", "2")), ",") {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(level), 64)
+		if err != nil || percent < 0 {
+			log.Error("Error parsing REFERRAL_LEVELS: ", level)
+			break
+		}
+		cfg.Levels = append(cfg.Levels, percent)
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("REFERRAL_PAYOUT_THRESHOLD"), 64); err == nil && v >= 0 {
+		cfg.Threshold = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("REFERRAL_MIN_STAKE"), 64); err == nil && v >= 0 {
+		cfg.MinStake = v
+	}
+	if v, err := strconv.ParseUint(os.Getenv("REFERRAL_MIN_HOLD_DAYS"), 10, 32); err == nil {
+		cfg.MinHold = uint(v)
+	}
+	return cfg
+}
+
+// AdminTelegramIds telegram id администраторов платформы через запятую: ADMIN_TELEGRAM_IDS
+func AdminTelegramIds() []uint64 {
+	ids := make([]uint64, 0)
+	for _, v := range strings.Split(os.Getenv("ADMIN_TELEGRAM_IDS"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			log.Error("Error parsing ADMIN_TELEGRAM_IDS: ", v)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// LoadTelegramSenderConfig TELEGRAM_GLOBAL_RATE (по умолчанию 25 в секунду), TELEGRAM_CHAT_INTERVAL (1s),
+// TELEGRAM_CHAT_BURST (3), TELEGRAM_SEND_RETRIES (3), TELEGRAM_SEND_WORKERS (4), TELEGRAM_QUEUE_SIZE (1000)
+func LoadTelegramSenderConfig() *TelegramSenderConfig {
+	cfg := &TelegramSenderConfig{
+		GlobalRate:   25,
+		ChatInterval: time.Second,
+		ChatBurst:    3,
+		Retries:      3,
+		Workers:      4,
+		QueueSize:    1000,
+	}
+	positive := func(key string, dst *int) {
+		if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+			*dst = v
+		}
+	}
+	positive("TELEGRAM_GLOBAL_RATE", &cfg.GlobalRate)
+	positive("TELEGRAM_CHAT_BURST", &cfg.ChatBurst)
+	positive("TELEGRAM_SEND_WORKERS", &cfg.Workers)
+	positive("TELEGRAM_QUEUE_SIZE", &cfg.QueueSize)
+	if v, err := strconv.Atoi(os.Getenv("TELEGRAM_SEND_RETRIES")); err == nil && v >= 0 {
+		cfg.Retries = v
+	}
+	if d, err := time.ParseDuration(os.Getenv("TELEGRAM_CHAT_INTERVAL")); err == nil && d >= 0 {
+		cfg.ChatInterval = d
+	}
+	return cfg
+}
+
+// LoadPriceWatchConfig PRICE_WATCH_INTERVAL (по умолчанию 15m) и PRICE_ALERT_NEAR_MARGIN (5 процентных пунктов)
+func LoadPriceWatchConfig() *PriceWatchConfig {
+	cfg := &PriceWatchConfig{
+		Interval:   15 * time.Minute,
+		NearMargin: 5,
+	}
+	if d, err := time.ParseDuration(os.Getenv("PRICE_WATCH_INTERVAL")); err == nil && d > 0 {
+		cfg.Interval = d
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("PRICE_ALERT_NEAR_MARGIN"), 64); err == nil && v >= 0 {
+		cfg.NearMargin = v
+	}
+	return cfg
+}
+
+// PayoutWalletCooldown задержка перед сменой кошелька для выплат: PAYOUT_WALLET_COOLDOWN, по умолчанию сутки
+func PayoutWalletCooldown() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PAYOUT_WALLET_COOLDOWN")); err == nil && d >= 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+func LoadWalletsRegistryConfig() *WalletsRegistryConfig {
+	cfg := &WalletsRegistryConfig{
+		Url:  firstEnv("WALLETS_LIST_URL", WALLETS_LIST_URL),
+		File: firstEnv("WALLETS_LIST_FILE", WALLETS_LIST_FILE),
+		Ttl:  24 * time.Hour,
+	}
+	if d, err := time.ParseDuration(os.Getenv("WALLETS_LIST_TTL")); err == nil && d > 0 {
+		cfg.Ttl = d
+	}
+	for _, name := range strings.Split(os.Getenv("TON_CONNECT_WALLETS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.Only = append(cfg.Only, strings.ToLower(name))
+		}
+	}
+	return cfg
+}
+
+// LoadTonConnectSessionConfig TON_CONNECT_SESSION_TTL (по умолчанию 30 дней) и TON_CONNECT_SESSION_KEY.
+// Без ключа сессии шифруются ключом, производным от сид-фразы кошелька
+func LoadTonConnectSessionConfig() *TonConnectSessionConfig {
+	cfg := &TonConnectSessionConfig{Ttl: 30 * 24 * time.Hour}
+	if d, err := time.ParseDuration(os.Getenv("TON_CONNECT_SESSION_TTL")); err == nil && d > 0 {
+		cfg.Ttl = d
+	}
+
+	secret := os.Getenv("TON_CONNECT_SESSION_KEY")
+	if secret == "" {
+		log.Warn("TON_CONNECT_SESSION_KEY is not set, session key is derived from WALLET_SEED")
+		secret = "tonconnect-session:" + os.Getenv("WALLET_SEED")
+	}
+	key := sha256.Sum256([]byte(secret))
+	cfg.Key = key[:]
+	return cfg
+}
+
+// QrCodeUrl ссылка на картинку QR-кода с data или "", если сервис не задан в QR_CODE_URL.
+// Сервис получает адрес казны, сумму и комментарий перевода, поэтому по умолчанию выключен
+func QrCodeUrl(data string) string {
+	service := os.Getenv("QR_CODE_URL")
+	if service == "" {
+		return ""
+	}
+	return service + url.QueryEscape(data)
+}
+
+// envList значения переменной окружения через запятую
+func envList(key string) []string {
+	res := make([]string, 0)
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func firstEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
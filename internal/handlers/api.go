@@ -0,0 +1,466 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+)
+
+var log = config.InitLogger()
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Api JSON API v1 для веб-дашборда. Данные пулов открыты всем,
+// данные пользователей доступны только с токеном API_TOKEN
+type Api struct {
+	ps    *services.PoolService
+	ss    *services.StakeService
+	us    *services.UserService
+	opS   *services.OperationService
+	token string
+	cors  *cors
+}
+
+func NewApi(ps *services.PoolService, ss *services.StakeService, us *services.UserService,
+	opS *services.OperationService, token string, origins []string) *Api {
+	return &Api{
+		ps:    ps,
+		ss:    ss,
+		us:    us,
+		opS:   opS,
+		token: token,
+		cors:  newCors(origins, http.MethodGet),
+	}
+}
+
+func (a *Api) Register(mux *http.ServeMux) {
+	// дашборд размещается отдельно и читает API из браузера с другого адреса
+	handle := func(pattern string, next http.HandlerFunc) {
+		mux.HandleFunc(pattern, a.cors.wrap(next))
+	}
+	mux.HandleFunc("OPTIONS /api/v1/", a.cors.preflight)
+	handle("GET /api/v1/pools", a.listPools)
+	handle("GET /api/v1/pools/{id}", a.getPool)
+	handle("GET /api/v1/pools/{id}/stats", a.poolStats)
+
+	handle("GET /api/v1/users/{id}/pools", a.private(a.userPools))
+	handle("GET /api/v1/users/{id}/stakes", a.private(a.userStakes))
+	handle("GET /api/v1/users/{id}/operations", a.private(a.userOperations))
+	handle("GET /api/v1/users/{id}/stats", a.private(a.ownerStats))
+}
+
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type PoolResponse struct {
+	Id               int64     `json:"id"`
+	OwnerId          uint64    `json:"owner_id"`
+	JettonName       string    `json:"jetton_name"`
+	JettonMaster     string    `json:"jetton_master"`
+	Reserve          float64   `json:"reserve"`
+	AvailableReserve float64   `json:"available_reserve"`
+	MinStakeAmount   float64   `json:"min_stake_amount"`
+	Reward           float64   `json:"reward"`
+	Period           uint      `json:"period"`
+	InsuranceCoating uint      `json:"insurance_coating"`
+	InsuranceAsset   string    `json:"insurance_asset"`
+	InsuranceReserve float64   `json:"insurance_reserve"`
+	EarlyExitPenalty float64   `json:"early_exit_penalty"`
+	EarlyExitMinDays uint      `json:"early_exit_min_days"`
+	EarlyExitPartial bool      `json:"early_exit_partial"`
+	MaxTotalStake    float64   `json:"max_total_stake"`
+	MaxUserStake     float64   `json:"max_user_stake"`
+	MaxStakers       uint      `json:"max_stakers"`
+	IsWhitelist      bool      `json:"is_whitelist"`
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type StakeResponse struct {
+	Id              int64      `json:"id"`
+	PoolId          uint64     `json:"pool_id"`
+	Amount          float64    `json:"amount"`
+	Balance         float64    `json:"balance"`
+	Reward          float64    `json:"reward"`
+	Period          uint       `json:"period"`
+	StartDate       time.Time  `json:"start_date"`
+	EndDate         time.Time  `json:"end_date"`
+	CloseDate       *time.Time `json:"close_date,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	IsRewardPaid    bool       `json:"is_reward_paid"`
+	IsInsurancePaid bool       `json:"is_insurance_paid"`
+	AutoRollover    bool       `json:"auto_rollover"`
+	RolledFrom      *int64     `json:"rolled_from,omitempty"`
+}
+
+type OperationResponse struct {
+	Id           int64     `json:"id"`
+	NumOperation int       `json:"num_operation"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PoolStatsResponse struct {
+	PoolId           int64   `json:"pool_id"`
+	JettonName       string  `json:"jetton_name"`
+	ActiveStakes     int     `json:"active_stakes"`
+	ClosedStakes     int     `json:"closed_stakes"`
+	Stakers          int     `json:"stakers"`
+	TotalStaked      float64 `json:"total_staked"`
+	Reserve          float64 `json:"reserve"`
+	AvailableReserve float64 `json:"available_reserve"`
+	InsuranceReserve float64 `json:"insurance_reserve"`
+}
+
+type OwnerStatsResponse struct {
+	Pools        int                 `json:"pools"`
+	ActivePools  int                 `json:"active_pools"`
+	ActiveStakes int                 `json:"active_stakes"`
+	Stakers      int                 `json:"stakers"`
+	TotalStaked  map[string]float64  `json:"total_staked"`
+	PoolStats    []PoolStatsResponse `json:"pool_stats"`
+}
+
+func (a *Api) listPools(w http.ResponseWriter, r *http.Request) {
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	var pools *[]models.Pool
+	var total int
+	switch r.URL.Query().Get("active") {
+	case "":
+		pools = a.ps.AllLimit(offset, limit)
+		total = a.ps.CountAll()
+	case "true", "false":
+		isActive := r.URL.Query().Get("active") == "true"
+		pools = a.ps.AllLimitByStatus(isActive, offset, limit)
+		total = a.ps.CountAllByStatus(isActive)
+	default:
+		writeError(w, http.StatusBadRequest, "active must be true or false")
+		return
+	}
+
+	writeJson(w, http.StatusOK, Page[PoolResponse]{
+		Items:  a.poolResponses(pools),
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+func (a *Api) getPool(w http.ResponseWriter, r *http.Request) {
+	pool, ok := a.pathPool(w, r)
+	if !ok {
+		return
+	}
+
+	writeJson(w, http.StatusOK, a.poolResponse(pool))
+}
+
+func (a *Api) poolStats(w http.ResponseWriter, r *http.Request) {
+	pool, ok := a.pathPool(w, r)
+	if !ok {
+		return
+	}
+
+	writeJson(w, http.StatusOK, a.poolStatsResponse(pool))
+}
+
+func (a *Api) userPools(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.pathUser(w, r)
+	if !ok {
+		return
+	}
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	writeJson(w, http.StatusOK, Page[PoolResponse]{
+		Items:  a.poolResponses(a.ps.GetPoolsByUserIdLimit(userId, offset, limit)),
+		Total:  a.ps.CountUserPool(userId),
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+func (a *Api) userStakes(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.pathUser(w, r)
+	if !ok {
+		return
+	}
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	stakes := a.ss.GetStakesUserLimit(userId, offset, limit)
+	items := make([]StakeResponse, 0, len(*stakes))
+	for _, s := range *stakes {
+		items = append(items, stakeResponse(&s))
+	}
+
+	writeJson(w, http.StatusOK, Page[StakeResponse]{
+		Items:  items,
+		Total:  a.ss.CountUser(userId),
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+func (a *Api) userOperations(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.pathUser(w, r)
+	if !ok {
+		return
+	}
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	operations, err := a.opS.GetByUserIdLimit(userId, offset, limit)
+	if err != nil {
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, "failed to load operations")
+		return
+	}
+
+	items := make([]OperationResponse, 0, len(operations))
+	for _, op := range operations {
+		items = append(items, OperationResponse{
+			Id:           op.Id.Int64,
+			NumOperation: op.NumOperation,
+			Name:         op.Name,
+			Description:  op.Description,
+			CreatedAt:    op.CreatedAt,
+		})
+	}
+
+	writeJson(w, http.StatusOK, Page[OperationResponse]{
+		Items:  items,
+		Total:  a.opS.CountByUserId(userId),
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+func (a *Api) ownerStats(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	res := OwnerStatsResponse{
+		TotalStaked: make(map[string]float64),
+		PoolStats:   make([]PoolStatsResponse, 0),
+	}
+	stakers := make(map[uint64]bool)
+	pools := a.ps.GetPoolsByUserId(uint64(user.Id.Int64))
+	if pools != nil {
+		for _, p := range *pools {
+			stats := a.poolStatsResponse(&p)
+			res.Pools++
+			if p.IsActive {
+				res.ActivePools++
+			}
+			res.ActiveStakes += stats.ActiveStakes
+			res.TotalStaked[p.JettonName] += stats.TotalStaked
+			res.PoolStats = append(res.PoolStats, stats)
+
+			for _, s := range a.ss.GetPoolStakes(uint64(p.Id.Int64)) {
+				if s.IsActive {
+					stakers[s.UserId] = true
+				}
+			}
+		}
+	}
+	res.Stakers = len(stakers)
+
+	writeJson(w, http.StatusOK, res)
+}
+
+// private пропускает запрос только с заголовком Authorization: Bearer <API_TOKEN>
+func (a *Api) private(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if a.token == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (a *Api) pathPool(w http.ResponseWriter, r *http.Request) (*models.Pool, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid pool id")
+		return nil, false
+	}
+
+	pool, err := a.ps.GetId(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "pool not found")
+		return nil, false
+	}
+
+	return pool, true
+}
+
+func (a *Api) pathUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return nil, false
+	}
+
+	user, err := a.us.GetById(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (a *Api) poolResponses(pools *[]models.Pool) []PoolResponse {
+	res := make([]PoolResponse, 0)
+	if pools == nil {
+		return res
+	}
+	for _, p := range *pools {
+		res = append(res, a.poolResponse(&p))
+	}
+	return res
+}
+
+func (a *Api) poolResponse(p *models.Pool) PoolResponse {
+	return PoolResponse{
+		Id:               p.Id.Int64,
+		OwnerId:          p.OwnerId,
+		JettonName:       p.JettonName,
+		JettonMaster:     p.JettonMaster,
+		Reserve:          p.Reserve,
+		AvailableReserve: util.AvailableReserve(p, a.ss.GetPoolStakes(uint64(p.Id.Int64))),
+		MinStakeAmount:   p.MinStakeAmount,
+		Reward:           p.Reward,
+		Period:           p.Period,
+		InsuranceCoating: p.InsuranceCoating,
+		InsuranceAsset:   p.InsuranceAsset,
+		InsuranceReserve: p.InsuranceReserve,
+		EarlyExitPenalty: p.EarlyExitPenalty,
+		EarlyExitMinDays: p.EarlyExitMinDays,
+		EarlyExitPartial: p.EarlyExitPartial,
+		MaxTotalStake:    p.MaxTotalStake,
+		MaxUserStake:     p.MaxUserStake,
+		MaxStakers:       p.MaxStakers,
+		IsWhitelist:      p.IsWhitelist,
+		IsActive:         p.IsActive,
+		CreatedAt:        p.CreatedAt,
+	}
+}
+
+func (a *Api) poolStatsResponse(p *models.Pool) PoolStatsResponse {
+	stakes := a.ss.GetPoolStakes(uint64(p.Id.Int64))
+	res := PoolStatsResponse{
+		PoolId:           p.Id.Int64,
+		JettonName:       p.JettonName,
+		Reserve:          p.Reserve,
+		AvailableReserve: util.AvailableReserve(p, stakes),
+		InsuranceReserve: p.InsuranceReserve,
+	}
+
+	stakers := make(map[uint64]bool)
+	for _, s := range stakes {
+		if !s.IsActive {
+			res.ClosedStakes++
+			continue
+		}
+		res.ActiveStakes++
+		res.TotalStaked += s.Amount
+		stakers[s.UserId] = true
+	}
+	res.Stakers = len(stakers)
+
+	return res
+}
+
+func stakeResponse(s *models.Stake) StakeResponse {
+	res := StakeResponse{
+		Id:              s.Id.Int64,
+		PoolId:          s.PoolId,
+		Amount:          s.Amount,
+		Balance:         s.Balance,
+		Reward:          s.Reward,
+		Period:          s.Period,
+		StartDate:       s.StartDate,
+		EndDate:         s.EndDate,
+		IsActive:        s.IsActive,
+		IsRewardPaid:    s.IsRewardPaid,
+		IsInsurancePaid: s.IsInsurancePaid,
+		AutoRollover:    s.AutoRollover,
+	}
+	if !s.IsActive {
+		closeDate := s.CloseDate
+		res.CloseDate = &closeDate
+	}
+	if s.RolledFrom.Valid {
+		rolledFrom := s.RolledFrom.Int64
+		res.RolledFrom = &rolledFrom
+	}
+	return res
+}
+
+func pagination(w http.ResponseWriter, r *http.Request) (offset, limit int, ok bool) {
+	offset, limit = 0, defaultPageLimit
+	q := r.URL.Query()
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid offset")
+			return 0, 0, false
+		}
+		offset = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageLimit {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxPageLimit))
+			return 0, 0, false
+		}
+		limit = n
+	}
+	return offset, limit, true
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Error while writing response: ", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJson(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsMaxAge сколько секунд браузер хранит ответ на preflight
+const corsMaxAge = "600"
+
+// cors разрешает запросы из браузера с заданных адресов. Без адресов заголовки не ставятся
+// и запросы проходят только с того же адреса
+type cors struct {
+	origins []string
+	methods string
+}
+
+func newCors(origins []string, methods ...string) *cors {
+	return &cors{
+		origins: origins,
+		methods: strings.Join(append(methods, http.MethodOptions), ", "),
+	}
+}
+
+// allow ставит заголовки CORS, если адрес запроса разрешен
+func (c *cors) allow(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	w.Header().Add("Vary", "Origin")
+	switch {
+	case slices.Contains(c.origins, "*"):
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	case slices.Contains(c.origins, origin):
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	default:
+		return false
+	}
+	return true
+}
+
+func (c *cors) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.allow(w, r)
+		next(w, r)
+	}
+}
+
+// preflight отвечает на OPTIONS перед запросом с Authorization или JSON телом
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request) {
+	if c.allow(w, r) {
+		w.Header().Set("Access-Control-Allow-Methods", c.methods)
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,40 +1,130 @@
 package handlers
 
 import (
-	"io"
+	"encoding/json"
 	"net/http"
 	"os"
 	"path/filepath"
+	"tonclient/internal/config"
 )
 
-func ManifestHandler(w http.ResponseWriter, r *http.Request) {
-	fileName := "tonconnect-manifest.json"
-	filePath := filepath.Join("", fileName)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		http.Error(w, "File not found", http.StatusNotFound)
-		return
+const (
+	manifestPath = "/tonconnect-manifest.json"
+	iconPath     = "/tonconnect-icon"
+	termsPath    = "/terms"
+	privacyPath  = "/privacy"
+
+	// кошельки кэшируют манифест, поэтому долго держать его в кэше не стоит
+	manifestCacheControl = "public, max-age=3600"
+)
+
+// ManifestFile файл манифеста, значения из которого используются, если не заданы в env
+var ManifestFile = config.MANIFEST_FILE
+
+type TonConnectManifest struct {
+	Url              string `json:"url"`
+	Name             string `json:"name"`
+	IconUrl          string `json:"iconUrl"`
+	TermsOfUseUrl    string `json:"termsOfUseUrl,omitempty"`
+	PrivacyPolicyUrl string `json:"privacyPolicyUrl,omitempty"`
+}
+
+// Manifest раздает манифест TON Connect, иконку и документы, на которые он ссылается
+type Manifest struct {
+	cfg  *config.ManifestConfig
+	body []byte
+}
+
+func NewManifest(cfg *config.ManifestConfig) (*Manifest, error) {
+	var manifest TonConnectManifest
+	if data, err := os.ReadFile(ManifestFile); err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
 	}
-	file, err := os.Open(filePath)
+
+	manifest.Url = firstNotEmpty(cfg.AppUrl, manifest.Url)
+	manifest.Name = firstNotEmpty(cfg.Name, manifest.Name)
+	manifest.IconUrl = firstNotEmpty(cfg.IconUrl, fileUrl(cfg.PublicUrl, iconPath+filepath.Ext(cfg.IconFile), cfg.IconFile), manifest.IconUrl)
+	manifest.TermsOfUseUrl = firstNotEmpty(cfg.TermsUrl, fileUrl(cfg.PublicUrl, termsPath, cfg.TermsFile), manifest.TermsOfUseUrl)
+	manifest.PrivacyPolicyUrl = firstNotEmpty(cfg.PrivacyUrl, fileUrl(cfg.PublicUrl, privacyPath, cfg.PrivacyFile), manifest.PrivacyPolicyUrl)
+
+	body, err := json.Marshal(manifest)
 	if err != nil {
-		http.Error(w, "Error opening file", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
 
-		}
-	}(file)
+	return &Manifest{
+		cfg:  cfg,
+		body: body,
+	}, nil
+}
 
-	json, err := io.ReadAll(file)
-	if err != nil {
-		http.Error(w, "Error reading file", http.StatusInternalServerError)
+func (m *Manifest) Register(mux *http.ServeMux) {
+	mux.HandleFunc(manifestPath, m.cors(m.manifest))
+	if m.cfg.IconFile != "" {
+		mux.HandleFunc(iconPath+filepath.Ext(m.cfg.IconFile), m.cors(serveFile(m.cfg.IconFile)))
+	}
+	if m.cfg.TermsFile != "" {
+		mux.HandleFunc(termsPath, m.cors(serveFile(m.cfg.TermsFile)))
 	}
+	if m.cfg.PrivacyFile != "" {
+		mux.HandleFunc(privacyPath, m.cors(serveFile(m.cfg.PrivacyFile)))
+	}
+}
 
+func (m *Manifest) manifest(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_, err = w.Write(json)
-	if err != nil {
-		return
+	if _, err := w.Write(m.body); err != nil {
+		log.Error("Error while writing manifest: ", err)
 	}
+}
+
+// cors кошельки загружают манифест и иконку из браузера с другого домена
+func (m *Manifest) cors(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "*")
+
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case http.MethodGet, http.MethodHead:
+		default:
+			w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
+		w.Header().Set("Cache-Control", manifestCacheControl)
+		next(w, r)
+	}
+}
+
+func serveFile(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := os.Stat(path); err != nil {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.ServeFile(w, r, path)
+	}
+}
+
+func fileUrl(publicUrl, path, file string) string {
+	if publicUrl == "" || file == "" {
+		return ""
+	}
+	return publicUrl + path
+}
+
+func firstNotEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
 }
@@ -0,0 +1,729 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"tonclient/internal/i18n"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+
+	"github.com/cameo-engineering/tonconnect"
+	"github.com/xssnick/tonutils-go/address"
+)
+
+// initData Mini App старше суток не принимается
+const webAppAuthMaxAge = 24 * time.Hour
+
+type webAppUserKey struct{}
+
+// WebApp backend Telegram Mini App. Запросы авторизуются заголовком
+// Authorization: tma <initData>, пользователь определяется по Telegram id
+type WebApp struct {
+	api      *Api
+	ps       *services.PoolService
+	ss       *services.StakeService
+	us       *services.UserService
+	ts       *services.TelegramService
+	ws       *services.WalletTonService
+	aws      *services.AdminWalletService
+	as       *services.AdminService
+	tcs      *services.TonConnectService
+	opS      *services.OperationService
+	botToken string
+	payout   func(ctx context.Context, f func()) error
+	cors     *cors
+}
+
+func NewWebApp(
+	api *Api,
+	ps *services.PoolService,
+	ss *services.StakeService,
+	us *services.UserService,
+	ts *services.TelegramService,
+	ws *services.WalletTonService,
+	aws *services.AdminWalletService,
+	as *services.AdminService,
+	tcs *services.TonConnectService,
+	opS *services.OperationService,
+	botToken string,
+	payout func(ctx context.Context, f func()) error,
+	origins []string,
+) *WebApp {
+	return &WebApp{
+		api:      api,
+		ps:       ps,
+		ss:       ss,
+		us:       us,
+		ts:       ts,
+		ws:       ws,
+		aws:      aws,
+		as:       as,
+		tcs:      tcs,
+		opS:      opS,
+		botToken: botToken,
+		payout:   payout,
+		cors:     newCors(origins, http.MethodGet, http.MethodPost, http.MethodPut),
+	}
+}
+
+func (a *WebApp) Register(mux *http.ServeMux) {
+	// Mini App размещается отдельно, поэтому обращается к маршрутам из браузера с другого адреса
+	handle := func(pattern string, next http.HandlerFunc) {
+		mux.HandleFunc(pattern, a.cors.wrap(a.auth(next)))
+	}
+	mux.HandleFunc("OPTIONS /webapp/v1/", a.cors.preflight)
+	handle("GET /webapp/v1/me", a.me)
+	handle("GET /webapp/v1/wallet/proof", a.proofPayload)
+	handle("PUT /webapp/v1/wallet", a.bindWallet)
+	handle("GET /webapp/v1/wallets", a.wallets)
+	handle("POST /webapp/v1/wallets/{id}/default", a.setDefaultWallet)
+	handle("GET /webapp/v1/pools", a.api.listPools)
+	handle("GET /webapp/v1/pools/{id}", a.api.getPool)
+	handle("GET /webapp/v1/stakes", a.stakes)
+	handle("POST /webapp/v1/stakes", a.createStake)
+	handle("POST /webapp/v1/stakes/{id}/claim", a.claim)
+}
+
+type WalletResponse struct {
+	Id        int64      `json:"id"`
+	Address   string     `json:"address"`
+	Name      string     `json:"name"`
+	Label     string     `json:"label"`
+	IsDefault bool       `json:"is_default"`
+	DefaultAt *time.Time `json:"default_at,omitempty"`
+}
+
+// DefaultWalletRequest смена кошелька для выплат требует явного подтверждения
+type DefaultWalletRequest struct {
+	Confirm bool `json:"confirm"`
+}
+
+type MeResponse struct {
+	UserId       int64           `json:"user_id"`
+	TelegramId   int64           `json:"telegram_id"`
+	Username     string          `json:"username"`
+	Wallet       *WalletResponse `json:"wallet"`
+	ActiveStakes int             `json:"active_stakes"`
+}
+
+type ProofPayloadResponse struct {
+	Payload string `json:"payload"`
+}
+
+// TonProofRequest tonProof из ответа TON Connect UI
+type TonProofRequest struct {
+	Timestamp int64 `json:"timestamp"`
+	Domain    struct {
+		LengthBytes uint32 `json:"lengthBytes"`
+		Value       string `json:"value"`
+	} `json:"domain"`
+	Signature string `json:"signature"`
+	Payload   string `json:"payload"`
+}
+
+type BindWalletRequest struct {
+	Address   string           `json:"address"`
+	Name      string           `json:"name"`
+	PublicKey string           `json:"public_key"`
+	StateInit string           `json:"state_init"`
+	Proof     *TonProofRequest `json:"proof"`
+}
+
+type CreateStakeRequest struct {
+	PoolId uint64  `json:"pool_id"`
+	Amount float64 `json:"amount"`
+	Period uint    `json:"period"`
+}
+
+// TonConnectMessage сообщение в формате sendTransaction TON Connect UI
+type TonConnectMessage struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	Payload string `json:"payload,omitempty"`
+}
+
+type TonConnectTransaction struct {
+	ValidUntil int64               `json:"validUntil"`
+	Messages   []TonConnectMessage `json:"messages"`
+}
+
+type CreateStakeResponse struct {
+	Stake           StakeResponse         `json:"stake"`
+	Commission      float64               `json:"commission"`
+	CommissionAsset string                `json:"commission_asset"`
+	Transaction     TonConnectTransaction `json:"transaction"`
+}
+
+type ClaimResponse struct {
+	StakeId       int64   `json:"stake_id"`
+	Amount        float64 `json:"amount"`
+	Hash          string  `json:"hash"`
+	Insurance     float64 `json:"insurance,omitempty"`
+	InsuranceHash string  `json:"insurance_hash,omitempty"`
+	InsuranceSent bool    `json:"insurance_sent"`
+	// PayoutDeferred баланс не отправлен и будет выплачен администратором, компенсация могла уйти
+	PayoutDeferred bool `json:"payout_deferred,omitempty"`
+}
+
+func (a *WebApp) me(w http.ResponseWriter, r *http.Request) {
+	user, tgUser := webAppUser(r)
+	res := MeResponse{
+		UserId:       user.Id.Int64,
+		TelegramId:   tgUser.Id,
+		Username:     user.Username,
+		ActiveStakes: a.ss.CountByUserIdIsActive(uint64(user.Id.Int64), true),
+	}
+	if wallet, err := a.ws.GetByUserId(uint64(user.Id.Int64)); err == nil {
+		res.Wallet = walletResponse(wallet)
+	}
+
+	writeJson(w, http.StatusOK, res)
+}
+
+// proofPayload выдает payload, который Mini App передает в tonProof при подключении кошелька
+func (a *WebApp) proofPayload(w http.ResponseWriter, r *http.Request) {
+	_, tgUser := webAppUser(r)
+	payload, err := a.tcs.GenerateProofPayload(webAppProofKey(tgUser.Id))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to generate proof payload")
+		return
+	}
+
+	writeJson(w, http.StatusOK, ProofPayloadResponse{Payload: payload})
+}
+
+// bindWallet привязывает адрес, подключенный через TON Connect в браузере, после проверки ton_proof
+func (a *WebApp) bindWallet(w http.ResponseWriter, r *http.Request) {
+	user, tgUser := webAppUser(r)
+
+	var req BindWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// TON Connect UI отдает адрес в raw формате, в базе храним user-friendly
+	proven, err := services.ParseAnyAddr(req.Address)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid wallet address")
+		return
+	}
+	addr := proven.String()
+	if req.Proof == nil {
+		writeError(w, http.StatusBadRequest, "ton_proof is required")
+		return
+	}
+
+	proof, err := tonProof(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid ton_proof")
+		return
+	}
+	if err := a.tcs.CheckProof(webAppProofKey(tgUser.Id), proof); err != nil {
+		if services.IsProofError(err) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, "failed to check ton_proof")
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	if other, _ := a.ws.FindWalletByAddr(addr); other != nil && other.UserId != userId {
+		writeError(w, http.StatusConflict, "wallet is bound to another account")
+		return
+	}
+
+	// повторная привязка своего кошелька только обновляет название приложения
+	wallet, err := a.ws.FindWalletByAddr(addr)
+	if err == nil {
+		wallet.Name = req.Name
+		if err := a.ws.Update(wallet); err != nil {
+			log.Error(err)
+			writeError(w, http.StatusInternalServerError, "failed to update wallet")
+			return
+		}
+	} else {
+		wallet, err = a.ws.CreateNewWallet(userId, addr, req.Name)
+		if err != nil {
+			log.Error(err)
+			writeError(w, http.StatusInternalServerError, "failed to bind wallet")
+			return
+		}
+	}
+
+	writeJson(w, http.StatusOK, walletResponse(wallet))
+}
+
+func (a *WebApp) wallets(w http.ResponseWriter, r *http.Request) {
+	user, _ := webAppUser(r)
+	wallets := a.ws.GetUserWallets(uint64(user.Id.Int64))
+	items := make([]WalletResponse, 0, len(wallets))
+	for _, wallet := range wallets {
+		items = append(items, *walletResponse(&wallet))
+	}
+
+	writeJson(w, http.StatusOK, items)
+}
+
+// setDefaultWallet выбирает кошелек для выплат. Смена основного кошелька вступает в силу после задержки
+func (a *WebApp) setDefaultWallet(w http.ResponseWriter, r *http.Request) {
+	user, _ := webAppUser(r)
+	walletId, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid wallet id")
+		return
+	}
+
+	var req DefaultWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Confirm {
+		writeError(w, http.StatusBadRequest, "confirmation is required")
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	if _, err := a.ws.RequestDefault(userId, walletId); err != nil {
+		if errors.Is(err, services.ErrWalletNotFound) {
+			writeError(w, http.StatusNotFound, "wallet not found")
+			return
+		}
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, "failed to set default wallet")
+		return
+	}
+
+	wallet, err := a.ws.GetUserWallet(userId, walletId)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	writeJson(w, http.StatusOK, walletResponse(wallet))
+}
+
+func walletResponse(wallet *models.WalletTon) *WalletResponse {
+	res := &WalletResponse{
+		Id:        wallet.Id.Int64,
+		Address:   wallet.Addr,
+		Name:      wallet.Name,
+		Label:     wallet.Label,
+		IsDefault: wallet.IsDefault,
+	}
+	if wallet.DefaultAt.Valid && !wallet.IsDefault {
+		res.DefaultAt = &wallet.DefaultAt.Time
+	}
+	return res
+}
+
+func webAppProofKey(telegramId int64) string {
+	return "webapp:" + strconv.FormatInt(telegramId, 10)
+}
+
+func tonProof(req *BindWalletRequest) (*models.TonProof, error) {
+	signature, err := base64.StdEncoding.DecodeString(req.Proof.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var stateInit []byte
+	if req.StateInit != "" {
+		if stateInit, err = base64.StdEncoding.DecodeString(req.StateInit); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.TonProof{
+		Address:   req.Address,
+		PublicKey: req.PublicKey,
+		StateInit: stateInit,
+		Timestamp: req.Proof.Timestamp,
+		Domain:    req.Proof.Domain.Value,
+		Signature: signature,
+		Payload:   req.Proof.Payload,
+	}, nil
+}
+
+func (a *WebApp) stakes(w http.ResponseWriter, r *http.Request) {
+	user, _ := webAppUser(r)
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	userId := uint64(user.Id.Int64)
+	stakes := a.ss.GetStakesUserLimit(userId, offset, limit)
+	items := make([]StakeResponse, 0, len(*stakes))
+	for _, s := range *stakes {
+		items = append(items, stakeResponse(&s))
+	}
+
+	writeJson(w, http.StatusOK, Page[StakeResponse]{
+		Items:  items,
+		Total:  a.ss.CountUser(userId),
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+// createStake проверяет стейк по тем же правилам, что и бот, и возвращает транзакцию
+// для TON Connect в браузере: комиссия и депозит отправляются одной транзакцией,
+// стейк создается после поступления депозита на админский кошелек
+func (a *WebApp) createStake(w http.ResponseWriter, r *http.Request) {
+	user, tgUser := webAppUser(r)
+	userId := uint64(user.Id.Int64)
+
+	var req CreateStakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	pool, err := a.ps.GetId(req.PoolId)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "pool not found")
+		return
+	}
+	if !pool.IsActive || pool.Reserve == 0 {
+		writeError(w, http.StatusConflict, "pool is not accepting stakes")
+		return
+	}
+	if req.Amount < pool.MinStakeAmount || req.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("amount must be at least %v", util.RemoveZeroFloat(pool.MinStakeAmount)))
+		return
+	}
+
+	stakes := a.ss.GetPoolStakes(req.PoolId)
+	if maxAmount := util.MaxStakeAmount(pool, util.CalculateSumStakesFromPool(&stakes, pool)); req.Amount > maxAmount {
+		writeError(w, http.StatusConflict, fmt.Sprintf("not enough reserve, max stake is %v", util.RemoveZeroFloat(maxAmount)))
+		return
+	}
+
+	wallet, err := a.ws.GetByUserId(userId)
+	if err != nil {
+		writeError(w, http.StatusConflict, "wallet is not bound")
+		return
+	}
+
+	if pool.IsWhitelist {
+		tg, _ := a.ts.GetByUserId(userId)
+		if !a.ps.IsWhitelisted(pool, tg, wallet) {
+			writeError(w, http.StatusForbidden, poolLimitError(services.ErrNotWhitelisted))
+			return
+		}
+	}
+	if err := a.ss.CanStake(pool, userId, req.Amount); err != nil {
+		writeError(w, http.StatusConflict, poolLimitError(err))
+		return
+	}
+
+	tiers := a.ps.GetRewardTiers(req.PoolId)
+	period := req.Period
+	if period == 0 {
+		period = pool.Period
+	}
+	if !slices.Contains(util.StakePeriods(pool, tiers), period) {
+		writeError(w, http.StatusBadRequest, "period is not available in this pool")
+		return
+	}
+
+	now := time.Now()
+	reward, err := a.ps.ResolveReward(pool, tiers, req.Amount, period, now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "no reward tier for this amount and period")
+		return
+	}
+
+	commission, err := a.ps.ResolveCommission(pool, tiers, user, req.Amount, period, now, util.GetCurrentPriceJettonAddr)
+	if errors.Is(err, services.ErrCommissionAmount) {
+		writeError(w, http.StatusBadRequest, "amount does not cover stake commission")
+		return
+	}
+	if err != nil {
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, "failed to calculate stake commission")
+		return
+	}
+	// комиссия из стейка удерживается с депозита, в стейк идет остаток
+	amount := req.Amount
+	if commission.Asset == models.COMMISSION_ASSET_STAKE {
+		amount -= commission.Amount
+	}
+
+	stake := &models.Stake{
+		UserId:               userId,
+		PoolId:               req.PoolId,
+		Amount:               amount,
+		Balance:              amount,
+		StartDate:            now,
+		IsActive:             true,
+		EndDate:              now.Add(time.Duration(period) * time.Hour * 24),
+		DepositCreationPrice: util.GetCurrentPriceJettonAddr(pool.JettonMaster),
+		Reward:               reward,
+		Period:               period,
+		DepositAddr:          wallet.Addr,
+		PayoutAddr:           wallet.Addr,
+		Commission:           commission.Amount,
+		CommissionAsset:      commission.Asset,
+	}
+
+	tx, err := a.stakeTransaction(tgUser.Id, stake, pool, wallet)
+	if err != nil {
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, "failed to build transaction")
+		return
+	}
+
+	writeJson(w, http.StatusOK, CreateStakeResponse{
+		Stake:           stakeResponse(stake),
+		Commission:      commission.Amount,
+		CommissionAsset: commission.Asset,
+		Transaction:     *tx,
+	})
+}
+
+// stakeTransaction сообщения комиссии и депозита с общим intent: бот сопоставляет по нему оба перевода
+// и обрабатывает поступление только одного из них. Комиссия из стейка входит в депозит,
+// нулевая комиссия не отправляется
+func (a *WebApp) stakeTransaction(telegramId int64, stake *models.Stake, pool *models.Pool, wallet *models.WalletTon) (*TonConnectTransaction, error) {
+	jsonData, err := json.Marshal(stake)
+	if err != nil {
+		return nil, err
+	}
+	senderAddr, err := address.ParseAddr(wallet.Addr)
+	if err != nil {
+		return nil, err
+	}
+	adminAddr := a.aws.GetAdminWalletAddr().String()
+
+	var transfers []services.Transfer
+	deposit := stake.Amount
+	if stake.CommissionAsset == models.COMMISSION_ASSET_STAKE {
+		deposit += stake.Commission
+	} else if stake.Commission > 0 {
+		master := services.CommissionJettonMaster(pool, stake.CommissionAsset)
+		var jettonWallet string
+		if master != "" {
+			commissionWallet, err := a.aws.TokenWalletAddress(master, senderAddr)
+			if err != nil {
+				return nil, err
+			}
+			jettonWallet = commissionWallet.Address().String()
+		}
+		transfers = append(transfers, services.Transfer{
+			JettonWallet: jettonWallet,
+			Amount:       strconv.FormatFloat(stake.Commission, 'f', -1, 64),
+			Payload: &models.Payload{
+				OperationType: models.OP_PAID_COMMISSION_STAKE,
+				JettonMaster:  master,
+				Amount:        stake.Commission,
+				Payload:       string(jsonData),
+				Source:        models.PAYLOAD_SOURCE_WEBAPP,
+			},
+		})
+	}
+
+	depositWallet, err := a.aws.TokenWalletAddress(pool.JettonMaster, senderAddr)
+	if err != nil {
+		return nil, err
+	}
+	transfers = append(transfers, services.Transfer{
+		JettonWallet: depositWallet.Address().String(),
+		Amount:       strconv.FormatFloat(deposit, 'f', -1, 64),
+		Payload: &models.Payload{
+			OperationType: models.OP_STAKE,
+			JettonMaster:  pool.JettonMaster,
+			Amount:        deposit,
+			Payload:       string(jsonData),
+			Source:        models.PAYLOAD_SOURCE_WEBAPP,
+		},
+	})
+
+	_, msgs, err := a.tcs.TransferMessages(uint64(telegramId), adminAddr, wallet.Addr, transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &TonConnectTransaction{
+		ValidUntil: time.Now().Add(5 * time.Minute).Unix(),
+	}
+	for i := range msgs {
+		tx.Messages = append(tx.Messages, tonConnectMessage(&msgs[i]))
+	}
+	return tx, nil
+}
+
+func tonConnectMessage(msg *tonconnect.Message) TonConnectMessage {
+	return TonConnectMessage{
+		Address: msg.Address,
+		Amount:  msg.Amount,
+		Payload: base64.StdEncoding.EncodeToString(msg.Payload),
+	}
+}
+
+// claim выплата по закрытому стейку: награда или, при падении цены ниже покрытия, компенсация
+func (a *WebApp) claim(w http.ResponseWriter, r *http.Request) {
+	user, _ := webAppUser(r)
+	stakeId, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid stake id")
+		return
+	}
+
+	var (
+		status int
+		msg    string
+		res    ClaimResponse
+		done   = make(chan struct{})
+	)
+	if err := a.payout(r.Context(), func() {
+		defer close(done)
+		status, msg, res = a.claimStake(uint64(user.Id.Int64), stakeId)
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "payout queue is busy")
+		return
+	}
+	<-done
+
+	if status != http.StatusOK {
+		writeError(w, status, msg)
+		return
+	}
+	writeJson(w, http.StatusOK, res)
+}
+
+func (a *WebApp) claimStake(userId, stakeId uint64) (int, string, ClaimResponse) {
+	stake, err := a.ss.GetById(stakeId)
+	if err != nil || stake.UserId != userId {
+		return http.StatusNotFound, "stake not found", ClaimResponse{}
+	}
+	pool, err := a.ps.GetId(stake.PoolId)
+	if err != nil {
+		return http.StatusNotFound, "pool not found", ClaimResponse{}
+	}
+	wallet, err := a.ws.GetByUserId(userId)
+	if err != nil {
+		return http.StatusConflict, "wallet is not bound", ClaimResponse{}
+	}
+	jettonData, err := a.aws.DataJetton(pool.JettonMaster)
+	if err != nil {
+		log.Error(err)
+		return http.StatusServiceUnavailable, "payouts are temporarily unavailable", ClaimResponse{}
+	}
+
+	isInsurance := util.IsInsuranceCase(stake, pool)
+	var res *util.ClaimResult
+	if isInsurance {
+		res, err = util.ClaimInsurance(a.aws, a.as, a.ss, a.ps, stake, pool, wallet, jettonData.Decimals)
+	} else {
+		res, err = util.ClaimReward(a.aws, a.as, a.ss, a.ps, stake, pool, wallet, jettonData.Decimals)
+	}
+	switch {
+	case errors.Is(err, util.ErrStakeStillActive):
+		return http.StatusConflict, "stake is still active", ClaimResponse{}
+	case errors.Is(err, util.ErrStakeAlreadyPaid):
+		return http.StatusConflict, "stake is already paid", ClaimResponse{}
+	case errors.Is(err, util.ErrNotEnoughReserve):
+		return http.StatusConflict, "not enough pool reserve, the pool owner has to top it up", ClaimResponse{}
+	case errors.Is(err, util.ErrPayoutDeferred) && res == nil:
+		log.Error(err)
+		return http.StatusBadGateway, "payout failed, it will be made manually by support", ClaimResponse{}
+	case errors.Is(err, util.ErrPayoutDeferred):
+		log.Error(err)
+	case err != nil:
+		log.Error(err)
+		return http.StatusServiceUnavailable, "payouts are temporarily unavailable", ClaimResponse{}
+	}
+
+	resp := ClaimResponse{
+		StakeId:   stake.Id.Int64,
+		Amount:    res.Amount,
+		Hash:      base64.StdEncoding.EncodeToString(res.Boc),
+		Insurance: res.Insurance,
+	}
+	opType := models.OP_CLAIM
+	desc := fmt.Sprintf("Снятие токенов. %v %v. Hash: %v", util.RemoveZeroFloat(res.Amount), jettonData.Name, resp.Hash)
+	if isInsurance {
+		opType = models.OP_CLAIM_INSURANCE
+		desc = fmt.Sprintf("\n-Получение страховки.\n-Сумма: %v %v.\n-Hash: %v", util.RemoveZeroFloat(res.Amount), jettonData.Name, resp.Hash)
+	}
+	if err != nil {
+		resp.Hash = ""
+		resp.PayoutDeferred = true
+		desc = fmt.Sprintf("\n-Получение страховки.\n-Сумма %v %v не отправлена: %v", util.RemoveZeroFloat(res.Amount), jettonData.Name, err)
+	}
+	if res.Insurance > 0 {
+		assetName := util.InsuranceAssetName(i18n.Default, pool.InsuranceAsset)
+		if res.InsuranceErr != nil {
+			log.Error(res.InsuranceErr)
+			desc += fmt.Sprintf("\n-Компенсация %v %v не отправлена: %v", util.RemoveZeroFloat(res.Insurance), assetName, res.InsuranceErr)
+		} else {
+			resp.InsuranceSent = true
+			resp.InsuranceHash = base64.StdEncoding.EncodeToString(res.InsuranceBoc)
+			desc += fmt.Sprintf("\n-Компенсация: %v %v.\n-Hash: %v", util.RemoveZeroFloat(res.Insurance), assetName, resp.InsuranceHash)
+		}
+	}
+	if _, err := a.opS.Create(userId, opType, desc); err != nil {
+		log.Error(err)
+	}
+
+	return http.StatusOK, "", resp
+}
+
+// auth проверяет initData Mini App и находит пользователя бота по Telegram id
+func (a *WebApp) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initData, ok := strings.CutPrefix(r.Header.Get("Authorization"), "tma ")
+		if !ok || a.botToken == "" {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		tgUser, err := ValidateInitData(initData, a.botToken, webAppAuthMaxAge, time.Now())
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		user, err := a.us.GetByTelegramChatId(uint64(tgUser.Id))
+		if err != nil {
+			writeError(w, http.StatusForbidden, "account is not activated, send /start to the bot")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), webAppUserKey{}, webAppSession{user: user, tgUser: tgUser})
+		next(w, r.WithContext(ctx))
+	}
+}
+
+type webAppSession struct {
+	user   *models.User
+	tgUser *WebAppUser
+}
+
+func webAppUser(r *http.Request) (*models.User, *WebAppUser) {
+	s := r.Context().Value(webAppUserKey{}).(webAppSession)
+	return s.user, s.tgUser
+}
+
+func poolLimitError(err error) string {
+	switch {
+	case errors.Is(err, services.ErrPoolMaxTotalStake):
+		return "pool is full"
+	case errors.Is(err, services.ErrPoolMaxUserStake):
+		return "per-user stake limit exceeded"
+	case errors.Is(err, services.ErrPoolMaxStakers):
+		return "pool has the maximum number of stakers"
+	case errors.Is(err, services.ErrNotWhitelisted):
+		return "pool is available to whitelisted users only"
+	default:
+		return "failed to check pool limits"
+	}
+}
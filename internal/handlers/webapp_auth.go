@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInitDataHash    = errors.New("invalid init data hash")
+	ErrInitDataExpired = errors.New("init data expired")
+	ErrInitDataUser    = errors.New("init data has no user")
+)
+
+// WebAppUser пользователь Telegram из initData Mini App
+type WebAppUser struct {
+	Id           int64  `json:"id"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	LanguageCode string `json:"language_code"`
+}
+
+// ValidateInitData проверяет подпись initData по алгоритму Telegram:
+// secret = HMAC_SHA256("WebAppData", botToken), hash = HMAC_SHA256(secret, data_check_string)
+func ValidateInitData(initData, botToken string, maxAge time.Duration, now time.Time) (*WebAppUser, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return nil, ErrInitDataHash
+	}
+
+	pairs := make([]string, 0, len(values))
+	for k := range values {
+		if k == "hash" {
+			continue
+		}
+		pairs = append(pairs, k+"="+values.Get(k))
+	}
+	sort.Strings(pairs)
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	sign := hmac.New(sha256.New, secret.Sum(nil))
+	sign.Write([]byte(strings.Join(pairs, "\n")))
+
+	expected, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(sign.Sum(nil), expected) {
+		return nil, ErrInitDataHash
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, ErrInitDataExpired
+	}
+	if maxAge > 0 && now.Sub(time.Unix(authDate, 0)) > maxAge {
+		return nil, ErrInitDataExpired
+	}
+
+	var user WebAppUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.Id == 0 {
+		return nil, ErrInitDataUser
+	}
+
+	return &user, nil
+}
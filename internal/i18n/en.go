@@ -0,0 +1,1100 @@
+package i18n
+
+var en = map[string]string{
+	// меню
+	"menu.select_pool":       "📋 Choose pool",
+	"menu.profile":           "🧑‍💼 Profile",
+	"menu.history_operation": "📃 History",
+	"menu.take_awards":       "💵 Rewards",
+	"menu.payments":          "💵 Payouts",
+	"menu.check_insurance":   "💰 Compensation",
+	"menu.my_stakes":         "📈 My stakes",
+	"menu.create_pool":       "➕ Create pool",
+	"menu.my_pools":          "📃 My pools",
+	"menu.learn_more":        "❓ Info",
+	"menu.setting":           "⚙️ Settings",
+	"menu.invite_friend":     "🧑‍💼 Invite a friend",
+
+	// уведомления
+	"notify.stake_created": `{{if .Owner -}}
+✅ New stake in your {{.JettonName}} pool: {{num .Amount}} {{.JettonName}}
+{{- else -}}
+✅ Stake created!
+
+Amount: {{num .Amount}} {{.JettonName}}
+Rate: {{num .Reward}}% per day
+Ends: {{date .EndDate}}
+{{- end}}`,
+	"notify.daily_accrual": `📈 {{num .Profit}} {{.JettonName}} accrued on your stake.
+Stake balance: {{num .Balance}} {{.JettonName}}`,
+	"notify.maturity_soon": `⏳ Your stake of {{num .Amount}} {{.JettonName}} ends on {{date .EndDate}}.
+Current balance: {{num .Balance}} {{.JettonName}}`,
+	"notify.matured": `✅ Your {{.JettonName}} stake has been closed.
+
+ Earned: {{num .Profit}} {{.JettonName}}.
+ Total balance: {{num .Balance}} {{.JettonName}}
+ You can now withdraw your tokens or claim compensation if it is due.`,
+	"notify.insurance": `🛡 The {{.JettonName}} price dropped by {{pct .PriceChange}}% during the stake, more than the {{.Coverage}}% insurance threshold.
+You are entitled to compensation, claim it in the stakes section.`,
+	"notify.price_alert": `{{if .Triggered -}}
+🛡 The {{.JettonName}} price dropped by {{pct .PriceChange}}% from the entry price of your {{num .Amount}} {{.JettonName}} stake, the {{.Coverage}}% insurance threshold is passed.
+If the price does not recover by {{date .EndDate}}, compensation is due when the stake closes.
+{{- else -}}
+⚠️ The {{.JettonName}} price dropped by {{pct .PriceChange}}% from the entry price of your {{num .Amount}} {{.JettonName}} stake. Insurance triggers on a drop of more than {{.Coverage}}%.
+{{- end}}`,
+	"notify.pool_reserve_low": `⚠️ Your {{.JettonName}} pool is running out of reserve! Please top it up!`,
+	"notify.pool_paused": `{{if .Paused -}}
+⚠️ Your {{.JettonName}} pool has been paused by the platform administrator. New stakes are not accepted.
+{{- else -}}
+✅ The platform administrator has resumed the {{.JettonName}} pool.
+{{- if not .Active}} Open the pool when it is ready to accept stakes.{{end}}
+{{- end}}`,
+
+	// start
+	"❌ Реферальный код не был применен. Возможно он не действителен!":                                "❌ The referral code was not applied. It may be invalid!",
+	"Ваш реферальный код не был применен! Нельзя использовать свою же ссылку для получения бонусов!": "Your referral code was not applied! You can't use your own link to get bonuses!",
+	"Ошибка при создании профиля. Введите команду /start, чтобы попробовать снова!":                  "Failed to create your profile. Send /start to try again!",
+	"❌ Ошибка при создании профиля, попробуйте повторить попытку введя команду: /start":              "❌ Failed to create your profile, try again by sending: /start",
+	`
+👋 Добро пожаловать в <b>Nestrah Stake Bot</b>
+
+🚀 <b>Что мы предлагаем:</b>
+• Стейкинг токенов — замораживайте токены и получайте ежедневные вознаграждения
+• Простота и контроль — всё через Telegram и TON-кошелёк.
+
+💡 <b>Как начать:</b>
+• Выберите пул с подходящими условиями.
+• Подключите TON-кошелёк через TON Connect.
+• Стейкайте токены и управляйте своими активами.
+
+🔒 <b>Преимущества:</b>
+• Гибкость — создавайте собственные пулы или присоединяйтесь к существующим.
+• Реферальная система — приглашайте друзей и получайте бонусы в токенах NESTRAH.
+`: `
+👋 Welcome to <b>Nestrah Stake Bot</b>
+
+🚀 <b>What we offer:</b>
+• Token staking — lock your tokens and earn daily rewards
+• Simplicity and control — everything through Telegram and your TON wallet.
+
+💡 <b>How to start:</b>
+• Choose a pool with suitable terms.
+• Connect your TON wallet via TON Connect.
+• Stake tokens and manage your assets.
+
+🔒 <b>Advantages:</b>
+• Flexibility — create your own pools or join existing ones.
+• Referral program — invite friends and earn bonuses in NESTRAH tokens.
+`,
+
+	// настройки
+	`
+<b>%v</b>
+
+Тут вы можете выбрать роль. При выборе роли, клавиатура будет изменена в соответствии с ролью.
+
+В разделе «Уведомления» можно выбрать, о каких событиях бот будет присылать сообщения, в разделе «Язык» - язык бота`: `
+<b>%v</b>
+
+Here you can choose your role. The keyboard will change according to the selected role.
+
+In «Notifications» you can choose which events the bot will message you about, in «Language» - the bot language`,
+	"🌐 Язык":           "🌐 Language",
+	"🌐 Как в Telegram": "🌐 Same as Telegram",
+	"<b>🌐 Язык</b>\n\nВыберите язык бота. Если язык не выбран, он определяется по языку Telegram.": "<b>🌐 Language</b>\n\nChoose the bot language. If no language is selected, it follows your Telegram language.",
+	"✅ Язык бота: %v\n\nВыберите роль, чтобы открыть меню на новом языке.":                         "✅ Bot language: %v\n\nChoose a role to open the menu in the new language.",
+
+	// общие тексты, пулы и уведомления
+	"❌ Профиль не найден. Введите команду /start, чтобы создать профиль!": "❌ Profile not found. Send /start to create a profile!",
+	"❌ Что-то пошло не так. Повторите попытку.":                           "❌ Something went wrong. Please try again.",
+	"Далее ⏩":                         "Next ⏩",
+	"Назад ⏪":                         "Back ⏪",
+	"Закрыть ❌":                       "Close ❌",
+	"Купить %v":                       "Buy %v",
+	"Создание стейка":                 "Stake creation",
+	"Ежедневные начисления":           "Daily accruals",
+	"Скорое окончание стейка":         "Stake ending soon",
+	"Окончание стейка":                "Stake ended",
+	"Срабатывание страховки":          "Insurance triggered",
+	"Падение цены к порогу страховки": "Price drop near insurance threshold",
+	"Низкий резерв пула":              "Low pool reserve",
+	"Приостановка пула":               "Pool paused",
+	"Создание пула":                   "Pool creation",
+	"Пополнение резерва":              "Reserve top-up",
+	"Оплата комиссии за пул":          "Pool commission payment",
+	"Закрытие пула":                   "Pool closing",
+	"Получение страховки":             "Insurance claim",
+	"Получение награды":               "Reward claim",
+	"Открытие пула":                   "Pool opening",
+	"Возврат токенов":                 "Token return",
+	"Снятие резерва":                  "Reserve withdrawal",
+	"Оплата комиссии за стейк":        "Stake commission payment",
+	"Досрочное закрытие стейка":       "Early stake closing",
+	"Удаление пула":                   "Pool deletion",
+	"Пополнение страхового резерва":   "Insurance reserve top-up",
+	"Перевыпуск стейка":               "Stake rollover",
+	"Выплата реферальных наград":      "Referral rewards payout",
+	"Неизвестная команда":             "Unknown operation",
+	"❌ Транзакция «%v» отклонена в кошельке. Средства не списаны":                                                                                                         "❌ Transaction «%v» was rejected in the wallet. No funds were debited",
+	"⏳ Транзакция «%v» подписана и отправлена в сеть. Сообщим, когда она будет выполнена":                                                                                 "⏳ Transaction «%v» is signed and sent to the network. We'll let you know when it is executed",
+	"✅ Транзакция «%v» выполнена в сети. Зачисление произойдет после поступления средств\n\n<a href=\"https://tonviewer.com/transaction/%v\">Открыть в обозревателе</a>":  "✅ Transaction «%v» is executed in the network. It will be credited once the funds arrive\n\n<a href=\"https://tonviewer.com/transaction/%v\">Open in explorer</a>",
+	"❌ Код %v для операции «%v» истек: переводы с ним не поступили вовремя. Если вы уже отправили средства, обратитесь в поддержку":                                       "❌ Code %v for operation «%v» has expired: no transfers with it arrived in time. If you have already sent funds, please contact support",
+	"❌ Транзакция «%v» не была подписана: кошелек не ответил или произошла ошибка. Средства не списаны, повторите попытку":                                                "❌ Transaction «%v» was not signed: the wallet did not respond or an error occurred. No funds were debited, please try again",
+	"❌ Транзакция «%v» не появилась в сети и истекла. Средства не списаны, повторите попытку":                                                                             "❌ Transaction «%v» did not appear in the network and expired. No funds were debited, please try again",
+	"❌ Транзакция «%v» не выполнена в сети. Проверьте баланс кошелька и повторите попытку\n\n<a href=\"https://tonviewer.com/transaction/%v\">Открыть в обозревателе</a>": "❌ Transaction «%v» failed in the network. Check your wallet balance and try again\n\n<a href=\"https://tonviewer.com/transaction/%v\">Open in explorer</a>",
+	"Без названия":                  "Untitled",
+	"%v (%d %v / %v%% / резерв %v)": "%v (%d %v / %v%% / reserve %v)",
+	"✅ Активен":                     "✅ Active",
+	"⏳ Пул не активен":              "⏳ Pool is not active",
+	"До 90% от суммы стейка.":       "Up to 90% of the stake amount.",
+	"До 90%% от стоимости стейка в $ на момент входа. Выплачивается в %v по курсу на момент закрытия стейка.": "Up to 90%% of the stake value in $ at entry. Paid in %v at the rate when the stake closes.",
+	`
+🛡 Страховой резерв (%v):
+ •	Зарезервировано на выплаты: %v %v
+ •	Свободно: %v %v
+`: `
+🛡 Insurance reserve (%v):
+ •	Reserved for payouts: %v %v
+ •	Free: %v %v
+`,
+	"\n<b>🚧 Лимиты:</b>\n": "\n<b>🚧 Limits:</b>\n",
+	"%v%% в день начисляется на застейканую сумму.":                                       "%v%% per day is accrued on the staked amount.",
+	"Ставка в день начисляется на застейканую сумму и фиксируется при открытии стейка:\n": "The daily rate is accrued on the staked amount and fixed when the stake is opened:\n",
+	"%v %v на выбор": "%v %v to choose from",
+	`
+<b> 📦 Описание пула %v: </b>
+
+<b>Статус</b>: %v
+<b>Текущая цена токена:</b> %v$
+
+<b>📈 Доходность: </b>
+%v
+
+<b>⏳Срок холда:</b>
+%v с возможностью досрочного вывода.
+
+<b>🚪 Досрочный выход:</b>
+%v
+%v
+<b>💵 Минимальный размер стейка </b>
+%v %v
+
+<b>🛡️ Страховка:</b>
+Если цена токена упадет более чем на %v%% к моменту окончания стейкинга, вам будет выплачена компенсация
+
+<b>💸 Максимальная компенсация:</b>
+%v
+
+🔒 Резерв пула:
+ •	Заблокировано участниками: %v токенов
+ •	Доступно для новых стейков: %v токенов
+ •  Общий резерв: %v
+%v`: `
+<b> 📦 Pool %v: </b>
+
+<b>Status</b>: %v
+<b>Current token price:</b> %v$
+
+<b>📈 Yield: </b>
+%v
+
+<b>⏳Hold period:</b>
+%v with early withdrawal available.
+
+<b>🚪 Early exit:</b>
+%v
+%v
+<b>💵 Minimum stake </b>
+%v %v
+
+<b>🛡️ Insurance:</b>
+If the token price drops by more than %v%% by the end of staking, you will receive compensation
+
+<b>💸 Maximum compensation:</b>
+%v
+
+🔒 Pool reserve:
+ •	Locked by participants: %v tokens
+ •	Available for new stakes: %v tokens
+ •  Total reserve: %v
+%v`,
+	" •\t%v %v: %v%% в день\n": " •\t%v %v: %v%% per day\n",
+	" от %v %v":                " from %v %v",
+	": %v%% в день":            ": %v%% per day",
+	" (с %v)":                  " (from %v)",
+	", комиссия ":              ", commission ",
+	" •\tВсего в пуле: %v из %v %v\n":                               " •\tTotal in pool: %v of %v %v\n",
+	" •\tНа одного участника: до %v %v\n":                           " •\tPer participant: up to %v %v\n",
+	" •\tУчастников: %v из %v\n":                                    " •\tParticipants: %v of %v\n",
+	" •\tТолько для участников белого списка\n":                     " •\tWhitelisted participants only\n",
+	"%v%% из суммы стейка":                                          "%v%% of the stake amount",
+	"%v%% от стейка в %v":                                           "%v%% of the stake in %v",
+	" из суммы стейка":                                              " from the stake amount",
+	" (удерживается из суммы стейка)":                               " (deducted from the stake amount)",
+	", с учетом скидки %v%% по приглашению":                         ", including a %v%% referral discount",
+	"❌ Пул заполнен: общий лимит %v %v":                             "❌ The pool is full: total limit %v %v",
+	"❌ Превышен лимит на одного участника: %v %v":                   "❌ Per-participant limit exceeded: %v %v",
+	"❌ В пуле уже максимальное число участников: %v":                "❌ The pool already has the maximum number of participants: %v",
+	"❌ Этот пул доступен только участникам белого списка":           "❌ This pool is available to whitelisted participants only",
+	"❌ Условия стейка не совпадают с текущими условиями пула":       "❌ The stake terms do not match the current pool terms",
+	"❌ Комиссия за стейк не поступила":                              "❌ The stake commission has not arrived",
+	"❌ Не удалось проверить лимиты пула. Повторите попытку позже!":  "❌ Failed to check the pool limits. Please try again later!",
+	" •\tДоступен через %v %v после старта стейка\n":                " •\tAvailable %v %v after the stake starts\n",
+	" •\tДоступен в любой момент\n":                                 " •\tAvailable at any time\n",
+	" •\tШтраф: %v%% от выводимой суммы\n":                          " •\tPenalty: %v%% of the withdrawn amount\n",
+	" •\tБез штрафа\n":                                              " •\tNo penalty\n",
+	" •\tНачисленная награда выплачивается пропорционально сроку\n": " •\tAccrued reward is paid pro rata to the time staked\n",
+	" •\tНаграда за стейкинг не выплачивается\n":                    " •\tNo staking reward is paid\n",
+	" •\tМожно вывести часть депозита":                              " •\tPart of the deposit can be withdrawn",
+	" •\tВыводится только весь депозит":                             " •\tOnly the whole deposit can be withdrawn",
+	"<b>Выводимая сумма</b>: %v %v":                                 "<b>Withdrawn amount</b>: %v %v",
+	"\n<b>Штраф</b>: -%v %v":                                        "\n<b>Penalty</b>: -%v %v",
+	"\n<b>Награда</b>: +%v %v":                                      "\n<b>Reward</b>: +%v %v",
+	"\n<b>Сгорает награды</b>: %v %v":                               "\n<b>Forfeited reward</b>: %v %v",
+	"\n- Нет компенсации падения цены":                              "\n- No price drop compensation",
+	"\n<b>💎 К выплате</b>: %v %v":                                   "\n<b>💎 Payout</b>: %v %v",
+	"\n<b>Останется в стейке</b>: %v %v":                            "\n<b>Remaining in stake</b>: %v %v",
+	"токен пула": "pool token",
+	"❌ Не могу обработать данное сообщение! Скорее всего оно мне не доступно!":                                    "❌ I can't process this message! It is most likely not available to me!",
+	"❌ Что-то пошло не так. Попробуйте повторить попытку!":                                                        "❌ Something went wrong. Please try again!",
+	"❌ Произошла ошибка генерации ссылок, для подключения кошелька. Повторите попытку!":                           "❌ Failed to generate wallet connection links. Please try again!",
+	"Выберите кошелек, который хотите подключить: ":                                                               "Choose the wallet you want to connect: ",
+	"❌ Произошла ошибка подключения. Повторите попытку!":                                                          "❌ Connection failed. Please try again!",
+	"❌ Кошелек не подтвердил владение адресом. Повторите подключение!":                                            "❌ The wallet did not prove ownership of the address. Please reconnect!",
+	"❌ Произошла ошибка при подключении, повторите попытку!":                                                      "❌ An error occurred while connecting, please try again!",
+	"❌ Не удалось получить соединение с TonConnect. Повторите попытку позже!":                                     "❌ Failed to get a TonConnect connection. Please try again later!",
+	"❌ Соединение с кошельком через TonConnect истекло или было отключено. Переподключите кошелек и ":             "❌ The TonConnect wallet connection has expired or was disconnected. Reconnect your wallet and ",
+	"❌ Возможно вы отключили TonConnect! Подтвердите подключение снова! А затем нажмите <b>Повторить попытку</b>": "❌ You may have disconnected TonConnect! Confirm the connection again! Then press <b>Try again</b>",
+	"✅ Кошелек привязан. Нажмите 'повторить попытку' и подтвердите транзакцию по резерву в привязанном кошельке":  "✅ Wallet linked. Press 'try again' and confirm the reserve transaction in the linked wallet",
+	"❌ Не хватает резерва пула. Мы отправили владельцу пула уведомление. Попробуйте позже!":                       "❌ The pool reserve is insufficient. We have notified the pool owner. Please try again later!",
+	"Открыть пул": "Open pool",
+	"❌ Не могу обработать эту кнопку!": "❌ I can't process this button!",
+	"%v. Стейков: %v": "%v. Stakes: %v",
+	"Стейк от %v":     "Stake from %v",
+	`
+📜<b> Пользовательское соглашение</b>
+
+Продолжая использование данного бота, вы подтверждаете, что ознакомлены и согласны со следующими условиями:
+• Вы используете сервис добровольно и на свой страх и риск.
+Администраторы бота, разработчики и владельцы проекта не несут ответственности за любые потери средств, включая, но не ограничиваясь: техническими сбоями, ошибками в коде, изменениями рыночной цены токена или действиями третьих лиц.
+• Никакие функции бота не являются финансовой рекомендацией, гарантией дохода или страхованием.
+Все действия — включая стейкинг, вывод средств и использование компенсирующих механизмов — реализуются как есть («as is») и без каких-либо гарантий.
+• Пользователь самостоятельно выбирает токены, с которыми работает в рамках сервиса.
+Администрация бота не проводит проверку, аудит, отбор, оценку надёжности или перспективности токенов, принимаемых пользователями. Ответственность за выбор и последствия использования конкретных токенов лежит исключительно на пользователе.
+• Пользователь самостоятельно оценивает все риски, связанные с участием в проекте.
+В том числе, риски полной или частичной потери вложенных средств.
+• Бот может быть изменён, приостановлен или отключён в любой момент без предварительного уведомления.
+• Используя бота, вы подтверждаете, что обладаете достаточным пониманием принципов работы криптовалют, блокчейна и связанных с ними рисков.
+✅ Нажимая «Согласен», вы подтверждаете принятие всех условий.
+`: `
+📜<b> User agreement</b>
+
+By continuing to use this bot, you confirm that you have read and agree to the following terms:
+• You use the service voluntarily and at your own risk.
+The bot administrators, developers and project owners are not responsible for any loss of funds, including but not limited to: technical failures, code errors, changes in the market price of a token or actions of third parties.
+• No bot feature is financial advice, a guarantee of income or insurance.
+All actions — including staking, withdrawals and compensation mechanisms — are provided «as is» without any guarantees.
+• Users choose the tokens they work with within the service on their own.
+The bot administration does not check, audit, select or assess the reliability or prospects of the tokens accepted by users. Responsibility for the choice and consequences of using specific tokens lies solely with the user.
+• Users assess all risks of participating in the project on their own.
+Including the risk of full or partial loss of invested funds.
+• The bot may be changed, suspended or disabled at any time without prior notice.
+• By using the bot, you confirm that you have a sufficient understanding of cryptocurrencies, blockchain and the related risks.
+✅ By pressing «I agree», you confirm that you accept all the terms.
+`,
+	`✅ Стейк с токеном %v был закрыт.
+
+ Заработано: %v %v.
+ Общий баланс: %v %v
+❌ Автоматически перевыпустить стейк не удалось: %%v.
+ Вы можете вывести токены или получить компенсацию, если она полагается.`: `✅ Your %v stake has been closed.
+
+ Earned: %v %v.
+ Total balance: %v %v
+❌ Automatic rollover failed: %%v.
+ You can withdraw your tokens or claim compensation if it is due.`,
+	"пул закрыт": "the pool is closed",
+	"недостаточно страхового резерва":                 "insufficient insurance reserve",
+	"недостаточно резерва в пуле":                     "insufficient pool reserve",
+	"превышены лимиты пула":                           "pool limits exceeded",
+	"не удалось сохранить новый стейк":                "the new stake could not be saved",
+	"стейк уже выплачен":                              "the stake has already been paid out",
+	"\n Компенсация %v %v отправлена на ваш кошелек.": "\n Compensation of %v %v has been sent to your wallet.",
+	"\n❌ Компенсацию %v %v отправить не удалось. Обратитесь в поддержку, выплата будет произведена вручную.": "\n❌ Failed to send compensation of %v %v. Please contact support, the payout will be made manually.",
+	"\n Компенсация %v %v добавлена к депозиту.": "\n Compensation of %v %v has been added to the deposit.",
+	`🔁 Стейк с токеном %v перевыпущен.
+
+ Заработано: %v %v.%v
+ Новый депозит: %v %v до %v`: `🔁 Your %v stake has been rolled over.
+
+ Earned: %v %v.%v
+ New deposit: %v %v until %v`,
+	"пользователя": "a user",
+	"✅ Реферальная награда %v %v за стейк %v %v (%v, уровень %v).\n\nРеферальный баланс: %v %v. Выплата на кошелек при достижении %v %v": "✅ Referral reward of %v %v for a stake of %v %v (%v, level %v).\n\nReferral balance: %v %v. Paid to your wallet once it reaches %v %v",
+
+	// платежи
+	"❌ Комиссия за стейк не поступила, стейк не создан. Депозит возвращен на ваш кошелек":                    "❌ The stake commission was not received, so the stake was not created. The deposit has been returned to your wallet",
+	"❌ Депозит стейка не поступил, стейк не создан. Комиссия возвращена на ваш кошелек":                      "❌ The stake deposit was not received, so the stake was not created. The commission has been returned to your wallet",
+	"❌ Кошелек не привязан, стейк не создан. Комиссия возвращена на ваш кошелек":                             "❌ No wallet is linked, so the stake was not created. The commission has been returned to your wallet",
+	"❌ Комиссия за стейк не совпадает с условиями пула. Стейк не создан, комиссия возвращена на ваш кошелек": "❌ The stake commission does not match the pool terms. The stake was not created and the commission has been returned to your wallet",
+	"❌ Перевод с комментарием %v меньше ожидаемой суммы и не засчитан. Обратитесь в поддержку":               "❌ The transfer with comment %v is less than the expected amount and was not credited. Please contact support",
+	"✅ Перевод с комментарием %v получен. Ожидаем остальные переводы с этим кодом":                           "✅ The transfer with comment %v has been received. Waiting for the remaining transfers with this code",
+	"повторите стейк. Комиссия возвращена на ваш кошелек":                                                    "try the stake again. The commission has been returned to your wallet",
+	"✅ Комиссия принята. Подтвердите свой стейк в кошельке. %v %v":                                           "✅ Commission accepted. Confirm your stake in the wallet. %v %v",
+	"%v\nСтейк не создан, токены возвращены на ваш кошелек.":                                                 "%v\nThe stake was not created, the tokens have been returned to your wallet.",
+	"✅ Реферальные награды %v %v отправлены на ваш кошелек":                                                  "✅ Referral rewards of %v %v have been sent to your wallet",
+	"✅ Пул был успешно создан! Оплатите комиссию, чтобы активировать его!\n\n":                               "✅ The pool has been created! Pay the commission to activate it!\n\n",
+	"✅ Резерв пополнен. Новый баланс резерва: ":                                                              "✅ Reserve topped up. New reserve balance: ",
+	"✅ Страховой резерв пополнен. Новый баланс страхового резерва: %v %v":                                    "✅ Insurance reserve topped up. New insurance reserve balance: %v %v",
+	"❌ Комиссия должна быть %v.": "❌ The commission must be %v.",
+	"✅ Комиссия принята. Теперь ваш пул активен! Активность вы так же можете менять в настройках пула!": "✅ Commission accepted. Your pool is now active! You can also change its activity in the pool settings!",
+	"✅ Объявление #%v разослано. Доставлено: %v из %v, заблокировали бота: %v, ошибок: %v":              "✅ Announcement #%v has been sent. Delivered: %v of %v, blocked the bot: %v, errors: %v",
+
+	// кнопки и команды
+	"✅ Оплатите комиссию: . %v %v. Транзакция отправлена на привязаный кошелек.\n\n❗Транзакция должна быть подтверждена в течении 10 минут": "✅ Pay the commission: %v %v. The transaction has been sent to your linked wallet.\n\n❗The transaction must be confirmed within 10 minutes",
+	"✅ Подтвердите транзакцию на вашем кошельке! \n\n❗Транзакция должна быть подтверждена в течении 10 минут":                               "✅ Confirm the transaction in your wallet! \n\n❗The transaction must be confirmed within 10 minutes",
+	"Я холдер":    "I'm a holder",
+	"Я девелопер": "I'm a developer",
+	"Вернуться к списку операций ⏮️":  "Back to operations list ⏮️",
+	"📊 Статистика":                    "📊 Statistics",
+	"⏸ Пулы":                          "⏸ Pools",
+	"⚠️ Неудачные выплаты":            "⚠️ Failed payouts",
+	"🔁 Повторить выплату":             "🔁 Retry payout",
+	"✖️ Закрыть без выплаты":          "✖️ Close without payout",
+	"📢 Рассылка":                      "📢 Broadcast",
+	"👥 Все пользователи":              "👥 All users",
+	"🏊 Стейкеры пула":                 "🏊 Pool stakers",
+	"👑 Владельцы пулов":               "👑 Pool owners",
+	"⏳ С невыплаченными наградами":    "⏳ With unpaid rewards",
+	"✅ Отправить сейчас":              "✅ Send now",
+	"🕒 Запланировать":                 "🕒 Schedule",
+	"✖️ Отменить объявление":          "✖️ Cancel announcement",
+	"📋 История объявлений":            "📋 Announcement history",
+	"⏪ Назад":                         "⏪ Back",
+	"👥 Мои рефералы":                  "👥 My referrals",
+	"🔔 Уведомления":                   "🔔 Notifications",
+	"🔒 Стейкнуть токены":              "🔒 Stake tokens",
+	"⏪ Вернуться к списку пулов":      "⏪ Back to pools list",
+	"❌ Удалить пул":                   "❌ Delete pool",
+	"💸 Оплатить комиссию":             "💸 Pay commission",
+	"➕ Добавить резерв":               "➕ Add reserve",
+	"🔓 Пул открыт":                    "🔓 Pool is open",
+	"🔒 Пул закрыт":                    "🔒 Pool is closed",
+	"🏦 Вывести токены":                "🏦 Withdraw tokens",
+	"🛡 Пополнить страховой резерв":    "🛡 Top up insurance reserve",
+	"🛡 Компенсация в: ":               "🛡 Compensation in: ",
+	"⚙️ Условия досрочного выхода":    "⚙️ Early exit terms",
+	"Штраф: ":                         "Penalty: ",
+	"Мин. срок до выхода: ":           "Min. term before exit: ",
+	"Частичный вывод: ":               "Partial withdrawal: ",
+	"Награда пропорционально сроку: ": "Reward pro rata: ",
+	"⏪ Вернуться к пулу":              "⏪ Back to pool",
+	"📊 Тарифы":                        "📊 Reward tiers",
+	"➕ Добавить тариф":                "➕ Add tier",
+	"🚧 Лимиты":                        "🚧 Limits",
+	"Макс. в пуле: ":                  "Max in pool: ",
+	"Макс. на участника: ":            "Max per staker: ",
+	"Макс. участников: ":              "Max stakers: ",
+	"Белый список: ":                  "Whitelist: ",
+	"➕ Добавить в белый список":       "➕ Add to whitelist",
+	"без лимита":                      "no limit",
+	"💸 Получить награды":              "💸 Claim rewards",
+	"🏦 Получить компенсацию":          "🏦 Claim compensation",
+	"🔒 Закрыть стейк досрочно":        "🔒 Close stake early",
+	"🔁 Авто-перевыпуск: ":             "🔁 Auto-rollover: ",
+	"✅ Подтвердить вывод":             "✅ Confirm withdrawal",
+	"✂️ Вывести часть":                "✂️ Withdraw part",
+	"➕ Добавить кошелек":              "➕ Add wallet",
+	"👛 Мои кошельки":                  "👛 My wallets",
+	"⏪ К списку кошельков":            "⏪ Back to wallets",
+	"⭐ Сделать основным":              "⭐ Make primary",
+	"✅ Подтвердить смену":             "✅ Confirm change",
+	"↩️ Отменить смену":               "↩️ Cancel change",
+	"✏️ Подпись":                      "✏️ Label",
+	"🗑 Удалить":                       "🗑 Delete",
+	"7 Дней":                          "7 Days",
+	"30 Дней":                         "30 Days",
+	"60 дней":                         "60 days",
+	"Ввести свое":                     "Enter your own",
+	"🔁 Повторить попытку":             "🔁 Try again",
+	"🔁 Реконект кошелька":             "🔁 Reconnect wallet",
+	"🔌 Отключить TonConnect":          "🔌 Disconnect TonConnect",
+	"💸 Перевод с комментарием":        "💸 Transfer with comment",
+	"👛 Открыть в Tonkeeper":           "👛 Open in Tonkeeper",
+	"Вернуться к списку групп ⏮️":     "Back to groups list ⏮️",
+	"Вернуться к списку стейков ⏮️":   "Back to stakes list ⏮️",
+	"Открыть в браузере":              "Open in browser",
+	"Открыть кошелек":                 "Open wallet",
+	"✅ Согласен":                      "✅ I agree",
+	"❌ Аккаунт не активирован! Введите команду /start":                             "❌ Account is not activated! Enter the /start command",
+	"✅ Вы приняли пользовательское соглашение!":                                    "✅ You have accepted the user agreement!",
+	"❌ Что-то пошло не так, начните операцию сначала!":                             "❌ Something went wrong, start the operation again!",
+	"❌ Данный пул не принадлежит вам!":                                             "❌ This pool does not belong to you!",
+	"❌ Сумма должна быть числом! Например: 23":                                     "❌ The amount must be a number! For example: 23",
+	"❌ Сумма должна быть больше нуля!":                                             "❌ The amount must be greater than zero!",
+	"❌ Пул выплачивает компенсацию в своем токене. Сначала выберите USDT или TON!": "❌ The pool pays compensation in its own token. Choose USDT or TON first!",
+	"попробуйте еще раз!":                                                          "try again!",
+	"❌ У вас не привязан кошелек! Это можно сделать в профиле!":                    "❌ You have no linked wallet! You can link one in your profile!",
+	"❌ Произошла ошибка! Повторите позже":                                          "❌ An error occurred! Try again later",
+	"❌ ID пула невалидный":                                                         "❌ Invalid pool ID",
+	"Введите кол-во токенов, которое хотите добавить в резерв:":                    "Enter the number of tokens you want to add to the reserve:",
+	"❌ Пул не найден. Возможно он был удален!":                                     "❌ Pool not found. It may have been deleted!",
+	`Введите сумму в %v, которую хотите добавить в страховой резерв.
+Из него выплачиваются компенсации стейкерам при падении цены токена.
+
+Текущий страховой резерв: %v %v`: `Enter the amount in %v you want to add to the insurance reserve.
+Compensations to stakers are paid from it when the token price drops.
+
+Current insurance reserve: %v %v`,
+	"❌ Раздел доступен только администраторам платформы":                                "❌ This section is available to platform administrators only",
+	"<b>📢 Рассылка</b>\n\nВыберите получателей объявления.":                             "<b>📢 Broadcast</b>\n\nChoose the announcement recipients.",
+	"❌ Введите числовой ID пула":                                                        "❌ Enter a numeric pool ID",
+	"❌ Пул не найден. Введите другой ID":                                                "❌ Pool not found. Enter another ID",
+	"Получатели: активные стейкеры пула %v #%v\n\n%v":                                   "Recipients: active stakers of pool %v #%v\n\n%v",
+	"❌ Сообщение пустое, отправьте текст объявления":                                    "❌ The message is empty, send the announcement text",
+	"❌ Получатели не выбраны, начните рассылку заново":                                  "❌ No recipients selected, start the broadcast again",
+	"❌ Не удалось показать сообщение. Проверьте HTML-разметку и отправьте текст снова.": "❌ Failed to display the message. Check the HTML markup and send the text again.",
+	"❌ Не удалось сохранить объявление":                                                 "❌ Failed to save the announcement",
+	"❌ Неверный формат. Введите время в формате ДД.ММ.ГГГГ ЧЧ:ММ":                       "❌ Invalid format. Enter the time as DD.MM.YYYY HH:MM",
+	"❌ Время рассылки должно быть в будущем":                                            "❌ The broadcast time must be in the future",
+	"❌ Объявление уже отправлено или отменено":                                          "❌ The announcement has already been sent or cancelled",
+	"\n\n🕒 Рассылка запланирована на %v":                                                "\n\n🕒 Broadcast scheduled for %v",
+	"\n\n❌ Повторная отправка не удалась":                                               "\n\n❌ Retry failed",
+	"✅ Возврат %v %v отправлен на ваш кошелек":                                          "✅ A refund of %v %v has been sent to your wallet",
+	"\n\n✅ Выплата отправлена":                                                          "\n\n✅ Payout sent",
+	"❌ Не удалось загрузить статистику":                                                 "❌ Failed to load statistics",
+	"<b>📊 Статистика платформы</b>\n\n":                                                 "<b>📊 Platform statistics</b>\n\n",
+	"Пользователей: <b>%v</b>\n":                                                        "Users: <b>%v</b>\n",
+	"Стейков: <b>%v</b> (активных: %v)\n":                                               "Stakes: <b>%v</b> (active: %v)\n",
+	"Пулов: <b>%v</b> (активных: %v)\n":                                                 "Pools: <b>%v</b> (active: %v)\n",
+	"Неудачных выплат: <b>%v</b>\n\n":                                                   "Failed payouts: <b>%v</b>\n\n",
+	"<b>TVL по токенам:</b>\n":                                                          "<b>TVL by token:</b>\n",
+	"Активных стейков нет":                                                              "No active stakes",
+	" •\t%v: <b>%v</b> (%v стейков)\n":                                                  " •\t%v: <b>%v</b> (%v stakes)\n",
+	"❌ Не удалось загрузить пулы":                                                       "❌ Failed to load pools",
+	"⏸ Приостановить %v #%v":                                                            "⏸ Pause %v #%v",
+	"▶️ Снять паузу %v #%v":                                                             "▶️ Unpause %v #%v",
+	"⏸ Закрыт владельцем %v #%v":                                                        "⏸ Closed by owner %v #%v",
+	"<b>⏸ Пулы</b>\n\nПриостановленный администратором пул не принимает стейки, владелец не может открыть его сам.": "<b>⏸ Pools</b>\n\nA pool paused by the administrator does not accept stakes, and the owner cannot open it.",
+	"❌ Не удалось изменить статус пула":                                                    "❌ Failed to change the pool status",
+	"❌ Не удалось загрузить выплаты":                                                       "❌ Failed to load payouts",
+	"<b>⚠️ Неудачные выплаты</b>\n\nВыберите выплату, чтобы повторить ее или закрыть.":     "<b>⚠️ Failed payouts</b>\n\nChoose a payout to retry or close it.",
+	"<b>⚠️ Неудачные выплаты</b>\n\nНеотправленных выплат нет.":                            "<b>⚠️ Failed payouts</b>\n\nThere are no unsent payouts.",
+	"\n\n✖️ Выплата закрыта без отправки":                                                  "\n\n✖️ Payout closed without sending",
+	"❌ Выплата не найдена":                                                                 "❌ Payout not found",
+	"❌ Выплата уже отправлена или закрыта":                                                 "❌ The payout has already been sent or closed",
+	"<b>📢 Рассылка</b>\n\nВведите ID пула, стейкерам которого нужно отправить объявление.": "<b>📢 Broadcast</b>\n\nEnter the ID of the pool whose stakers should receive the announcement.",
+	`<b>📢 Рассылка</b>
+
+Получатели: %v
+
+%v`: `<b>📢 Broadcast</b>
+
+Recipients: %v
+
+%v`,
+	"\n\n📢 Рассылка запущена, по завершении придет отчет":                                       "\n\n📢 Broadcast started, a report will arrive when it finishes",
+	"\n\n🕒 Введите время рассылки в формате ДД.ММ.ГГГГ ЧЧ:ММ":                                   "\n\n🕒 Enter the broadcast time as DD.MM.YYYY HH:MM",
+	"❌ Не удалось загрузить объявления":                                                         "❌ Failed to load announcements",
+	"<b>📋 История объявлений</b>\n\nВыберите объявление, чтобы посмотреть статистику доставки.": "<b>📋 Announcement history</b>\n\nChoose an announcement to see its delivery statistics.",
+	"<b>📋 История объявлений</b>\n\nОбъявлений пока нет.":                                       "<b>📋 Announcement history</b>\n\nNo announcements yet.",
+	"❌ Объявление не найдено":                                                                   "❌ Announcement not found",
+	"<b>📢 Объявление #%v</b>\n\n":                                                               "<b>📢 Announcement #%v</b>\n\n",
+	"Получатели: %v\n":                "Recipients: %v\n",
+	"Статус: %v\n":                    "Status: %v\n",
+	"Отправка: %v\n":                  "Sending at: %v\n",
+	"Получателей сейчас: <b>%v</b>\n": "Current recipients: <b>%v</b>\n",
+	`Всего: <b>%v</b>
+Доставлено: %v
+Заблокировали бота: %v
+Ошибок: %v
+`: `Total: <b>%v</b>
+Delivered: %v
+Blocked the bot: %v
+Errors: %v
+`,
+	"<b>🛠 Панель администратора</b>\n\nВыберите раздел.":                 "<b>🛠 Admin panel</b>\n\nChoose a section.",
+	"Отправьте текст объявления. Поддерживается HTML-разметка Telegram.": "Send the announcement text. Telegram HTML markup is supported.",
+	"все пользователи":                        "all users",
+	"активные стейкеры пула #%v":              "active stakers of pool #%v",
+	"активные стейкеры пула":                  "active pool stakers",
+	"владельцы пулов":                         "pool owners",
+	"пользователи с невыплаченными наградами": "users with unpaid rewards",
+	"📝 черновик":                              "📝 draft",
+	"🕒 запланировано":                         "🕒 scheduled",
+	"📤 рассылается":                           "📤 sending",
+	"✅ разослано":                             "✅ sent",
+	"✖️ отменено":                             "✖️ cancelled",
+	`<b>Выплата #%v</b>
+
+Пользователь: %v
+Сумма: <b>%v %v</b>
+Назначение: %v
+Попыток: %v
+Создана: %v
+Ошибка: %v`: `<b>Payout #%v</b>
+
+User: %v
+Amount: <b>%v %v</b>
+Purpose: %v
+Attempts: %v
+Created: %v
+Error: %v`,
+	"❌ Не могу обработать данную кнопку":                                                       "❌ Cannot handle this button",
+	"❌ Стейк не найден. Возможно он был удален!":                                               "❌ Stake not found. It may have been deleted!",
+	"❌ Это не ваш стейк!":                                                                      "❌ This is not your stake!",
+	"❌ Стейк уже закрыт!":                                                                      "❌ The stake is already closed!",
+	"❌ Настройка не была сохранена. Повторите попытку позже!":                                  "❌ The setting was not saved. Try again later!",
+	"❌ Что-то пошло не так, повторите попытку!":                                                "❌ Something went wrong, try again!",
+	"❌ Не верный ID пула!":                                                                     "❌ Invalid pool ID!",
+	"❌ В текущем пуле не оплачена комиссия! Сначала оплатите комиссию!":                        "❌ The commission for this pool is not paid! Pay the commission first!",
+	"❌ Пул приостановлен администратором платформы. Открыть его сейчас нельзя!":                "❌ The pool is paused by the platform administrator. It cannot be opened now!",
+	"❌ Статус не был изменен. Пополните резерв, чтобы можно было открыть пул!":                 "❌ The status was not changed. Top up the reserve to open the pool!",
+	"❌ Аккаунт не активирован. Введите команду /start":                                         "❌ Account is not activated. Enter the /start command",
+	"❌ Вы не владелец этого пула!":                                                             "❌ You are not the owner of this pool!",
+	"❌ Статус не был изменен. Повторите попытку позже!":                                        "❌ The status was not changed. Try again later!",
+	"❌ В этом пуле нельзя вывести часть депозита!":                                             "❌ Partial withdrawal is not allowed in this pool!",
+	"Введите сколько %v хотите вывести (в стейке должно остаться не меньше %v):":               "Enter how much %v you want to withdraw (at least %v must remain in the stake):",
+	"❌ Сумма должна быть положительным числом! Например: 100":                                  "❌ The amount must be a positive number! For example: 100",
+	"❌ Сумма должна быть меньше депозита (%v %v)!":                                             "❌ The amount must be less than the deposit (%v %v)!",
+	"<b>🔒 Досрочное закрытие стейка</b>\n\n":                                                   "<b>🔒 Early stake closing</b>\n\n",
+	"❌ У вас не привязан кошелек!":                                                             "❌ You have no linked wallet!",
+	"❌ Не удалось отправить токены. Повторите попытку позже!":                                  "❌ Failed to send the tokens. Try again later!",
+	"💸 %v %v были отправлены на ваш привязанный кошелек: %v":                                   "💸 %v %v have been sent to your linked wallet: %v",
+	"❌ Стейк не найден! Возможно он был удален!":                                               "❌ Stake not found! It may have been deleted!",
+	"❌ Токены уже получены!":                                                                   "❌ The tokens have already been received!",
+	"❌ Не удалось отправить %v %v. Выплата будет произведена вручную, обратитесь в поддержку!": "❌ Failed to send %v %v. The payout will be made manually, please contact support!",
+	"⚠️ Кошелек для выплат не привязан. Привяжите его в профиле, подтвердив владение через TonConnect, чтобы получить выплаты по стейку": "⚠️ No payout wallet is linked. Link one in your profile and confirm ownership via TonConnect to receive the stake payouts",
+	"❌ Перевод поступил после закрытия транзакции и возвращен на ваш кошелек":                                                            "❌ The transfer arrived after the transaction was closed and has been returned to your wallet",
+	"❌ Депозит стейка изменился, запросите расчет заново!":                                                                               "❌ The stake deposit has changed, please request a new quote!",
+	"❌ Не удалось отправить токены. Выплата будет произведена вручную, обратитесь в поддержку!":                                          "❌ Failed to send the tokens. The payout will be made manually, please contact support!",
+	"❌ Не смог найти нужный пул!":                 "❌ Could not find the pool!",
+	"❌ Досрочный выход будет доступен с %v":       "❌ Early exit will be available from %v",
+	"❌ В стейке должно остаться не меньше %v %v!": "❌ At least %v %v must remain in the stake!",
+	`Отлично! Давайте создадим новый пул
+
+1. Введите <b>адрес вашего токена</b> <b>(Jetton Master Address)</b>:
+`: `Great! Let's create a new pool
+
+1. Enter <b>your token address</b> <b>(Jetton Master Address)</b>:
+`,
+	"❌ Аккаунт не активирован. Чтобы активировать аккаунт введите команду /start":                 "❌ Account is not activated. To activate it, enter the /start command",
+	"❌ Привяжите кошелек ваш кошелек! Для этого откройте: <b>Профиль</b>":                         "❌ Link your wallet! To do this, open: <b>Profile</b>",
+	"❌ Что-то пошло не так! Повторите команду!":                                                   "❌ Something went wrong! Repeat the command!",
+	"❌ Что-то пошло не так. Повторите попытку":                                                    "❌ Something went wrong. Try again",
+	"🔁 Пул создается! Пожалуйста подождите...":                                                    "🔁 The pool is being created! Please wait...",
+	"❌ Сумма должна быть числом! Например: 1":                                                     "❌ The amount must be a number! For example: 1",
+	"❌ Что-то пошло не так! Повторите операцию!":                                                  "❌ Something went wrong! Repeat the operation!",
+	"❌ Максимальный объем пула не должен привышать %v":                                            "❌ The maximum pool size must not exceed %v",
+	"❌ Минимальный объем пула должен быть больше %v":                                              "❌ The minimum pool size must be greater than %v",
+	"❌ Вы указали минимальный стейк %v. Минимальная сумма пула должна быть %v":                    "❌ You set the minimum stake to %v. The minimum pool amount must be %v",
+	"❌ Укажите минимальный размер стейка в цифрах! Например: 1":                                   "❌ Enter the minimum stake size as a number! For example: 1",
+	"❌ Минимальный стейк не может чем 1":                                                          "❌ The minimum stake cannot be less than 1",
+	"✅ Отлично! Вы указали %v минимальный стейк.\n\nУкажите сумму которая будет зарезервирована:": "✅ Great! You set the minimum stake to %v.\n\nEnter the amount to be reserved:",
+	"❌ Укажите страховое покрытие в цифрах! Например: 1":                                          "❌ Enter the insurance coverage as a number! For example: 1",
+	"❌ Страховое покрытие не может быть меньше чем 1 и не больше 50":                              "❌ Insurance coverage cannot be less than 1 or greater than 50",
+	"✅ Отлично! Вы указали %v%% за страховое покрытие.\n\nУкажите размер минимального стейка:":    "✅ Great! You set %v%% as insurance coverage.\n\nEnter the minimum stake size:",
+	"❌ Укажите число! Например: 1":                                                                "❌ Enter a number! For example: 1",
+	"❌ Доходность не может быть меньше чем 0.1 и больше чем 1!":                                   "❌ The yield cannot be less than 0.1 or greater than 1!",
+	`✅ Отлично! Доходность <b>%v%%</b> указана!
+
+Укажите страховое покрытие в процентах: 
+Сработает, если цена упадет на указанное кол-во процентов.`: `✅ Great! Yield <b>%v%%</b> is set!
+
+Enter the insurance coverage in percent: 
+It triggers if the price drops by the given number of percent.`,
+	"❌ Укажите срок холда в целых цифрах! Например: 7":              "❌ Enter the holding period in whole numbers! For example: 7",
+	"❌ Срок холда не может быть меньше чем 7 и не больше 356 дней!": "❌ The holding period cannot be less than 7 or more than 356 days!",
+	"❌ Что-то пошло не так. Повторите операцию сначала!":            "❌ Something went wrong. Start the operation again!",
+	`✅ Отлично. Вы выбрали <b>%v %v</b>.
+
+ Укажите <b>доходность для участников</b> (%% в день). Например: 0.5 или 3.
+`: `✅ Great. You chose <b>%v %v</b>.
+
+ Enter the <b>yield for stakers</b> (%% per day). For example: 0.5 or 3.
+`,
+	"❌ Невалидный адрес! Повторите попытку!":                                                                           "❌ Invalid address! Try again!",
+	"❌ Что-то пошло не так. Повторите попытку!":                                                                        "❌ Something went wrong. Try again!",
+	"✅ Отлично! Выбранный токен <b>%v</b>.\n\nВыберите срок холда:":                                                    "✅ Great! Selected token <b>%v</b>.\n\nChoose the holding period:",
+	"❌ Ваш аккаунт не активирован! Введите команду /start":                                                             "❌ Your account is not activated! Enter the /start command",
+	"❌ Кошелек не привязан. Перейдите в профиль и привяжите его":                                                       "❌ No wallet linked. Go to your profile and link one",
+	"Введите свой срок холда в днях: ":                                                                                 "Enter your holding period in days: ",
+	"❌ Неизвестная мне команда!":                                                                                       "❌ Unknown command!",
+	"❌ Вводите только цифры! Например: 1.5":                                                                            "❌ Enter digits only! For example: 1.5",
+	"❌ Сумма стейка должна быть больше чем %v":                                                                         "❌ The stake amount must be greater than %v",
+	"❌ Для срока %v %v нет тарифа на такую сумму. Посмотрите условия пула и увеличьте сумму!":                          "❌ There is no tier for this amount for the %v %v period. Check the pool terms and increase the amount!",
+	"❌ Не удалось рассчитать комиссию за стейк. Повторите попытку позже!":                                              "❌ Failed to calculate the stake commission. Try again later!",
+	"❌ Сумма стейка не покрывает комиссию. Увеличьте сумму!":                                                           "❌ The stake amount does not cover the commission. Increase the amount!",
+	"❌ Кошелек не привязан. Привяжите кошелек в профиле или оплатите стейк переводом с комментарием с любого кошелька": "❌ No wallet linked. Link a wallet in your profile or pay for the stake by a transfer with comment from any wallet",
+	"❌ Соединение с кошельком через TonConnect истекло или было отключено. Переподключите кошелек и повторите стейк или оплатите его переводом с комментарием": "❌ The TonConnect wallet session has expired or was disconnected. Reconnect the wallet and repeat the stake, or pay for it by a transfer with comment",
+	"❌ Не могу выполнить эту команду!":                    "❌ Cannot execute this command!",
+	"❌ Не могу найти данный пул! возможно он был удален!": "❌ Cannot find this pool! It may have been deleted!",
+	"❌ Нельзя стейкнуть в закрытый пул!":                  "❌ You cannot stake in a closed pool!",
+	"❌ Нельзя сделать стейк, так как резерв пуст!":        "❌ You cannot stake because the reserve is empty!",
+	"❌ Такой срок холда в пуле недоступен!":               "❌ This holding period is not available in the pool!",
+	`Срок холда: %v %v.
+%v
+Комиссия за стейк: %v.
+
+Введите кол-во токенов, которое хотите стейкнуть. Минимальный стейк в данном пуле %v %v.`: `Holding period: %v %v.
+%v
+Stake commission: %v.
+
+Enter the number of tokens you want to stake. The minimum stake in this pool is %v %v.`,
+	"✅ Подтвердите в кошельке стейк %v %v.\nКомиссия: %v":                                "✅ Confirm the stake of %v %v in your wallet.\nCommission: %v",
+	"\nКомиссия и депозит отправлены одной транзакцией - ее нужно подтвердить один раз.": "\nThe commission and the deposit are sent in one transaction - you need to confirm it only once.",
+	"\n\n❗Транзакция должна быть подтверждена в течении 10 минут":                        "\n\n❗The transaction must be confirmed within 10 minutes",
+	" — %v%% в день":       " — %v%% per day",
+	"Выберите срок холда:": "Choose the holding period:",
+	"❌ Недостаточно резерва. Максимальная сумма стейка не должна быть больше: %v": "❌ Not enough reserve. The maximum stake amount must not exceed: %v",
+	"❌ Пул не найден! возможно он был удален!":                                    "❌ Pool not found! It may have been deleted!",
+	"❌ Невозможно удалить пул. Резерв должен быть пуст и пул закрыт!":             "❌ Cannot delete the pool. The reserve must be empty and the pool closed!",
+	"❌ Дождитесь пока все заберут свои награды. Пользователей осталось: %d":       "❌ Wait until everyone claims their rewards. Users left: %d",
+	"❌ Ошибка при удалении пула. Повторите попытку!":                              "❌ Error while deleting the pool. Try again!",
+	"✅ Пул успешно удален!": "✅ The pool has been deleted!",
+	"Введите штраф за досрочный выход в % от выводимой суммы (от 0 до 100). Например: 10":   "Enter the early exit penalty in % of the withdrawn amount (from 0 to 100). For example: 10",
+	"Введите через сколько дней после старта стейка разрешен досрочный выход (от 0 до %v):": "Enter how many days after the stake start early exit is allowed (from 0 to %v):",
+	"❌ Настройки не были сохранены. Повторите попытку позже!":                               "❌ Settings were not saved. Try again later!",
+	"❌ Штраф должен быть числом от 0 до 100! Например: 10":                                  "❌ The penalty must be a number from 0 to 100! For example: 10",
+	"❌ Укажите целое число дней от 0 до %v!":                                                "❌ Enter a whole number of days from 0 to %v!",
+	"✅ Условия сохранены!\n": "✅ Terms saved!\n",
+	"❌ Условия досрочного выхода нельзя менять, пока в пуле есть активные стейки!": "❌ Early exit terms cannot be changed while the pool has active stakes!",
+	`<b>⚙️ Условия досрочного выхода из пула %v</b>
+
+%v
+
+Условия можно менять, пока в пуле нет активных стейков.`: `<b>⚙️ Early exit terms of pool %v</b>
+
+%v
+
+The terms can be changed while the pool has no active stakes.`,
+	"вкл ✅":  "on ✅",
+	"выкл ❌": "off ❌",
+	`
+<b>Что такое стейкинг?</b>
+Стейкинг — это процесс блокировки ваших криптоактивов на определенный срок. 
+Взамен вы получаете вознаграждение — это похоже на процент по вкладу в банке, но с криптовалютной доходностью.
+
+<b>Как это работает в NESTRAH?</b>
+Вы выбираете <b>пул</b>
+— Замораживаете токены на срок (<b>7/30/60/365 дней</b>).
+
+<b>Активируется защита</b>
+— Если цена токена упадет ниже установленного порога (например, -10%), вы получите компенсацию.
+
+🔒 <b>Компенсация при падении цены:</b>
+Если токен потеряет в стоимости — мы компенсируем <b>до 90%</b> от вашей замороженной суммы.
+
+<b>Автоматические выплаты:</b>
+Все расчеты выполняют смарт-контракты TON. <b>Никакого ручного вмешательства!</b>
+
+💎 <b>Надежность</b>
+<b>Резервы пула:</b>
+Токены для выплат хранятся на защищенных кошельках. Вы всегда можете проверить резерв.
+
+⚡️ <b>Прозрачность</b>
+Цена токена фиксируется при старте стейкинга и проверяется через <b>независимые ораклы</b> (Ston.fi, TonAPI).
+
+<b>Почему это выгодно?</b>
+<b>Пассивный доход + гарантия компенсации</b> = уверенность даже при падении цены.
+
+💡 <b>Пример:</b>
+Вы застейкали <b>1000 токенов</b> по цене <b>1 TON</b> за штуку.
+Через 30 дней получили <b>300 токенов</b> награды (1% в день).
+
+Цена упала до <b>0.7 TON (-30%)</b>, но вы получили компенсацию <b>300 токенов</b> (30% от стейка).
+<b>Итог: 1300 токенов вместо возможных 700!</b>
+
+🚀 <b>Готовы начать?</b>
+Выберите пул, в нем выберите условия — ваши токены будут работать даже в медвежьем рынке!
+
+➖➖➖➖➖➖➖➖➖
+❓ <b>Остались вопросы?</b>
+Задайте их в поддержке: @NestrahDev
+`: `
+<b>What is staking?</b>
+Staking means locking your crypto assets for a certain period. 
+In return you receive a reward — similar to interest on a bank deposit, but with crypto yield.
+
+<b>How does it work in NESTRAH?</b>
+You choose a <b>pool</b>
+— Lock tokens for a period (<b>7/30/60/365 days</b>).
+
+<b>Protection is activated</b>
+— If the token price falls below the set threshold (for example, -10%), you receive compensation.
+
+🔒 <b>Compensation for price drops:</b>
+If the token loses value — we compensate <b>up to 90%</b> of your locked amount.
+
+<b>Automatic payouts:</b>
+All calculations are performed by TON smart contracts. <b>No manual intervention!</b>
+
+💎 <b>Reliability</b>
+<b>Pool reserves:</b>
+Tokens for payouts are kept in secure wallets. You can always check the reserve.
+
+⚡️ <b>Transparency</b>
+The token price is fixed at the start of staking and checked via <b>independent oracles</b> (Ston.fi, TonAPI).
+
+<b>Why is it profitable?</b>
+<b>Passive income + guaranteed compensation</b> = confidence even when the price falls.
+
+💡 <b>Example:</b>
+You staked <b>1000 tokens</b> at <b>1 TON</b> each.
+After 30 days you received <b>300 tokens</b> of rewards (1% per day).
+
+The price fell to <b>0.7 TON (-30%)</b>, but you received <b>300 tokens</b> of compensation (30% of the stake).
+<b>Result: 1300 tokens instead of a possible 700!</b>
+
+🚀 <b>Ready to start?</b>
+Choose a pool and its terms — your tokens will work even in a bear market!
+
+➖➖➖➖➖➖➖➖➖
+❓ <b>Any questions?</b>
+Ask our support: @NestrahDev
+`,
+	"❌ Ваш профиль не найден. Введите команду: /start и повторите попытку":                "❌ Your profile was not found. Enter the /start command and try again",
+	"❌ Не удалось получить реферальную ссылку. Попробуйте позже!":                         "❌ Failed to get the referral link. Try again later!",
+	"Ваша реферальная ссылка: ":                                                           "Your referral link: ",
+	" •\t%v уровень: <b>%v%%</b>\n":                                                       " •\tLevel %v: <b>%v%%</b>\n",
+	"от суммы каждого закрытого стейка приглашенных":                                      "of every closed stake amount of invited users",
+	"от комиссии за первый стейк каждого приглашенного":                                   "of the commission for the first stake of each invited user",
+	"от комиссии за каждый стейк приглашенных":                                            "of the commission for every stake of invited users",
+	"от суммы первого закрытого стейка каждого приглашенного":                             "of the first closed stake amount of each invited user",
+	"Стейк засчитывается, если он не закрыт досрочно":                                     "A stake counts if it is not closed early",
+	", его сумма не меньше %v %v":                                                         ", its amount is at least %v %v",
+	", срок не меньше %v дн.":                                                             ", its term is at least %v days",
+	", а друг стейкает со своего кошелька, не связанного с вашим или другими аккаунтами.": ", and your friend stakes from their own wallet that is not linked to yours or other accounts.",
+	`Реферальный баланс: <b>%v</b>
+Выплачено: %v
+Рефералов с начислениями: %v
+
+`: `Referral balance: <b>%v</b>
+Paid out: %v
+Referrals with accruals: %v
+
+`,
+	"<b>Список ваших операций</b>\n\n Выберите операцию из списка, чтобы узнать подробности.":                "<b>Your operations</b>\n\n Choose an operation from the list to see the details.",
+	"Выберите пул из списка, чтобы узнать подробную информацию о нем или сделать стейк.\n\nСозданные пулы: ": "Choose a pool from the list to see detailed information about it or to stake.\n\nCreated pools: ",
+	"❌ Не удалось сформировать перевод. Повторите попытку позже!":                                            "❌ Failed to create the transfer. Try again later!",
+	`💸 <b>Оплата стейка переводом</b>
+
+Отправьте с любого кошелька на адрес казны:
+<code>%v</code>
+
+%v
+
+Комментарий к каждому переводу (обязательно!): <code>%v</code>
+
+Код действует до %v (UTC). Стейк будет создан, когда поступят все переводы. Выплаты пойдут на ваш основной кошелек, привязанный в профиле.`: `💸 <b>Paying for a stake by transfer</b>
+
+Send from any wallet to the treasury address:
+<code>%v</code>
+
+%v
+
+Comment for each transfer (required!): <code>%v</code>
+
+The code is valid until %v (UTC). The stake will be created once all transfers arrive. Payouts will go to your default wallet linked in your profile.`,
+	`%v. %v %v
+
+Отсканируйте QR-код кошельком или откройте ссылку:
+<code>%v</code>`: `%v. %v %v
+
+Scan the QR code with your wallet or open the link:
+<code>%v</code>`,
+	`%v. %v %v
+
+Откройте ссылку в кошельке:
+<code>%v</code>`: `%v. %v %v
+
+Open the link in your wallet:
+<code>%v</code>`,
+	"❌ Ваш аккаунт не активирован. Чтобы активировать аккаунт введите /start":                           "❌ Your account is not activated. To activate it, enter /start",
+	"Выберите пул из списка, чтобы узнать подробную информацию о нем или управлять ими.\n\nВаши пулы: ": "Choose a pool from the list to see detailed information about it or manage it.\n\nYour pools: ",
+	"❌ Не удалось загрузить список рефералов. Попробуйте позже!":                                        "❌ Failed to load the referral list. Try again later!",
+	"<b>👥 Мои рефералы</b>\n\n": "<b>👥 My referrals</b>\n\n",
+	`Приглашено: <b>%v</b>
+Реферальный баланс: <b>%v</b>
+Выплачено: <b>%v</b>
+
+`: `Invited: <b>%v</b>
+Referral balance: <b>%v</b>
+Paid out: <b>%v</b>
+
+`,
+	"Вы еще никого не пригласили. Поделитесь реферальной ссылкой с друзьями!": "You haven't invited anyone yet. Share your referral link with friends!",
+	"без имени": "no name",
+	`%v. %v с %v
+ •	Стейков: %v, активных: %v
+ •	Награда за реферала: <b>%v</b>
+
+`: `%v. %v since %v
+ •	Stakes: %v, active: %v
+ •	Reward for referral: <b>%v</b>
+
+`,
+	"<b>🔔 Уведомления</b>\n\nВыберите события, о которых бот будет присылать сообщения. Нажмите на событие, чтобы включить или отключить его.": "<b>🔔 Notifications</b>\n\nChoose the events the bot will send you messages about. Tap an event to turn it on or off.",
+	"❌ Не удалось загрузить настройки уведомлений. Попробуйте позже!":                                                                          "❌ Failed to load notification settings. Try again later!",
+	"❌ Не могу открыть данную операцию!":                "❌ Cannot open this operation!",
+	"❌ Операция не найдена! Возможно она была удалена!": "❌ Operation not found! It may have been deleted!",
+	`
+	<b>%v</b>
+
+	<b>Детали</b>: %v
+	<b>Время выполнения операции</b>: %v
+	`: `
+	<b>%v</b>
+
+	<b>Details</b>: %v
+	<b>Operation time</b>: %v
+	`,
+	"Роль была успешно поменена. Воспользуйтесь меню 👇":                               "The role has been changed. Use the menu 👇",
+	"❌ Что-то пошло не так! Повторите попытку!":                                       "❌ Something went wrong! Try again!",
+	"❌ Ваш аккаунт не активирован, чтобы активировать аккаунт введите команду /start": "❌ Your account is not activated, to activate it enter the /start command",
+	"❌ Что-то пошло не так, попробуйте снова":                                         "❌ Something went wrong, try again",
+	"❌ Не смог найти выбранный пул. Возможно он был удален. Выберите другой":          "❌ Could not find the selected pool. It may have been deleted. Choose another one",
+	`
+
+<b>При досрочном закрытии</b>:
+`: `
+
+<b>On early closing</b>:
+`,
+	"\n\n<b>Досрочное закрытие доступно с</b>: %v": "\n\n<b>Early closing available from</b>: %v",
+	"\n\n🔁 По окончании срока депозит с наградой будет автоматически застейкан повторно, если в пуле хватит резерва.": "\n\n🔁 At the end of the term the deposit with the reward will be staked again automatically if the pool has enough reserve.",
+	"⌛ Активен":  "⌛ Active",
+	"✅ Выплачен": "✅ Paid out",
+	"✅ Завершен": "✅ Completed",
+	`
+	<b> 📦 Стейк с токеном [%v]</b>
+	<b>💰 Ставка:</b> +%v%% за %v %v
+	<b>🛡 Компенсация:</b> При снижении цены %v более чем на %v%%
+
+	<b>💰 Вложено:</b> %v
+	<b>💵 Цена на входе:</b> %v $
+	<b>📉 Текущая цена:</b> %v $ (%v%%)
+	<b>📅Старт:</b> %v
+	<b>📅Финиш:</b> %v
+	<b>⏳ Статус:</b> %v
+	<b>🎁 Доход</b> +%v %v
+	`: `
+	<b> 📦 Stake with token [%v]</b>
+	<b>💰 Rate:</b> +%v%% for %v %v
+	<b>🛡 Compensation:</b> If the price of %v drops by more than %v%%
+
+	<b>💰 Invested:</b> %v
+	<b>💵 Entry price:</b> %v $
+	<b>📉 Current price:</b> %v $ (%v%%)
+	<b>📅Start:</b> %v
+	<b>📅Finish:</b> %v
+	<b>⏳ Status:</b> %v
+	<b>🎁 Income</b> +%v %v
+	`,
+	"\n\n<b>📉 Цена на момент закрытия стейка</b>: %v$ (%v%%)":        "\n\n<b>📉 Price at stake closing</b>: %v$ (%v%%)",
+	"\n<b>💥 Компенсация за падение на %.0f%%</b>: %v %v (курс %v $)": "\n<b>💥 Compensation for a %.0f%% drop</b>: %v %v (rate %v $)",
+	"\n<b>💎 К выплате</b>: %v %v + %v %v":                            "\n<b>💎 To be paid</b>: %v %v + %v %v",
+	"\n<b>💥 Компенсация за падение на %.0f%%</b>: %v %v":             "\n<b>💥 Compensation for a %.0f%% drop</b>: %v %v",
+	"\n<b>💥 Выплата с процентами(%.0f%%)</b>: %v %v":                 "\n<b>💥 Payout with interest(%.0f%%)</b>: %v %v",
+	"\n\n<b>🛡 Текущее покрытие</b>: цена токена сейчас недоступна":   "\n\n<b>🛡 Current coverage</b>: the token price is unavailable right now",
+	`
+
+<b>🛡 Текущее покрытие</b>: порог страховки пройден (падение %.1f%% при пороге %v%%).
+Компенсация по текущей цене: %v %v. Итог считается по цене на момент окончания стейка.`: `
+
+<b>🛡 Current coverage</b>: the insurance threshold has been crossed (drop %.1f%% with threshold %v%%).
+Compensation at the current price: %v %v. The final amount is calculated at the price at the end of the stake.`,
+	"\n\n<b>🛡 Текущее покрытие</b>: ⚠️ цена близка к порогу страховки (падение %.1f%%, порог %v%%)":           "\n\n<b>🛡 Current coverage</b>: ⚠️ the price is close to the insurance threshold (drop %.1f%%, threshold %v%%)",
+	"\n\n<b>🛡 Текущее покрытие</b>: ✅ страховка не срабатывает, до порога %.1f%%":                             "\n\n<b>🛡 Current coverage</b>: ✅ insurance is not triggered, %.1f%% left to the threshold",
+	"❌ Я не могу обработать это сообщение":                                                                    "❌ I cannot handle this message",
+	"Вы открыли меню пользователя 👇. Сменить меню, вы можете в настройках":                                    "You opened the user menu 👇. You can switch the menu in the settings",
+	"❌ Пул не найден! Возможно он был удален!":                                                                "❌ Pool not found! It may have been deleted!",
+	"❌ Комиссия за этот пул уже оплачена!":                                                                    "❌ The commission for this pool is already paid!",
+	"❌ Запись не найдена. Возможно она уже удалена!":                                                          "❌ Entry not found. It may have been deleted already!",
+	"❌ Запись не была удалена. Повторите попытку позже!":                                                      "❌ The entry was not deleted. Try again later!",
+	"Введите максимальную сумму всех стейков в пуле в %v (0 - без лимита):":                                   "Enter the maximum total of all stakes in the pool in %v (0 - no limit):",
+	"Введите максимальную сумму стейков одного участника в %v (0 - без лимита):":                              "Enter the maximum stake total per staker in %v (0 - no limit):",
+	"Введите максимальное число участников пула (0 - без лимита):":                                            "Enter the maximum number of pool stakers (0 - no limit):",
+	"Отправьте адрес кошелька, Telegram ID или @username участника. Можно несколько - каждый с новой строки.": "Send the wallet address, Telegram ID or @username of the staker. You can send several - each on a new line.",
+	"✅ Лимиты сохранены!\n":                                                                      "✅ Limits saved!\n",
+	"❌ Введите число не меньше 0! Например: 100000":                                              "❌ Enter a number not less than 0! For example: 100000",
+	"❌ Введите целое число не меньше 0! Например: 100":                                           "❌ Enter a whole number not less than 0! For example: 100",
+	"❌ Не удалось распознать <code>%v</code>. Укажите адрес кошелька, Telegram ID или @username": "❌ Could not recognize <code>%v</code>. Enter a wallet address, Telegram ID or @username",
+	"✅ Добавлено в белый список: %v\n":                                                           "✅ Added to the whitelist: %v\n",
+	" •\tЛимиты не установлены\n":                                                                " •\tNo limits set\n",
+	"\nНажмите на запись, чтобы удалить ее из белого списка.":                                    "\nTap an entry to remove it from the whitelist.",
+	"❌ Вы не приняли пользовательское соглашение!":                                               "❌ You have not accepted the user agreement!",
+	"Не указан": "Not set",
+	`
+<b>👤 Ваш профиль NESTRAH</b>
+
+<b>Кошелек для выплат</b>: %v
+<b>Дата регистрации</b>: %v
+`: `
+<b>👤 Your NESTRAH profile</b>
+
+<b>Payout wallet</b>: %v
+<b>Registration date</b>: %v
+`,
+	"❌ Тариф не найден. Возможно он уже удален!":                                                                        "❌ Tier not found. It may have been deleted already!",
+	"❌ Тариф не был удален. Повторите попытку позже!":                                                                   "❌ The tier was not deleted. Try again later!",
+	"❌ Неверный формат. Пример: <code>30 1.5 1000 01.12.2026 2@ton</code> (мин. сумма, дата и комиссия не обязательны)": "❌ Invalid format. Example: <code>30 1.5 1000 01.12.2026 2@ton</code> (min. amount, date and commission are optional)",
+	"❌ Тариф не был сохранен. Срок и ставка должны быть больше нуля!":                                                   "❌ The tier was not saved. Term and rate must be greater than zero!",
+	"✅ Тариф добавлен!\n": "✅ Tier added!\n",
+	", от %v":             ", from %v",
+	", с %v":              ", starting %v",
+	"❌ Валюту компенсации нельзя менять, пока в пуле есть активные стейки!":                                   "❌ The compensation currency cannot be changed while the pool has active stakes!",
+	"❌ В страховом резерве есть %v %v. Валюту компенсации можно сменить только при пустом страховом резерве!": "❌ The insurance reserve holds %v %v. The compensation currency can only be changed when the insurance reserve is empty!",
+	"❌ Валюта компенсации не была изменена. Повторите попытку позже!":                                         "❌ The compensation currency was not changed. Try again later!",
+	`
+	<b>Привязка кошелька</b>
+
+	Отправьте адрес кошелька. Первый привязанный кошелек становится основным: на него будут отправляться выплаты.
+	`: `
+	<b>Linking a wallet</b>
+
+	Send the wallet address. The first linked wallet becomes primary: payouts will be sent to it.
+	`,
+	"❌ Что-то пошло не так. Выберите повторно операцию.":                                                     "❌ Something went wrong. Choose the operation again.",
+	"❌ Невалидный адрес кошелька! Повторите попытку!":                                                        "❌ Invalid wallet address! Try again!",
+	"❌ Номер кошелька уже привязан к другому аккаунту! Повторите попытку":                                    "❌ This wallet is already linked to another account! Try again",
+	"❌ Этот кошелек уже добавлен в ваш профиль!":                                                             "❌ This wallet is already added to your profile!",
+	"❌ Подключенный кошелек не совпадает с введенным адресом. Подключите кошелек с этим адресом!":            "❌ The connected wallet does not match the entered address. Connect the wallet with this address!",
+	"❌ Ваш аккаунт не найден! Напишите команду /start, чтобы активировать аккаунт, затем повторите попытку!": "❌ Your account was not found! Send the /start command to activate your account, then try again!",
+	"❌ Адрес кошелька уже привязан!":                                                                         "❌ The wallet address is already linked!",
+	"✅ Кошелек %v, был успешно подключен. Имя кошелька: %v":                                                  "✅ Wallet %v has been connected. Wallet name: %v",
+	"\n\nНа этот кошелек будут отправляться выплаты.":                                                        "\n\nPayouts will be sent to this wallet.",
+	"\n\nВыплаты по-прежнему идут на основной кошелек. Сменить его можно в разделе <b>%v</b>.":               "\n\nPayouts still go to the primary wallet. You can change it in the <b>%v</b> section.",
+	"Список стейков по токену %v":                                                                            "Stakes with token %v",
+	"❌ Ваш аккаунт не активировать. Введите команду /start":                                                  "❌ Your account is not activated. Enter the /start command",
+	"Список стейков в которых вы можете получить выплату:":                                                   "Stakes for which you can receive a payout:",
+	"Список стейков в которых вы можете получить награду:":                                                   "Stakes for which you can receive a reward:",
+	"Список ваших стейков. Выберите стейк из списка, чтобы посмотреть информацию: ":                          "Your stakes. Choose a stake from the list to see the information: ",
+	"❌ Стейк не найден. Возможно он был удален.":                                                             "❌ Stake not found. It may have been deleted.",
+	"❌ Дождитесь закрытия стейка!":                                                                           "❌ Wait until the stake is closed!",
+	"❌ Возмещение или вознаграждение уже были выплачены!":                                                    "❌ The compensation or reward has already been paid!",
+	"❌ Пул не был найден.": "❌ The pool was not found.",
+	"❌ У вас не привязан кошелек! Возмещение не возможно!":                                                     "❌ You have no linked wallet! Compensation is not possible!",
+	"❌ Ошибка. Пока что вывод не доступен. Попробуйте позже":                                                   "❌ Error. Withdrawal is not available yet. Try again later",
+	"✅ Вам отправлено %v %v\nHash операции: %v":                                                                "✅ You have been sent %v %v\nOperation hash: %v",
+	"\n\n❌ Компенсацию %v %v отправить не удалось. Обратитесь в поддержку, выплата будет произведена вручную.": "\n\n❌ Failed to send the compensation of %v %v. Contact support, the payout will be made manually.",
+	`
+
+✅ Компенсация: %v %v
+Hash операции: %v`: `
+
+✅ Compensation: %v %v
+Operation hash: %v`,
+	"❌ Что-то пошло не так. Повторите попытку позже!":                    "❌ Something went wrong. Try again later!",
+	"❌ Награда или возмещение уже были получены!":                        "❌ The reward or compensation has already been received!",
+	"❌ Аккаунт не активирован. Введите команду /start!":                  "❌ Account is not activated. Enter the /start command!",
+	"❌ Не возможно получить награды, так как у вас не привязан кошелек!": "❌ Cannot claim rewards because you have no linked wallet!",
+	"❌ На данный момент вывод не возможен. Повторите попытку позже!":     "❌ Withdrawal is not possible at the moment. Try again later!",
+	"💸 Токены были отправлены. Hash: ":                                   "💸 The tokens have been sent. Hash: ",
+	"❌ Не могу обработать данную кнопку! Попробуйте позже!":              "❌ Cannot handle this button! Try again later!",
+	"❌ Аккаунт не активирован. Введите /start":                           "❌ Account is not activated. Enter /start",
+	"❌ Резерв пуст":                    "❌ The reserve is empty",
+	"❌ Вы не создатель пула":           "❌ You are not the pool creator",
+	"❌ Комиссия не оплачена!":          "❌ The commission is not paid!",
+	"❌ Сначала вы должны закрыть пул!": "❌ You must close the pool first!",
+	"❌ Нельзя вывести токены пока есть активные стейки. Вывод будет доступен, когда стейки будут закрыты! Активных стейков: %d. Дата завершения последнего стейка: %v": "❌ Tokens cannot be withdrawn while there are active stakes. Withdrawal will be available when the stakes are closed! Active stakes: %d. Last stake end date: %v",
+	"❌ Недостаточно резерва для выплаты стейкерам. Нужно выплатить %v %v стейкерам":                                                                                    "❌ Not enough reserve for payouts to stakers. %v %v must be paid to stakers",
+	"❌ Произошла ошибка при выводе средств, повторите попытку позже!":                                                                                                  "❌ An error occurred while withdrawing funds, try again later!",
+	"✅ Снятие средст прошло успешно! Снято: %v %v.":                                                                                                                    "✅ Withdrawal completed! Withdrawn: %v %v.",
+	"\n\nСумма может быть меньше, так как с резерва зарезервировано %v %v стейкерам":                                                                                   "\n\nThe amount may be smaller because %v %v of the reserve is set aside for stakers",
+	"\n\n❌ Страховой резерв %v %v вывести не удалось. Повторите попытку позже!":                                                                                        "\n\n❌ Failed to withdraw the insurance reserve of %v %v. Try again later!",
+	"\n\n✅ Выведено из страхового резерва: %v %v.":                                                                                                                     "\n\n✅ Withdrawn from the insurance reserve: %v %v.",
+	"\nЗарезервировано на компенсации стейкерам: %v %v":                                                                                                                "\nSet aside for staker compensations: %v %v",
+	"❌ Кошелек не подключен через TonConnect":                                                                                                                          "❌ The wallet is not connected via TonConnect",
+	"❌ Не удалось отключить кошелек. Повторите попытку позже!":                                                                                                         "❌ Failed to disconnect the wallet. Try again later!",
+	"✅ Кошелек отключен от TonConnect. Привязанные адреса сохранены, для новых операций подключите кошелек заново в профиле":                                           "✅ The wallet has been disconnected from TonConnect. Linked addresses are kept; reconnect the wallet in your profile for new operations",
+	"‼️ Подключайте уже привязанный кошелек! Так как все выводы будут идти на него!":                                                                                   "‼️ Connect the wallet that is already linked! All withdrawals will go to it!",
+	"❌ Повторите попытку! Что-то пошло не так!":                                                                                                                        "❌ Try again! Something went wrong!",
+	"❌ Подключен другой кошелек! Подключите кошелек ":                                                                                                                  "❌ A different wallet is connected! Connect the wallet ",
+	"✅ Кошелек был успешно привязан!":                                                                                                                                  "✅ The wallet has been linked!",
+	"❌ Не удалось отменить смену кошелька. Повторите попытку позже!":                                                                                                   "❌ Failed to cancel the wallet change. Try again later!",
+	"❌ Кошелек не найден. Возможно он был удален!":                                                                                                                     "❌ Wallet not found. It may have been deleted!",
+	"Введите подпись для кошелька (до %v символов), например: Основной, Ledger, Для наград":                                                                            "Enter a label for the wallet (up to %v characters), for example: Main, Ledger, Rewards",
+	"❌ Кошелек не был удален. Повторите попытку позже!":                                                                                                                "❌ The wallet was not deleted. Try again later!",
+	"❌ Основной кошелек удалить нельзя: на него отправляются выплаты. Сначала сделайте основным другой кошелек.":                                                       "❌ The primary wallet cannot be deleted: payouts are sent to it. Make another wallet primary first.",
+	"❌ Подпись должна быть от 1 до %v символов!":                                                                                                                       "❌ The label must be from 1 to %v characters!",
+	"❌ Подпись не сохранена. Повторите попытку позже!":                                                                                                                 "❌ The label was not saved. Try again later!",
+	`Сделать основным кошелек <b>%v</b>?
+
+<code>%v</code>
+
+На него будут отправляться выплаты по всем невыплаченным стейкам.`: `Make wallet <b>%v</b> primary?
+
+<code>%v</code>
+
+Payouts for all unpaid stakes will be sent to it.`,
+	"\n\n⏳ Для безопасности смена вступит в силу через %v. До этого выплаты идут на <code>%v</code>.": "\n\n⏳ For security, the change takes effect in %v. Until then payouts go to <code>%v</code>.",
+	"❌ Не удалось сменить основной кошелек. Повторите попытку позже!":                                 "❌ Failed to change the primary wallet. Try again later!",
+	"✅ Кошелек %v теперь основной. Выплаты будут отправляться на него.":                               "✅ Wallet %v is now primary. Payouts will be sent to it.",
+	"⏳ Кошелек %v станет основным %v. Отменить смену можно в разделе <b>%v</b>.":                      "⏳ Wallet %v will become primary on %v. You can cancel the change in the <b>%v</b> section.",
+	`<b>👛 Мои кошельки</b>
+
+⭐ - основной кошелек, на него отправляются выплаты.
+`: `<b>👛 My wallets</b>
+
+⭐ - primary wallet, payouts are sent to it.
+`,
+	"\n    ⏳ станет основным %v":                "\n    ⏳ becomes primary on %v",
+	"\nКошельки не привязаны.":                  "\nNo wallets linked.",
+	"⭐ Основной - на него отправляются выплаты": "⭐ Primary - payouts are sent to it",
+	"Дополнительный":                            "Secondary",
+	"⏳ Станет основным %v":                      "⏳ Becomes primary on %v",
+	`<b>👛 %v</b>
+
+<b>Адрес</b>: <code>%v</code>
+<b>Приложение</b>: %v
+<b>Статус</b>: %v`: `<b>👛 %v</b>
+
+<b>Address</b>: <code>%v</code>
+<b>App</b>: %v
+<b>Status</b>: %v`,
+	"%v ч.":   "%v h",
+	"%v мин.": "%v min",
+	`<b>🚧 Лимиты пула %v</b>
+
+%v
+Лимиты проверяются при открытии стейка и при подтверждении депозита, уже открытые стейки они не затрагивают.%v`: `<b>🚧 Limits of pool %v</b>
+
+%v
+Limits are checked when a stake is opened and when the deposit is confirmed, they do not affect stakes that are already open.%v`,
+	`Введите тариф в формате:
+<code>срок ставка [мин. сумма] [дата начала] [комиссия]</code>
+
+срок - в днях, ставка - % в день, дата начала - ДД.ММ.ГГГГ.
+комиссия за стейк - <code>сумма@валюта</code> или <code>процент%@валюта</code>, валюта: ton, jetton (токен платформы) или stake (удерживается из стейка).
+Например: <code>30 1.5 1000 01.12.2026 0.5%@stake</code>
+
+Ставка фиксируется при открытии стейка, поэтому новые тарифы не меняют уже открытые стейки.`: `Enter a tier in the format:
+<code>term rate [min. amount] [start date] [commission]</code>
+
+term - in days, rate - % per day, start date - DD.MM.YYYY.
+stake commission - <code>amount@currency</code> or <code>percent%@currency</code>, currency: ton, jetton (platform token) or stake (withheld from the stake).
+For example: <code>30 1.5 1000 01.12.2026 0.5%@stake</code>
+
+The rate is fixed when a stake is opened, so new tiers do not change stakes that are already open.`,
+	`<b>📊 Тарифы пула %v</b>
+
+%v
+Базовые срок и ставка пула действуют, если не подошел ни один тариф. Из подходящих тарифов применяется самый поздний по дате начала, при равной дате - с большей мин. суммой.`: `<b>📊 Tiers of pool %v</b>
+
+%v
+The pool's base term and rate apply if no tier matches. Among matching tiers, the one with the latest start date applies; with equal dates, the one with the larger min. amount.`,
+	`Пригласи друзей и получай награду %v. Награда начисляется и за друзей, которых пригласили они:
+%v
+%v
+
+Награды копятся на реферальном балансе и выплачиваются в %v коинах на привязанный кошелек, когда баланс достигнет %v.
+
+`: `Invite friends and get a reward %v. You also get rewards for friends they invite:
+%v
+%v
+
+Rewards accumulate on your referral balance and are paid out in %v coins to your linked wallet once the balance reaches %v.
+
+`,
+}
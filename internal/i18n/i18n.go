@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"fmt"
+	"maps"
+
+	"golang.org/x/text/language"
+)
+
+// Тексты бота пишутся в коде на русском и служат ключами каталога, перевод ищется по ним.
+// Кнопки меню, которые бот сравнивает с текстом сообщения, и шаблоны уведомлений имеют
+// символьные ключи и тексты на всех языках, включая русский.
+
+const (
+	RU = "ru"
+	EN = "en"
+
+	// Default язык, если Telegram не передал language_code
+	Default = RU
+)
+
+var Languages = []string{RU, EN}
+
+var catalogs = map[string]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+var (
+	supported = []language.Tag{language.English, language.Russian}
+	matcher   = language.NewMatcher(supported)
+)
+
+// T текст на языке lang. Если перевода нет, используется русский текст. При переданных args
+// результат форматируется как fmt.Sprintf
+func T(lang, key string, args ...any) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		if text, ok = catalogs[Default][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Detect язык бота по language_code пользователя Telegram. Языки без перевода получают английский
+func Detect(code string) string {
+	if code == "" {
+		return Default
+	}
+	_, i, conf := matcher.Match(language.Make(code))
+	if conf == language.No {
+		return EN
+	}
+	base, _ := supported[i].Base()
+	return base.String()
+}
+
+// Resolve язык пользователя: выбранный в настройках, иначе определенный по language_code
+func Resolve(override, code string) string {
+	if IsSupported(override) {
+		return override
+	}
+	return Detect(code)
+}
+
+// Messages копия каталога языка lang: ключ - русский текст или ключ кнопки меню
+func Messages(lang string) map[string]string {
+	return maps.Clone(catalogs[lang])
+}
+
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Name название языка на экране настроек
+func Name(lang string) string {
+	switch lang {
+	case RU:
+		return "🇷🇺 Русский"
+	case EN:
+		return "🇬🇧 English"
+	}
+	return lang
+}
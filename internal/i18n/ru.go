@@ -0,0 +1,52 @@
+package i18n
+
+// ru подписи кнопок меню и шаблоны уведомлений с символьными ключами, остальные тексты уже на русском
+var ru = map[string]string{
+	"menu.select_pool":       "📋 Выбрать пул",
+	"menu.profile":           "🧑‍💼 Профиль",
+	"menu.history_operation": "📃 История",
+	"menu.take_awards":       "💵 Награды",
+	"menu.payments":          "💵 Выплаты",
+	"menu.check_insurance":   "💰 Компенсация",
+	"menu.my_stakes":         "📈 Мои стейки",
+	"menu.create_pool":       "➕ Создать пул",
+	"menu.my_pools":          "📃 Мои пулы",
+	"menu.learn_more":        "❓ Инфо",
+	"menu.setting":           "⚙️ Настройки",
+	"menu.invite_friend":     "🧑‍💼 Пригласить друга",
+
+	// шаблоны уведомлений notify.<событие>, данные - models.NotificationData
+	"notify.stake_created": `{{if .Owner -}}
+✅ Новый стейк в вашем пуле {{.JettonName}}: {{num .Amount}} {{.JettonName}}
+{{- else -}}
+✅ Стейк создан!
+
+Сумма: {{num .Amount}} {{.JettonName}}
+Ставка: {{num .Reward}}% в день
+Окончание: {{date .EndDate}}
+{{- end}}`,
+	"notify.daily_accrual": `📈 Начислено {{num .Profit}} {{.JettonName}} по стейку.
+Баланс стейка: {{num .Balance}} {{.JettonName}}`,
+	"notify.maturity_soon": `⏳ Стейк {{num .Amount}} {{.JettonName}} закончится {{date .EndDate}}.
+Текущий баланс: {{num .Balance}} {{.JettonName}}`,
+	"notify.matured": `✅ Стейк с токеном {{.JettonName}} был закрыт.
+
+ Заработано: {{num .Profit}} {{.JettonName}}.
+ Общий баланс: {{num .Balance}} {{.JettonName}}
+ Теперь вы можете вывести токены или получить компенсацию, если она полагается.`,
+	"notify.insurance": `🛡 Цена {{.JettonName}} упала на {{pct .PriceChange}}% за время стейка, больше порога страховки {{.Coverage}}%.
+Вам полагается компенсация, получите ее в разделе стейков.`,
+	"notify.price_alert": `{{if .Triggered -}}
+🛡 Цена {{.JettonName}} упала на {{pct .PriceChange}}% от цены входа в стейк {{num .Amount}} {{.JettonName}}, порог страховки {{.Coverage}}% пройден.
+Если цена не восстановится до {{date .EndDate}}, при закрытии стейка полагается компенсация.
+{{- else -}}
+⚠️ Цена {{.JettonName}} упала на {{pct .PriceChange}}% от цены входа в стейк {{num .Amount}} {{.JettonName}}. Страховка срабатывает при падении более чем на {{.Coverage}}%.
+{{- end}}`,
+	"notify.pool_reserve_low": `⚠️ В вашем пуле с токеном {{.JettonName}} кончается резерв! Пополните его!`,
+	"notify.pool_paused": `{{if .Paused -}}
+⚠️ Ваш пул {{.JettonName}} приостановлен администратором платформы. Новые стейки не принимаются.
+{{- else -}}
+✅ Администратор платформы снял приостановку с пула {{.JettonName}}.
+{{- if not .Active}} Откройте пул, когда он будет готов принимать стейки.{{end}}
+{{- end}}`,
+}
@@ -5,4 +5,31 @@ type TonConnectResult struct {
 	Version    string
 	Addr       string
 	Platform   string
+	Proof      *TonProof
+}
+
+// TonProof подтверждение владения кошельком (ton_proof) из ответа TON Connect
+type TonProof struct {
+	Address   string
+	PublicKey string
+	StateInit []byte
+	Timestamp int64
+	Domain    string
+	Signature []byte
+	Payload   string
+}
+
+// TonConnectWallet кошелек из реестра TON Connect
+type TonConnectWallet struct {
+	AppName      string
+	Name         string
+	Image        string
+	UniversalUrl string
+	BridgeUrl    string
+}
+
+// WalletConnectLink ссылка на подключение конкретного кошелька
+type WalletConnectLink struct {
+	Wallet TonConnectWallet
+	Url    string
 }
@@ -9,3 +9,14 @@ type JettonData struct {
 	Decimals    int
 	Description string
 }
+
+// EarlyExitQuote расчет выплаты при досрочном выходе из стейка
+type EarlyExitQuote struct {
+	Amount    float64 // выводимая часть депозита
+	Penalty   float64 // штраф, удерживаемый с выводимой части
+	Reward    float64 // награда, которая будет выплачена
+	Forfeited float64 // начисленная награда, которая сгорает
+	Payout    float64 // итого к выплате
+	Remaining float64 // остаток депозита в стейке
+	IsPartial bool
+}
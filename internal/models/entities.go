@@ -6,11 +6,13 @@ import (
 )
 
 type User struct {
-	Id                sql.NullInt64 `db:"id" json:"id"`
-	Username          string        `db:"username" json:"username"`
-	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
-	RefererId         sql.NullInt64 `db:"referer_id" json:"referer_id"`
-	IsAcceptAgreement bool          `db:"is_accept_agreement" json:"is_accept_agreement"`
+	Id                sql.NullInt64  `db:"id" json:"id"`
+	Username          string         `db:"username" json:"username"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	RefererId         sql.NullInt64  `db:"referer_id" json:"referer_id"`
+	IsAcceptAgreement bool           `db:"is_accept_agreement" json:"is_accept_agreement"`
+	ReferralCode      sql.NullString `db:"referral_code" json:"referral_code"` // код для реферальной ссылки
+	Role              string         `db:"role" json:"role"`
 }
 
 type Pool struct {
@@ -28,6 +30,60 @@ type Pool struct {
 	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
 	IsActive         bool          `db:"is_active" json:"is_active"`
 	IsCommissionPaid bool          `db:"is_commission_paid" json:"is_commission_paid"`
+	AdminPaused      bool          `db:"admin_paused" json:"admin_paused"` // приостановлен администратором
+	InsuranceAsset   string        `db:"insurance_asset" json:"insurance_asset"`
+	InsuranceReserve float64       `db:"insurance_reserve" json:"insurance_reserve"`
+	EarlyExitPenalty float64       `db:"early_exit_penalty" json:"early_exit_penalty"`
+	EarlyExitMinDays uint          `db:"early_exit_min_days" json:"early_exit_min_days"`
+	EarlyExitPartial bool          `db:"early_exit_partial" json:"early_exit_partial"`
+	EarlyExitProRata bool          `db:"early_exit_pro_rata" json:"early_exit_pro_rata"`
+	MaxTotalStake    float64       `db:"max_total_stake" json:"max_total_stake"` // 0 - без ограничений
+	MaxUserStake     float64       `db:"max_user_stake" json:"max_user_stake"`
+	MaxStakers       uint          `db:"max_stakers" json:"max_stakers"`
+	IsWhitelist      bool          `db:"is_whitelist" json:"is_whitelist"`
+}
+
+// TxIntent транзакция, отправленная пользователю на подпись через TonConnect, и ее судьба в сети
+type TxIntent struct {
+	Id            sql.NullInt64 `db:"id" json:"id"`
+	TelegramId    uint64        `db:"telegram_id" json:"telegram_id"`
+	OperationType uint64        `db:"operation_type" json:"operation_type"`
+	SenderAddr    string        `db:"sender_addr" json:"sender_addr"`
+	Payload       string        `db:"payload" json:"payload"`
+	Boc           string        `db:"boc" json:"-"`
+	MsgHash       string        `db:"msg_hash" json:"msg_hash"`
+	TxHash        string        `db:"tx_hash" json:"tx_hash"`
+	Status        string        `db:"status" json:"status"`
+	Error         string        `db:"error" json:"error"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+
+	// перевод напрямую в казну с кодом в комментарии, без TonConnect
+	Memo           string       `db:"memo" json:"memo,omitempty"`
+	JettonMaster   string       `db:"jetton_master" json:"jetton_master,omitempty"`
+	TreasuryWallet string       `db:"treasury_wallet" json:"treasury_wallet,omitempty"`
+	AmountUnits    string       `db:"amount_units" json:"amount_units,omitempty"`
+	ExpiresAt      sql.NullTime `db:"expires_at" json:"expires_at"`
+
+	// транзакция из нескольких сообщений: число переводов и JSON-массив payload поступивших
+	Parts    int    `db:"parts" json:"parts"`
+	Received string `db:"received" json:"-"`
+}
+
+// PoolWhitelist участник белого списка пула: telegram username/id или адрес кошелька
+type PoolWhitelist struct {
+	Id     sql.NullInt64 `db:"id" json:"id"`
+	PoolId uint64        `db:"pool_id" json:"pool_id"`
+	Kind   string        `db:"kind" json:"kind"`
+	Value  string        `db:"value" json:"value"`
+}
+
+// PoolStakeTotals занятость пула активными стейками
+type PoolStakeTotals struct {
+	Total     float64 `db:"total"`
+	Stakers   int     `db:"stakers"`
+	UserTotal float64 `db:"user_total"`
+	IsStaker  bool    `db:"is_staker"`
 }
 
 type Operation struct {
@@ -55,20 +111,70 @@ type Stake struct {
 	IsInsurancePaid      bool          `db:"is_insurance_paid" json:"is_insurance_paid"`
 	IsRewardPaid         bool          `db:"is_reward_paid" json:"is_reward_paid"`
 	IsCommissionPaid     bool          `db:"is_commission_paid" json:"is_commission_paid"`
+	InsuranceAssetPrice  float64       `db:"insurance_asset_price" json:"insurance_asset_price"`
+	AutoRollover         bool          `db:"auto_rollover" json:"auto_rollover"`
+	RolledFrom           sql.NullInt64 `db:"rolled_from" json:"rolled_from"`
+	Reward               float64       `db:"reward" json:"reward"` // ставка в % в день, действовавшая при открытии
+	Period               uint          `db:"period" json:"period"`
+	DepositAddr          string        `db:"deposit_addr" json:"deposit_addr"`         // кошелек, с которого внесен депозит
+	PayoutAddr           string        `db:"payout_addr" json:"payout_addr"`           // кошелек для выплат по стейку
+	Commission           float64       `db:"commission" json:"commission"`             // комиссия за стейк в валюте CommissionAsset
+	CommissionAsset      string        `db:"commission_asset" json:"commission_asset"` // валюта комиссии
+	PriceAlert           int           `db:"price_alert" json:"price_alert"`           // последний отправленный уровень оповещения о падении цены
+}
+
+// RewardTier тариф пула: ставка для срока Period от суммы MinAmount,
+// действующая с StartsAt (если не задано - всегда)
+type RewardTier struct {
+	Id        sql.NullInt64 `db:"id" json:"id"`
+	PoolId    uint64        `db:"pool_id" json:"pool_id"`
+	Period    uint          `db:"period" json:"period"`
+	MinAmount float64       `db:"min_amount" json:"min_amount"`
+	Reward    float64       `db:"reward" json:"reward"`
+	StartsAt  sql.NullTime  `db:"starts_at" json:"starts_at"`
+
+	// комиссия за стейк по тарифу, пустые значения - политика по умолчанию
+	CommissionAsset string  `db:"commission_asset" json:"commission_asset"`
+	CommissionType  string  `db:"commission_type" json:"commission_type"`
+	CommissionValue float64 `db:"commission_value" json:"commission_value"`
+}
+
+// CommissionPolicy как берется комиссия за стейк
+type CommissionPolicy struct {
+	Asset string  `json:"asset"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+// StakeCommission рассчитанная комиссия за стейк
+type StakeCommission struct {
+	Asset    string  `json:"asset"`
+	Amount   float64 `json:"amount"`   // в валюте Asset
+	Discount float64 `json:"discount"` // скидка в %, примененная к комиссии
 }
 
 type Telegram struct {
-	Id         sql.NullInt64 `db:"id" json:"id"`
-	UserId     uint64        `db:"user_id" json:"user_id"`
-	TelegramId uint64        `db:"telegram_id" json:"telegram_id"`
-	Username   string        `db:"username" json:"username"`
+	Id           sql.NullInt64 `db:"id" json:"id"`
+	UserId       uint64        `db:"user_id" json:"user_id"`
+	TelegramId   uint64        `db:"telegram_id" json:"telegram_id"`
+	Username     string        `db:"username" json:"username"`
+	IsBlocked    bool          `db:"is_blocked" json:"is_blocked"` // пользователь заблокировал бота
+	BlockedAt    sql.NullTime  `db:"blocked_at" json:"blocked_at"`
+	LanguageCode string        `db:"language_code" json:"language_code"` // язык из настроек Telegram
+	Language     string        `db:"language" json:"language"`           // выбран в настройках бота, пусто - по LanguageCode
 }
 
+// WalletTon кошелек пользователя. Выплаты идут на основной (IsDefault) кошелек,
+// DefaultAt - время, с которого кошелек станет основным после подтвержденной смены
 type WalletTon struct {
-	Id     sql.NullInt64 `db:"id" json:"id"`
-	UserId uint64        `db:"user_id" json:"user_id"`
-	Name   string        `db:"name" json:"name"`
-	Addr   string        `db:"addr" json:"addr"`
+	Id        sql.NullInt64 `db:"id" json:"id"`
+	UserId    uint64        `db:"user_id" json:"user_id"`
+	Name      string        `db:"name" json:"name"`
+	Addr      string        `db:"addr" json:"addr"`
+	Label     string        `db:"label" json:"label"`
+	IsDefault bool          `db:"is_default" json:"is_default"`
+	DefaultAt sql.NullTime  `db:"default_at" json:"default_at"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
 }
 
 type Referral struct {
@@ -76,6 +182,100 @@ type Referral struct {
 	ReferrerUserId sql.NullInt64 `db:"referrer_user_id" json:"referrer_user_id"` //прегласивший пользователь
 	ReferralUserId sql.NullInt64 `db:"referral_user_id" json:"referral_user_id"` //приглашенный пользоваель
 	FirstStakeId   sql.NullInt64 `db:"first_stake_id" json:"first_stake_id"`
-	RewardGiven    bool          `db:"reward_given" json:"reward_given"`
+	RewardGiven    bool          `db:"reward_given" json:"reward_given"` // награда выплачена
 	RewardAmount   float64       `db:"reward_amount" json:"reward_amount"`
+	StakeId        sql.NullInt64 `db:"stake_id" json:"stake_id"` // стейк, за который начислена награда
+	Level          int           `db:"level" json:"level"`       // уровень приглашения, 1 - прямой реферал
+	Base           string        `db:"base" json:"base"`
+	BaseAmount     float64       `db:"base_amount" json:"base_amount"` // сумма в токенах платформы, от которой считалась награда
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	PaidAt         sql.NullTime  `db:"paid_at" json:"paid_at"`
+	PayoutHash     string        `db:"payout_hash" json:"payout_hash"`
+	IdemKey        string        `db:"idem_key" json:"idem_key"` // повторное начисление с тем же ключом пропускается
+}
+
+// ReferralInfo приглашенный пользователь, его стейки и награды, начисленные за него пригласившему
+type ReferralInfo struct {
+	UserId       int64     `db:"user_id" json:"user_id"`
+	Username     string    `db:"username" json:"username"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	Stakes       int       `db:"stakes" json:"stakes"`
+	ActiveStakes int       `db:"active_stakes" json:"active_stakes"`
+	Earned       float64   `db:"earned" json:"earned"`
+}
+
+// ReferralStats реферальный баланс и статистика пригласившего
+type ReferralStats struct {
+	Balance  float64 `db:"balance" json:"balance"`   // начислено и еще не выплачено
+	Paid     float64 `db:"paid" json:"paid"`         // выплачено всего
+	Accruals int     `db:"accruals" json:"accruals"` // число начислений
+	Users    int     `db:"users" json:"users"`       // рефералов всех уровней, за которых были начисления
+}
+
+// FailedPayout выплата с кошелька платформы, которую не удалось отправить
+type FailedPayout struct {
+	Id           sql.NullInt64 `db:"id" json:"id"`
+	UserId       uint64        `db:"user_id" json:"user_id"`
+	JettonMaster string        `db:"jetton_master" json:"jetton_master"` // пусто - выплата в TON
+	Amount       float64       `db:"amount" json:"amount"`
+	Reason       string        `db:"reason" json:"reason"`
+	Error        string        `db:"error" json:"error"`
+	Status       string        `db:"status" json:"status"`
+	Attempts     int           `db:"attempts" json:"attempts"`
+	PayoutHash   string        `db:"payout_hash" json:"payout_hash"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+	ResolvedAt   sql.NullTime  `db:"resolved_at" json:"resolved_at"`
+}
+
+// JettonTvl сумма активных стейков в токене
+type JettonTvl struct {
+	JettonName   string  `db:"jetton_name" json:"jetton_name"`
+	JettonMaster string  `db:"jetton_master" json:"jetton_master"`
+	Amount       float64 `db:"amount" json:"amount"`
+	Stakes       int     `db:"stakes" json:"stakes"`
+}
+
+// PlatformStats статистика платформы для администратора
+type PlatformStats struct {
+	Users        int
+	Stakes       int
+	ActiveStakes int
+	Pools        int
+	ActivePools  int
+	FailedPayout int
+	Tvl          []JettonTvl
+}
+
+// Announcement объявление администратора для сегмента пользователей
+type Announcement struct {
+	Id          sql.NullInt64 `db:"id" json:"id"`
+	AuthorId    uint64        `db:"author_id" json:"author_id"` // telegram id администратора
+	Text        string        `db:"text" json:"text"`
+	Segment     string        `db:"segment" json:"segment"`
+	PoolId      sql.NullInt64 `db:"pool_id" json:"pool_id"` // пул для сегмента стейкеров пула
+	Status      string        `db:"status" json:"status"`
+	ScheduledAt sql.NullTime  `db:"scheduled_at" json:"scheduled_at"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	StartedAt   sql.NullTime  `db:"started_at" json:"started_at"`
+	FinishedAt  sql.NullTime  `db:"finished_at" json:"finished_at"`
+	Total       int           `db:"total" json:"total"`
+	Delivered   int           `db:"delivered" json:"delivered"`
+	Blocked     int           `db:"blocked" json:"blocked"`
+	Failed      int           `db:"failed" json:"failed"`
+}
+
+// AnnouncementDelivery доставка объявления одному получателю
+type AnnouncementDelivery struct {
+	AnnouncementId int64        `db:"announcement_id" json:"announcement_id"`
+	TelegramId     uint64       `db:"telegram_id" json:"telegram_id"`
+	Status         string       `db:"status" json:"status"`
+	Error          string       `db:"error" json:"error"`
+	SentAt         sql.NullTime `db:"sent_at" json:"sent_at"`
+}
+
+// NotificationSetting включенное или отключенное пользователем событие уведомлений
+type NotificationSetting struct {
+	UserId  uint64 `db:"user_id" json:"user_id"`
+	Event   string `db:"event" json:"event"`
+	Enabled bool   `db:"enabled" json:"enabled"`
 }
@@ -1,10 +1,29 @@
 package models
 
+import "time"
+
 type NotificationStake struct {
 	Stake *Stake
+	Event string
 	Msg   string
 }
 
+// NotificationData данные для шаблона уведомления о событии
+type NotificationData struct {
+	JettonName  string
+	Amount      float64
+	Balance     float64
+	Profit      float64
+	Reward      float64 // ставка в % в день
+	EndDate     time.Time
+	PriceChange float64 // изменение цены токена в %
+	Coverage    uint    // порог страховки в %
+	Owner       bool    // уведомление владельцу пула
+	Paused      bool
+	Active      bool
+	Triggered   bool // порог страховки пройден
+}
+
 type GroupElements struct {
 	Name  string `db:"name" json:"name"`
 	Count int    `db:"count" json:"count"`
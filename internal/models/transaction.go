@@ -16,6 +16,108 @@ const (
 	OP_PAID_COMMISSION_STAKE = 12
 	OP_EARLY_CLOSOURE        = 13
 	OP_DELETE_POOL           = 14
+	OP_ADD_INSURANCE_RESERVE = 15 //пополнить страховой резерв (USDT/TON)
+	OP_ROLLOVER_STAKE        = 16 //перевыпуск стейка по окончании срока
+	OP_REFERRAL_PAYOUT       = 17 //выплата реферального баланса
+)
+
+const (
+	USER_ROLE_USER  = "user"
+	USER_ROLE_ADMIN = "admin"
+
+	//статус неудачной выплаты
+	FAILED_PAYOUT_FAILED    = "failed"    //ждет повтора
+	FAILED_PAYOUT_RESOLVED  = "resolved"  //отправлена повторно
+	FAILED_PAYOUT_CANCELLED = "cancelled" //закрыта администратором без отправки
+)
+
+const (
+	//получатели объявления
+	ANNOUNCEMENT_SEGMENT_ALL            = "all"            //все пользователи
+	ANNOUNCEMENT_SEGMENT_POOL_STAKERS   = "pool_stakers"   //активные стейкеры пула
+	ANNOUNCEMENT_SEGMENT_POOL_OWNERS    = "pool_owners"    //владельцы пулов
+	ANNOUNCEMENT_SEGMENT_UNPAID_REWARDS = "unpaid_rewards" //закрытые стейки без выплаты
+
+	//статус объявления
+	ANNOUNCEMENT_DRAFT     = "draft"
+	ANNOUNCEMENT_SCHEDULED = "scheduled"
+	ANNOUNCEMENT_SENDING   = "sending"
+	ANNOUNCEMENT_DONE      = "done"
+	ANNOUNCEMENT_CANCELLED = "cancelled"
+
+	//статус доставки получателю
+	DELIVERY_PENDING   = "pending"
+	DELIVERY_DELIVERED = "delivered"
+	DELIVERY_BLOCKED   = "blocked" //пользователь заблокировал бота
+	DELIVERY_FAILED    = "failed"
+)
+
+const (
+	//события уведомлений
+	NOTIFY_STAKE_CREATED = "stake_created"
+	NOTIFY_DAILY_ACCRUAL = "daily_accrual"
+	NOTIFY_MATURITY_SOON = "maturity_soon" //до окончания стейка осталось меньше суток
+	NOTIFY_MATURED       = "matured"
+	NOTIFY_INSURANCE     = "insurance"   //падение цены превысило порог страховки
+	NOTIFY_PRICE_ALERT   = "price_alert" //цена активного стейка близка к порогу страховки или прошла его
+	NOTIFY_POOL_RESERVE  = "pool_reserve_low"
+	NOTIFY_POOL_PAUSED   = "pool_paused"
+
+	//уровень оповещения о падении цены по активному стейку
+	PRICE_ALERT_NONE      = 0
+	PRICE_ALERT_NEAR      = 1
+	PRICE_ALERT_TRIGGERED = 2
+)
+
+const (
+	//валюта выплаты компенсации
+	INSURANCE_ASSET_JETTON = "jetton"
+	INSURANCE_ASSET_USDT   = "usdt"
+	INSURANCE_ASSET_TON    = "ton"
+)
+
+const (
+	//валюта комиссии за стейк
+	COMMISSION_ASSET_JETTON = "jetton" //токен платформы JETTON_CONTRACT_ADMIN_JETTON
+	COMMISSION_ASSET_TON    = "ton"
+	COMMISSION_ASSET_STAKE  = "stake" //удерживается из суммы стейка
+
+	//способ расчета комиссии за стейк
+	COMMISSION_TYPE_FIXED   = "fixed"   //фиксированная сумма в валюте комиссии
+	COMMISSION_TYPE_PERCENT = "percent" //процент от суммы стейка
+)
+
+const (
+	//база расчета реферальной награды
+	REFERRAL_BASE_STAKE      = "stake"      //процент от суммы стейка
+	REFERRAL_BASE_COMMISSION = "commission" //процент от комиссии платформы за стейк
+
+	//как часто начисляется награда за приглашенного
+	REFERRAL_MODE_ONCE  = "once"  //один раз за первый подходящий стейк
+	REFERRAL_MODE_EVERY = "every" //за каждый подходящий стейк
+)
+
+const (
+	//источник транзакции
+	PAYLOAD_SOURCE_WEBAPP = "webapp" //Mini App: комиссия и депозит стейка приходят одной транзакцией
+)
+
+const (
+	//вид записи белого списка пула
+	WHITELIST_TELEGRAM = "telegram"
+	WHITELIST_WALLET   = "wallet"
+)
+
+const (
+	//статус транзакции, отправленной на подпись через TonConnect
+	TX_INTENT_PENDING   = "pending"   //ждет подписи в кошельке
+	TX_INTENT_REJECTED  = "rejected"  //пользователь отклонил транзакцию
+	TX_INTENT_SIGNED    = "signed"    //кошелек вернул подписанный BOC
+	TX_INTENT_BROADCAST = "broadcast" //BOC принят лайт-сервером
+	TX_INTENT_CONFIRMED = "confirmed" //транзакция кошелька выполнена в сети
+	TX_INTENT_FAILED    = "failed"    //ошибка отправки, транзакция не выполнена или истекла
+	TX_INTENT_COMPLETED = "completed" //поступили все переводы транзакции из нескольких сообщений
+	TX_INTENT_PARTIAL   = "partial"   //поступила только часть переводов
 )
 
 type SubmitTransaction struct {
@@ -23,6 +125,15 @@ type SubmitTransaction struct {
 	Amount        float64 `json:"amount"`
 	SenderAddr    string  `json:"sender_addr"`
 	Payload       []byte  `json:"payload"`
+
+	// кошелек казны, на который пришел перевод (raw): сам кошелек для TON или его jetton-кошелек,
+	// и сумма перевода в минимальных единицах
+	TreasuryWallet string `json:"treasury_wallet,omitempty"`
+	Units          string `json:"units,omitempty"`
+
+	// перевод с текстовым комментарием
+	Comment string `json:"comment,omitempty"`
+	TxHash  string `json:"tx_hash,omitempty"`
 }
 
 type Payload struct {
@@ -30,6 +141,9 @@ type Payload struct {
 	JettonMaster  string  `json:"master_jetton"`
 	Amount        float64 `json:"amount"`
 	Payload       string  `json:"payload"`
+	Source        string  `json:"source,omitempty"`    // откуда отправлена транзакция, пусто - бот
+	IntentId      int64   `json:"intent_id,omitempty"` // общий intent сообщений, подписанных одной транзакцией
+	Received      float64 `json:"received,omitempty"`  // сумма, фактически пришедшая в казну. Заполняет бот после сверки перевода
 }
 
 type AddReserve struct {
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrUnknownSegment = errors.New("unknown announcement segment")
+
+type AnnouncementRepository struct {
+	db *sqlx.DB
+}
+
+func NewAnnouncementRepository(db *sqlx.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		db: db,
+	}
+}
+
+// recipientsQuery запрос telegram id получателей сегмента без заблокировавших бота, poolArg — номер параметра с id пула
+func recipientsQuery(segment string, poolArg int) (string, error) {
+	switch segment {
+	case models.ANNOUNCEMENT_SEGMENT_ALL:
+		return "select telegram_id from telegram where not is_blocked", nil
+	case models.ANNOUNCEMENT_SEGMENT_POOL_STAKERS:
+		return fmt.Sprintf(`select distinct t.telegram_id from stake s
+join telegram t on t.user_id = s.user_id
+where s.pool_id = $%d and s.is_active and not t.is_blocked`, poolArg), nil
+	case models.ANNOUNCEMENT_SEGMENT_POOL_OWNERS:
+		return `select distinct t.telegram_id from pool p
+join telegram t on t.user_id = p.owner_id
+where not t.is_blocked`, nil
+	case models.ANNOUNCEMENT_SEGMENT_UNPAID_REWARDS:
+		return `select distinct t.telegram_id from stake s
+join telegram t on t.user_id = s.user_id
+where not s.is_active and not s.is_reward_paid and not s.is_insurance_paid and not t.is_blocked`, nil
+	}
+	return "", ErrUnknownSegment
+}
+
+func (r *AnnouncementRepository) Save(a *models.Announcement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, args, err := r.db.BindNamed(
+		`insert into announcement(author_id, text, segment, pool_id, status, scheduled_at, created_at)
+values (:author_id, :text, :segment, :pool_id, :status, :scheduled_at, :created_at)
+returning id`,
+		a,
+	)
+	if err != nil {
+		log.Error("Error while creating announcement query: ", err)
+		return err
+	}
+
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&a.Id); err != nil {
+		log.Error("Error while saving announcement: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) Update(a *models.Announcement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		`update announcement
+set status=:status, scheduled_at=:scheduled_at, started_at=:started_at, finished_at=:finished_at
+where id=:id`,
+		a,
+	); err != nil {
+		log.Error("Error while updating announcement: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) FindById(id uint64) (*models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var a models.Announcement
+	if err := r.db.GetContext(ctx, &a, "select * from announcement where id = $1", id); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) FindAllLimit(offset, limit int) ([]models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := make([]models.Announcement, 0, limit)
+	if err := r.db.SelectContext(
+		ctx,
+		&res,
+		"select * from announcement order by id desc offset $1 limit $2",
+		offset,
+		limit,
+	); err != nil {
+		log.Error("Error while getting announcements: ", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *AnnouncementRepository) CountAll() int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var res int
+	if err := r.db.GetContext(ctx, &res, "select count(*) from announcement"); err != nil {
+		log.Error("Error while counting announcements: ", err)
+		return 0
+	}
+	return res
+}
+
+// FindDue запланированные объявления, время которых наступило, и прерванные рассылки
+func (r *AnnouncementRepository) FindDue(now time.Time) ([]models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := make([]models.Announcement, 0)
+	if err := r.db.SelectContext(
+		ctx,
+		&res,
+		`select * from announcement
+where status = $1 or (status = $2 and scheduled_at <= $3)
+order by coalesce(scheduled_at, created_at), id`,
+		models.ANNOUNCEMENT_SENDING,
+		models.ANNOUNCEMENT_SCHEDULED,
+		now,
+	); err != nil {
+		log.Error("Error while getting due announcements: ", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *AnnouncementRepository) CountRecipients(segment string, poolId sql.NullInt64) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, err := recipientsQuery(segment, 1)
+	if err != nil {
+		return 0, err
+	}
+	args := make([]any, 0, 1)
+	if segment == models.ANNOUNCEMENT_SEGMENT_POOL_STAKERS {
+		args = append(args, poolId)
+	}
+
+	var res int
+	if err := r.db.GetContext(ctx, &res, "select count(*) from ("+query+") r", args...); err != nil {
+		log.Error("Error while counting announcement recipients: ", err)
+		return 0, err
+	}
+	return res, nil
+}
+
+// CreateDeliveries фиксирует получателей объявления на момент начала рассылки
+func (r *AnnouncementRepository) CreateDeliveries(a *models.Announcement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, err := recipientsQuery(a.Segment, 2)
+	if err != nil {
+		return err
+	}
+	args := []any{a.Id}
+	if a.Segment == models.ANNOUNCEMENT_SEGMENT_POOL_STAKERS {
+		args = append(args, a.PoolId)
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`insert into announcement_delivery(announcement_id, telegram_id)
+select $1, r.telegram_id from (`+query+`) r
+on conflict do nothing`,
+		args...,
+	); err != nil {
+		log.Error("Error while creating announcement deliveries: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) FindPendingDeliveries(announcementId int64, limit int) ([]models.AnnouncementDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := make([]models.AnnouncementDelivery, 0, limit)
+	if err := r.db.SelectContext(
+		ctx,
+		&res,
+		"select * from announcement_delivery where announcement_id = $1 and status = $2 order by telegram_id limit $3",
+		announcementId,
+		models.DELIVERY_PENDING,
+		limit,
+	); err != nil {
+		log.Error("Error while getting announcement deliveries: ", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *AnnouncementRepository) UpdateDelivery(d *models.AnnouncementDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		`update announcement_delivery
+set status=:status, error=:error, sent_at=:sent_at
+where announcement_id=:announcement_id and telegram_id=:telegram_id`,
+		d,
+	); err != nil {
+		log.Error("Error while updating announcement delivery: ", err)
+		return err
+	}
+	return nil
+}
+
+// RefreshCounters пересчитывает статистику доставки объявления
+func (r *AnnouncementRepository) RefreshCounters(a *models.Announcement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.db.QueryRowxContext(
+		ctx,
+		`update announcement a
+set total = c.total, delivered = c.delivered, blocked = c.blocked, failed = c.failed
+from (select count(*)                                  as total,
+             count(*) filter (where status = 'delivered') as delivered,
+             count(*) filter (where status = 'blocked')   as blocked,
+             count(*) filter (where status = 'failed')    as failed
+      from announcement_delivery
+      where announcement_id = $1) c
+where a.id = $1
+returning a.total, a.delivered, a.blocked, a.failed`,
+		a.Id,
+	).Scan(&a.Total, &a.Delivered, &a.Blocked, &a.Failed); err != nil {
+		log.Error("Error while refreshing announcement counters: ", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FailedPayoutRepository struct {
+	db *sqlx.DB
+}
+
+func NewFailedPayoutRepository(db *sqlx.DB) *FailedPayoutRepository {
+	return &FailedPayoutRepository{
+		db: db,
+	}
+}
+
+func (r *FailedPayoutRepository) Save(payout *models.FailedPayout) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, args, err := r.db.BindNamed(
+		`insert into failed_payout(user_id, jetton_master, amount, reason, error, status, attempts, created_at)
+values (:user_id, :jetton_master, :amount, :reason, :error, :status, :attempts, :created_at)
+returning id`,
+		payout,
+	)
+	if err != nil {
+		log.Error("Error while creating failed payout query: ", err)
+		return err
+	}
+
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&payout.Id); err != nil {
+		log.Error("Error while saving failed payout: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *FailedPayoutRepository) Update(payout *models.FailedPayout) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		`update failed_payout
+set error=:error, status=:status, attempts=:attempts, payout_hash=:payout_hash, resolved_at=:resolved_at
+where id=:id`,
+		payout,
+	); err != nil {
+		log.Error("Error while updating failed payout: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *FailedPayoutRepository) FindById(id uint64) (*models.FailedPayout, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var payout models.FailedPayout
+	if err := r.db.GetContext(ctx, &payout, "select * from failed_payout where id = $1", id); err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+func (r *FailedPayoutRepository) FindByStatusLimit(status string, offset, limit int) ([]models.FailedPayout, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	payouts := make([]models.FailedPayout, 0, limit)
+	if err := r.db.SelectContext(
+		ctx,
+		&payouts,
+		"select * from failed_payout where status = $1 order by created_at desc, id desc offset $2 limit $3",
+		status,
+		offset,
+		limit,
+	); err != nil {
+		log.Error("Error while getting failed payouts: ", err)
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (r *FailedPayoutRepository) CountByStatus(status string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var res int
+	if err := r.db.GetContext(ctx, &res, "select count(*) from failed_payout where status = $1", status); err != nil {
+		log.Error("Error while counting failed payouts: ", err)
+		return 0
+	}
+	return res
+}
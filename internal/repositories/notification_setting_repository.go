@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationSettingRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationSettingRepository(db *sqlx.DB) *NotificationSettingRepository {
+	return &NotificationSettingRepository{
+		db: db,
+	}
+}
+
+func (r *NotificationSettingRepository) Save(setting *models.NotificationSetting) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		`insert into notification_setting(user_id, event, enabled)
+values (:user_id, :event, :enabled)
+on conflict (user_id, event) do update set enabled = excluded.enabled`,
+		setting,
+	); err != nil {
+		log.Error("Error while saving notification setting: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationSettingRepository) FindByUserId(userId uint64) ([]models.NotificationSetting, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	settings := make([]models.NotificationSetting, 0)
+	if err := r.db.SelectContext(ctx, &settings, "select * from notification_setting where user_id = $1", userId); err != nil {
+		log.Error("Error while getting notification settings: ", err)
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *NotificationSettingRepository) FindByUserIdAndEvent(userId uint64, event string) (*models.NotificationSetting, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var setting models.NotificationSetting
+	if err := r.db.GetContext(
+		ctx,
+		&setting,
+		"select * from notification_setting where user_id = $1 and event = $2",
+		userId,
+		event,
+	); err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
@@ -30,8 +30,8 @@ func (r *PoolRepository) Save(pool *models.Pool) error {
 
 	query, args, err := tx.BindNamed(
 		`insert into
-pool(owner_id, reserve, jetton_wallet, reward, period, is_active, insurance_coating, is_commission_paid, jetton_master, created_at, jetton_name, min_stake_amount, temp_reserve)
-values (:owner_id, :reserve, :jetton_wallet, :reward, :period, :is_active, :insurance_coating, :is_commission_paid, :jetton_master, :created_at, :jetton_name, :min_stake_amount, :temp_reserve)
+pool(owner_id, reserve, jetton_wallet, reward, period, is_active, insurance_coating, is_commission_paid, jetton_master, created_at, jetton_name, min_stake_amount, temp_reserve, insurance_asset, insurance_reserve, early_exit_penalty, early_exit_min_days, early_exit_partial, early_exit_pro_rata, max_total_stake, max_user_stake, max_stakers, is_whitelist)
+values (:owner_id, :reserve, :jetton_wallet, :reward, :period, :is_active, :insurance_coating, :is_commission_paid, :jetton_master, :created_at, :jetton_name, :min_stake_amount, :temp_reserve, :insurance_asset, :insurance_reserve, :early_exit_penalty, :early_exit_min_days, :early_exit_partial, :early_exit_pro_rata, :max_total_stake, :max_user_stake, :max_stakers, :is_whitelist)
 returning id`,
 		pool,
 	)
@@ -69,7 +69,7 @@ func (r *PoolRepository) Update(pool *models.Pool) error {
 	}
 	if _, err := tx.NamedExecContext(
 		ctx,
-		"update pool set owner_id = :owner_id, reserve = :reserve, jetton_wallet = :jetton_wallet, reward = :reward, period = :period, is_active = :is_active, is_commission_paid = :is_commission_paid, jetton_master = :jetton_master, created_at = :created_at, jetton_name=:jetton_name, min_stake_amount=:min_stake_amount, temp_reserve= :temp_reserve where id = :id",
+		"update pool set owner_id = :owner_id, reserve = :reserve, jetton_wallet = :jetton_wallet, reward = :reward, period = :period, is_active = :is_active, is_commission_paid = :is_commission_paid, admin_paused = :admin_paused, jetton_master = :jetton_master, created_at = :created_at, jetton_name=:jetton_name, min_stake_amount=:min_stake_amount, temp_reserve= :temp_reserve, insurance_asset = :insurance_asset, insurance_reserve = :insurance_reserve, early_exit_penalty = :early_exit_penalty, early_exit_min_days = :early_exit_min_days, early_exit_partial = :early_exit_partial, early_exit_pro_rata = :early_exit_pro_rata, max_total_stake = :max_total_stake, max_user_stake = :max_user_stake, max_stakers = :max_stakers, is_whitelist = :is_whitelist where id = :id",
 		pool); err != nil {
 		log.Error("Error while updating pool: ", err)
 	}
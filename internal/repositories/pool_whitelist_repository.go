@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PoolWhitelistRepository struct {
+	db *sqlx.DB
+}
+
+func NewPoolWhitelistRepository(db *sqlx.DB) *PoolWhitelistRepository {
+	return &PoolWhitelistRepository{
+		db: db,
+	}
+}
+
+func (r *PoolWhitelistRepository) Save(entry *models.PoolWhitelist) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	query, args, err := tx.BindNamed(
+		`insert into pool_whitelist(pool_id, kind, value)
+values (:pool_id, :kind, :value)
+on conflict (pool_id, kind, value) do update set value = excluded.value
+returning id`,
+		entry,
+	)
+	if err != nil {
+		log.Error("Error while creating whitelist query: ", err)
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&entry.Id); err != nil {
+		log.Error("Error while saving whitelist entry: ", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+			return er
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PoolWhitelistRepository) DeleteById(id uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error while beginning transaction: ", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "delete from pool_whitelist where id=$1", id); err != nil {
+		log.Error("Error while deleting whitelist entry: ", err)
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+			return er
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PoolWhitelistRepository) FindById(id uint64) *models.PoolWhitelist {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var entry models.PoolWhitelist
+	if err := r.db.GetContext(ctx, &entry, "select * from pool_whitelist where id=$1", id); err != nil {
+		log.Error("Error while getting whitelist entry: ", err)
+		return nil
+	}
+
+	return &entry
+}
+
+func (r *PoolWhitelistRepository) FindByPoolId(poolId uint64) []models.PoolWhitelist {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var entries []models.PoolWhitelist
+	if err := r.db.SelectContext(
+		ctx,
+		&entries,
+		"select * from pool_whitelist where pool_id=$1 order by kind, value",
+		poolId,
+	); err != nil {
+		log.Error("Error while getting whitelist: ", err)
+		return nil
+	}
+
+	return entries
+}
+
+func (r *PoolWhitelistRepository) Exists(poolId uint64, kind string, values []string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, args, err := sqlx.In(
+		"select count(*) from pool_whitelist where pool_id=? and kind=? and value in (?)",
+		poolId,
+		kind,
+		values,
+	)
+	if err != nil {
+		log.Error("Error while creating whitelist query: ", err)
+		return false
+	}
+
+	var count int
+	if err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).Scan(&count); err != nil {
+		log.Error("Error while checking whitelist: ", err)
+		return false
+	}
+
+	return count > 0
+}
@@ -2,10 +2,13 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 	"tonclient/internal/models"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ReferralRepository struct {
@@ -28,7 +31,11 @@ func (r *ReferralRepository) Save(ref *models.Referral) error {
 		return err
 	}
 	query, args, err := tx.BindNamed(
-		"insert into referral(referrer_user_id, referral_user_id, first_stake_id, reward_given, reward_amount) values (:referrer_user_id, :referral_user_id, :first_stake_id, :reward_given, :reward_amount) returning id",
+		`insert into referral(referrer_user_id, referral_user_id, first_stake_id, reward_given, reward_amount,
+                     stake_id, level, base, base_amount, created_at, paid_at, payout_hash, idem_key)
+values (:referrer_user_id, :referral_user_id, :first_stake_id, :reward_given, :reward_amount,
+        :stake_id, :level, :base, :base_amount, :created_at, :paid_at, :payout_hash, :idem_key)
+returning id`,
 		ref,
 	)
 
@@ -52,3 +59,152 @@ func (r *ReferralRepository) Save(ref *models.Referral) error {
 	}
 	return nil
 }
+
+// SaveAccruals сохраняет начисления за стейк. Начисления с уже сохраненным ключом идемпотентности пропускаются
+func (r *ReferralRepository) SaveAccruals(refs []models.Referral) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	saved := 0
+	for i := range refs {
+		query, args, err := tx.BindNamed(
+			`insert into referral(referrer_user_id, referral_user_id, reward_given, reward_amount,
+                     stake_id, level, base, base_amount, created_at, idem_key)
+values (:referrer_user_id, :referral_user_id, false, :reward_amount,
+        :stake_id, :level, :base, :base_amount, :created_at, :idem_key)
+on conflict (idem_key) do nothing
+returning id`,
+			refs[i],
+		)
+		if err != nil {
+			log.Error("Error creating referral accrual query: ", err)
+			return 0, err
+		}
+		err = tx.QueryRowxContext(ctx, query, args...).Scan(&refs[i].Id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			log.Error("Error saving referral accrual: ", err)
+			return 0, err
+		}
+		saved++
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Error committing referral accruals: ", err)
+		return 0, err
+	}
+	return saved, nil
+}
+
+// Stats баланс и статистика начислений пригласившего
+func (r *ReferralRepository) Stats(referrerId uint64) (*models.ReferralStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var stats models.ReferralStats
+	if err := r.db.GetContext(
+		ctx,
+		&stats,
+		`select coalesce(sum(reward_amount) filter ( where not reward_given ), 0) as balance,
+       coalesce(sum(reward_amount) filter ( where reward_given ), 0)     as paid,
+       count(*)                                                           as accruals,
+       count(distinct referral_user_id)                                   as users
+from referral
+where referrer_user_id = $1`,
+		referrerId,
+	); err != nil {
+		log.Error("Error getting referral stats: ", err)
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// FindDueReferrers пригласившие, невыплаченный баланс которых достиг threshold
+func (r *ReferralRepository) FindDueReferrers(threshold float64) ([]uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var ids []uint64
+	if err := r.db.SelectContext(
+		ctx,
+		&ids,
+		`select referrer_user_id
+from referral
+where not reward_given
+  and referrer_user_id is not null
+group by referrer_user_id
+having sum(reward_amount) >= $1 and sum(reward_amount) > 0
+order by referrer_user_id`,
+		threshold,
+	); err != nil {
+		log.Error("Error finding referrers due for payout: ", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ReserveUnpaid помечает невыплаченные начисления пригласившего выплаченными до отправки,
+// чтобы они не попали в следующую выплату. Возвращает зарезервированные начисления
+func (r *ReferralRepository) ReserveUnpaid(referrerId uint64, now time.Time) ([]models.Referral, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var refs []models.Referral
+	if err := r.db.SelectContext(
+		ctx,
+		&refs,
+		`update referral
+set reward_given = true,
+    paid_at      = $2
+where referrer_user_id = $1
+  and not reward_given
+returning *`,
+		referrerId,
+		now,
+	); err != nil {
+		log.Error("Error reserving referral accruals: ", err)
+		return nil, err
+	}
+	return refs, nil
+}
+
+// ReleaseReserved возвращает начисления в баланс, если выплата не отправлена
+func (r *ReferralRepository) ReleaseReserved(ids []int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		"update referral set reward_given = false, paid_at = null where id = any($1)",
+		pq.Array(ids),
+	); err != nil {
+		log.Error("Error releasing referral accruals: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ReferralRepository) SetPayoutHash(ids []int64, hash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		"update referral set payout_hash = $1 where id = any($2)",
+		hash,
+		pq.Array(ids),
+	); err != nil {
+		log.Error("Error saving referral payout hash: ", err)
+		return err
+	}
+	return nil
+}
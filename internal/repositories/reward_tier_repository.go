@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RewardTierRepository struct {
+	db *sqlx.DB
+}
+
+func NewRewardTierRepository(db *sqlx.DB) *RewardTierRepository {
+	return &RewardTierRepository{
+		db: db,
+	}
+}
+
+func (r *RewardTierRepository) Save(tier *models.RewardTier) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	query, args, err := tx.BindNamed(
+		`insert into
+pool_reward_tier(pool_id, period, min_amount, reward, starts_at, commission_asset, commission_type, commission_value)
+values (:pool_id, :period, :min_amount, :reward, :starts_at, :commission_asset, :commission_type, :commission_value)
+returning id`,
+		tier,
+	)
+	if err != nil {
+		log.Error("Error while creating reward tier query: ", err)
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&tier.Id); err != nil {
+		log.Error("Error while saving reward tier: ", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+			return er
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *RewardTierRepository) DeleteById(id uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error while beginning transaction: ", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "delete from pool_reward_tier where id=$1", id); err != nil {
+		log.Error("Error while deleting reward tier: ", err)
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+			return er
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *RewardTierRepository) FindById(id uint64) *models.RewardTier {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var tier models.RewardTier
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error while beginning transaction: ", err)
+		return nil
+	}
+	if err := tx.GetContext(ctx, &tier, "select * from pool_reward_tier where id=$1", id); err != nil {
+		log.Error("Error while getting reward tier: ", err)
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+		}
+		return nil
+	}
+
+	return &tier
+}
+
+func (r *RewardTierRepository) FindByPoolId(poolId uint64) []models.RewardTier {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var tiers []models.RewardTier
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error while beginning transaction: ", err)
+		return nil
+	}
+	if err := tx.SelectContext(
+		ctx,
+		&tiers,
+		"select * from pool_reward_tier where pool_id=$1 order by period, min_amount, starts_at nulls first",
+		poolId,
+	); err != nil {
+		log.Error("Error while getting reward tiers: ", err)
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Error while committing transaction: ", err)
+		if er := tx.Rollback(); er != nil {
+			log.Error("Failed to rollback transaction: ", er)
+		}
+		return nil
+	}
+
+	return tiers
+}
@@ -2,12 +2,15 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 	"tonclient/internal/models"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var ErrStakePaid = errors.New("stake is already paid")
+
 type StakeRepository struct {
 	db *sqlx.DB
 }
@@ -31,8 +34,8 @@ func (r *StakeRepository) Save(stake *models.Stake) error {
 	query, args, err := tx.BindNamed(
 		`
 insert into
-stake(user_id, pool_id, amount, start_date, is_active, deposit_creation_price, balance, is_insurance_paid, is_reward_paid, jetton_price_closed, is_commission_paid, end_date, close_date, start_pool_deposit) 
-values (:user_id, :pool_id, :amount, :start_date, :is_active, :deposit_creation_price, :balance, :is_insurance_paid, :is_reward_paid, :jetton_price_closed, :is_commission_paid, :end_date, :close_date, :start_pool_deposit)
+stake(user_id, pool_id, amount, start_date, is_active, deposit_creation_price, balance, is_insurance_paid, is_reward_paid, jetton_price_closed, is_commission_paid, end_date, close_date, start_pool_deposit, insurance_asset_price, auto_rollover, rolled_from, reward, period, deposit_addr, payout_addr, commission, commission_asset) 
+values (:user_id, :pool_id, :amount, :start_date, :is_active, :deposit_creation_price, :balance, :is_insurance_paid, :is_reward_paid, :jetton_price_closed, :is_commission_paid, :end_date, :close_date, :start_pool_deposit, :insurance_asset_price, :auto_rollover, :rolled_from, :reward, :period, :deposit_addr, :payout_addr, :commission, :commission_asset)
 returning id`,
 		stake,
 	)
@@ -59,7 +62,57 @@ returning id`,
 	return nil
 }
 
-func (r *StakeRepository) Update(stake *models.Stake) error {
+const poolStakeTotalsQuery = `
+select coalesce(sum(amount), 0)                                as total,
+       count(distinct user_id)                                 as stakers,
+       coalesce(sum(amount) filter ( where user_id = $2 ), 0) as user_total,
+       count(*) filter ( where user_id = $2 ) > 0             as is_staker
+from stake
+where pool_id = $1
+  and is_active`
+
+func (r *StakeRepository) PoolTotals(poolId, userId uint64) (*models.PoolStakeTotals, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var totals models.PoolStakeTotals
+	if err := r.db.GetContext(ctx, &totals, poolStakeTotalsQuery, poolId, userId); err != nil {
+		log.Error("Failed to get pool totals: ", err)
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// SaveChecked сохраняет стейк, если check разрешает его с учетом текущей занятости пула.
+// Строка пула блокируется до конца транзакции, поэтому параллельные депозиты проверяются по очереди.
+func (r *StakeRepository) SaveChecked(stake *models.Stake, check func(totals *models.PoolStakeTotals) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertChecked(ctx, tx, stake, check); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Rollover сохраняет новый стейк и закрытый стейк old, из которого он перевыпущен, в одной транзакции:
+// либо перевыпуск засчитан и old помечен выплаченным, либо не изменилось ничего.
+// Если old уже выплачен, возвращает ErrStakePaid
+func (r *StakeRepository) Rollover(old, stake *models.Stake, check func(totals *models.PoolStakeTotals) error) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -68,10 +121,87 @@ func (r *StakeRepository) Update(stake *models.Stake) error {
 		log.Error("Error starting transaction:", err)
 		return err
 	}
+	defer tx.Rollback()
 
-	if _, err := tx.NamedExecContext(
+	var paid bool
+	if err := tx.GetContext(
 		ctx,
+		&paid,
+		"select is_reward_paid or is_insurance_paid from stake where id = $1 for update",
+		old.Id.Int64,
+	); err != nil {
+		log.Error("Failed to lock stake: ", err)
+		return err
+	}
+	if paid {
+		return ErrStakePaid
+	}
+
+	if err := insertChecked(ctx, tx, stake, check); err != nil {
+		return err
+	}
+	if _, err := tx.NamedExecContext(ctx, updateStakeQuery, old); err != nil {
+		log.Error("Failed to update stake: ", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func insertChecked(ctx context.Context, tx *sqlx.Tx, stake *models.Stake, check func(totals *models.PoolStakeTotals) error) error {
+	if _, err := tx.ExecContext(ctx, "select id from pool where id=$1 for update", stake.PoolId); err != nil {
+		log.Error("Failed to lock pool: ", err)
+		return err
+	}
+
+	var totals models.PoolStakeTotals
+	if err := tx.GetContext(ctx, &totals, poolStakeTotalsQuery, stake.PoolId, stake.UserId); err != nil {
+		log.Error("Failed to get pool totals: ", err)
+		return err
+	}
+
+	if err := check(&totals); err != nil {
+		return err
+	}
+
+	query, args, err := tx.BindNamed(
 		`
+insert into
+stake(user_id, pool_id, amount, start_date, is_active, deposit_creation_price, balance, is_insurance_paid, is_reward_paid, jetton_price_closed, is_commission_paid, end_date, close_date, start_pool_deposit, insurance_asset_price, auto_rollover, rolled_from, reward, period, deposit_addr, payout_addr, commission, commission_asset) 
+values (:user_id, :pool_id, :amount, :start_date, :is_active, :deposit_creation_price, :balance, :is_insurance_paid, :is_reward_paid, :jetton_price_closed, :is_commission_paid, :end_date, :close_date, :start_pool_deposit, :insurance_asset_price, :auto_rollover, :rolled_from, :reward, :period, :deposit_addr, :payout_addr, :commission, :commission_asset)
+returning id`,
+		stake,
+	)
+	if err != nil {
+		log.Error("Failed to create new query: ", err)
+		return err
+	}
+
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&stake.Id); err != nil {
+		log.Error("Failed to save stake: ", err)
+		return err
+	}
+	return nil
+}
+
+// SetPriceAlert сохраняет уровень последнего оповещения о падении цены, не трогая остальные поля стейка
+func (r *StakeRepository) SetPriceAlert(stakeId uint64, level int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, "update stake set price_alert = $2 where id = $1", stakeId, level); err != nil {
+		log.Error("Error while updating stake price alert: ", err)
+		return err
+	}
+	return nil
+}
+
+const updateStakeQuery = `
 update stake 
 set user_id = :user_id,
     pool_id = :pool_id,
@@ -86,10 +216,25 @@ set user_id = :user_id,
     is_commission_paid=:is_commission_paid,
     end_date =:end_date,
     close_date =:close_date,
-    start_pool_deposit =:start_pool_deposit
-where id=:id`,
-		stake,
-	); err != nil {
+    start_pool_deposit =:start_pool_deposit,
+    insurance_asset_price =:insurance_asset_price,
+    auto_rollover =:auto_rollover,
+    reward =:reward,
+    period =:period,
+    payout_addr =:payout_addr
+where id=:id`
+
+func (r *StakeRepository) Update(stake *models.Stake) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Error starting transaction:", err)
+		return err
+	}
+
+	if _, err := tx.NamedExecContext(ctx, updateStakeQuery, stake); err != nil {
 		log.Error("Failed to update stake: ", err)
 		return err
 	}
@@ -258,7 +403,7 @@ func (r *StakeRepository) GetUserStakesLimit(offset, limit int, userId int64) *[
 	if err := tx.SelectContext(
 		ctx,
 		&stakes,
-		"select s.* from stake as s join usr as u on s.user_id = u.id where u.id=$1 offset $2 limit $3",
+		"select s.* from stake as s join usr as u on s.user_id = u.id where u.id=$1 order by s.start_date desc offset $2 limit $3",
 		userId,
 		offset,
 		limit); err != nil {
@@ -375,6 +520,42 @@ func (r *StakeRepository) CountAll() int {
 	return res
 }
 
+func (r *StakeRepository) CountAllByStatus(isActive bool) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var res int
+	if err := r.db.GetContext(ctx, &res, "select count(*) from stake where is_active = $1", isActive); err != nil {
+		log.Error("Failed to count stakes: ", err)
+		return 0
+	}
+
+	return res
+}
+
+// TvlByJetton сумма активных стейков по токенам пулов
+func (r *StakeRepository) TvlByJetton() ([]models.JettonTvl, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := make([]models.JettonTvl, 0)
+	if err := r.db.SelectContext(
+		ctx,
+		&res,
+		`select p.jetton_name, p.jetton_master, coalesce(sum(s.amount), 0) as amount, count(s.id) as stakes
+		 from stake s
+		          join pool p on p.id = s.pool_id
+		 where s.is_active
+		 group by p.jetton_name, p.jetton_master
+		 order by amount desc`,
+	); err != nil {
+		log.Error("Failed to get tvl: ", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (r *StakeRepository) CountUser(userId uint64) int {
 	var res int
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
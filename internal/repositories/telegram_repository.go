@@ -28,7 +28,7 @@ func (r *TelegramRepository) Save(telegram *models.Telegram) error {
 		return err
 	}
 	query, args, err := tx.BindNamed(
-		"insert into telegram(username, telegram_id, user_id) values(:username, :telegram_id, :user_id) returning id",
+		"insert into telegram(username, telegram_id, user_id, language_code) values(:username, :telegram_id, :user_id, :language_code) returning id",
 		telegram,
 	)
 
@@ -137,6 +137,59 @@ func (r *TelegramRepository) FindByTelegramId(telegramId uint64) *models.Telegra
 	return &telegram
 }
 
+// SetBlocked отмечает, что пользователь заблокировал бота или снова доступен
+func (r *TelegramRepository) SetBlocked(telegramId uint64, blocked bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`update telegram
+set is_blocked = $2, blocked_at = case when $2 then now() end
+where telegram_id = $1 and is_blocked != $2`,
+		telegramId,
+		blocked,
+	); err != nil {
+		log.Error("Failed to update telegram blocked status: ", err)
+		return err
+	}
+	return nil
+}
+
+// SetLanguageCode сохраняет language_code, который Telegram передал в последнем обновлении
+func (r *TelegramRepository) SetLanguageCode(telegramId uint64, code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		"update telegram set language_code = $2 where telegram_id = $1 and language_code != $2",
+		telegramId,
+		code,
+	); err != nil {
+		log.Error("Failed to update telegram language code: ", err)
+		return err
+	}
+	return nil
+}
+
+// SetLanguage язык, выбранный пользователем в настройках, пустая строка - по language_code
+func (r *TelegramRepository) SetLanguage(telegramId uint64, lang string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		"update telegram set language = $2 where telegram_id = $1",
+		telegramId,
+		lang,
+	); err != nil {
+		log.Error("Failed to update telegram language: ", err)
+		return err
+	}
+	return nil
+}
+
 func (r *TelegramRepository) FindAll() *[]models.Telegram {
 	var telegrams []models.Telegram
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TxIntentRepository struct {
+	db *sqlx.DB
+}
+
+func NewTxIntentRepository(db *sqlx.DB) *TxIntentRepository {
+	return &TxIntentRepository{
+		db: db,
+	}
+}
+
+func (r *TxIntentRepository) Save(intent *models.TxIntent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query, args, err := r.db.BindNamed(
+		`insert into tx_intent(telegram_id, operation_type, sender_addr, payload, status, created_at, updated_at,
+                      memo, jetton_master, treasury_wallet, amount_units, expires_at, parts, received)
+values (:telegram_id, :operation_type, :sender_addr, :payload, :status, :created_at, :updated_at,
+        :memo, :jetton_master, :treasury_wallet, :amount_units, :expires_at, :parts, :received)
+returning id`,
+		intent,
+	)
+	if err != nil {
+		log.Error("Error while creating tx intent query: ", err)
+		return err
+	}
+
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&intent.Id); err != nil {
+		log.Error("Error while saving tx intent: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TxIntentRepository) Update(intent *models.TxIntent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		`update tx_intent
+set sender_addr=:sender_addr, boc=:boc, msg_hash=:msg_hash, tx_hash=:tx_hash, status=:status, error=:error, updated_at=:updated_at
+where id=:id`,
+		intent,
+	); err != nil {
+		log.Error("Error while updating tx intent: ", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateReceived поступившие переводы транзакции из нескольких сообщений
+func (r *TxIntentRepository) UpdateReceived(intent *models.TxIntent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(
+		ctx,
+		"update tx_intent set received=:received, status=:status, error=:error, updated_at=:updated_at where id=:id",
+		intent,
+	); err != nil {
+		log.Error("Error while updating tx intent received parts: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TxIntentRepository) FindById(id uint64) (*models.TxIntent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intent models.TxIntent
+	if err := r.db.GetContext(ctx, &intent, "select * from tx_intent where id=$1", id); err != nil {
+		log.Error("Error while finding tx intent: ", err)
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (r *TxIntentRepository) FindByStatuses(statuses ...string) ([]models.TxIntent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intents []models.TxIntent
+	if err := r.db.SelectContext(
+		ctx,
+		&intents,
+		"select * from tx_intent where status = any($1) order by id",
+		pq.Array(statuses),
+	); err != nil {
+		log.Error("Error while finding tx intents: ", err)
+		return nil, err
+	}
+	return intents, nil
+}
+
+func (r *TxIntentRepository) FindByMemo(memo string) ([]models.TxIntent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intents []models.TxIntent
+	if err := r.db.SelectContext(ctx, &intents, "select * from tx_intent where memo=$1 order by id", memo); err != nil {
+		log.Error("Error while finding tx intents by memo: ", err)
+		return nil, err
+	}
+	return intents, nil
+}
+
+// FindExpiredMemo неоплаченные переводы с кодом, срок которых истек
+func (r *TxIntentRepository) FindExpiredMemo(now time.Time) ([]models.TxIntent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intents []models.TxIntent
+	if err := r.db.SelectContext(
+		ctx,
+		&intents,
+		"select * from tx_intent where memo <> '' and status=$1 and expires_at < $2 order by id",
+		models.TX_INTENT_PENDING,
+		now,
+	); err != nil {
+		log.Error("Error while finding expired memo intents: ", err)
+		return nil, err
+	}
+	return intents, nil
+}
+
+// FindIncompleteParts незакрытые транзакции из нескольких сообщений, созданные между since и before
+func (r *TxIntentRepository) FindIncompleteParts(since, before time.Time) ([]models.TxIntent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intents []models.TxIntent
+	if err := r.db.SelectContext(
+		ctx,
+		&intents,
+		"select * from tx_intent where parts > 1 and status = any($1) and created_at > $2 and created_at < $3 order by id",
+		pq.Array([]string{models.TX_INTENT_PENDING, models.TX_INTENT_SIGNED, models.TX_INTENT_BROADCAST, models.TX_INTENT_CONFIRMED, models.TX_INTENT_FAILED}),
+		since,
+		before,
+	); err != nil {
+		log.Error("Error while finding incomplete tx intents: ", err)
+		return nil, err
+	}
+	return intents, nil
+}
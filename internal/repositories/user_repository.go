@@ -2,13 +2,17 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 	"tonclient/internal/config"
 	"tonclient/internal/models"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+var ErrReferralCodeTaken = errors.New("referral code already taken")
+
 var log = config.InitLogger()
 
 type UserRepository struct {
@@ -102,6 +106,90 @@ func (u *UserRepository) FindUserReferal(refererId uint64) *[]models.User {
 	return &users
 }
 
+// FindReferralsInfo приглашенные пользователем с активностью по стейкам и наградами за них.
+// referer_id хранит telegram id пригласившего, награды записаны на id пользователя
+func (u *UserRepository) FindReferralsInfo(refererTelegramId, referrerUserId uint64, offset, limit int) ([]models.ReferralInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := make([]models.ReferralInfo, 0, limit)
+	if err := u.db.SelectContext(
+		ctx,
+		&res,
+		`select u.id as user_id,
+		        u.username,
+		        u.created_at,
+		        (select count(*) from stake s where s.user_id = u.id) as stakes,
+		        (select count(*) from stake s where s.user_id = u.id and s.is_active) as active_stakes,
+		        (select coalesce(sum(r.reward_amount), 0)
+		         from referral r
+		         where r.referral_user_id = u.id
+		           and r.referrer_user_id = $2) as earned
+		 from usr u
+		 where u.referer_id = $1
+		 order by u.created_at desc, u.id desc
+		 offset $3 limit $4`,
+		refererTelegramId,
+		referrerUserId,
+		offset,
+		limit,
+	); err != nil {
+		log.Error("Failed find referrals info: ", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *UserRepository) CountUserReferal(refererId uint64) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var res int
+	if err := u.db.GetContext(ctx, &res, "select count(*) from usr where referer_id = $1", refererId); err != nil {
+		log.Error("Failed count referrals: ", err)
+		return 0
+	}
+
+	return res
+}
+
+// SetReferralCode сохраняет код, если у пользователя его еще нет, и возвращает действующий код
+func (u *UserRepository) SetReferralCode(id uint64, code string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var res string
+	err := u.db.QueryRowxContext(
+		ctx,
+		"update usr set referral_code = coalesce(referral_code, $1) where id = $2 returning referral_code",
+		code,
+		id,
+	).Scan(&res)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return "", ErrReferralCodeTaken
+		}
+		log.Error("Failed set referral code: ", err)
+		return "", err
+	}
+
+	return res, nil
+}
+
+func (u *UserRepository) FindByReferralCode(code string) *models.User {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := u.db.GetContext(ctx, &user, "select * from usr where referral_code = $1", code); err != nil {
+		return nil
+	}
+
+	return &user
+}
+
 func (u *UserRepository) FindById(id uint64) *models.User {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -7,6 +7,7 @@ import (
 	"tonclient/internal/models"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type WalletTonRepository struct {
@@ -24,9 +25,9 @@ func (r *WalletTonRepository) Save(ton *models.WalletTon) error {
 	defer cancel()
 
 	query := `
-        INSERT INTO wallet_ton (user_id, name, addr)
-        VALUES (:user_id, :name, :addr)
-        RETURNING id
+        INSERT INTO wallet_ton (user_id, name, addr, label, is_default)
+        VALUES (:user_id, :name, :addr, :label, :is_default)
+        RETURNING id, created_at
     `
 
 	// Используем NamedQuery вместо BindNamed
@@ -44,7 +45,7 @@ func (r *WalletTonRepository) Save(ton *models.WalletTon) error {
 
 	// Сканируем возвращённый ID
 	if rows.Next() {
-		if err := rows.Scan(&ton.Id); err != nil {
+		if err := rows.Scan(&ton.Id, &ton.CreatedAt); err != nil {
 			log.Error("Failed to scan ID: ", err)
 			return fmt.Errorf("scan error: %w", err)
 		}
@@ -62,7 +63,7 @@ func (r *WalletTonRepository) Update(ton *models.WalletTon) error {
 		log.Error("Failed to begin transaction: ", err)
 		return fmt.Errorf("begin transaction error: %w", err)
 	}
-	if _, err := tx.NamedExecContext(ctx, "update wallet_ton set name = :name, addr = :addr, user_id = :user_id where id = :id", ton); err != nil {
+	if _, err := tx.NamedExecContext(ctx, "update wallet_ton set name = :name, addr = :addr, label = :label, user_id = :user_id where id = :id", ton); err != nil {
 		log.Error("Failed to update wallet: ", err)
 		return err
 	}
@@ -88,7 +89,7 @@ func (r *WalletTonRepository) DeleteById(id uint64) error {
 		log.Error("Failed to begin transaction: ", err)
 		return fmt.Errorf("begin transaction error: %w", err)
 	}
-	if _, err := tx.ExecContext(ctx, "delete from wallet_ton where id = :id", id); err != nil {
+	if _, err := tx.ExecContext(ctx, "delete from wallet_ton where id = $1", id); err != nil {
 		log.Error("Failed to delete wallet: ", err)
 		return err
 	}
@@ -135,7 +136,7 @@ func (r *WalletTonRepository) FindById(id uint64) *models.WalletTon {
 
 	var wallet models.WalletTon
 
-	if err := r.db.GetContext(ctx, &wallet, "select * from wallet_ton where id = :id", id); err != nil {
+	if err := r.db.GetContext(ctx, &wallet, "select * from wallet_ton where id = $1", id); err != nil {
 		log.Error("Failed to find wallet: ", err)
 		return nil
 	}
@@ -143,6 +144,7 @@ func (r *WalletTonRepository) FindById(id uint64) *models.WalletTon {
 	return &wallet
 }
 
+// FindByUserId основной кошелек пользователя
 func (r *WalletTonRepository) FindByUserId(userId uint64) *models.WalletTon {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -151,7 +153,7 @@ func (r *WalletTonRepository) FindByUserId(userId uint64) *models.WalletTon {
 	if err := r.db.GetContext(
 		ctx,
 		&wallet,
-		"select w.* from wallet_ton as w join usr as u on w.user_id = u.id where u.id=$1",
+		"select * from wallet_ton where user_id=$1 order by is_default desc, id limit 1",
 		userId,
 	); err != nil {
 		log.Error("Failed to find wallet: ", err)
@@ -161,6 +163,167 @@ func (r *WalletTonRepository) FindByUserId(userId uint64) *models.WalletTon {
 	return &wallet
 }
 
+func (r *WalletTonRepository) FindAllByUserId(userId uint64) []models.WalletTon {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wallets []models.WalletTon
+	if err := r.db.SelectContext(
+		ctx,
+		&wallets,
+		"select * from wallet_ton where user_id=$1 order by is_default desc, id",
+		userId,
+	); err != nil {
+		log.Error("Failed to find wallets: ", err)
+		return nil
+	}
+
+	return wallets
+}
+
+// FindUserAddrs адреса пользователя: привязанные кошельки и кошельки, с которых он стейкал и получал выплаты
+func (r *WalletTonRepository) FindUserAddrs(userId uint64) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	addrs := make([]string, 0)
+	if err := r.db.SelectContext(
+		ctx,
+		&addrs,
+		`select addr from wallet_ton where user_id = $1
+		 union
+		 select deposit_addr from stake where user_id = $1 and deposit_addr <> ''
+		 union
+		 select payout_addr from stake where user_id = $1 and payout_addr <> ''`,
+		userId,
+	); err != nil {
+		log.Error("Failed to find user addresses: ", err)
+		return nil
+	}
+
+	return addrs
+}
+
+// FindAddrOwners другие пользователи, у которых есть любой из адресов
+func (r *WalletTonRepository) FindAddrOwners(addrs []string, exceptUserId uint64) ([]uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	owners := make([]uint64, 0)
+	if err := r.db.SelectContext(
+		ctx,
+		&owners,
+		`select user_id from wallet_ton where addr = any($1) and user_id <> $2
+		 union
+		 select user_id from stake where (deposit_addr = any($1) or payout_addr = any($1)) and user_id <> $2`,
+		pq.Array(addrs),
+		exceptUserId,
+	); err != nil {
+		log.Error("Failed to find address owners: ", err)
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// FindDueDefault кошелек, срок смены на который уже наступил
+func (r *WalletTonRepository) FindDueDefault(userId uint64, now time.Time) *models.WalletTon {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wallet models.WalletTon
+	if err := r.db.GetContext(
+		ctx,
+		&wallet,
+		"select * from wallet_ton where user_id=$1 and not is_default and default_at <= $2 order by default_at desc limit 1",
+		userId,
+		now,
+	); err != nil {
+		return nil
+	}
+
+	return &wallet
+}
+
+// SetDefault делает кошелек основным и переводит на него выплаты по невыплаченным стейкам
+func (r *WalletTonRepository) SetDefault(userId uint64, wallet *models.WalletTon) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Failed to begin transaction: ", err)
+		return fmt.Errorf("begin transaction error: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "update wallet_ton set is_default = false, default_at = null where user_id = $1", userId); err != nil {
+		log.Error("Failed to reset default wallet: ", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "update wallet_ton set is_default = true where id = $1 and user_id = $2", wallet.Id.Int64, userId); err != nil {
+		log.Error("Failed to set default wallet: ", err)
+		return err
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		"update stake set payout_addr = $1 where user_id = $2 and not is_reward_paid and not is_insurance_paid",
+		wallet.Addr,
+		userId,
+	); err != nil {
+		log.Error("Failed to update stakes payout address: ", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to set default wallet: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetDefaultAt планирует смену основного кошелька. walletId = 0 отменяет запланированную смену
+func (r *WalletTonRepository) SetDefaultAt(userId, walletId uint64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error("Failed to begin transaction: ", err)
+		return fmt.Errorf("begin transaction error: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "update wallet_ton set default_at = null where user_id = $1", userId); err != nil {
+		log.Error("Failed to reset default wallet request: ", err)
+		return err
+	}
+	if walletId != 0 {
+		if _, err := tx.ExecContext(
+			ctx,
+			"update wallet_ton set default_at = $1 where id = $2 and user_id = $3 and not is_default",
+			at,
+			walletId,
+			userId,
+		); err != nil {
+			log.Error("Failed to request default wallet: ", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to request default wallet: ", err)
+		return err
+	}
+
+	return nil
+}
+
 func (r *WalletTonRepository) FindByAddr(addr string) *models.WalletTon {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
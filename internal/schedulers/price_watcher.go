@@ -0,0 +1,86 @@
+package schedulers
+
+import (
+	"log"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+)
+
+// PriceWatcher сравнивает текущую цену токена с ценой входа активных стейков
+// и предупреждает стейкеров о приближении к порогу страховки и его прохождении
+type PriceWatcher struct {
+	b  *bot.Bot
+	ss *services.StakeService
+	ps *services.PoolService
+}
+
+func NewPriceWatcher(b *bot.Bot, ss *services.StakeService, ps *services.PoolService) *PriceWatcher {
+	return &PriceWatcher{
+		b:  b,
+		ss: ss,
+		ps: ps,
+	}
+}
+
+func (w *PriceWatcher) CheckActiveStakes() func() {
+	return func() {
+		stakes := w.ss.GetAllIsStatus(true)
+		if stakes == nil {
+			return
+		}
+		margin := config.LoadPriceWatchConfig().NearMargin
+		now := time.Now()
+		pools := make(map[uint64]*models.Pool)
+		prices := make(map[string]float64)
+
+		for _, stake := range *stakes {
+			if !stake.EndDate.After(now) || stake.DepositCreationPrice <= 0 {
+				continue
+			}
+			pool, ok := pools[stake.PoolId]
+			if !ok {
+				p, err := w.ps.GetId(stake.PoolId)
+				if err != nil {
+					continue
+				}
+				pool = p
+				pools[stake.PoolId] = pool
+			}
+			price, ok := prices[pool.JettonMaster]
+			if !ok {
+				price = util.GetCurrentPriceJettonAddr(pool.JettonMaster)
+				prices[pool.JettonMaster] = price
+			}
+			if price <= 0 {
+				// цена недоступна - не считаем это падением
+				continue
+			}
+
+			change := util.CalculateProcientEditPrice(price, stake.DepositCreationPrice)
+			level := util.PriceAlertLevel(change, pool.InsuranceCoating, margin)
+			// уровень снижается только после полного восстановления цены, чтобы колебания у порога не давали повторных сообщений
+			if level == stake.PriceAlert || (level != models.PRICE_ALERT_NONE && level < stake.PriceAlert) {
+				continue
+			}
+			if err := w.ss.SetPriceAlert(uint64(stake.Id.Int64), level); err != nil {
+				log.Println("Failed to save price alert:", err)
+				continue
+			}
+			if level > stake.PriceAlert {
+				util.Notify(w.b, stake.UserId, models.NOTIFY_PRICE_ALERT, &models.NotificationData{
+					JettonName:  pool.JettonName,
+					Amount:      stake.Amount,
+					EndDate:     stake.EndDate,
+					PriceChange: change,
+					Coverage:    pool.InsuranceCoating,
+					Triggered:   level == models.PRICE_ALERT_TRIGGERED,
+				})
+			}
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package schedulers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
 	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
 	"tonclient/internal/models"
+	"tonclient/internal/repositories"
 	"tonclient/internal/services"
 	"tonclient/internal/tonfi"
 	"tonclient/internal/util"
@@ -22,9 +25,12 @@ type StakeScheduler struct {
 	ps          *services.PoolService
 	rs          *services.ReferalService
 	aws         *services.AdminWalletService
+	as          *services.AdminService
 	ws          *services.WalletTonService
 	ts          *services.TelegramService
+	ops         *services.OperationService
 	closedStake chan *models.NotificationStake
+	payout      func(ctx context.Context, f func()) error
 }
 
 func NewStakScheduler(
@@ -34,9 +40,12 @@ func NewStakScheduler(
 	ps *services.PoolService,
 	rs *services.ReferalService,
 	aws *services.AdminWalletService,
+	as *services.AdminService,
 	ws *services.WalletTonService,
 	ts *services.TelegramService,
+	ops *services.OperationService,
 	closeStaked chan *models.NotificationStake,
+	payout func(ctx context.Context, f func()) error,
 ) *StakeScheduler {
 	return &StakeScheduler{
 		b:           b,
@@ -45,9 +54,12 @@ func NewStakScheduler(
 		ps:          ps,
 		rs:          rs,
 		aws:         aws,
+		as:          as,
 		ws:          ws,
 		closedStake: closeStaked,
 		ts:          ts,
+		ops:         ops,
+		payout:      payout,
 	}
 }
 
@@ -70,144 +82,332 @@ func (s *StakeScheduler) AddStakeBonusActiveStakes() func() {
 					stake.CloseDate = time.Now()
 					currentPrice := util.GetCurrentPriceJettonAddr(pool.JettonMaster)
 					stake.JettonPriceClosed = currentPrice
+					if util.HasInsuranceReserve(pool) {
+						stake.InsuranceAssetPrice = util.GetInsuranceAssetPrice(pool.InsuranceAsset)
+					}
 					err = s.ss.Update(&stake)
 					if err != nil {
 						continue
 					}
+					priceChange := util.CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice)
+					data := &models.NotificationData{
+						JettonName:  jettonData.DisplayName,
+						Amount:      stake.Amount,
+						Balance:     stake.Balance,
+						Profit:      stake.Balance - stake.Amount,
+						EndDate:     stake.EndDate,
+						PriceChange: priceChange,
+						Coverage:    pool.InsuranceCoating,
+					}
+					msg := util.NotificationText(util.UserLang(stake.UserId), models.NOTIFY_MATURED, data)
+					if stake.AutoRollover {
+						msg = s.rollover(&stake, pool, jettonData.DisplayName)
+					}
 					if s.closedStake != nil {
-						profit := stake.Balance - stake.Amount
 						s.closedStake <- &models.NotificationStake{
 							Stake: &stake,
-							Msg: fmt.Sprintf("✅ Стейк с токеном %v был закрыт.\n\n Заработано: %v %v.\n Общий баланс: %v %v\n Теперь вы можете вывести токены или получить компенсацию, если она полагается.",
-								jettonData.DisplayName,
-								util.RemoveZeroFloat(profit),
-								jettonData.DisplayName,
-								util.RemoveZeroFloat(stake.Balance),
-								jettonData.DisplayName,
-							),
+							Event: models.NOTIFY_MATURED,
+							Msg:   msg,
 						}
 					}
-					log.Println("проверка кол-во стейков")
-					stakesCountUser := s.ss.CountUser(stake.UserId)
-					tgStaker, err := s.ts.GetByUserId(stake.UserId)
-					if err != nil {
-						continue
-					}
-					if stakesCountUser == 1 {
-						u, err := s.us.GetById(stake.UserId)
-						if err == nil {
-							log.Println("отправка бонуса")
-							if u.RefererId.Valid && u.RefererId.Int64 != 0 {
-								go func() {
-									if err := s.sendBonus(
-										s.b,
-										uint64(u.RefererId.Int64),
-										&stake,
-										tgStaker,
-									); err != nil {
-										log.Println("Failed to send bonus:", err)
-										return
-									}
-								}()
-							}
-						}
+					// при автоперевыпуске компенсация уже учтена в новом стейке
+					if !stake.AutoRollover && priceChange < float64(pool.InsuranceCoating)*-1 {
+						util.Notify(s.b, stake.UserId, models.NOTIFY_INSURANCE, data)
 					}
+					go s.accrueReferral(&stake, pool, jettonData.DisplayName)
 				}
 				continue
 			}
 			if currentTime.Hour() == stake.EndDate.Hour() && currentTime.Minute() == stake.EndDate.Minute() {
-				bonusPercent := float64(pool.Reward) / 100
+				reward, period := util.StakeTerms(&stake, pool)
+				bonusPercent := reward / 100
 				amountBonus := stake.Amount * bonusPercent
-				rewardAllTime := amountBonus * float64(pool.Period)
+				rewardAllTime := amountBonus * float64(period)
+				accrued := 0.
 				if stake.Balance < rewardAllTime+stake.Amount {
 					stake.Balance += amountBonus
+					accrued = amountBonus
 				}
 				if err := s.ss.Update(&stake); err != nil {
 					continue
 				}
+				s.notifyAccrual(&stake, pool, accrued, currentTime)
 			}
 		}
 	}
 }
 
-func (s *StakeScheduler) sendBonus(b *bot.Bot, referalId uint64, stake *models.Stake, tgStaker *models.Telegram) error {
-	u, err := s.us.GetByTelegramChatId(referalId)
-	if err != nil {
-		log.Println("Failed to get user :", err)
-		return err
+// notifyAccrual уведомления о ежедневном начислении и о том, что до окончания стейка осталось меньше суток
+func (s *StakeScheduler) notifyAccrual(stake *models.Stake, pool *models.Pool, accrued float64, now time.Time) {
+	data := &models.NotificationData{
+		JettonName: pool.JettonName,
+		Amount:     stake.Amount,
+		Balance:    stake.Balance,
+		Profit:     accrued,
+		EndDate:    stake.EndDate,
+	}
+	if accrued > 0 {
+		util.Notify(s.b, stake.UserId, models.NOTIFY_DAILY_ACCRUAL, data)
+	}
+	// начисление идет раз в сутки во время окончания стейка, поэтому последнее перед окончанием попадает в это окно один раз
+	if left := stake.EndDate.Sub(now); left > 0 && left <= 24*time.Hour+time.Minute {
+		util.Notify(s.b, stake.UserId, models.NOTIFY_MATURITY_SOON, data)
+	}
+}
+
+// rollover перевыпускает созревший стейк в том же пуле на депозит с наградой без вывода токенов.
+// Возвращает текст уведомления для стейкера.
+func (s *StakeScheduler) rollover(stake *models.Stake, pool *models.Pool, jettonName string) string {
+	lang := util.UserLang(stake.UserId)
+	failMsg := i18n.T(
+		lang,
+		"✅ Стейк с токеном %v был закрыт.\n\n Заработано: %v %v.\n Общий баланс: %v %v\n❌ Автоматически перевыпустить стейк не удалось: %%v.\n Вы можете вывести токены или получить компенсацию, если она полагается.",
+		jettonName,
+		util.RemoveZeroFloat(stake.Balance-stake.Amount),
+		jettonName,
+		util.RemoveZeroFloat(stake.Balance),
+		jettonName,
+	)
+
+	if !pool.IsActive {
+		return fmt.Sprintf(failMsg, i18n.T(lang, "пул закрыт"))
+	}
+
+	profit := stake.Balance - stake.Amount
+	isInsurance := util.CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice) < float64(pool.InsuranceCoating)*-1
+
+	// компенсация в токене пула добавляется к депозиту, в USDT/TON - отправляется на кошелек
+	insuranceJetton := 0.
+	insuranceAsset := 0.
+	if isInsurance && util.HasInsuranceReserve(pool) {
+		insuranceAsset = util.CalculateInsuranceInAsset(stake)
+		if pool.InsuranceReserve < insuranceAsset {
+			return fmt.Sprintf(failMsg, i18n.T(lang, "недостаточно страхового резерва"))
+		}
+	} else if isInsurance {
+		insuranceJetton = util.CalculateInsurance(pool, stake)
 	}
-	w, err := s.ws.GetByUserId(uint64(u.Id.Int64))
+
+	amount := stake.Balance + insuranceJetton
+	reserve := pool.Reserve - profit - insuranceJetton
+
+	stakes := s.ss.GetPoolStakes(stake.PoolId)
+	others := make([]models.Stake, 0, len(stakes))
+	for _, st := range stakes {
+		if st.Id.Int64 != stake.Id.Int64 {
+			others = append(others, st)
+		}
+	}
+	settled := *pool
+	settled.Reserve = reserve
+	if util.MaxStakeAmount(&settled, util.CalculateSumStakesFromPool(&others, &settled)) < amount {
+		return fmt.Sprintf(failMsg, i18n.T(lang, "недостаточно резерва в пуле"))
+	}
+	if err := s.ss.CanStake(pool, stake.UserId, amount); err != nil {
+		return fmt.Sprintf(failMsg, i18n.T(lang, "превышены лимиты пула"))
+	}
+
+	now := time.Now()
+	_, period := util.StakeTerms(stake, pool)
+	reward, err := s.ps.ResolveReward(pool, s.ps.GetRewardTiers(stake.PoolId), amount, period, now)
 	if err != nil {
-		log.Println("Failed to get user:", err)
-		return err
+		// тариф с этим сроком удален - перевыпускаем на базовых условиях пула
+		period = pool.Period
+		reward = pool.Reward
 	}
-	jettonAdminAddr := os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
-	if jettonAdminAddr == "" {
-		return err
+	newStake := &models.Stake{
+		UserId:               stake.UserId,
+		PoolId:               stake.PoolId,
+		Amount:               amount,
+		Balance:              amount,
+		StartPoolDeposit:     amount * 20,
+		StartDate:            now,
+		IsActive:             true,
+		EndDate:              now.Add(time.Duration(period) * 24 * time.Hour),
+		DepositCreationPrice: stake.JettonPriceClosed,
+		IsCommissionPaid:     true,
+		AutoRollover:         true,
+		RolledFrom:           stake.Id,
+		Reward:               reward,
+		Period:               period,
+		DepositAddr:          stake.DepositAddr,
+		PayoutAddr:           stake.PayoutAddr,
+	}
+	// новый стейк и выплата закрытого сохраняются одной транзакцией до любых выплат: если лимиты пула
+	// не позволяют или сохранить не удалось, закрытый стейк остается к выводу как обычно
+	closed := *stake
+	if isInsurance {
+		closed.IsInsurancePaid = true
+	} else {
+		closed.IsRewardPaid = true
+	}
+	if err := s.ss.Rollover(&closed, newStake); err != nil {
+		log.Println("Failed to roll over stake:", err)
+		if errors.Is(err, repositories.ErrStakePaid) {
+			return fmt.Sprintf(failMsg, i18n.T(lang, "стейк уже выплачен"))
+		}
+		if errors.Is(err, services.ErrPoolMaxTotalStake) || errors.Is(err, services.ErrPoolMaxUserStake) || errors.Is(err, services.ErrPoolMaxStakers) {
+			return fmt.Sprintf(failMsg, i18n.T(lang, "превышены лимиты пула"))
+		}
+		return fmt.Sprintf(failMsg, i18n.T(lang, "не удалось сохранить новый стейк"))
+	}
+	*stake = closed
+
+	insuranceText := ""
+	if insuranceAsset > 0 {
+		insuranceText = i18n.T(
+			lang,
+			"\n Компенсация %v %v отправлена на ваш кошелек.",
+			util.RemoveZeroFloat(insuranceAsset),
+			util.InsuranceAssetName(lang, pool.InsuranceAsset),
+		)
+		// отправка идет через общую очередь выплат, чтобы не пересекаться с другими переводами админского кошелька
+		var err error
+		done := make(chan struct{})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if enqueueErr := s.payout(ctx, func() {
+			defer close(done)
+			err = s.sendInsurance(stake, pool, insuranceAsset)
+		}); enqueueErr != nil {
+			close(done)
+			err = enqueueErr
+			s.recordInsurance(stake, pool, insuranceAsset, err)
+		}
+		cancel()
+		<-done
+		if err != nil {
+			log.Println("Failed to send insurance:", err)
+			insuranceText = i18n.T(
+				lang,
+				"\n❌ Компенсацию %v %v отправить не удалось. Обратитесь в поддержку, выплата будет произведена вручную.",
+				util.RemoveZeroFloat(insuranceAsset),
+				util.InsuranceAssetName(lang, pool.InsuranceAsset),
+			)
+		}
+	} else if insuranceJetton > 0 {
+		insuranceText = i18n.T(lang, "\n Компенсация %v %v добавлена к депозиту.", util.RemoveZeroFloat(insuranceJetton), jettonName)
 	}
-	bonus := os.Getenv("REFERAL_BONUS")
-	if bonus == "" {
-		bonus = "2"
+
+	pool.Reserve = reserve
+	stakes = s.ss.GetPoolStakes(stake.PoolId)
+	pool.TempReserve = pool.Reserve - util.CalculateSumStakesFromPool(&stakes, pool)
+	if err := s.ps.Update(pool); err != nil {
+		log.Println("Failed to update pool:", err)
+	}
+
+	if _, err := s.ops.Create(
+		stake.UserId,
+		models.OP_ROLLOVER_STAKE,
+		fmt.Sprintf("Перевыпуск стейка %v. Новый депозит: %v %v", stake.Id.Int64, util.RemoveZeroFloat(amount), jettonName),
+	); err != nil {
+		log.Println("Failed to create operation:", err)
+	}
+
+	return i18n.T(
+		lang,
+		"🔁 Стейк с токеном %v перевыпущен.\n\n Заработано: %v %v.%v\n Новый депозит: %v %v до %v",
+		jettonName,
+		util.RemoveZeroFloat(profit),
+		jettonName,
+		insuranceText,
+		util.RemoveZeroFloat(amount),
+		jettonName,
+		newStake.EndDate.Format("02 January 2006 15:04:05"),
+	)
+}
+
+// sendInsurance отправляет компенсацию в USDT/TON на кошелек выплат стейка. Вызывается из очереди выплат
+func (s *StakeScheduler) sendInsurance(stake *models.Stake, pool *models.Pool, amount float64) error {
+	w, err := s.ws.GetByUserId(stake.UserId)
+	if err == nil {
+		if pool.InsuranceAsset == models.INSURANCE_ASSET_TON {
+			_, err = s.aws.SendTon(util.PayoutAddr(stake, w), "", util.RemoveZeroFloat(amount))
+		} else {
+			_, err = s.aws.SendJetton(
+				config.USDT_JETTON_MASTER,
+				util.PayoutAddr(stake, w),
+				"",
+				util.RemoveZeroFloat(amount),
+				config.USDT_DECIMALS,
+			)
+		}
 	}
-	bonusNum, err := strconv.ParseFloat(bonus, 64)
 	if err != nil {
-		log.Println("Failed to parse bonus:", err)
+		s.recordInsurance(stake, pool, amount, err)
 		return err
 	}
-	decimal := os.Getenv("JETTON_DECIMAL")
-	if decimal == "" {
-		decimal = "9"
+	pool.InsuranceReserve -= amount
+	return nil
+}
+
+// recordInsurance передает неотправленную компенсацию администратору. Она уже причитается стейкеру,
+// поэтому списывается из страхового резерва
+func (s *StakeScheduler) recordInsurance(stake *models.Stake, pool *models.Pool, amount float64, sendErr error) {
+	insuranceMaster := config.USDT_JETTON_MASTER
+	if pool.InsuranceAsset == models.INSURANCE_ASSET_TON {
+		insuranceMaster = ""
+	}
+	util.RecordFailedPayout(s.as, stake.UserId, insuranceMaster, amount, fmt.Sprintf("Компенсация по стейку %v", stake.Id.Int64), sendErr)
+	pool.InsuranceReserve -= amount
+}
+
+// accrueReferral начисляет пригласившим всех уровней реферальную награду за закрытый стейк.
+// Перевыпущенный стейк не приносит награду от суммы повторно
+func (s *StakeScheduler) accrueReferral(stake *models.Stake, pool *models.Pool, jettonName string) {
+	if stake.RolledFrom.Valid && config.LoadReferralConfig().Base == models.REFERRAL_BASE_STAKE {
+		return
+	}
+
+	refs, err := s.rs.Accrue(stake, pool, util.GetCurrentPriceJettonAddr)
+	if services.IsReferralIneligible(err) {
+		log.Println("Stake", stake.Id.Int64, "is not eligible for referral rewards:", err)
+		return
 	}
-	decimalNum, err := strconv.Atoi(decimal)
 	if err != nil {
-		log.Println("Failed to parse decimal:", err)
-		return err
+		log.Println("Failed to accrue referral rewards:", err)
+		return
 	}
-	bonusAmount := stake.Amount * (bonusNum / 100)
-	if _, err := s.aws.SendJetton(
-		jettonAdminAddr,
-		w.Addr,
-		"",
-		util.RemoveZeroFloat(bonusAmount),
-		decimalNum,
-	); err != nil {
-		log.Println("Failed to send bonus:", err)
-		return err
+
+	staker := ""
+	if tg, err := s.ts.GetByUserId(stake.UserId); err == nil && tg.Username != "" {
+		staker = "@" + tg.Username
 	}
 	tokenName := os.Getenv("JETTON_NAME_COIN")
 	if tokenName == "" {
 		tokenName = "NESTRAH"
 	}
+	threshold := config.LoadReferralConfig().Threshold
 
-	if tgStaker != nil {
-		if _, err := util.SendTextMessage(
-			b,
-			referalId,
-			fmt.Sprintf(
-				"✅ Вы получили бонус %v %v, за пользователя %v. Токены были отправлены на привязанный кошелек",
-				util.RemoveZeroFloat(bonusAmount),
+	for _, ref := range refs {
+		tg, err := s.ts.GetByUserId(uint64(ref.ReferrerUserId.Int64))
+		if err != nil {
+			continue
+		}
+		stats, err := s.rs.Stats(uint64(ref.ReferrerUserId.Int64))
+		if err != nil {
+			continue
+		}
+		name := staker
+		if name == "" {
+			name = util.T(tg.TelegramId, "пользователя")
+		}
+		util.QueueTextMessage(
+			s.b,
+			tg.TelegramId,
+			util.T(
+				tg.TelegramId,
+				"✅ Реферальная награда %v %v за стейк %v %v (%v, уровень %v).\n\nРеферальный баланс: %v %v. Выплата на кошелек при достижении %v %v",
+				util.RemoveZeroFloat(ref.RewardAmount),
+				tokenName,
+				util.RemoveZeroFloat(stake.Amount),
+				jettonName,
+				name,
+				ref.Level,
+				util.RemoveZeroFloat(stats.Balance),
+				tokenName,
+				util.RemoveZeroFloat(threshold),
 				tokenName,
-				tgStaker.Username,
 			),
-		); err != nil {
-			log.Print("Failed to send bonus:", err)
-			return err
-		}
-	}
-
-	if err := s.rs.Save(&models.Referral{
-		ReferrerUserId: u.Id,
-		ReferralUserId: sql.NullInt64{
-			Int64: int64(stake.UserId),
-			Valid: true,
-		},
-		FirstStakeId: stake.Id,
-		RewardGiven:  true,
-		RewardAmount: bonusAmount,
-	}); err != nil {
-		log.Println("Failed to save referral:", err)
-		return err
+		)
 	}
-
-	return nil
 }
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+	"tonclient/internal/repositories"
+)
+
+var ErrFailedPayoutClosed = errors.New("failed payout already closed")
+
+// AdminService операции администратора платформы
+type AdminService struct {
+	us   *UserService
+	ps   *PoolService
+	ss   *StakeService
+	repo *repositories.FailedPayoutRepository
+}
+
+func NewAdminService(us *UserService, ps *PoolService, ss *StakeService, repo *repositories.FailedPayoutRepository) *AdminService {
+	return &AdminService{
+		us:   us,
+		ps:   ps,
+		ss:   ss,
+		repo: repo,
+	}
+}
+
+// IsAdmin администратор задан в ADMIN_TELEGRAM_IDS или имеет роль admin
+func (s *AdminService) IsAdmin(telegramId uint64) bool {
+	if slices.Contains(config.AdminTelegramIds(), telegramId) {
+		return true
+	}
+	u, err := s.us.GetByTelegramChatId(telegramId)
+	return err == nil && u.Role == models.USER_ROLE_ADMIN
+}
+
+func (s *AdminService) Stats() (*models.PlatformStats, error) {
+	tvl, err := s.ss.TvlByJetton()
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlatformStats{
+		Users:        s.us.CountAll(),
+		Stakes:       s.ss.CountAll(),
+		ActiveStakes: s.ss.CountAllByStatus(true),
+		Pools:        s.ps.CountAll(),
+		ActivePools:  s.ps.CountAllByStatus(true),
+		FailedPayout: s.repo.CountByStatus(models.FAILED_PAYOUT_FAILED),
+		Tvl:          tvl,
+	}, nil
+}
+
+// SetPoolPaused приостанавливает пул без права владельца открыть его или снимает приостановку
+func (s *AdminService) SetPoolPaused(poolId uint64, paused bool) (*models.Pool, error) {
+	pool, err := s.ps.GetId(poolId)
+	if err != nil {
+		return nil, err
+	}
+	pool.AdminPaused = paused
+	// снятая приостановка возвращает пул в работу, только если он оплачен и у него есть резерв
+	pool.IsActive = !paused && pool.IsCommissionPaid && pool.Reserve > 0
+	if err := s.ps.Update(pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// RecordFailedPayout сохраняет выплату, которую не удалось отправить, для повтора администратором
+func (s *AdminService) RecordFailedPayout(userId uint64, jettonMaster string, amount float64, reason string, sendErr error) (*models.FailedPayout, error) {
+	payout := &models.FailedPayout{
+		UserId:       userId,
+		JettonMaster: jettonMaster,
+		Amount:       amount,
+		Reason:       reason,
+		Status:       models.FAILED_PAYOUT_FAILED,
+		Attempts:     1,
+		CreatedAt:    time.Now(),
+	}
+	if sendErr != nil {
+		payout.Error = sendErr.Error()
+	}
+	if err := s.repo.Save(payout); err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+func (s *AdminService) FailedPayouts(offset, limit int) ([]models.FailedPayout, error) {
+	return s.repo.FindByStatusLimit(models.FAILED_PAYOUT_FAILED, offset, limit)
+}
+
+func (s *AdminService) CountFailedPayouts() int {
+	return s.repo.CountByStatus(models.FAILED_PAYOUT_FAILED)
+}
+
+// OpenFailedPayout выплата, которую еще можно повторить или закрыть
+func (s *AdminService) OpenFailedPayout(id uint64) (*models.FailedPayout, error) {
+	payout, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if payout.Status != models.FAILED_PAYOUT_FAILED {
+		return nil, ErrFailedPayoutClosed
+	}
+	return payout, nil
+}
+
+// RetryResult фиксирует итог повторной отправки
+func (s *AdminService) RetryResult(payout *models.FailedPayout, hash string, sendErr error) error {
+	payout.Attempts++
+	if sendErr != nil {
+		payout.Error = sendErr.Error()
+		return s.repo.Update(payout)
+	}
+	payout.Status = models.FAILED_PAYOUT_RESOLVED
+	payout.PayoutHash = hash
+	payout.ResolvedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return s.repo.Update(payout)
+}
+
+// CancelFailedPayout закрывает выплату без отправки, например если она проведена вручную
+func (s *AdminService) CancelFailedPayout(id uint64) error {
+	payout, err := s.OpenFailedPayout(id)
+	if err != nil {
+		return err
+	}
+	payout.Status = models.FAILED_PAYOUT_CANCELLED
+	payout.ResolvedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return s.repo.Update(payout)
+}
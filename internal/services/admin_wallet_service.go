@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"github.com/go-telegram/bot"
 	"github.com/xssnick/tonutils-go/address"
@@ -134,11 +136,21 @@ func (s *AdminWalletService) StartSubscribeTransaction(ch chan models.SubmitTran
 			if err := tlb.LoadFromCell(&transfer, ti.Body.BeginParse()); err == nil {
 
 				src = transfer.Sender
+				if transfer.ForwardPayload == nil {
+					continue
+				}
 				payload := transfer.ForwardPayload.BeginParse()
 				op, err := payload.LoadUInt(32)
 				if err != nil {
 					continue
 				}
+				// op 0 - текстовый комментарий: перевод напрямую в казну с кодом
+				if op == 0 {
+					if comment, err := payload.LoadStringSnake(); err == nil {
+						go s.processComment(comment, ti.SrcAddr.StringRaw(), transfer.Amount.Nano().String(), transfer.Sender.String(), tx.Hash, ch)
+					}
+					continue
+				}
 				payloadDataBase64, err := payload.LoadStringSnake()
 				if err != nil {
 					log.Fatalln("load payload err: ", err.Error())
@@ -151,7 +163,28 @@ func (s *AdminWalletService) StartSubscribeTransaction(ch chan models.SubmitTran
 					continue
 				}
 
-				go s.processOperation(op, amount, transfer.Sender.String(), payloadDataBase64, ch)
+				// уведомление пришло с jetton-кошелька ti.SrcAddr: по нему бот сверяет jetton, сумма - из самого перевода
+				go s.processOperation(op, amount, transfer.Sender.String(), ti.SrcAddr.StringRaw(), transfer.Amount.Nano().String(), payloadDataBase64, ch)
+			} else if ti.Body != nil {
+				body := ti.Body.BeginParse()
+				op, err := body.LoadUInt(32)
+				if err == nil && op == 0 {
+					if comment, err := body.LoadStringSnake(); err == nil {
+						go s.processComment(comment, s.treasuryAddress.StringRaw(), ti.Amount.Nano().String(), src.String(), tx.Hash, ch)
+					}
+				}
+				// op 0 - обычный текстовый комментарий, операции бота идут с кодом операции
+				if err == nil && isTonOperation(op) {
+					if payloadDataBase64, err := body.LoadStringSnake(); err == nil {
+						amount, err := strconv.ParseFloat(ti.Amount.String(), 64)
+						if err != nil {
+							log.Error("parse amount err: ", err.Error())
+							continue
+						}
+
+						go s.processOperation(op, amount, src.String(), s.treasuryAddress.StringRaw(), ti.Amount.Nano().String(), payloadDataBase64, ch)
+					}
+				}
 			}
 
 			if ti.Amount.Nano().Sign() > 0 {
@@ -161,17 +194,25 @@ func (s *AdminWalletService) StartSubscribeTransaction(ch chan models.SubmitTran
 	}
 }
 
-func (s *AdminWalletService) processOperation(op uint64, amount float64, senderAddr, payloadDataBase64 string, ch chan models.SubmitTransaction) {
+// isTonOperation операции, которые бот оплачивает переводом TON: пополнение страхового резерва в TON
+// и комиссия за стейк в TON. Остальные операции в TON не принимаются
+func isTonOperation(op uint64) bool {
+	return op == models.OP_ADD_INSURANCE_RESERVE || op == models.OP_PAID_COMMISSION_STAKE
+}
+
+func (s *AdminWalletService) processOperation(op uint64, amount float64, senderAddr, treasuryWallet, units, payloadDataBase64 string, ch chan models.SubmitTransaction) {
 	data, err := base64.StdEncoding.DecodeString(payloadDataBase64)
 	if err != nil {
 		log.Infoln("Failed to decode payload data:", err)
 		return
 	}
 	tr := models.SubmitTransaction{
-		OperationType: op,
-		Amount:        amount,
-		Payload:       data,
-		SenderAddr:    senderAddr,
+		OperationType:  op,
+		Amount:         amount,
+		Payload:        data,
+		SenderAddr:     senderAddr,
+		TreasuryWallet: treasuryWallet,
+		Units:          units,
 	}
 
 	log.Infoln("запись в канал")
@@ -179,6 +220,20 @@ func (s *AdminWalletService) processOperation(op uint64, amount float64, senderA
 	log.Infoln("запись добавлена")
 }
 
+// processComment передает перевод с текстовым комментарием на сверку с кодами переводов
+func (s *AdminWalletService) processComment(comment, treasuryWallet, units, senderAddr string, txHash []byte, ch chan models.SubmitTransaction) {
+	if NormalizeMemo(comment) == "" {
+		return
+	}
+	ch <- models.SubmitTransaction{
+		SenderAddr:     senderAddr,
+		Comment:        comment,
+		TreasuryWallet: treasuryWallet,
+		Units:          units,
+		TxHash:         hex.EncodeToString(txHash),
+	}
+}
+
 func (s *AdminWalletService) SendJetton(jettonMaster, receiverAddr, comment string, amount string, decimal int) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
@@ -267,6 +322,50 @@ func (s *AdminWalletService) SendJetton(jettonMaster, receiverAddr, comment stri
 	return tx.Hash, nil
 }
 
+func (s *AdminWalletService) SendTon(receiverAddr, comment string, amount string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	amountTon, err := tlb.FromTON(amount)
+	if err != nil {
+		log.Error("Error parse amount ", err)
+		return nil, err
+	}
+
+	block, err := s.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		log.Error("get masterchain info err: ", err.Error())
+		return nil, err
+	}
+
+	balanceTon, err := s.wallet.GetBalance(ctx, block)
+	if err != nil {
+		log.Error("get balance err: ", err.Error())
+		return nil, err
+	}
+
+	commission := tlb.MustFromTON("0.01")
+	if balanceTon.Nano().Cmp(new(big.Int).Add(amountTon.Nano(), commission.Nano())) < 0 {
+		return nil, errors.New("balance is insufficient")
+	}
+
+	to, err := address.ParseAddr(receiverAddr)
+	if err != nil {
+		log.Errorf("Failed to parse receiver address: %v", err)
+		return nil, err
+	}
+
+	log.Infoln("sending ton...")
+	tx, _, err := s.wallet.TransferWaitTransaction(ctx, to, amountTon, comment)
+	if err != nil {
+		log.Errorf("Failed to send transaction: %v", err)
+		return nil, err
+	}
+
+	log.Infoln("transaction confirmed, hash:", base64.StdEncoding.EncodeToString(tx.Hash))
+	return tx.Hash, nil
+}
+
 func (s *AdminWalletService) TokenWalletAddress(jettonMaster string, walletAddr *address.Address) (*jetton.WalletClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -300,7 +399,16 @@ func (s *AdminWalletService) DataJetton(masterAddr string) (*models.JettonData,
 		return nil, err
 	}
 
-	return getContent(data), nil
+	content := getContent(data)
+	// у USDT метаданные хранятся offchain, поэтому decimals по умолчанию будут неверными
+	if content != nil && tokenContract.Equals(address.MustParseAddr(config.USDT_JETTON_MASTER)) {
+		content.Decimals = config.USDT_DECIMALS
+		if content.Name == "" {
+			content.Name = "USDT"
+		}
+	}
+
+	return content, nil
 }
 
 func (s *AdminWalletService) CheckValidAddr(addr string) error {
@@ -412,3 +520,42 @@ func (s *AdminWalletService) GetJettonBalance(wallAddr, jettonMaster string) (*b
 func (s *AdminWalletService) GetUserAdminAddr() string {
 	return s.adminWalletAddr
 }
+
+// WalletPublicKey публичный ключ развернутого кошелька (get-метод get_public_key)
+func (s *AdminWalletService) WalletPublicKey(addr *address.Address) (ed25519.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	block, err := s.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.api.RunGetMethod(ctx, block, addr, "get_public_key")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := res.Int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.FillBytes(make([]byte, ed25519.PublicKeySize)), nil
+}
+
+// SendExternalMessage отправляет подписанное внешнее сообщение через лайт-сервер
+func (s *AdminWalletService) SendExternalMessage(msg *tlb.ExternalMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	return s.api.SendExternalMessage(ctx, msg)
+}
+
+// FindTransactionByInMsg транзакция кошелька, обработавшая внешнее сообщение
+func (s *AdminWalletService) FindTransactionByInMsg(msg *tlb.ExternalMessage) (*tlb.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return s.api.FindLastTransactionByInMsgHash(ctx, msg.DstAddr, msg.Payload().Hash(), 30)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+	"tonclient/internal/models"
+	"tonclient/internal/repositories"
+)
+
+// AnnouncementTimeLayout формат времени запланированной рассылки
+const AnnouncementTimeLayout = "02.01.2006 15:04"
+
+var (
+	ErrAnnouncementSegment = errors.New("unknown announcement segment")
+	ErrAnnouncementPool    = errors.New("announcement pool not found")
+	ErrAnnouncementText    = errors.New("announcement text is empty")
+	ErrAnnouncementStatus  = errors.New("announcement can not be changed in current status")
+	ErrAnnouncementTime    = errors.New("announcement time must be in the future")
+)
+
+var announcementSegments = []string{
+	models.ANNOUNCEMENT_SEGMENT_ALL,
+	models.ANNOUNCEMENT_SEGMENT_POOL_STAKERS,
+	models.ANNOUNCEMENT_SEGMENT_POOL_OWNERS,
+	models.ANNOUNCEMENT_SEGMENT_UNPAID_REWARDS,
+}
+
+// AnnouncementService объявления для сегментов пользователей
+type AnnouncementService struct {
+	ps   *PoolService
+	repo *repositories.AnnouncementRepository
+	wake chan struct{}
+}
+
+func NewAnnouncementService(ps *PoolService, repo *repositories.AnnouncementRepository) *AnnouncementService {
+	return &AnnouncementService{
+		ps:   ps,
+		repo: repo,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Wake сигнал воркеру рассылки, что появилось объявление к отправке
+func (s *AnnouncementService) Wake() <-chan struct{} {
+	return s.wake
+}
+
+func (s *AnnouncementService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// CreateDraft сохраняет черновик объявления для сегмента
+func (s *AnnouncementService) CreateDraft(authorId uint64, segment string, poolId uint64, text string) (*models.Announcement, error) {
+	if !slices.Contains(announcementSegments, segment) {
+		return nil, ErrAnnouncementSegment
+	}
+	if text == "" {
+		return nil, ErrAnnouncementText
+	}
+	a := &models.Announcement{
+		AuthorId:  authorId,
+		Text:      text,
+		Segment:   segment,
+		Status:    models.ANNOUNCEMENT_DRAFT,
+		CreatedAt: time.Now(),
+	}
+	if segment == models.ANNOUNCEMENT_SEGMENT_POOL_STAKERS {
+		if _, err := s.ps.GetId(poolId); err != nil {
+			return nil, ErrAnnouncementPool
+		}
+		a.PoolId = sql.NullInt64{Int64: int64(poolId), Valid: true}
+	}
+	if err := s.repo.Save(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *AnnouncementService) GetById(id uint64) (*models.Announcement, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *AnnouncementService) Recent(offset, limit int) ([]models.Announcement, error) {
+	return s.repo.FindAllLimit(offset, limit)
+}
+
+func (s *AnnouncementService) CountAll() int {
+	return s.repo.CountAll()
+}
+
+// Recipients количество получателей объявления на текущий момент
+func (s *AnnouncementService) Recipients(a *models.Announcement) (int, error) {
+	return s.repo.CountRecipients(a.Segment, a.PoolId)
+}
+
+// Schedule ставит черновик или запланированное объявление в очередь на время at
+func (s *AnnouncementService) Schedule(id uint64, at time.Time) (*models.Announcement, error) {
+	a, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != models.ANNOUNCEMENT_DRAFT && a.Status != models.ANNOUNCEMENT_SCHEDULED {
+		return nil, ErrAnnouncementStatus
+	}
+	a.Status = models.ANNOUNCEMENT_SCHEDULED
+	a.ScheduledAt = sql.NullTime{Time: at, Valid: true}
+	if err := s.repo.Update(a); err != nil {
+		return nil, err
+	}
+	s.notify()
+	return a, nil
+}
+
+// SendNow отправляет объявление при следующем проходе воркера
+func (s *AnnouncementService) SendNow(id uint64) (*models.Announcement, error) {
+	return s.Schedule(id, time.Now())
+}
+
+// Cancel отменяет объявление, недоставленные сообщения больше не отправляются
+func (s *AnnouncementService) Cancel(id uint64) (*models.Announcement, error) {
+	a, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == models.ANNOUNCEMENT_DONE || a.Status == models.ANNOUNCEMENT_CANCELLED {
+		return nil, ErrAnnouncementStatus
+	}
+	a.Status = models.ANNOUNCEMENT_CANCELLED
+	a.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.repo.Update(a); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RefreshCounters(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Due объявления, которые пора отправлять, включая прерванные рестартом рассылки
+func (s *AnnouncementService) Due(now time.Time) ([]models.Announcement, error) {
+	return s.repo.FindDue(now)
+}
+
+// Start фиксирует список получателей и переводит объявление в рассылку
+func (s *AnnouncementService) Start(a *models.Announcement) error {
+	if a.Status == models.ANNOUNCEMENT_SENDING {
+		return nil
+	}
+	if err := s.repo.CreateDeliveries(a); err != nil {
+		return err
+	}
+	a.Status = models.ANNOUNCEMENT_SENDING
+	a.StartedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.repo.Update(a); err != nil {
+		return err
+	}
+	return s.repo.RefreshCounters(a)
+}
+
+// IsCancelled объявление отменено администратором во время рассылки
+func (s *AnnouncementService) IsCancelled(id int64) bool {
+	a, err := s.repo.FindById(uint64(id))
+	return err == nil && a.Status == models.ANNOUNCEMENT_CANCELLED
+}
+
+func (s *AnnouncementService) PendingDeliveries(a *models.Announcement, limit int) ([]models.AnnouncementDelivery, error) {
+	return s.repo.FindPendingDeliveries(a.Id.Int64, limit)
+}
+
+// MarkDelivery сохраняет результат отправки объявления получателю
+func (s *AnnouncementService) MarkDelivery(d *models.AnnouncementDelivery, status string, sendErr error) error {
+	d.Status = status
+	d.Error = ""
+	if sendErr != nil {
+		d.Error = sendErr.Error()
+	}
+	d.SentAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return s.repo.UpdateDelivery(d)
+}
+
+// Finish завершает рассылку и обновляет статистику доставки
+func (s *AnnouncementService) Finish(a *models.Announcement) error {
+	a.Status = models.ANNOUNCEMENT_DONE
+	a.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.repo.Update(a); err != nil {
+		return err
+	}
+	return s.repo.RefreshCounters(a)
+}
+
+// ParseAnnouncementTime разбирает время рассылки в формате ДД.ММ.ГГГГ ЧЧ:ММ
+func ParseAnnouncementTime(text string, now time.Time) (time.Time, error) {
+	at, err := time.ParseInLocation(AnnouncementTimeLayout, text, now.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !at.After(now) {
+		return time.Time{}, ErrAnnouncementTime
+	}
+	return at, nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+	"tonclient/internal/models"
+
+	"github.com/cameo-engineering/tonconnect"
+)
+
+var (
+	ErrIntentNotFound = errors.New("tx intent not found")
+	ErrIntentClosed   = errors.New("tx intent is already closed")
+)
+
+// CombinedPartsTTL сколько ждать все переводы транзакции из нескольких сообщений после ее создания:
+// уведомления jetton-кошельков приходят позже выполнения транзакции кошелька
+const CombinedPartsTTL = 2 * TxIntentTTL
+
+var partsMu sync.Mutex
+
+// Transfer перевод в составе транзакции из нескольких сообщений.
+// JettonWallet - jetton-кошелек отправителя, пустой - перевод TON.
+// Paid - перевод уже поступил в казну отдельно: он сразу засчитывается в intent и в транзакцию не входит
+type Transfer struct {
+	JettonWallet string
+	Amount       string
+	Payload      *models.Payload
+	Paid         bool
+}
+
+// SendTransfers отправляет переводы на receiverAddr одной транзакцией, которую пользователь подписывает один раз
+func (s *TonConnectService) SendTransfers(key, receiverAddr, senderAddr string, transfers []Transfer, session *tonconnect.Session) ([]byte, error) {
+	defer func() {
+		if err := s.SaveSession(key, session); err != nil {
+			log.Error("Error saving session", err)
+		}
+	}()
+
+	telegramId, err := strconv.ParseUint(key, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	intent, msgs, err := s.TransferMessages(telegramId, receiverAddr, senderAddr, transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []func(*tonconnect.Transaction){tonconnect.WithTimeout(5 * time.Minute)}
+	for _, msg := range msgs {
+		opts = append(opts, tonconnect.WithMessage(msg))
+	}
+	tx, err := tonconnect.NewTransaction(opts...)
+	if err != nil {
+		log.Error("Error creating transaction", err)
+		return nil, err
+	}
+
+	return s.sendIntent(intent, session, tx)
+}
+
+// TransferMessages создает общий intent переводов и сообщения транзакции для подписи в боте или в Mini App.
+// Payload всех переводов получает id intent, основная операция - последний перевод.
+// Без intent переводы не сопоставить друг с другом, поэтому транзакция не собирается
+func (s *TonConnectService) TransferMessages(telegramId uint64, receiverAddr, senderAddr string, transfers []Transfer) (*models.TxIntent, []tonconnect.Message, error) {
+	if s.intents == nil {
+		return nil, nil, errors.New("tx intents are not tracked")
+	}
+	var paid []models.Payload
+	for _, t := range transfers {
+		if t.Paid {
+			paid = append(paid, *t.Payload)
+		}
+	}
+	intent, err := s.intents.create(telegramId, transfers[len(transfers)-1].Payload, len(transfers), paid...)
+	if err != nil {
+		log.Error("Error creating tx intent", err)
+		return nil, nil, err
+	}
+
+	msgs := make([]tonconnect.Message, 0, len(transfers))
+	for _, t := range transfers {
+		if t.Paid {
+			continue
+		}
+		t.Payload.IntentId = intent.Id.Int64
+
+		var msg *tonconnect.Message
+		if t.JettonWallet == "" {
+			msg, err = s.TonTransferMessage(receiverAddr, t.Amount, t.Payload)
+		} else {
+			msg, err = s.JettonTransferMessage(t.JettonWallet, receiverAddr, senderAddr, t.Amount, t.Payload)
+		}
+		if err != nil {
+			if err := s.intents.Failed(intent, err); err != nil {
+				log.Error("Error updating tx intent", err)
+			}
+			return nil, nil, err
+		}
+		msgs = append(msgs, *msg)
+	}
+	return intent, msgs, nil
+}
+
+// ReceivePart засчитывает поступивший перевод транзакции из нескольких сообщений.
+// Возвращает intent и true, когда поступили все переводы
+func (s *TxIntentService) ReceivePart(payload *models.Payload) (*models.TxIntent, bool, error) {
+	partsMu.Lock()
+	defer partsMu.Unlock()
+
+	intent, err := s.rep.FindById(uint64(payload.IntentId))
+	if err != nil {
+		return nil, false, ErrIntentNotFound
+	}
+	if intent.Status == models.TX_INTENT_COMPLETED || intent.Status == models.TX_INTENT_PARTIAL {
+		return intent, false, ErrIntentClosed
+	}
+	// все переводы транзакции несут данные основной операции, сохраненные ботом при создании intent
+	var main models.Payload
+	if err := json.Unmarshal([]byte(intent.Payload), &main); err != nil || main.Payload != payload.Payload {
+		return nil, false, ErrIntentNotFound
+	}
+
+	received := ReceivedParts(intent)
+	for _, p := range received {
+		// повторное уведомление о том же переводе
+		if p.OperationType == payload.OperationType {
+			return intent, false, nil
+		}
+	}
+	received = append(received, *payload)
+
+	data, err := json.Marshal(received)
+	if err != nil {
+		return nil, false, err
+	}
+	intent.Received = string(data)
+
+	complete := len(received) >= intent.Parts
+	if complete {
+		intent.Status = models.TX_INTENT_COMPLETED
+	}
+	intent.UpdatedAt = time.Now()
+	if err := s.rep.UpdateReceived(intent); err != nil {
+		return nil, false, err
+	}
+	return intent, complete, nil
+}
+
+// expireParts закрывает транзакции из нескольких сообщений, переводы которых не поступили за CombinedPartsTTL.
+// Поступила часть переводов - статус partial, бот возвращает или доплачивает их
+func (s *TxIntentService) expireParts(now time.Time) {
+	intents, err := s.rep.FindIncompleteParts(now.Add(-CombinedPartsTTL-24*time.Hour), now.Add(-CombinedPartsTTL))
+	if err != nil {
+		return
+	}
+
+	partsMu.Lock()
+	defer partsMu.Unlock()
+
+	for i := range intents {
+		intent := &intents[i]
+		received := len(ReceivedParts(intent))
+		switch {
+		case received >= intent.Parts:
+			continue
+		case received == 0 && intent.Status == models.TX_INTENT_PENDING:
+			// транзакцию из Mini App не подписали: сообщать не о чем
+			if err := s.update(intent, models.TX_INTENT_FAILED, "transaction was not signed"); err != nil {
+				log.Error("Error closing tx intent ", intent.Id.Int64, ": ", err)
+			}
+			continue
+		case received > 0:
+			intent.Status = models.TX_INTENT_PARTIAL
+			intent.Error = "not all transfers arrived"
+		case intent.Status == models.TX_INTENT_FAILED:
+			// о невыполненной транзакции уже сообщили
+			continue
+		default:
+			intent.Status = models.TX_INTENT_FAILED
+			intent.Error = "transfers did not arrive"
+		}
+
+		intent.UpdatedAt = now
+		if err := s.rep.UpdateReceived(intent); err != nil {
+			log.Error("Error closing tx intent ", intent.Id.Int64, ": ", err)
+			continue
+		}
+		s.notify(intent)
+	}
+}
+
+// ReceivedParts payload поступивших переводов транзакции
+func ReceivedParts(intent *models.TxIntent) []models.Payload {
+	var received []models.Payload
+	if intent.Received == "" {
+		return received
+	}
+	if err := json.Unmarshal([]byte(intent.Received), &received); err != nil {
+		log.Error("Error parsing received parts of tx intent ", intent.Id.Int64, ": ", err)
+	}
+	return received
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"tonclient/internal/models"
+)
+
+var (
+	ErrMemoNotFound = errors.New("memo is unknown, expired or already paid")
+	ErrMemoAmount   = errors.New("transfer amount is less than expected")
+)
+
+const (
+	// MemoDepositTTL сколько ждать перевод с кодом
+	MemoDepositTTL = 24 * time.Hour
+
+	memoPrefix   = "NS-"
+	memoAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	memoLength   = 6
+)
+
+var memoMu sync.Mutex
+
+// CreateMemo выдает код для переводов напрямую в казну. Каждая часть - отдельный перевод,
+// части в одном jetton объединяются в один перевод. Последняя часть - основная операция,
+// она выполняется, когда оплачены все части
+func (s *TxIntentService) CreateMemo(telegramId uint64, parts ...*models.Payload) ([]models.TxIntent, error) {
+	memo, err := s.newMemo()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	intents := make([]models.TxIntent, 0, len(parts))
+	for _, part := range parts {
+		units, err := s.amountUnits(part.JettonMaster, part.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+
+		merged := false
+		for i := range intents {
+			if intents[i].JettonMaster == part.JettonMaster {
+				intents[i].OperationType = part.OperationType
+				intents[i].Payload = string(data)
+				intents[i].AmountUnits = new(big.Int).Add(units, mustUnits(intents[i].AmountUnits)).String()
+				merged = true
+				break
+			}
+		}
+		if merged {
+			continue
+		}
+
+		treasury, err := s.treasuryWallet(part.JettonMaster)
+		if err != nil {
+			return nil, err
+		}
+		intents = append(intents, models.TxIntent{
+			TelegramId:     telegramId,
+			OperationType:  part.OperationType,
+			Payload:        string(data),
+			Status:         models.TX_INTENT_PENDING,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			Memo:           memo,
+			JettonMaster:   part.JettonMaster,
+			TreasuryWallet: treasury,
+			AmountUnits:    units.String(),
+			ExpiresAt:      sql.NullTime{Time: now.Add(MemoDepositTTL), Valid: true},
+			Parts:          1,
+			Received:       "[]",
+		})
+	}
+
+	for i := range intents {
+		if err := s.rep.Save(&intents[i]); err != nil {
+			return nil, err
+		}
+	}
+	return intents, nil
+}
+
+// MatchMemo засчитывает перевод с кодом в комментарии. Возвращает основную операцию
+// и true, когда оплачены все переводы с этим кодом
+func (s *TxIntentService) MatchMemo(tr *models.SubmitTransaction) (*models.TxIntent, bool, error) {
+	memoMu.Lock()
+	defer memoMu.Unlock()
+
+	memo := NormalizeMemo(tr.Comment)
+	if memo == "" {
+		return nil, false, ErrMemoNotFound
+	}
+
+	group, err := s.rep.FindByMemo(memo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var intent *models.TxIntent
+	for i := range group {
+		if group[i].TreasuryWallet == tr.TreasuryWallet && group[i].Status == models.TX_INTENT_PENDING {
+			intent = &group[i]
+		}
+	}
+	if intent == nil || (intent.ExpiresAt.Valid && time.Now().After(intent.ExpiresAt.Time)) {
+		return nil, false, ErrMemoNotFound
+	}
+
+	received, ok := new(big.Int).SetString(tr.Units, 10)
+	if !ok || received.Cmp(mustUnits(intent.AmountUnits)) < 0 {
+		return intent, false, ErrMemoAmount
+	}
+
+	intent.SenderAddr = tr.SenderAddr
+	intent.TxHash = tr.TxHash
+	if err := s.update(intent, models.TX_INTENT_CONFIRMED, ""); err != nil {
+		return nil, false, err
+	}
+
+	for i := range group {
+		if group[i].Status != models.TX_INTENT_CONFIRMED {
+			return intent, false, nil
+		}
+	}
+	return &group[len(group)-1], true, nil
+}
+
+// expireMemo закрывает неоплаченные коды, о каждом коде сообщает один раз
+func (s *TxIntentService) expireMemo(now time.Time) {
+	intents, err := s.rep.FindExpiredMemo(now)
+	if err != nil {
+		return
+	}
+
+	notified := make(map[string]bool)
+	for i := range intents {
+		intent := &intents[i]
+		if err := s.update(intent, models.TX_INTENT_FAILED, "memo expired"); err != nil {
+			continue
+		}
+		if !notified[intent.Memo] {
+			notified[intent.Memo] = true
+			s.notify(intent)
+		}
+	}
+}
+
+func (s *TxIntentService) newMemo() (string, error) {
+	for range 5 {
+		memo, err := GenerateMemo()
+		if err != nil {
+			return "", err
+		}
+		existing, err := s.rep.FindByMemo(memo)
+		if err != nil {
+			return "", err
+		}
+		if len(existing) == 0 {
+			return memo, nil
+		}
+	}
+	return "", errors.New("failed to generate unique memo")
+}
+
+// treasuryWallet адрес (raw), на который придет перевод: кошелек казны для TON или его jetton-кошелек
+func (s *TxIntentService) treasuryWallet(jettonMaster string) (string, error) {
+	admin := s.aws.GetAdminWalletAddr()
+	if jettonMaster == "" {
+		return admin.StringRaw(), nil
+	}
+	jw, err := s.aws.TokenWalletAddress(jettonMaster, admin)
+	if err != nil {
+		return "", err
+	}
+	return jw.Address().StringRaw(), nil
+}
+
+func (s *TxIntentService) amountUnits(jettonMaster string, amount float64) (*big.Int, error) {
+	decimals := 9
+	if jettonMaster != "" {
+		data, err := s.aws.DataJetton(jettonMaster)
+		if err != nil {
+			return nil, err
+		}
+		decimals = data.Decimals
+	}
+	return new(big.Int).SetUint64(uint64(math.Round(amount * math.Pow10(decimals)))), nil
+}
+
+// GenerateMemo короткий код без похожих символов (0/O, 1/I), например NS-7KQ2MX
+func GenerateMemo() (string, error) {
+	data := make([]byte, memoLength)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(memoPrefix)
+	for _, v := range data {
+		b.WriteByte(memoAlphabet[int(v)%len(memoAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// NormalizeMemo код из комментария перевода: регистр и пробелы вокруг не важны
+func NormalizeMemo(comment string) string {
+	memo := strings.ToUpper(strings.TrimSpace(comment))
+	if !strings.HasPrefix(memo, memoPrefix) || len(memo) != len(memoPrefix)+memoLength {
+		return ""
+	}
+	return memo
+}
+
+// TransferLink ссылка ton://transfer на перевод TON или jetton с комментарием
+func TransferLink(receiver, jettonMaster, units, memo string) string {
+	return "ton://transfer/" + receiver + "?" + transferQuery(jettonMaster, units, memo)
+}
+
+// TonkeeperTransferLink та же ссылка для кнопки: Telegram не открывает схему ton:// из кнопок
+func TonkeeperTransferLink(receiver, jettonMaster, units, memo string) string {
+	return "https://app.tonkeeper.com/transfer/" + receiver + "?" + transferQuery(jettonMaster, units, memo)
+}
+
+func transferQuery(jettonMaster, units, memo string) string {
+	q := url.Values{}
+	if jettonMaster != "" {
+		q.Set("jetton", jettonMaster)
+	}
+	q.Set("amount", units)
+	q.Set("text", memo)
+	return q.Encode()
+}
+
+func mustUnits(units string) *big.Int {
+	v, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
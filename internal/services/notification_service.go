@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"tonclient/internal/models"
+	"tonclient/internal/repositories"
+)
+
+var ErrUnknownNotificationEvent = errors.New("unknown notification event")
+
+// NotificationEvents события уведомлений в порядке экрана настроек
+var NotificationEvents = []string{
+	models.NOTIFY_STAKE_CREATED,
+	models.NOTIFY_DAILY_ACCRUAL,
+	models.NOTIFY_MATURITY_SOON,
+	models.NOTIFY_MATURED,
+	models.NOTIFY_INSURANCE,
+	models.NOTIFY_PRICE_ALERT,
+	models.NOTIFY_POOL_RESERVE,
+	models.NOTIFY_POOL_PAUSED,
+}
+
+// NotificationDefault включено ли событие, пока пользователь не менял настройку.
+// Ежедневные начисления приходят слишком часто и по умолчанию отключены
+func NotificationDefault(event string) bool {
+	return event != models.NOTIFY_DAILY_ACCRUAL
+}
+
+// NotificationService настройки уведомлений пользователей
+type NotificationService struct {
+	repo *repositories.NotificationSettingRepository
+}
+
+func NewNotificationService(repo *repositories.NotificationSettingRepository) *NotificationService {
+	return &NotificationService{
+		repo: repo,
+	}
+}
+
+// Settings состояние всех событий для пользователя с учетом значений по умолчанию
+func (s *NotificationService) Settings(userId uint64) (map[string]bool, error) {
+	saved, err := s.repo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]bool, len(NotificationEvents))
+	for _, event := range NotificationEvents {
+		res[event] = NotificationDefault(event)
+	}
+	for _, setting := range saved {
+		if _, ok := res[setting.Event]; ok {
+			res[setting.Event] = setting.Enabled
+		}
+	}
+	return res, nil
+}
+
+func (s *NotificationService) IsEnabled(userId uint64, event string) bool {
+	setting, err := s.repo.FindByUserIdAndEvent(userId, event)
+	if err != nil {
+		return NotificationDefault(event)
+	}
+	return setting.Enabled
+}
+
+// Toggle переключает событие и возвращает новое состояние
+func (s *NotificationService) Toggle(userId uint64, event string) (bool, error) {
+	if !slices.Contains(NotificationEvents, event) {
+		return false, ErrUnknownNotificationEvent
+	}
+	setting := &models.NotificationSetting{
+		UserId:  userId,
+		Event:   event,
+		Enabled: !s.IsEnabled(userId, event),
+	}
+	if err := s.repo.Save(setting); err != nil {
+		return false, err
+	}
+	return setting.Enabled, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"time"
+	"tonclient/internal/i18n"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 )
@@ -15,7 +16,7 @@ func NewOperationService(rep *repositories.OperationRepository) *OperationServic
 }
 
 func (s *OperationService) Create(userId uint64, numOperation int, description string) (*models.Operation, error) {
-	opName := OperationName(numOperation)
+	opName := OperationName(i18n.Default, numOperation)
 	op := models.Operation{
 		UserId:       userId,
 		NumOperation: numOperation,
@@ -58,35 +59,42 @@ func (s *OperationService) CountByUserId(userId uint64) int {
 	return s.rep.CountByUserId(userId)
 }
 
-func OperationName(numOperation int) string {
+// OperationName название операции на языке lang. В историю сохраняется название на языке по умолчанию
+func OperationName(lang string, numOperation int) string {
 	switch numOperation {
 	case models.OP_ADMIN_CREATE_POOL:
-		return "Создание пула"
+		return i18n.T(lang, "Создание пула")
 	case models.OP_ADMIN_ADD_RESERVE:
-		return "Пополнение резерва"
+		return i18n.T(lang, "Пополнение резерва")
 	case models.OP_PAY_COMMISION:
-		return "Оплата комиссии за пул"
+		return i18n.T(lang, "Оплата комиссии за пул")
 	case models.OP_STAKE:
-		return "Создание стейка"
+		return i18n.T(lang, "Создание стейка")
 	case models.OP_ADMIN_CLOSE_POOL:
-		return "Закрытие пула"
+		return i18n.T(lang, "Закрытие пула")
 	case models.OP_CLAIM_INSURANCE:
-		return "Получение страховки"
+		return i18n.T(lang, "Получение страховки")
 	case models.OP_CLAIM:
-		return "Получение награды"
+		return i18n.T(lang, "Получение награды")
 	case models.OP_ADMIN_OPEN_POOL:
-		return "Открытие пула"
+		return i18n.T(lang, "Открытие пула")
 	case models.OP_RETURNING_TOKENS:
-		return "Возврат токенов"
+		return i18n.T(lang, "Возврат токенов")
 	case models.OP_CLAIM_RESERVE:
-		return "Снятие резерва"
+		return i18n.T(lang, "Снятие резерва")
 	case models.OP_PAID_COMMISSION_STAKE:
-		return "Оплата комиссии за стейк"
+		return i18n.T(lang, "Оплата комиссии за стейк")
 	case models.OP_EARLY_CLOSOURE:
-		return "Досрочное закрытие стейка"
+		return i18n.T(lang, "Досрочное закрытие стейка")
 	case models.OP_DELETE_POOL:
-		return "Удаление пула"
+		return i18n.T(lang, "Удаление пула")
+	case models.OP_ADD_INSURANCE_RESERVE:
+		return i18n.T(lang, "Пополнение страхового резерва")
+	case models.OP_ROLLOVER_STAKE:
+		return i18n.T(lang, "Перевыпуск стейка")
+	case models.OP_REFERRAL_PAYOUT:
+		return i18n.T(lang, "Выплата реферальных наград")
 	default:
-		return "Неизвестная команда"
+		return i18n.T(lang, "Неизвестная команда")
 	}
 }
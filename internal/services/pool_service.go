@@ -2,24 +2,36 @@ package services
 
 import (
 	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
+
+	"github.com/xssnick/tonutils-go/address"
 )
 
 type PoolService struct {
-	poolRepository    *repositories.PoolRepository
-	tonConnectService *TonConnectService
-	UserService       *UserService
+	poolRepository          *repositories.PoolRepository
+	rewardTierRepository    *repositories.RewardTierRepository
+	poolWhitelistRepository *repositories.PoolWhitelistRepository
+	tonConnectService       *TonConnectService
+	UserService             *UserService
 }
 
 func NewPoolService(
 	poolRepository *repositories.PoolRepository,
+	rewardTierRepository *repositories.RewardTierRepository,
+	poolWhitelistRepository *repositories.PoolWhitelistRepository,
 	userService *UserService,
 ) *PoolService {
 
 	return &PoolService{
-		poolRepository: poolRepository,
-		UserService:    userService,
+		poolRepository:          poolRepository,
+		rewardTierRepository:    rewardTierRepository,
+		poolWhitelistRepository: poolWhitelistRepository,
+		UserService:             userService,
 	}
 }
 
@@ -45,6 +57,10 @@ func (s *PoolService) CreatePool(pool *models.Pool) (*models.Pool, error) {
 		return nil, errors.New("insurance_coating must be greater than zero")
 	}
 
+	if pool.InsuranceAsset == "" {
+		pool.InsuranceAsset = models.INSURANCE_ASSET_JETTON
+	}
+
 	if err := s.poolRepository.Save(pool); err != nil {
 		return nil, err
 	}
@@ -77,6 +93,46 @@ func (s *PoolService) AddReserve(poolId uint64, reserve float64) (newReserve flo
 	return pool.Reserve, nil
 }
 
+func (s *PoolService) AddInsuranceReserve(poolId uint64, reserve float64) (newReserve float64, err error) {
+	pool := s.poolRepository.FindById(poolId)
+	if pool == nil {
+		return 0, errors.New("pool not found")
+	}
+
+	if pool.InsuranceAsset == models.INSURANCE_ASSET_JETTON {
+		return 0, errors.New("pool does not use a separate insurance reserve")
+	}
+
+	pool.InsuranceReserve += reserve
+	if err = s.poolRepository.Update(pool); err != nil {
+		return 0, err
+	}
+
+	return pool.InsuranceReserve, nil
+}
+
+// SetInsuranceAsset меняет валюту выплаты компенсации. Сменить валюту можно только пока страховой резерв пуст,
+// иначе уже внесенные средства окажутся учтены не в той валюте.
+func (s *PoolService) SetInsuranceAsset(poolId uint64, asset string) error {
+	pool := s.poolRepository.FindById(poolId)
+	if pool == nil {
+		return errors.New("pool not found")
+	}
+
+	switch asset {
+	case models.INSURANCE_ASSET_JETTON, models.INSURANCE_ASSET_USDT, models.INSURANCE_ASSET_TON:
+	default:
+		return errors.New("unknown insurance asset")
+	}
+
+	if pool.InsuranceReserve > 0 {
+		return errors.New("insurance reserve must be empty")
+	}
+
+	pool.InsuranceAsset = asset
+	return s.poolRepository.Update(pool)
+}
+
 func (s *PoolService) SetCommissionPaid(poolId uint64, b bool) error {
 	pool := s.poolRepository.FindById(poolId)
 	if pool == nil {
@@ -138,3 +194,163 @@ func (s *PoolService) GetPoolsByUserIdLimit(userId uint64, offset, limit int) *[
 func (s *PoolService) Update(pool *models.Pool) error {
 	return s.poolRepository.Update(pool)
 }
+
+func (s *PoolService) GetRewardTiers(poolId uint64) []models.RewardTier {
+	return s.rewardTierRepository.FindByPoolId(poolId)
+}
+
+func (s *PoolService) GetRewardTierById(id uint64) (*models.RewardTier, error) {
+	tier := s.rewardTierRepository.FindById(id)
+	if tier == nil {
+		return nil, errors.New("reward tier not found")
+	}
+	return tier, nil
+}
+
+func (s *PoolService) AddRewardTier(tier *models.RewardTier) error {
+	if tier.Period < 1 {
+		return errors.New("period must be greater than zero")
+	}
+	if tier.Reward <= 0 {
+		return errors.New("reward must be greater than zero")
+	}
+	if tier.MinAmount < 0 {
+		return errors.New("min amount must not be negative")
+	}
+	return s.rewardTierRepository.Save(tier)
+}
+
+func (s *PoolService) DeleteRewardTier(id uint64) error {
+	return s.rewardTierRepository.DeleteById(id)
+}
+
+// ResolveReward ставка в % в день для стейка amount на period дней, открываемого в момент now.
+// Из подходящих тарифов действует самый поздно вступивший в силу, при равенстве - с большей мин. суммой.
+// Без подходящего тарифа на базовый срок действует базовая ставка пула.
+func (s *PoolService) ResolveReward(pool *models.Pool, tiers []models.RewardTier, amount float64, period uint, now time.Time) (float64, error) {
+	if best := matchTier(tiers, amount, period, now); best != nil {
+		return best.Reward, nil
+	}
+	if period == pool.Period {
+		return pool.Reward, nil
+	}
+	return 0, errors.New("reward tier not found")
+}
+
+var ErrStakeTerms = errors.New("stake terms do not match pool reward tiers")
+
+// CheckStakeTerms сверяет срок и ставку стейка из payload депозита с тарифами пула на момент создания стейка
+// и пересчитывает дату окончания. deposit - пришедшая в казну сумма, по ней выбирается тариф
+func (s *PoolService) CheckStakeTerms(pool *models.Pool, tiers []models.RewardTier, stake *models.Stake, deposit float64, now time.Time) error {
+	// депозит приходит не позже, чем истекает код перевода
+	if stake.StartDate.After(now) || now.Sub(stake.StartDate) > MemoDepositTTL {
+		return ErrStakeTerms
+	}
+
+	period := stake.Period
+	if period == 0 {
+		period = pool.Period
+	}
+	reward, err := s.ResolveReward(pool, tiers, deposit, period, stake.StartDate)
+	if err != nil || math.Abs(reward-stake.Reward) > 1e-9 {
+		return ErrStakeTerms
+	}
+
+	stake.Period = period
+	stake.Reward = reward
+	stake.EndDate = stake.StartDate.Add(time.Duration(period) * time.Hour * 24)
+	return nil
+}
+
+// matchTier тариф, действующий для стейка amount на period дней в момент now
+func matchTier(tiers []models.RewardTier, amount float64, period uint, now time.Time) *models.RewardTier {
+	var best *models.RewardTier
+	for i := range tiers {
+		t := &tiers[i]
+		if t.Period != period || t.MinAmount > amount {
+			continue
+		}
+		if t.StartsAt.Valid && t.StartsAt.Time.After(now) {
+			continue
+		}
+		if best == nil ||
+			t.StartsAt.Time.After(best.StartsAt.Time) ||
+			(t.StartsAt.Time.Equal(best.StartsAt.Time) && t.MinAmount > best.MinAmount) {
+			best = t
+		}
+	}
+	return best
+}
+
+func (s *PoolService) GetWhitelist(poolId uint64) []models.PoolWhitelist {
+	return s.poolWhitelistRepository.FindByPoolId(poolId)
+}
+
+func (s *PoolService) GetWhitelistById(id uint64) (*models.PoolWhitelist, error) {
+	entry := s.poolWhitelistRepository.FindById(id)
+	if entry == nil {
+		return nil, errors.New("whitelist entry not found")
+	}
+	return entry, nil
+}
+
+func (s *PoolService) AddWhitelist(entry *models.PoolWhitelist) error {
+	return s.poolWhitelistRepository.Save(entry)
+}
+
+func (s *PoolService) DeleteWhitelist(id uint64) error {
+	return s.poolWhitelistRepository.DeleteById(id)
+}
+
+// IsWhitelisted проверяет, может ли пользователь стейкать в пул с белым списком.
+// Пользователь проходит по telegram username/id или по адресу привязанного кошелька.
+func (s *PoolService) IsWhitelisted(pool *models.Pool, tg *models.Telegram, w *models.WalletTon) bool {
+	if !pool.IsWhitelist {
+		return true
+	}
+
+	poolId := uint64(pool.Id.Int64)
+	if tg != nil {
+		values := []string{strconv.FormatUint(tg.TelegramId, 10)}
+		if tg.Username != "" {
+			values = append(values, strings.ToLower(strings.TrimPrefix(tg.Username, "@")))
+		}
+		if s.poolWhitelistRepository.Exists(poolId, models.WHITELIST_TELEGRAM, values) {
+			return true
+		}
+	}
+
+	if w != nil {
+		addr, err := address.ParseAddr(w.Addr)
+		if err == nil && s.poolWhitelistRepository.Exists(poolId, models.WHITELIST_WALLET, []string{addr.StringRaw()}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseWhitelistEntry разбирает запись белого списка: @username, telegram id или адрес кошелька
+func ParseWhitelistEntry(text string) (*models.PoolWhitelist, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("empty whitelist entry")
+	}
+
+	if addr, err := address.ParseAddr(text); err == nil {
+		return &models.PoolWhitelist{Kind: models.WHITELIST_WALLET, Value: addr.StringRaw()}, nil
+	}
+	if addr, err := address.ParseRawAddr(text); err == nil {
+		return &models.PoolWhitelist{Kind: models.WHITELIST_WALLET, Value: addr.StringRaw()}, nil
+	}
+
+	if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return &models.PoolWhitelist{Kind: models.WHITELIST_TELEGRAM, Value: text}, nil
+	}
+
+	if strings.HasPrefix(text, "@") && len(text) > 1 && !strings.ContainsAny(text, " :") {
+		return &models.PoolWhitelist{Kind: models.WHITELIST_TELEGRAM, Value: strings.ToLower(text[1:])}, nil
+	}
+
+	return nil, errors.New("invalid whitelist entry")
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"tonclient/internal/models"
+)
+
+var ErrUnexpectedWallet = errors.New("transfer did not come to the treasury wallet of the jetton")
+
+// ReceivedAmount сумма перевода tr в jetton jettonMaster (пустой - TON). Перевод засчитывается, только если
+// пришел на кошелек казны: для jetton уведомление должно прийти с jetton-кошелька казны этого jetton
+func (s *TxIntentService) ReceivedAmount(tr *models.SubmitTransaction, jettonMaster string) (float64, error) {
+	treasury, err := s.treasuryWallet(jettonMaster)
+	if err != nil {
+		return 0, err
+	}
+	if tr.TreasuryWallet == "" || tr.TreasuryWallet != treasury {
+		return 0, ErrUnexpectedWallet
+	}
+
+	units, ok := new(big.Int).SetString(tr.Units, 10)
+	if !ok || units.Sign() <= 0 {
+		return 0, errors.New("invalid transfer amount")
+	}
+
+	decimals := 9
+	if jettonMaster != "" {
+		data, err := s.aws.DataJetton(jettonMaster)
+		if err != nil {
+			return 0, err
+		}
+		decimals = data.Decimals
+	}
+	amount, _ := new(big.Float).Quo(new(big.Float).SetInt(units), big.NewFloat(math.Pow10(decimals))).Float64()
+	return amount, nil
+}
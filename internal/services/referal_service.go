@@ -1,20 +1,197 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
+	"math"
+	"os"
+	"time"
+	"tonclient/internal/config"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 )
 
+var ErrReferralPrice = errors.New("referral reward price is unknown")
+
 type ReferalService struct {
 	repo *repositories.ReferralRepository
+	us   *UserService
+	ws   *WalletTonService
 }
 
-func NewReferalService(repo *repositories.ReferralRepository) *ReferalService {
+func NewReferalService(repo *repositories.ReferralRepository, us *UserService, ws *WalletTonService) *ReferalService {
 	return &ReferalService{
 		repo: repo,
+		us:   us,
+		ws:   ws,
 	}
 }
 
 func (s *ReferalService) Save(ref *models.Referral) error {
+	if ref.IdemKey == "" {
+		ref.IdemKey = ReferralIdemKey(models.REFERRAL_MODE_EVERY, uint64(ref.ReferralUserId.Int64), ref.StakeId.Int64, ref.Level)
+	}
 	return s.repo.Save(ref)
 }
+
+// Accrue начисляет на реферальный баланс награды всех уровней за стейк, если стейк и кошельки
+// приглашенного проходят проверки. price - цена в $ по адресу jetton, нужна, если база награды не в токенах платформы
+func (s *ReferalService) Accrue(stake *models.Stake, pool *models.Pool, price func(addr string) float64) ([]models.Referral, error) {
+	cfg := config.LoadReferralConfig()
+	if len(cfg.Levels) == 0 {
+		return nil, nil
+	}
+
+	staker, err := s.us.GetById(stake.UserId)
+	if err != nil {
+		return nil, err
+	}
+	chain := s.referrers(staker, len(cfg.Levels))
+	if len(chain) == 0 {
+		return nil, nil
+	}
+
+	value := 0.
+	if cfg.MinStake > 0 {
+		if value, err = ReferralBaseAmount(stake, pool, models.REFERRAL_BASE_STAKE, price); err != nil {
+			return nil, err
+		}
+	}
+	if err := CheckReferralStake(stake, pool, value, cfg, time.Now()); err != nil {
+		return nil, err
+	}
+	owners, err := s.ws.AddrOwners(s.ws.UserAddrs(stake.UserId), stake.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckReferralWallets(owners, chain); err != nil {
+		return nil, err
+	}
+
+	base, err := ReferralBaseAmount(stake, pool, cfg.Base, price)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := ReferralRewards(stake, chain, cfg.Levels, cfg.Base, cfg.Mode, base, time.Now())
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if _, err := s.repo.SaveAccruals(refs); err != nil {
+		return nil, err
+	}
+
+	// уже начисленные по ключу идемпотентности награды не сохраняются повторно
+	saved := make([]models.Referral, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Id.Valid {
+			saved = append(saved, ref)
+		}
+	}
+	return saved, nil
+}
+
+// referrers цепочка пригласивших: первый - пригласивший пользователя, затем его пригласивший и т.д.
+func (s *ReferalService) referrers(u *models.User, levels int) []models.User {
+	chain := make([]models.User, 0, levels)
+	seen := map[int64]bool{u.Id.Int64: true}
+	for len(chain) < levels && u.RefererId.Valid && u.RefererId.Int64 != 0 {
+		// referer_id хранит telegram id пригласившего
+		ref, err := s.us.GetByTelegramChatId(uint64(u.RefererId.Int64))
+		if err != nil || seen[ref.Id.Int64] {
+			break
+		}
+		seen[ref.Id.Int64] = true
+		chain = append(chain, *ref)
+		u = ref
+	}
+	return chain
+}
+
+func (s *ReferalService) Stats(referrerId uint64) (*models.ReferralStats, error) {
+	return s.repo.Stats(referrerId)
+}
+
+// DueReferrers пригласившие, баланс которых достиг порога выплаты
+func (s *ReferalService) DueReferrers() ([]uint64, error) {
+	return s.repo.FindDueReferrers(config.LoadReferralConfig().Threshold)
+}
+
+// ReservePayout забирает с баланса все невыплаченные начисления для выплаты одним переводом
+func (s *ReferalService) ReservePayout(referrerId uint64) ([]models.Referral, float64, error) {
+	refs, err := s.repo.ReserveUnpaid(referrerId, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	var amount float64
+	for _, ref := range refs {
+		amount += ref.RewardAmount
+	}
+	return refs, amount, nil
+}
+
+// ReleasePayout возвращает начисления на баланс, если выплата не отправлена
+func (s *ReferalService) ReleasePayout(refs []models.Referral) error {
+	return s.repo.ReleaseReserved(referralIds(refs))
+}
+
+func (s *ReferalService) CompletePayout(refs []models.Referral, hash string) error {
+	return s.repo.SetPayoutHash(referralIds(refs), hash)
+}
+
+// ReferralBaseAmount сумма в токенах платформы, от которой считается награда: стейк или комиссия за него
+func ReferralBaseAmount(stake *models.Stake, pool *models.Pool, base string, price func(addr string) float64) (float64, error) {
+	platform := os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
+
+	amount, master := stake.Amount, pool.JettonMaster
+	if base == models.REFERRAL_BASE_COMMISSION {
+		amount = stake.Commission
+		master = CommissionJettonMaster(pool, stake.CommissionAsset)
+		if stake.CommissionAsset == models.COMMISSION_ASSET_TON {
+			master = config.TON_NATIVE_ADDR
+		}
+	}
+	if amount <= 0 || master == platform {
+		return amount, nil
+	}
+
+	assetPrice, platformPrice := price(master), price(platform)
+	if assetPrice <= 0 || platformPrice <= 0 {
+		return 0, ErrReferralPrice
+	}
+	return amount * assetPrice / platformPrice, nil
+}
+
+// ReferralRewards начисления по уровням: chain[i] получает levels[i] % от base
+func ReferralRewards(stake *models.Stake, chain []models.User, levels []float64, base, mode string, amount float64, now time.Time) []models.Referral {
+	refs := make([]models.Referral, 0, len(chain))
+	for i, ref := range chain {
+		if i >= len(levels) {
+			break
+		}
+		reward := math.Round(amount*levels[i]/100*1e9) / 1e9
+		if reward <= 0 {
+			continue
+		}
+		refs = append(refs, models.Referral{
+			ReferrerUserId: ref.Id,
+			ReferralUserId: sql.NullInt64{Int64: int64(stake.UserId), Valid: true},
+			RewardAmount:   reward,
+			StakeId:        stake.Id,
+			Level:          i + 1,
+			Base:           base,
+			BaseAmount:     amount,
+			CreatedAt:      now,
+			IdemKey:        ReferralIdemKey(mode, stake.UserId, stake.Id.Int64, i+1),
+		})
+	}
+	return refs
+}
+
+func referralIds(refs []models.Referral) []int64 {
+	ids := make([]int64, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.Id.Int64)
+	}
+	return ids
+}
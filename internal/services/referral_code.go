@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"tonclient/internal/models"
+	"tonclient/internal/repositories"
+)
+
+const (
+	// без похожих символов 0/o, 1/l/i, чтобы код можно было переписать вручную
+	referralCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	ReferralCodeLength   = 8
+
+	referralCodeAttempts = 5
+)
+
+// GenerateReferralCode случайный код для реферальной ссылки, не связанный с id пользователя
+func GenerateReferralCode() (string, error) {
+	data := make([]byte, ReferralCodeLength)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, ReferralCodeLength)
+	for i, b := range data {
+		// 256 % 31 дает незначительный перекос, для кода приглашения это неважно
+		code[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// IsReferralCode проверяет формат кода из параметра /start
+func IsReferralCode(code string) bool {
+	if len(code) != ReferralCodeLength {
+		return false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(referralCodeAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReferralCode код пользователя, создается при первом обращении
+func (s *UserService) ReferralCode(user *models.User) (string, error) {
+	if user.ReferralCode.Valid && user.ReferralCode.String != "" {
+		return user.ReferralCode.String, nil
+	}
+
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, err := GenerateReferralCode()
+		if err != nil {
+			return "", err
+		}
+		code, err = s.userRepo.SetReferralCode(uint64(user.Id.Int64), code)
+		if errors.Is(err, repositories.ErrReferralCodeTaken) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		user.ReferralCode.String, user.ReferralCode.Valid = code, true
+		return code, nil
+	}
+	return "", repositories.ErrReferralCodeTaken
+}
+
+func (s *UserService) GetByReferralCode(code string) (*models.User, error) {
+	if !IsReferralCode(code) {
+		return nil, errors.New("user not found")
+	}
+	user := s.userRepo.FindByReferralCode(code)
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// GetReferralsInfo страница приглашенных пользователем и число всех приглашенных
+func (s *UserService) GetReferralsInfo(user *models.User, telegramId uint64, offset, limit int) ([]models.ReferralInfo, int, error) {
+	refs, err := s.userRepo.FindReferralsInfo(telegramId, uint64(user.Id.Int64), offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return refs, s.userRepo.CountUserReferal(telegramId), nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+)
+
+var (
+	ErrReferralMinStake     = errors.New("stake is below referral minimum")
+	ErrReferralHoldTime     = errors.New("stake was held less than referral minimum")
+	ErrReferralEarlyClose   = errors.New("stake was closed before the end of the period")
+	ErrReferralSameWallet   = errors.New("referral shares a wallet with referrer")
+	ErrReferralSharedWallet = errors.New("referral wallet is used by another account")
+)
+
+// допуск между планируемой датой окончания и фактическим закрытием стейка планировщиком
+const referralCloseTolerance = time.Minute
+
+// CheckReferralStake условия начисления по самому стейку: сумма в токенах платформы value,
+// срок удержания и закрытие по окончании срока, а не досрочным выходом
+func CheckReferralStake(stake *models.Stake, pool *models.Pool, value float64, cfg *config.ReferralConfig, now time.Time) error {
+	if cfg.MinStake > 0 && value < cfg.MinStake {
+		return ErrReferralMinStake
+	}
+
+	closeAt := stake.CloseDate
+	if closeAt.IsZero() {
+		closeAt = now
+	}
+	period := stake.Period
+	if period == 0 {
+		period = pool.Period
+	}
+	end := stake.StartDate.Add(time.Duration(period) * 24 * time.Hour)
+	if closeAt.Before(end.Add(-referralCloseTolerance)) {
+		return ErrReferralEarlyClose
+	}
+
+	if closeAt.Sub(stake.StartDate) < time.Duration(cfg.MinHold)*24*time.Hour {
+		return ErrReferralHoldTime
+	}
+	return nil
+}
+
+// CheckReferralWallets owners - другие пользователи с теми же кошельками, что у приглашенного.
+// Общий кошелек с пригласившим - приглашение самого себя, с кем-то еще - второй аккаунт
+func CheckReferralWallets(owners []uint64, chain []models.User) error {
+	if len(owners) == 0 {
+		return nil
+	}
+	for _, ref := range chain {
+		if slices.Contains(owners, uint64(ref.Id.Int64)) {
+			return ErrReferralSameWallet
+		}
+	}
+	return ErrReferralSharedWallet
+}
+
+// IsReferralIneligible стейк не подходит для начисления, это не ошибка сети или хранилища
+func IsReferralIneligible(err error) bool {
+	for _, e := range []error{
+		ErrReferralMinStake,
+		ErrReferralHoldTime,
+		ErrReferralEarlyClose,
+		ErrReferralSameWallet,
+		ErrReferralSharedWallet,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReferralIdemKey ключ начисления: в режиме once один на приглашенного и уровень, иначе на стейк и уровень
+func ReferralIdemKey(mode string, referralUserId uint64, stakeId int64, level int) string {
+	if mode == models.REFERRAL_MODE_ONCE {
+		return fmt.Sprintf("user:%v:%v", referralUserId, level)
+	}
+	return fmt.Sprintf("stake:%v:%v", stakeId, level)
+}
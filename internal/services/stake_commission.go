@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"os"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+)
+
+var (
+	ErrCommissionPolicy   = errors.New("unknown stake commission policy")
+	ErrCommissionPrice    = errors.New("stake commission price is unknown")
+	ErrCommissionAmount   = errors.New("stake amount does not cover commission")
+	ErrCommissionMismatch = errors.New("stake commission does not match pool policy")
+	ErrCommissionNotPaid  = errors.New("stake commission is not paid")
+)
+
+// CommissionPriceSlippage на сколько комиссия в % от стейка, переведенная в другую валюту по ценам, может быть
+// меньше пересчитанной при поступлении депозита: цены меняются после создания стейка
+const CommissionPriceSlippage = 0.1
+
+// CommissionPolicy политика комиссии за стейк amount на period дней в момент now
+func (s *PoolService) CommissionPolicy(tiers []models.RewardTier, amount float64, period uint, now time.Time) models.CommissionPolicy {
+	return TierCommissionPolicy(matchTier(tiers, amount, period, now))
+}
+
+// TierCommissionPolicy политика комиссии тарифа. Поля, не заданные в тарифе, берутся из env
+func TierCommissionPolicy(tier *models.RewardTier) models.CommissionPolicy {
+	cfg := config.LoadCommissionConfig()
+	policy := models.CommissionPolicy{
+		Asset: cfg.Asset,
+		Type:  cfg.Type,
+		Value: cfg.Value,
+	}
+	if tier == nil {
+		return policy
+	}
+
+	if tier.CommissionAsset != "" {
+		policy.Asset = tier.CommissionAsset
+	}
+	if tier.CommissionType != "" {
+		policy.Type = tier.CommissionType
+		policy.Value = tier.CommissionValue
+	}
+	return policy
+}
+
+// ResolveCommission комиссия за стейк с учетом тарифа и скидки для приглашенного пользователя.
+// price - цена в $ по адресу jetton, нужна для процента, который платится не из стейка
+func (s *PoolService) ResolveCommission(
+	pool *models.Pool,
+	tiers []models.RewardTier,
+	user *models.User,
+	amount float64,
+	period uint,
+	now time.Time,
+	price func(addr string) float64,
+) (*models.StakeCommission, error) {
+	policy := s.CommissionPolicy(tiers, amount, period, now)
+
+	var discount float64
+	if user != nil && user.RefererId.Valid {
+		discount = config.LoadCommissionConfig().ReferralDiscount
+	}
+
+	var stakePrice, assetPrice float64
+	if policy.Type == models.COMMISSION_TYPE_PERCENT && policy.Asset != models.COMMISSION_ASSET_STAKE {
+		stakePrice = price(pool.JettonMaster)
+		if policy.Asset == models.COMMISSION_ASSET_TON {
+			assetPrice = price(config.TON_NATIVE_ADDR)
+		} else {
+			assetPrice = price(CommissionJettonMaster(pool, policy.Asset))
+		}
+	}
+
+	return CalcCommission(policy, amount, discount, stakePrice, assetPrice)
+}
+
+// CheckStakeCommission сверяет комиссию и сумму стейка из payload депозита с ResolveCommission на момент
+// создания стейка. deposit - пришедший депозит, комиссия из стейка удерживается из него
+func (s *PoolService) CheckStakeCommission(
+	pool *models.Pool,
+	tiers []models.RewardTier,
+	user *models.User,
+	stake *models.Stake,
+	deposit float64,
+	price func(addr string) float64,
+) error {
+	commission, err := s.ResolveCommission(pool, tiers, user, deposit, stake.Period, stake.StartDate, price)
+	if err != nil {
+		return err
+	}
+	if commission.Asset != stake.CommissionAsset {
+		return ErrCommissionMismatch
+	}
+
+	policy := s.CommissionPolicy(tiers, deposit, stake.Period, stake.StartDate)
+	if policy.Type == models.COMMISSION_TYPE_PERCENT && policy.Asset != models.COMMISSION_ASSET_STAKE {
+		if stake.Commission < commission.Amount*(1-CommissionPriceSlippage) {
+			return ErrCommissionMismatch
+		}
+	} else if !sameAmount(stake.Commission, commission.Amount) {
+		return ErrCommissionMismatch
+	}
+
+	amount := deposit
+	if commission.Asset == models.COMMISSION_ASSET_STAKE {
+		amount -= commission.Amount
+	}
+	if !sameAmount(stake.Amount, amount) {
+		return ErrCommissionMismatch
+	}
+	stake.Balance = stake.Amount
+	return nil
+}
+
+// CommissionPaid поступила ли комиссия стейка: из стейка она удерживается с депозита,
+// в другой валюте должна прийти отдельным переводом commission
+func CommissionPaid(pool *models.Pool, stake *models.Stake, commission *models.Payload) bool {
+	if stake.Commission <= 0 || stake.CommissionAsset == models.COMMISSION_ASSET_STAKE {
+		return true
+	}
+	return commission != nil &&
+		commission.JettonMaster == CommissionJettonMaster(pool, stake.CommissionAsset) &&
+		(commission.Received >= stake.Commission || sameAmount(commission.Received, stake.Commission))
+}
+
+// sameAmount равны ли суммы с точностью до округления при переводе в минимальные единицы
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+// CalcCommission комиссия по политике для стейка amount со скидкой discount в %.
+// Процент считается от стейка и переводится в валюту комиссии по ценам stakePrice и assetPrice
+func CalcCommission(policy models.CommissionPolicy, amount, discount, stakePrice, assetPrice float64) (*models.StakeCommission, error) {
+	switch policy.Asset {
+	case models.COMMISSION_ASSET_JETTON, models.COMMISSION_ASSET_TON, models.COMMISSION_ASSET_STAKE:
+	default:
+		return nil, ErrCommissionPolicy
+	}
+
+	var value float64
+	switch policy.Type {
+	case models.COMMISSION_TYPE_FIXED:
+		value = policy.Value
+	case models.COMMISSION_TYPE_PERCENT:
+		value = amount * policy.Value / 100
+		if policy.Asset != models.COMMISSION_ASSET_STAKE {
+			if stakePrice <= 0 || assetPrice <= 0 {
+				return nil, ErrCommissionPrice
+			}
+			value = value * stakePrice / assetPrice
+		}
+	default:
+		return nil, ErrCommissionPolicy
+	}
+
+	discount = max(0, min(discount, 100))
+	value = math.Round(value*(1-discount/100)*1e9) / 1e9
+
+	if policy.Asset == models.COMMISSION_ASSET_STAKE && value >= amount {
+		return nil, ErrCommissionAmount
+	}
+
+	return &models.StakeCommission{
+		Asset:    policy.Asset,
+		Amount:   value,
+		Discount: discount,
+	}, nil
+}
+
+// CommissionJettonMaster jetton, в котором платится комиссия. Для TON - пустая строка
+func CommissionJettonMaster(pool *models.Pool, asset string) string {
+	switch asset {
+	case models.COMMISSION_ASSET_TON:
+		return ""
+	case models.COMMISSION_ASSET_STAKE:
+		return pool.JettonMaster
+	default:
+		return os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 )
@@ -38,10 +39,84 @@ func (s *StakeService) CreateStake(stake *models.Stake) (*models.Stake, error) {
 	return stake, nil
 }
 
+var (
+	ErrPoolMaxTotalStake = errors.New("pool max total stake exceeded")
+	ErrPoolMaxUserStake  = errors.New("pool max user stake exceeded")
+	ErrPoolMaxStakers    = errors.New("pool max stakers exceeded")
+	ErrNotWhitelisted    = errors.New("user is not whitelisted")
+)
+
+// CheckPoolLimits проверяет, помещается ли новый стейк amount в лимиты пула при текущей занятости totals
+func (s *StakeService) CheckPoolLimits(pool *models.Pool, totals *models.PoolStakeTotals, amount float64) error {
+	if pool.MaxTotalStake > 0 && totals.Total+amount > pool.MaxTotalStake {
+		return ErrPoolMaxTotalStake
+	}
+	if pool.MaxUserStake > 0 && totals.UserTotal+amount > pool.MaxUserStake {
+		return ErrPoolMaxUserStake
+	}
+	if pool.MaxStakers > 0 && !totals.IsStaker && totals.Stakers >= int(pool.MaxStakers) {
+		return ErrPoolMaxStakers
+	}
+	return nil
+}
+
+func (s *StakeService) GetPoolTotals(poolId, userId uint64) (*models.PoolStakeTotals, error) {
+	return s.stakeRepo.PoolTotals(poolId, userId)
+}
+
+// CanStake предварительная проверка лимитов пула при создании стейка
+func (s *StakeService) CanStake(pool *models.Pool, userId uint64, amount float64) error {
+	totals, err := s.stakeRepo.PoolTotals(uint64(pool.Id.Int64), userId)
+	if err != nil {
+		return err
+	}
+	return s.CheckPoolLimits(pool, totals, amount)
+}
+
+// CreateStakeWithinLimits сохраняет стейк, атомарно проверяя лимиты пула
+func (s *StakeService) CreateStakeWithinLimits(stake *models.Stake) (*models.Stake, error) {
+	if _, err := s.userService.GetById(stake.UserId); err != nil {
+		return nil, err
+	}
+	pool, err := s.poolService.GetId(stake.PoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.stakeRepo.SaveChecked(stake, func(totals *models.PoolStakeTotals) error {
+		return s.CheckPoolLimits(pool, totals, stake.Amount)
+	}); err != nil {
+		return nil, err
+	}
+
+	return stake, nil
+}
+
+// Rollover сохраняет стейк, перевыпущенный из закрытого стейка old, и old с отметкой о выплате одной транзакцией
+func (s *StakeService) Rollover(old, stake *models.Stake) error {
+	pool, err := s.poolService.GetId(stake.PoolId)
+	if err != nil {
+		return err
+	}
+
+	return s.stakeRepo.Rollover(old, stake, func(totals *models.PoolStakeTotals) error {
+		return s.CheckPoolLimits(pool, totals, stake.Amount)
+	})
+}
+
 func (s *StakeService) CountAll() int {
 	return s.stakeRepo.CountAll()
 }
 
+func (s *StakeService) CountAllByStatus(isActive bool) int {
+	return s.stakeRepo.CountAllByStatus(isActive)
+}
+
+// TvlByJetton сумма активных стейков по токенам пулов
+func (s *StakeService) TvlByJetton() ([]models.JettonTvl, error) {
+	return s.stakeRepo.TvlByJetton()
+}
+
 func (s *StakeService) CountUser(userId uint64) int {
 	return s.stakeRepo.CountUser(userId)
 }
@@ -83,6 +158,10 @@ func (s *StakeService) Update(stake *models.Stake) error {
 	return s.stakeRepo.Update(stake)
 }
 
+func (s *StakeService) SetPriceAlert(stakeId uint64, level int) error {
+	return s.stakeRepo.SetPriceAlert(stakeId, level)
+}
+
 func (s *StakeService) CountStakesPoolIdAndStatus(poolId uint64, b bool) int {
 	return s.stakeRepo.CountStakesPoolIdAndStatus(poolId, b)
 }
@@ -96,6 +175,10 @@ func (s *StakeService) GetStakesUser(userid uint64) *[]models.Stake {
 	return stakes
 }
 
+func (s *StakeService) GetStakesUserLimit(userId uint64, offset, limit int) *[]models.Stake {
+	return s.stakeRepo.GetUserStakesLimit(offset, limit, int64(userId))
+}
+
 func (s *StakeService) GetAllIsStatus(b bool) *[]models.Stake {
 	return s.stakeRepo.FindAllByStatus(b)
 }
@@ -184,3 +267,88 @@ func (s *StakeService) CountGroupsStakesByUserIdAndJettonNameIsNotPayment(userId
 func (s *StakeService) GroupFromPoolByUserIdLimitIsNotPayment(userId uint64, limit, offset int, b bool, isAcive bool) *[]models.GroupElements {
 	return s.stakeRepo.GroupFromPoolNameByUserIdLimitIsNotPayment(userId, offset, limit, b, isAcive)
 }
+
+var (
+	ErrStakeClosed              = errors.New("stake is already closed")
+	ErrEarlyExitLocked          = errors.New("early exit is locked")
+	ErrPartialExitNotAllowed    = errors.New("partial withdrawal is not allowed")
+	ErrRemainingLessThanMinimum = errors.New("remaining amount is less than min stake")
+)
+
+// EarlyExitQuote считает выплату при досрочном выходе по правилам пула.
+// amount - выводимая часть депозита, 0 или вся сумма означает полный выход.
+func (s *StakeService) EarlyExitQuote(stake *models.Stake, pool *models.Pool, amount float64, now time.Time) (*models.EarlyExitQuote, error) {
+	if !stake.IsActive || stake.IsRewardPaid || stake.IsInsurancePaid {
+		return nil, ErrStakeClosed
+	}
+
+	if now.Sub(stake.StartDate) < time.Duration(pool.EarlyExitMinDays)*24*time.Hour {
+		return nil, ErrEarlyExitLocked
+	}
+
+	if amount <= 0 || amount >= stake.Amount {
+		amount = stake.Amount
+	}
+
+	isPartial := amount < stake.Amount
+	if isPartial {
+		if !pool.EarlyExitPartial {
+			return nil, ErrPartialExitNotAllowed
+		}
+		if stake.Amount-amount < pool.MinStakeAmount {
+			return nil, ErrRemainingLessThanMinimum
+		}
+	}
+
+	share := amount / stake.Amount
+	earned := stake.Balance - stake.Amount
+	if earned < 0 {
+		earned = 0
+	}
+	earned *= share
+
+	reward := 0.
+	if pool.EarlyExitProRata {
+		reward = earned
+	}
+	penalty := amount * pool.EarlyExitPenalty / 100
+
+	return &models.EarlyExitQuote{
+		Amount:    amount,
+		Penalty:   penalty,
+		Reward:    reward,
+		Forfeited: earned - reward,
+		Payout:    amount - penalty + reward,
+		Remaining: stake.Amount - amount,
+		IsPartial: isPartial,
+	}, nil
+}
+
+// ApplyEarlyExit фиксирует досрочный выход после отправки выплаты: уменьшает или закрывает стейк
+// и возвращает в резерв пула удержанный штраф.
+func (s *StakeService) ApplyEarlyExit(stake *models.Stake, pool *models.Pool, quote *models.EarlyExitQuote, closePrice float64, now time.Time) error {
+	earned := stake.Balance - stake.Amount
+	if earned < 0 {
+		earned = 0
+	}
+
+	if quote.IsPartial {
+		share := quote.Amount / stake.Amount
+		stake.StartPoolDeposit -= stake.StartPoolDeposit * share
+		stake.Amount = quote.Remaining
+		stake.Balance = stake.Amount + earned*(1-share)
+	} else {
+		stake.IsActive = false
+		stake.IsRewardPaid = true
+		stake.CloseDate = now
+		stake.EndDate = now
+		stake.JettonPriceClosed = closePrice
+	}
+
+	if err := s.stakeRepo.Update(stake); err != nil {
+		return err
+	}
+
+	pool.Reserve += quote.Penalty - quote.Reward - quote.Forfeited
+	return s.poolService.Update(pool)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"tonclient/internal/i18n"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 )
@@ -18,16 +19,17 @@ func NewTelegramService(tgRepo *repositories.TelegramRepository, userServ *UserS
 	}
 }
 
-func (s *TelegramService) CreateTelegram(userId uint64, tgUsername string, tgId uint64) (*models.Telegram, error) {
+func (s *TelegramService) CreateTelegram(userId uint64, tgUsername string, tgId uint64, languageCode string) (*models.Telegram, error) {
 	user, err := s.userService.GetById(userId)
 	if user == nil {
 		return nil, err
 	}
 
 	tg := &models.Telegram{
-		Username:   tgUsername,
-		TelegramId: tgId,
-		UserId:     userId,
+		Username:     tgUsername,
+		TelegramId:   tgId,
+		UserId:       userId,
+		LanguageCode: languageCode,
 	}
 
 	if err := s.telegramRepo.Save(tg); err != nil {
@@ -64,3 +66,34 @@ func (s *TelegramService) GetByUserId(userId uint64) (*models.Telegram, error) {
 	}
 	return tg, nil
 }
+
+// SetBlocked статус блокировки бота пользователем, заблокированным уведомления не отправляются
+func (s *TelegramService) SetBlocked(telegramId uint64, blocked bool) error {
+	return s.telegramRepo.SetBlocked(telegramId, blocked)
+}
+
+func (s *TelegramService) IsBlocked(telegramId uint64) bool {
+	telegram := s.telegramRepo.FindByTelegramId(telegramId)
+	return telegram != nil && telegram.IsBlocked
+}
+
+func (s *TelegramService) SetLanguageCode(telegramId uint64, code string) error {
+	return s.telegramRepo.SetLanguageCode(telegramId, code)
+}
+
+// SetLanguage выбор языка в настройках, пустая строка возвращает определение по language_code
+func (s *TelegramService) SetLanguage(telegramId uint64, lang string) error {
+	if lang != "" && !i18n.IsSupported(lang) {
+		return errors.New("unsupported language")
+	}
+	return s.telegramRepo.SetLanguage(telegramId, lang)
+}
+
+// Language язык бота для пользователя
+func (s *TelegramService) Language(telegramId uint64) (string, error) {
+	telegram, err := s.GetTelegramId(telegramId)
+	if err != nil {
+		return "", err
+	}
+	return i18n.Resolve(telegram.Language, telegram.LanguageCode), nil
+}
@@ -2,15 +2,11 @@ package services
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base32"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"math"
-	"os"
 	"strconv"
-	"strings"
 	"time"
 	"tonclient/internal/config"
 	"tonclient/internal/models"
@@ -18,6 +14,7 @@ import (
 	"github.com/cameo-engineering/tonconnect"
 	"github.com/redis/go-redis/v9"
 	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
 	"github.com/xssnick/tonutils-go/ton/wallet"
 	"github.com/xssnick/tonutils-go/tvm/cell"
 )
@@ -27,107 +24,59 @@ var log = config.InitLogger()
 type TonConnectService struct {
 	redisCli        *redis.Client
 	adminWalletServ *AdminWalletService
+	registry        *WalletRegistry
+	sessions        *SessionStore
+	intents         *TxIntentService
 }
 
 func NewTonConnectService(redis *redis.Client, adminWalletServ *AdminWalletService) *TonConnectService {
 	return &TonConnectService{
 		redisCli:        redis,
 		adminWalletServ: adminWalletServ,
+		registry:        NewWalletRegistry(config.LoadWalletsRegistryConfig()),
+		sessions:        NewSessionStore(redis, config.LoadTonConnectSessionConfig()),
 	}
 }
 
-func (s *TonConnectService) LoadSession(key string) (*tonconnect.Session, error) {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-
-	result, err := s.redisCli.Get(ctx, key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, nil
-	}
-
+// GenerateConnectUrls ссылки на подключение каждого кошелька из реестра
+// с запросом ton_proof на payload, выданный для key
+func (s *TonConnectService) GenerateConnectUrls(key string, session *tonconnect.Session) ([]models.WalletConnectLink, error) {
+	connreq, err := s.GetTonConnector(key)
 	if err != nil {
-		log.Error("Error loading session", err)
 		return nil, err
 	}
 
-	var session tonconnect.Session
-	err = session.UnmarshalJSON([]byte(result))
-	if err != nil {
-		log.Error("Error loading session", err)
-		return nil, err
+	wallets := s.registry.Wallets()
+	result := make([]models.WalletConnectLink, 0, len(wallets))
+	for _, w := range wallets {
+		link, err := session.GenerateUniversalLink(toTonConnectWallet(w), *connreq)
+		if err != nil {
+			log.Error("Error generating link for ", w.Name, ": ", err)
+			continue
+		}
+		log.Debugln("Generated link: ", link)
+		result = append(result, models.WalletConnectLink{Wallet: w, Url: link})
 	}
-	return &session, nil
-}
-
-func (s *TonConnectService) SaveSession(key string, session *tonconnect.Session) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-
-	data, err := session.MarshalJSON()
-	if err != nil {
-		log.Error("Error marshaling session json", err)
-		return err
+	if len(result) == 0 {
+		return nil, ErrWalletsListEmpty
 	}
-	return s.redisCli.Set(ctx, key, data, 0).Err()
-}
-
-func (s *TonConnectService) DeleteSession(key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
 
-	return s.redisCli.Del(ctx, key).Err()
+	return result, nil
 }
 
-func (s *TonConnectService) GenerateConnectUrls(session *tonconnect.Session) (connectUrls map[string]string, error error) {
-	result := make(map[string]string)
-	data := make([]byte, 32)
-	_, err := rand.Read(data)
+func (s *TonConnectService) GetTonConnector(key string) (*tonconnect.ConnectRequest, error) {
+	payload, err := s.GenerateProofPayload(key)
 	if err != nil {
-		log.Error("Error generating connect url", err)
 		return nil, err
 	}
 	connreq, err := tonconnect.NewConnectRequest(
-		os.Getenv("MANIFEST_URL"),
-		tonconnect.WithProofRequest(base32.StdEncoding.EncodeToString(data)),
+		config.ManifestUrl(),
+		tonconnect.WithProofRequest(payload),
 	)
 	if err != nil {
 		log.Error("Error generating connect urls", err)
 		return nil, err
 	}
-	deeplink, err := session.GenerateDeeplink(*connreq, tonconnect.WithBackReturnStrategy())
-	if err != nil {
-		log.Error("Error generating deeplink", err)
-		return nil, err
-	}
-
-	log.Debugln("Generated deeplink: ", deeplink)
-
-	for _, w := range tonconnect.Wallets {
-		nameLower := strings.ToLower(w.Name)
-		if nameLower == "tonkeeper" || nameLower == "tonhub" {
-			link, err := session.GenerateUniversalLink(w, *connreq)
-			log.Debugln("Generated link: ", link)
-			if err != nil {
-				return nil, err
-			}
-			result[w.Name] = link
-		}
-
-	}
-
-	return result, nil
-}
-
-func (s *TonConnectService) GetTonConnector() (*tonconnect.ConnectRequest, error) {
-	data := make([]byte, 32)
-	connreq, err := tonconnect.NewConnectRequest(
-		os.Getenv("MANIFEST_URL"),
-		tonconnect.WithProofRequest(base32.StdEncoding.EncodeToString(data)),
-	)
-	if err != nil {
-		log.Error("Error generating connect urls", err)
-	}
 
 	return connreq, nil
 }
@@ -142,14 +91,28 @@ func (s *TonConnectService) GetWalletUniversalLink(walletName string) string {
 }
 
 func (s *TonConnectService) GetWallet(wal string) (*tonconnect.Wallet, error) {
-	for _, w := range tonconnect.Wallets {
-		nameLower := strings.ToLower(w.Name)
-		if nameLower == wal {
-			return &w, nil
-		}
+	w, ok := s.registry.Get(wal)
+	if !ok {
+		return nil, errors.New("wallet not found")
 	}
 
-	return nil, errors.New("wallet not found")
+	res := toTonConnectWallet(*w)
+	return &res, nil
+}
+
+// Wallets кошельки, доступные для подключения
+func (s *TonConnectService) Wallets() []models.TonConnectWallet {
+	return s.registry.Wallets()
+}
+
+// bridgeWallets кошельки, бриджи которых слушаются при подключении
+func (s *TonConnectService) bridgeWallets() []tonconnect.Wallet {
+	wallets := s.registry.Wallets()
+	res := make([]tonconnect.Wallet, 0, len(wallets))
+	for _, w := range wallets {
+		res = append(res, toTonConnectWallet(w))
+	}
+	return res
 }
 
 func (s *TonConnectService) GetTonkeeperUrl() string {
@@ -164,25 +127,49 @@ func (s *TonConnectService) GetTonhubUrl() string {
 	return "https://tonhub.com/"
 }
 
-func (s *TonConnectService) Connect(session *tonconnect.Session) (*models.TonConnectResult, error) {
+// Connect ждет подключения кошелька и возвращает только адрес, владение которым подтверждено ton_proof
+func (s *TonConnectService) Connect(key string, session *tonconnect.Session) (*models.TonConnectResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	res, err := session.Connect(ctx, tonconnect.Wallets["tonkeeper"], tonconnect.Wallets["tonhub"])
+	res, err := session.Connect(ctx, s.bridgeWallets()...)
 	if err != nil {
 		log.Error("Error generating connect urls", err)
 		return nil, err
 	}
-	var addr string
+	var addr, publicKey string
+	var stateInit []byte
+	var proof *models.TonProof
 	network := "mainnet"
 	for _, item := range res.Items {
-		if item.Name == "ton_addr" {
+		switch item.Name {
+		case "ton_addr":
 			addr = item.Address
+			publicKey = item.PublicKey
+			stateInit = item.WalletStateInit
 			if item.Network == -3 {
 				network = "testnet"
 			}
+		case "ton_proof":
+			proof = &models.TonProof{
+				Timestamp: int64(item.Proof.Timestamp),
+				Domain:    item.Proof.Domain.Value,
+				Signature: item.Proof.Signature,
+				Payload:   item.Proof.Payload,
+			}
 		}
 	}
+	if proof == nil {
+		return nil, ErrProofMissing
+	}
+	proof.Address = addr
+	proof.PublicKey = publicKey
+	proof.StateInit = stateInit
+
+	if err := s.CheckProof(key, proof); err != nil {
+		log.Error("Error checking ton_proof for ", addr, ": ", err)
+		return nil, err
+	}
 	log.Printf(
 		"%s %s for %s is connected to %s with %s address\n\n",
 		res.Device.AppName,
@@ -197,9 +184,15 @@ func (s *TonConnectService) Connect(session *tonconnect.Session) (*models.TonCon
 		Version:    res.Device.AppVersion,
 		Addr:       addr,
 		Platform:   res.Device.Platform,
+		Proof:      proof,
 	}, nil
 }
 
+// TrackIntents включает сохранение и отслеживание транзакций, отправленных на подпись
+func (s *TonConnectService) TrackIntents(intents *TxIntentService) {
+	s.intents = intents
+}
+
 func (s *TonConnectService) CreateSession() (*tonconnect.Session, error) {
 	return tonconnect.NewSession()
 }
@@ -211,6 +204,27 @@ func (s *TonConnectService) SendJettonTransaction(key, jettonAddr, receiverAddr,
 		}
 	}()
 
+	msg, err := s.JettonTransferMessage(jettonAddr, receiverAddr, senderAddr, amount, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := tonconnect.NewTransaction(
+		tonconnect.WithTimeout(5*time.Minute),
+		tonconnect.WithMessage(*msg),
+	)
+
+	if err != nil {
+		log.Error("Error creating transaction", err)
+		return nil, err
+	}
+
+	return s.sendTracked(key, payload, session, tx)
+}
+
+// JettonTransferMessage сообщение TON Connect на перевод jetton с кошелька пользователя.
+// jettonAddr - jetton-кошелек отправителя, в forward_payload передается payload операции
+func (s *TonConnectService) JettonTransferMessage(jettonAddr, receiverAddr, senderAddr, amount string, payload *models.Payload) (*tonconnect.Message, error) {
 	payloadJson, err := json.Marshal(payload)
 	if err != nil {
 		log.Error("Error marshaling payload", err)
@@ -257,31 +271,67 @@ func (s *TonConnectService) SendJettonTransaction(key, jettonAddr, receiverAddr,
 		log.Error("Error creating transaction", err)
 		return nil, err
 	}
+	return msg, nil
+}
+
+func (s *TonConnectService) SendTonTransaction(key, receiverAddr, amount string, payload *models.Payload, session *tonconnect.Session) ([]byte, error) {
+	defer func() {
+		if err := s.SaveSession(key, session); err != nil {
+			log.Error("Error saving session", err)
+		}
+	}()
+
+	msg, err := s.TonTransferMessage(receiverAddr, amount, payload)
+	if err != nil {
+		return nil, err
+	}
 	tx, err := tonconnect.NewTransaction(
 		tonconnect.WithTimeout(5*time.Minute),
 		tonconnect.WithMessage(*msg),
 	)
-
 	if err != nil {
 		log.Error("Error creating transaction", err)
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	return s.sendTracked(key, payload, session, tx)
+}
 
-	boc, err := session.SendTransaction(ctx, *tx)
+// TonTransferMessage сообщение TON Connect на перевод amount TON с payload операции в теле
+func (s *TonConnectService) TonTransferMessage(receiverAddr, amount string, payload *models.Payload) (*tonconnect.Message, error) {
+	payloadJson, err := json.Marshal(payload)
 	if err != nil {
-		log.Error("Error sending transaction", err)
+		log.Error("Error marshaling payload", err)
 		return nil, err
 	}
-	return boc, nil
+
+	pld := cell.BeginCell().
+		MustStoreUInt(payload.OperationType, 32).
+		MustStoreStringSnake(base64.StdEncoding.EncodeToString(payloadJson)).
+		EndCell()
+
+	coins, err := tlb.FromTON(amount)
+	if err != nil {
+		log.Error("Error parsing amount", err)
+		return nil, err
+	}
+
+	msg, err := tonconnect.NewMessage(
+		receiverAddr,
+		coins.Nano().String(),
+		tonconnect.WithPayload(pld.ToBOC()),
+	)
+	if err != nil {
+		log.Error("Error creating transaction", err)
+		return nil, err
+	}
+	return msg, nil
 }
 
 func (s *TonConnectService) ConnectSession(ses *tonconnect.Session) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
-	_, err := ses.Connect(ctx, tonconnect.Wallets["tonkeeper"])
+	_, err := ses.Connect(ctx, s.bridgeWallets()...)
 	if err != nil {
 		return err
 	}
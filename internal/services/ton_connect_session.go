@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"time"
+	"tonclient/internal/config"
+
+	"github.com/cameo-engineering/tonconnect"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrSessionNotFound = errors.New("ton connect session not found")
+	ErrSessionStale    = errors.New("ton connect session is not connected to wallet")
+	ErrSessionCorrupt  = errors.New("ton connect session cannot be decrypted")
+)
+
+const sessionRedisPrefix = "tonconnect:session:"
+
+// SessionStore хранит сессии TON Connect в Redis в зашифрованном виде.
+// Время жизни сессии продлевается при каждом использовании
+type SessionStore struct {
+	redisCli *redis.Client
+	cfg      *config.TonConnectSessionConfig
+}
+
+func NewSessionStore(redisCli *redis.Client, cfg *config.TonConnectSessionConfig) *SessionStore {
+	return &SessionStore{
+		redisCli: redisCli,
+		cfg:      cfg,
+	}
+}
+
+// Load сессия по key. Сессии, сохраненные до шифрования под голым ключом, переносятся в новое хранилище
+func (s *SessionStore) Load(key string) (*tonconnect.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	data, err := s.redisCli.Get(ctx, sessionRedisPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return s.loadLegacy(ctx, key)
+	}
+	if err != nil {
+		log.Error("Error loading session", err)
+		return nil, err
+	}
+
+	session, err := OpenSession(s.cfg.Key, data)
+	if err != nil {
+		log.Error("Error decrypting session ", key, ": ", err)
+		s.delete(ctx, key)
+		return nil, ErrSessionNotFound
+	}
+	if !sessionConnected(session) {
+		s.delete(ctx, key)
+		return nil, ErrSessionStale
+	}
+
+	if err := s.redisCli.Expire(ctx, sessionRedisPrefix+key, s.cfg.Ttl).Err(); err != nil {
+		log.Error("Error refreshing session ttl", err)
+	}
+	return session, nil
+}
+
+func (s *SessionStore) Save(key string, session *tonconnect.Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	data, err := SealSession(s.cfg.Key, session)
+	if err != nil {
+		log.Error("Error encrypting session", err)
+		return err
+	}
+	return s.redisCli.Set(ctx, sessionRedisPrefix+key, data, s.cfg.Ttl).Err()
+}
+
+func (s *SessionStore) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	return s.redisCli.Del(ctx, sessionRedisPrefix+key, key).Err()
+}
+
+func (s *SessionStore) loadLegacy(ctx context.Context, key string) (*tonconnect.Session, error) {
+	result, err := s.redisCli.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		log.Error("Error loading session", err)
+		return nil, err
+	}
+
+	var session tonconnect.Session
+	if err := session.UnmarshalJSON(result); err != nil {
+		log.Error("Error loading legacy session ", key, ": ", err)
+		return nil, ErrSessionNotFound
+	}
+	if !sessionConnected(&session) {
+		s.delete(ctx, key)
+		return nil, ErrSessionStale
+	}
+
+	if err := s.Save(key, &session); err != nil {
+		log.Error("Error migrating session", err)
+		return nil, err
+	}
+	if err := s.redisCli.Del(ctx, key).Err(); err != nil {
+		log.Error("Error deleting legacy session", err)
+	}
+	return &session, nil
+}
+
+func (s *SessionStore) delete(ctx context.Context, key string) {
+	if err := s.redisCli.Del(ctx, sessionRedisPrefix+key, key).Err(); err != nil {
+		log.Error("Error deleting session", err)
+	}
+}
+
+// SealSession шифрует сессию AES-GCM: nonce ++ ciphertext
+func SealSession(key []byte, session *tonconnect.Session) ([]byte, error) {
+	data, err := session.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := sessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// OpenSession расшифровывает сессию, зашифрованную SealSession
+func OpenSession(key, data []byte) (*tonconnect.Session, error) {
+	gcm, err := sessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrSessionCorrupt
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrSessionCorrupt
+	}
+
+	var session tonconnect.Session
+	if err := session.UnmarshalJSON(plain); err != nil {
+		return nil, ErrSessionCorrupt
+	}
+	return &session, nil
+}
+
+// IsSessionLost сессии нет или кошелек к ней не подключен - нужно переподключение
+func IsSessionLost(err error) bool {
+	return errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionStale)
+}
+
+func sessionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sessionConnected кошелек завершил подключение и известен его бридж
+func sessionConnected(session *tonconnect.Session) bool {
+	return session.ID != nil && session.PrivateKey != nil && session.ClientID != nil && session.BridgeURL != ""
+}
+
+func (s *TonConnectService) LoadSession(key string) (*tonconnect.Session, error) {
+	return s.sessions.Load(key)
+}
+
+func (s *TonConnectService) SaveSession(key string, session *tonconnect.Session) error {
+	return s.sessions.Save(key, session)
+}
+
+func (s *TonConnectService) DeleteSession(key string) error {
+	return s.sessions.Delete(key)
+}
+
+// Disconnect отправляет кошельку через бридж запрос на отключение и удаляет сессию.
+// Сессия удаляется, даже если бридж недоступен
+func (s *TonConnectService) Disconnect(key string) error {
+	session, err := s.sessions.Load(key)
+	if err != nil {
+		if IsSessionLost(err) {
+			_ = s.sessions.Delete(key)
+		}
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := session.Disconnect(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		log.Error("Error sending disconnect to bridge", err)
+	}
+	return s.sessions.Delete(key)
+}
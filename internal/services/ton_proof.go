@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/wallet"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+var (
+	ErrProofMissing   = errors.New("wallet did not return ton_proof")
+	ErrProofPayload   = errors.New("ton_proof payload is unknown or already used")
+	ErrProofDomain    = errors.New("ton_proof domain is not allowed")
+	ErrProofExpired   = errors.New("ton_proof expired")
+	ErrProofStateInit = errors.New("ton_proof state init does not match address")
+	ErrProofPublicKey = errors.New("cannot get wallet public key")
+	ErrProofSignature = errors.New("invalid ton_proof signature")
+)
+
+const (
+	// TonProofTTL время жизни payload и подписанного ton_proof
+	TonProofTTL = 15 * time.Minute
+
+	tonProofPrefix    = "ton-proof-item-v2/"
+	tonConnectPrefix  = "ton-connect"
+	proofPayloadRedis = "ton_proof:"
+)
+
+// GenerateProofPayload одноразовый payload для ton_proof, выданный владельцу key (chat id или пользователь Mini App)
+func (s *TonConnectService) GenerateProofPayload(key string) (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		log.Error("Error generating proof payload", err)
+		return "", err
+	}
+	payload := hex.EncodeToString(data)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := s.redisCli.Set(ctx, proofPayloadRedis+payload, key, TonProofTTL).Err(); err != nil {
+		log.Error("Error saving proof payload", err)
+		return "", err
+	}
+	return payload, nil
+}
+
+// CheckProof проверяет, что ton_proof подписан на payload, выданный для key, и погашает payload
+func (s *TonConnectService) CheckProof(key string, proof *models.TonProof) error {
+	if proof == nil || len(proof.Signature) == 0 {
+		return ErrProofMissing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	owner, err := s.redisCli.GetDel(ctx, proofPayloadRedis+proof.Payload).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrProofPayload
+	}
+	if err != nil {
+		log.Error("Error loading proof payload", err)
+		return err
+	}
+	if owner != key {
+		return ErrProofPayload
+	}
+
+	addr, err := ParseAnyAddr(proof.Address)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := ProofPublicKey(addr, proof.StateInit)
+	if errors.Is(err, ErrProofPublicKey) {
+		// state init не передан или кошелек нестандартный: берем ключ развернутого кошелька из сети
+		pubKey, err = s.adminWalletServ.WalletPublicKey(addr)
+		if err != nil {
+			log.Error("Error getting wallet public key", err)
+			return ErrProofPublicKey
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return VerifyTonProof(proof, pubKey, config.TonProofDomains(), time.Now())
+}
+
+// VerifyTonProof проверяет домен, время и подпись ton_proof по спецификации TON Connect:
+// signature = Ed25519(sha256(0xffff ++ "ton-connect" ++ sha256(message)))
+func VerifyTonProof(proof *models.TonProof, pubKey ed25519.PublicKey, domains []string, now time.Time) error {
+	if !slices.Contains(domains, proof.Domain) {
+		return ErrProofDomain
+	}
+
+	age := now.Sub(time.Unix(proof.Timestamp, 0))
+	if age > TonProofTTL || age < -time.Minute {
+		return ErrProofExpired
+	}
+
+	addr, err := ParseAnyAddr(proof.Address)
+	if err != nil {
+		return err
+	}
+
+	if len(pubKey) != ed25519.PublicKeySize || !ed25519.Verify(pubKey, TonProofHash(addr, proof), proof.Signature) {
+		return ErrProofSignature
+	}
+	return nil
+}
+
+// TonProofHash хэш, который подписывает кошелек
+func TonProofHash(addr *address.Address, proof *models.TonProof) []byte {
+	msg := bytes.NewBufferString(tonProofPrefix)
+	_ = binary.Write(msg, binary.BigEndian, addr.Workchain())
+	msg.Write(addr.Data())
+	_ = binary.Write(msg, binary.LittleEndian, uint32(len(proof.Domain)))
+	msg.WriteString(proof.Domain)
+	_ = binary.Write(msg, binary.LittleEndian, uint64(proof.Timestamp))
+	msg.WriteString(proof.Payload)
+	msgHash := sha256.Sum256(msg.Bytes())
+
+	full := bytes.NewBuffer([]byte{0xff, 0xff})
+	full.WriteString(tonConnectPrefix)
+	full.Write(msgHash[:])
+	hash := sha256.Sum256(full.Bytes())
+	return hash[:]
+}
+
+// ProofPublicKey достает публичный ключ из state init стандартного кошелька.
+// State init должен соответствовать адресу, иначе подпись ничего не доказывает
+func ProofPublicKey(addr *address.Address, stateInit []byte) (ed25519.PublicKey, error) {
+	if len(stateInit) == 0 {
+		return nil, ErrProofPublicKey
+	}
+
+	root, err := cell.FromBOC(stateInit)
+	if err != nil {
+		return nil, ErrProofStateInit
+	}
+	if !bytes.Equal(root.Hash(), addr.Data()) {
+		return nil, ErrProofStateInit
+	}
+
+	var si tlb.StateInit
+	if err := tlb.LoadFromCell(&si, root.BeginParse()); err != nil || si.Code == nil || si.Data == nil {
+		return nil, ErrProofStateInit
+	}
+
+	// количество бит в data перед публичным ключом
+	var skip uint
+	version := wallet.GetWalletVersion(&tlb.Account{
+		IsActive: true,
+		State:    &tlb.AccountState{AccountStorage: tlb.AccountStorage{Status: tlb.AccountStatusActive}},
+		Code:     si.Code,
+	})
+	switch version {
+	case wallet.V1R1, wallet.V1R2, wallet.V1R3, wallet.V2R1, wallet.V2R2:
+		skip = 32
+	case wallet.V3R1, wallet.V3R2, wallet.V4R1, wallet.V4R2:
+		skip = 64
+	case wallet.V5R1Beta:
+		skip = 113
+	case wallet.V5R1Final:
+		skip = 65
+	default:
+		return nil, ErrProofPublicKey
+	}
+
+	data := si.Data.BeginParse()
+	if _, err := data.LoadSlice(skip); err != nil {
+		return nil, ErrProofStateInit
+	}
+	key, err := data.LoadSlice(256)
+	if err != nil {
+		return nil, ErrProofStateInit
+	}
+	return key, nil
+}
+
+// IsProofError ошибка проверки ton_proof, а не сети или хранилища
+func IsProofError(err error) bool {
+	for _, e := range []error{
+		ErrProofMissing,
+		ErrProofPayload,
+		ErrProofDomain,
+		ErrProofExpired,
+		ErrProofStateInit,
+		ErrProofPublicKey,
+		ErrProofSignature,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAnyAddr разбирает адрес в user-friendly или raw (0:hex) формате
+func ParseAnyAddr(addr string) (*address.Address, error) {
+	if a, err := address.ParseAddr(addr); err == nil {
+		return a, nil
+	}
+	return address.ParseRawAddr(addr)
+}
+
+// SameAddress сравнивает адреса независимо от формата записи и флагов
+func SameAddress(a, b string) bool {
+	first, err := ParseAnyAddr(a)
+	if err != nil {
+		return false
+	}
+	second, err := ParseAnyAddr(b)
+	if err != nil {
+		return false
+	}
+	return first.Workchain() == second.Workchain() && bytes.Equal(first.Data(), second.Data())
+}
+
+// AddrForms варианты записи адреса, в которых он мог быть сохранен: raw, bounceable и non-bounceable
+func AddrForms(addr string) []string {
+	a, err := ParseAnyAddr(addr)
+	if err != nil {
+		return []string{addr}
+	}
+	forms := []string{a.StringRaw(), a.Bounce(true).String(), a.Bounce(false).String()}
+	if !slices.Contains(forms, addr) {
+		forms = append(forms, addr)
+	}
+	return forms
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"tonclient/internal/models"
+	"tonclient/internal/repositories"
+
+	"github.com/cameo-engineering/tonconnect"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+var ErrSignedBoc = errors.New("signed boc is not an external message")
+
+// TxIntentTTL сколько ждать транзакцию в сети после подписи: время жизни сообщения 5 минут и запас
+const TxIntentTTL = 15 * time.Minute
+
+// TxIntentService сохраняет транзакции, отправленные на подпись через TonConnect,
+// и отслеживает их выполнение в сети по хэшу внешнего сообщения
+type TxIntentService struct {
+	rep     *repositories.TxIntentRepository
+	aws     *AdminWalletService
+	updates chan models.TxIntent
+}
+
+func NewTxIntentService(rep *repositories.TxIntentRepository, aws *AdminWalletService) *TxIntentService {
+	return &TxIntentService{
+		rep:     rep,
+		aws:     aws,
+		updates: make(chan models.TxIntent, 64),
+	}
+}
+
+// Updates изменения статусов, о которых нужно сообщить пользователю
+func (s *TxIntentService) Updates() <-chan models.TxIntent {
+	return s.updates
+}
+
+func (s *TxIntentService) Create(telegramId uint64, payload *models.Payload) (*models.TxIntent, error) {
+	return s.create(telegramId, payload, 1)
+}
+
+// create сохраняет intent основной операции payload из parts переводов, received - уже поступившие переводы
+func (s *TxIntentService) create(telegramId uint64, payload *models.Payload, parts int, received ...models.Payload) (*models.TxIntent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	receivedData := []byte("[]")
+	if len(received) > 0 {
+		if receivedData, err = json.Marshal(received); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	intent := &models.TxIntent{
+		TelegramId:    telegramId,
+		OperationType: payload.OperationType,
+		Payload:       string(data),
+		Status:        models.TX_INTENT_PENDING,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Parts:         parts,
+		Received:      string(receivedData),
+	}
+	if err := s.rep.Save(intent); err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// Signed сохраняет подписанный BOC и его хэш и дублирует сообщение в сеть через лайт-сервер
+func (s *TxIntentService) Signed(intent *models.TxIntent, boc []byte) error {
+	msg, err := ParseSignedBoc(boc)
+	if err != nil {
+		return s.setStatus(intent, models.TX_INTENT_FAILED, err.Error())
+	}
+
+	intent.Boc = hex.EncodeToString(boc)
+	intent.MsgHash = hex.EncodeToString(msg.NormalizedHash())
+	intent.SenderAddr = msg.DstAddr.String()
+	if err := s.setStatus(intent, models.TX_INTENT_SIGNED, ""); err != nil {
+		return err
+	}
+
+	// кошелек отправляет сообщение сам, ошибка здесь обычно означает, что оно уже принято
+	if err := s.aws.SendExternalMessage(msg); err != nil {
+		log.Info("External message was not accepted by liteserver: ", err)
+		return nil
+	}
+	return s.setStatus(intent, models.TX_INTENT_BROADCAST, "")
+}
+
+// Failed транзакция не была подписана: отклонена пользователем или не дошла до кошелька
+func (s *TxIntentService) Failed(intent *models.TxIntent, sendErr error) error {
+	if IsUserRejected(sendErr) {
+		return s.setStatus(intent, models.TX_INTENT_REJECTED, sendErr.Error())
+	}
+	return s.setStatus(intent, models.TX_INTENT_FAILED, sendErr.Error())
+}
+
+// TrackPending ищет в сети транзакции по подписанным сообщениям, закрывает просроченные коды переводов
+// и транзакции из нескольких сообщений, переводы которых поступили не полностью
+func (s *TxIntentService) TrackPending() {
+	s.expireMemo(time.Now())
+	s.expireParts(time.Now())
+
+	intents, err := s.rep.FindByStatuses(models.TX_INTENT_SIGNED, models.TX_INTENT_BROADCAST)
+	if err != nil {
+		return
+	}
+
+	for i := range intents {
+		if err := s.track(&intents[i], time.Now()); err != nil {
+			log.Error("Error tracking tx intent ", intents[i].Id.Int64, ": ", err)
+		}
+	}
+}
+
+func (s *TxIntentService) track(intent *models.TxIntent, now time.Time) error {
+	data, err := hex.DecodeString(intent.Boc)
+	if err != nil {
+		return s.setStatus(intent, models.TX_INTENT_FAILED, err.Error())
+	}
+	msg, err := ParseSignedBoc(data)
+	if err != nil {
+		return s.setStatus(intent, models.TX_INTENT_FAILED, err.Error())
+	}
+
+	tx, err := s.aws.FindTransactionByInMsg(msg)
+	if err != nil {
+		if !errors.Is(err, ton.ErrTxWasNotFound) {
+			log.Error("Error finding transaction ", intent.MsgHash, ": ", err)
+		}
+		if now.Sub(intent.CreatedAt) > TxIntentTTL {
+			return s.setStatus(intent, models.TX_INTENT_FAILED, "transaction was not found before message expiration")
+		}
+		return nil
+	}
+
+	intent.TxHash = hex.EncodeToString(tx.Hash)
+	status, reason := TxOutcome(tx)
+	return s.setStatus(intent, status, reason)
+}
+
+func (s *TxIntentService) setStatus(intent *models.TxIntent, status, reason string) error {
+	if err := s.update(intent, status, reason); err != nil {
+		return err
+	}
+	if status != models.TX_INTENT_BROADCAST {
+		s.notify(intent)
+	}
+	return nil
+}
+
+func (s *TxIntentService) update(intent *models.TxIntent, status, reason string) error {
+	intent.Status = status
+	intent.Error = reason
+	intent.UpdatedAt = time.Now()
+	return s.rep.Update(intent)
+}
+
+func (s *TxIntentService) notify(intent *models.TxIntent) {
+	select {
+	case s.updates <- *intent:
+	default:
+		log.Warn("Tx intent updates channel is full, notification dropped: ", intent.Id.Int64)
+	}
+}
+
+// ParseSignedBoc внешнее сообщение, которое вернул кошелек после подписи
+func ParseSignedBoc(boc []byte) (*tlb.ExternalMessage, error) {
+	root, err := cell.FromBOC(boc)
+	if err != nil {
+		return nil, ErrSignedBoc
+	}
+
+	var msg tlb.ExternalMessage
+	if err := tlb.LoadFromCell(&msg, root.BeginParse()); err != nil || msg.DstAddr == nil {
+		return nil, ErrSignedBoc
+	}
+	return &msg, nil
+}
+
+// TxOutcome статус транзакции кошелька: сообщение обработано и исходящие сообщения отправлены
+func TxOutcome(tx *tlb.Transaction) (string, string) {
+	desc, ok := tx.Description.(tlb.TransactionDescriptionOrdinary)
+	if !ok {
+		return models.TX_INTENT_FAILED, "unexpected transaction type"
+	}
+	if desc.Aborted {
+		return models.TX_INTENT_FAILED, "transaction aborted"
+	}
+	if vm, ok := desc.ComputePhase.Phase.(tlb.ComputePhaseVM); !ok || !vm.Success {
+		return models.TX_INTENT_FAILED, "compute phase failed"
+	}
+	if desc.ActionPhase != nil && !desc.ActionPhase.Success {
+		return models.TX_INTENT_FAILED, "action phase failed"
+	}
+	return models.TX_INTENT_CONFIRMED, ""
+}
+
+// IsUserRejected пользователь отклонил транзакцию в кошельке
+func IsUserRejected(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "user declined")
+}
+
+// sendTracked отправляет транзакцию на подпись и сохраняет ее судьбу, если отслеживание включено
+func (s *TonConnectService) sendTracked(key string, payload *models.Payload, session *tonconnect.Session, tx *tonconnect.Transaction) ([]byte, error) {
+	var intent *models.TxIntent
+	if telegramId, err := strconv.ParseUint(key, 10, 64); err == nil && s.intents != nil {
+		if intent, err = s.intents.Create(telegramId, payload); err != nil {
+			log.Error("Error creating tx intent", err)
+		}
+	}
+	return s.sendIntent(intent, session, tx)
+}
+
+// sendIntent отправляет транзакцию на подпись и сохраняет результат в intent, если он создан
+func (s *TonConnectService) sendIntent(intent *models.TxIntent, session *tonconnect.Session, tx *tonconnect.Transaction) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	boc, err := session.SendTransaction(ctx, *tx)
+	if err != nil {
+		log.Error("Error sending transaction", err)
+		if intent != nil {
+			if err := s.intents.Failed(intent, err); err != nil {
+				log.Error("Error updating tx intent", err)
+			}
+		}
+		return nil, err
+	}
+
+	if intent != nil {
+		if err := s.intents.Signed(intent, boc); err != nil {
+			log.Error("Error updating tx intent", err)
+		}
+	}
+	return boc, nil
+}
@@ -29,8 +29,9 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
 	return user, nil
 }
 
-func (s *UserService) GetUserReferal(userId uint64) *[]models.User {
-	return s.GetUserReferal(userId)
+// GetUserReferal приглашенные пользователем, refererId - telegram id пригласившего
+func (s *UserService) GetUserReferal(refererId uint64) *[]models.User {
+	return s.userRepo.FindUserReferal(refererId)
 }
 
 func (s *UserService) GetById(id uint64) (*models.User, error) {
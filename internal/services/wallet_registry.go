@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+
+	"github.com/cameo-engineering/tonconnect"
+)
+
+var ErrWalletsListEmpty = errors.New("wallets list has no supported wallets")
+
+// порядок встроенного списка кошельков библиотеки, если реестр и локальный файл недоступны
+var builtinWalletsOrder = []string{
+	"tonkeeper",
+	"telegram-wallet",
+	"mytonwallet",
+	"tonhub",
+	"bitgetTonWallet",
+	"safepalwallet",
+	"dewallet",
+}
+
+type registryWallet struct {
+	AppName      string `json:"app_name"`
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	UniversalUrl string `json:"universal_url"`
+	Bridge       []struct {
+		Type string `json:"type"`
+		Url  string `json:"url"`
+	} `json:"bridge"`
+}
+
+// WalletRegistry список кошельков TON Connect из официального реестра с кэшем
+type WalletRegistry struct {
+	cfg      *config.WalletsRegistryConfig
+	client   *http.Client
+	mu       sync.Mutex
+	wallets  []models.TonConnectWallet
+	loadedAt time.Time
+}
+
+func NewWalletRegistry(cfg *config.WalletsRegistryConfig) *WalletRegistry {
+	return &WalletRegistry{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Wallets кошельки, которые можно подключить из бота: реестр, затем локальный файл, затем встроенный список
+func (r *WalletRegistry) Wallets() []models.TonConnectWallet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.wallets != nil && time.Since(r.loadedAt) < r.cfg.Ttl {
+		return r.wallets
+	}
+
+	wallets, err := r.fetch()
+	if err != nil {
+		log.Error("Failed to load wallets registry: ", err)
+		if r.wallets != nil {
+			// устаревший список лучше локального - повторим загрузку позже
+			r.loadedAt = time.Now()
+			return r.wallets
+		}
+		wallets, err = r.readFile()
+	}
+	if err != nil {
+		log.Error("Failed to read wallets list file: ", err)
+		wallets = builtinWallets()
+	}
+
+	r.wallets = r.filter(wallets)
+	if len(r.wallets) == 0 {
+		r.wallets = r.filter(builtinWallets())
+	}
+	r.loadedAt = time.Now()
+	return r.wallets
+}
+
+// Get кошелек по app_name или названию без учета регистра
+func (r *WalletRegistry) Get(name string) (*models.TonConnectWallet, bool) {
+	name = strings.ToLower(name)
+	for _, w := range r.Wallets() {
+		if strings.ToLower(w.AppName) == name || strings.ToLower(w.Name) == name {
+			return &w, true
+		}
+	}
+	return nil, false
+}
+
+func (r *WalletRegistry) fetch() ([]models.TonConnectWallet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.cfg.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wallets registry status %v", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWalletsList(data)
+}
+
+func (r *WalletRegistry) readFile() ([]models.TonConnectWallet, error) {
+	data, err := os.ReadFile(r.cfg.File)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWalletsList(data)
+}
+
+func (r *WalletRegistry) filter(wallets []models.TonConnectWallet) []models.TonConnectWallet {
+	if len(r.cfg.Only) == 0 {
+		return wallets
+	}
+	res := make([]models.TonConnectWallet, 0, len(r.cfg.Only))
+	for _, w := range wallets {
+		if slices.Contains(r.cfg.Only, strings.ToLower(w.AppName)) {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// ParseWalletsList разбирает wallets-v2.json. Из бота подключаются только кошельки
+// с universal link и HTTP (sse) бриджем
+func ParseWalletsList(data []byte) ([]models.TonConnectWallet, error) {
+	var list []registryWallet
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	wallets := make([]models.TonConnectWallet, 0, len(list))
+	for _, item := range list {
+		if item.UniversalUrl == "" {
+			continue
+		}
+		for _, b := range item.Bridge {
+			if b.Type == "sse" && b.Url != "" {
+				wallets = append(wallets, models.TonConnectWallet{
+					AppName:      item.AppName,
+					Name:         item.Name,
+					Image:        item.Image,
+					UniversalUrl: item.UniversalUrl,
+					BridgeUrl:    b.Url,
+				})
+				break
+			}
+		}
+	}
+	if len(wallets) == 0 {
+		return nil, ErrWalletsListEmpty
+	}
+	return wallets, nil
+}
+
+func builtinWallets() []models.TonConnectWallet {
+	wallets := make([]models.TonConnectWallet, 0, len(tonconnect.Wallets))
+	for _, appName := range builtinWalletsOrder {
+		w, ok := tonconnect.Wallets[appName]
+		if !ok {
+			continue
+		}
+		wallets = append(wallets, models.TonConnectWallet{
+			AppName:      appName,
+			Name:         w.Name,
+			UniversalUrl: w.UniversalURL,
+			BridgeUrl:    w.BridgeURL,
+		})
+	}
+	return wallets
+}
+
+func toTonConnectWallet(w models.TonConnectWallet) tonconnect.Wallet {
+	return tonconnect.Wallet{
+		Name:         w.Name,
+		UniversalURL: w.UniversalUrl,
+		BridgeURL:    w.BridgeUrl,
+	}
+}
@@ -2,10 +2,17 @@ package services
 
 import (
 	"errors"
+	"time"
+	"tonclient/internal/config"
 	"tonclient/internal/models"
 	"tonclient/internal/repositories"
 )
 
+var (
+	ErrWalletNotFound      = errors.New("wallet not found")
+	ErrDeleteDefaultWallet = errors.New("default wallet cannot be deleted")
+)
+
 type WalletTonService struct {
 	userService *UserService
 	walletRep   *repositories.WalletTonRepository
@@ -28,10 +35,12 @@ func (s *WalletTonService) CreateNewWallet(userId uint64, addr, name string) (*m
 		return nil, errors.New("address already exists")
 	}
 
+	// первый кошелек сразу становится основным, остальные - только через подтверждение
 	w = &models.WalletTon{
-		UserId: userId,
-		Addr:   addr,
-		Name:   name,
+		UserId:    userId,
+		Addr:      addr,
+		Name:      name,
+		IsDefault: len(s.walletRep.FindAllByUserId(userId)) == 0,
 	}
 
 	if err := s.walletRep.Save(w); err != nil {
@@ -91,10 +100,103 @@ func (s *WalletTonService) CountAll() int {
 	return s.userService.CountAll()
 }
 
+// GetByUserId основной кошелек пользователя, на который идут выплаты
 func (s *WalletTonService) GetByUserId(userId uint64) (*models.WalletTon, error) {
+	s.applyDueDefault(userId)
 	w := s.walletRep.FindByUserId(userId)
 	if w == nil {
-		return nil, errors.New("wallet not found")
+		return nil, ErrWalletNotFound
+	}
+	return w, nil
+}
+
+func (s *WalletTonService) GetUserWallets(userId uint64) []models.WalletTon {
+	s.applyDueDefault(userId)
+	return s.walletRep.FindAllByUserId(userId)
+}
+
+// GetUserWallet кошелек по id, если он принадлежит пользователю
+func (s *WalletTonService) GetUserWallet(userId, walletId uint64) (*models.WalletTon, error) {
+	w := s.walletRep.FindById(walletId)
+	if w == nil || w.UserId != userId {
+		return nil, ErrWalletNotFound
+	}
+	return w, nil
+}
+
+// RequestDefault подтвержденная пользователем смена основного кошелька.
+// Вступает в силу через PAYOUT_WALLET_COOLDOWN, если у пользователя уже есть основной кошелек
+func (s *WalletTonService) RequestDefault(userId, walletId uint64) (time.Time, error) {
+	w, err := s.GetUserWallet(userId, walletId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	if w.IsDefault {
+		return now, s.walletRep.SetDefaultAt(userId, 0, now)
+	}
+
+	current := s.walletRep.FindByUserId(userId)
+	if current == nil || !current.IsDefault {
+		return now, s.walletRep.SetDefault(userId, w)
+	}
+
+	at := now.Add(config.PayoutWalletCooldown())
+	return at, s.walletRep.SetDefaultAt(userId, walletId, at)
+}
+
+func (s *WalletTonService) CancelDefaultRequest(userId uint64) error {
+	return s.walletRep.SetDefaultAt(userId, 0, time.Now())
+}
+
+func (s *WalletTonService) SetLabel(userId, walletId uint64, label string) (*models.WalletTon, error) {
+	w, err := s.GetUserWallet(userId, walletId)
+	if err != nil {
+		return nil, err
+	}
+	w.Label = label
+	if err := s.walletRep.Update(w); err != nil {
+		return nil, err
 	}
 	return w, nil
 }
+
+// DeleteUserWallet удаляет кошелек пользователя. Основной кошелек удалить нельзя - на него идут выплаты
+func (s *WalletTonService) DeleteUserWallet(userId, walletId uint64) error {
+	w, err := s.GetUserWallet(userId, walletId)
+	if err != nil {
+		return err
+	}
+	if w.IsDefault {
+		return ErrDeleteDefaultWallet
+	}
+	return s.walletRep.DeleteById(walletId)
+}
+
+func (s *WalletTonService) applyDueDefault(userId uint64) {
+	w := s.walletRep.FindDueDefault(userId, time.Now())
+	if w == nil {
+		return
+	}
+	if err := s.walletRep.SetDefault(userId, w); err != nil {
+		log.Error("Failed to apply default wallet: ", err)
+	}
+}
+
+// UserAddrs все адреса пользователя: привязанные кошельки, кошельки депозитов и выплат
+func (s *WalletTonService) UserAddrs(userId uint64) []string {
+	return s.walletRep.FindUserAddrs(userId)
+}
+
+// AddrOwners другие пользователи, использующие любой из адресов в любой записи
+func (s *WalletTonService) AddrOwners(addrs []string, exceptUserId uint64) ([]uint64, error) {
+	forms := make([]string, 0, len(addrs)*3)
+	for _, addr := range addrs {
+		forms = append(forms, AddrForms(addr)...)
+	}
+	if len(forms) == 0 {
+		return nil, nil
+	}
+	return s.walletRep.FindAddrOwners(forms, exceptUserId)
+}
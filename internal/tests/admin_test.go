@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+	"tonclient/internal/config"
+)
+
+func TestAdminTelegramIds(t *testing.T) {
+	t.Setenv("ADMIN_TELEGRAM_IDS", " 100, 200,bad,,300 ")
+
+	ids := config.AdminTelegramIds()
+	if !slices.Equal(ids, []uint64{100, 200, 300}) {
+		t.Errorf("unexpected admin ids %v", ids)
+	}
+
+	t.Setenv("ADMIN_TELEGRAM_IDS", "")
+	if ids := config.AdminTelegramIds(); len(ids) != 0 {
+		t.Errorf("expected no admins, got %v", ids)
+	}
+}
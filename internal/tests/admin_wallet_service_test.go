@@ -42,7 +42,7 @@ func TestSendJetton(t *testing.T) {
 		"EQAJKTfw3qP0OFUba-1l7rtA7_TzXd9Cbm4DjNCaioCdofF_",
 		"UQAdpNJR-hZ72cPb70eFuQU3VDx8EcLsOEgm7K0Puh9cHA1d",
 		"test",
-		50,
+		"50",
 		9,
 	)
 	if err != nil {
@@ -95,15 +95,15 @@ func InitAdminService() *services.AdminWalletService {
 	ur := repositories.NewUserRepository(db.Db)
 	pr := repositories.NewPoolRepository(db.Db)
 	us := services.NewUserService(ur)
-	ps := services.NewPoolService(pr, us)
+	ps := services.NewPoolService(pr, repositories.NewRewardTierRepository(db.Db), repositories.NewPoolWhitelistRepository(db.Db), us)
 	tr := repositories.NewTelegramRepository(db.Db)
 	ts := services.NewTelegramService(tr, us)
-	rr := repositories.NewReferralRepository(db.Db)
-	rs := services.NewReferalService(rr)
 	stS := repositories.NewStakeRepository(db.Db)
 	ss := services.NewStakeService(stS, us, ps)
 	wr := repositories.NewWalletRepository(db.Db)
 	ws := services.NewWalletTonService(us, wr)
+	rr := repositories.NewReferralRepository(db.Db)
+	rs := services.NewReferalService(rr, us, ws)
 	ops := services.NewOperationService(repositories.NewOperationRepository(db.Db))
 	s, err := services.NewAdminWalletService(&config.TonClientConfig{
 		Seed:                seeds,
@@ -120,7 +120,12 @@ func InitAdminService() *services.AdminWalletService {
 	if err != nil {
 		log.Fatal("Failed connect to database: ", err)
 	}
-	bot := tonbot.NewTgBot("8112143412:AAE1EZ3rEmqNx4O41UYch1MtD7NLIxb6-i0", us, ts, ps, s, ss, ws, tcs, ops, rs)
+	is := services.NewTxIntentService(repositories.NewTxIntentRepository(db.Db), s)
+	tcs.TrackIntents(is)
+	as := services.NewAdminService(us, ps, ss, repositories.NewFailedPayoutRepository(db.Db))
+	ans := services.NewAnnouncementService(ps, repositories.NewAnnouncementRepository(db.Db))
+	ns := services.NewNotificationService(repositories.NewNotificationSettingRepository(db.Db))
+	bot := tonbot.NewTgBot("8112143412:AAE1EZ3rEmqNx4O41UYch1MtD7NLIxb6-i0", us, ts, ps, s, ss, ws, tcs, ops, rs, is, as, ans, ns)
 	go func() {
 		err := bot.StartBot(make(chan models.SubmitTransaction))
 		if err != nil {
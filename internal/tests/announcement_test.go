@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+)
+
+func TestParseAnnouncementTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	at, err := services.ParseAnnouncementTime("11.03.2025 09:30", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !at.Equal(time.Date(2025, 3, 11, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %v", at)
+	}
+
+	if _, err := services.ParseAnnouncementTime("10.03.2025 11:00", now); !errors.Is(err, services.ErrAnnouncementTime) {
+		t.Errorf("expected ErrAnnouncementTime, got %v", err)
+	}
+	if _, err := services.ParseAnnouncementTime("2025-03-11 09:30", now); err == nil {
+		t.Error("expected format error")
+	}
+}
+
+func TestDeliveryStatus(t *testing.T) {
+	if status, _ := util.DeliveryStatus(nil); status != models.DELIVERY_DELIVERED {
+		t.Errorf("expected delivered, got %v", status)
+	}
+
+	forbidden := fmt.Errorf("%w, %s", bot.ErrorForbidden, "bot was blocked by the user")
+	if status, _ := util.DeliveryStatus(forbidden); status != models.DELIVERY_BLOCKED {
+		t.Errorf("expected blocked, got %v", status)
+	}
+
+	status, retry := util.DeliveryStatus(&bot.TooManyRequestsError{Message: "too many requests", RetryAfter: 3})
+	if status != models.DELIVERY_PENDING || retry != 3*time.Second {
+		t.Errorf("expected pending with retry 3s, got %v %v", status, retry)
+	}
+
+	if status, _ := util.DeliveryStatus(errors.New("bad request")); status != models.DELIVERY_FAILED {
+		t.Errorf("expected failed, got %v", status)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tonclient/internal/handlers"
+)
+
+func TestApiRejectsInvalidRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	handlers.NewApi(nil, nil, nil, nil, "secret", []string{"https://dashboard.example.com"}).Register(mux)
+
+	cases := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"private without token", "/api/v1/users/1/stakes", "", http.StatusUnauthorized},
+		{"private with wrong token", "/api/v1/users/1/operations", "wrong", http.StatusUnauthorized},
+		{"invalid pool id", "/api/v1/pools/abc", "", http.StatusBadRequest},
+		{"invalid user id", "/api/v1/users/abc/pools", "secret", http.StatusBadRequest},
+		{"invalid limit", "/api/v1/pools?limit=1000", "", http.StatusBadRequest},
+		{"invalid active", "/api/v1/pools?active=maybe", "", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != c.status {
+			t.Errorf("%v: expected %v, got %v (%v)", c.name, c.status, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestApiCors(t *testing.T) {
+	mux := http.NewServeMux()
+	handlers.NewApi(nil, nil, nil, nil, "secret", []string{"https://dashboard.example.com"}).Register(mux)
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/users/1/stakes", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Headers", "authorization")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://dashboard.example.com" ||
+		!strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("preflight: got %v %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/users/1/stakes", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Access-Control-Allow-Origin") != "https://dashboard.example.com" {
+		t.Errorf("unauthorized: got %v %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tonclient/internal/handlers"
+)
+
+func TestWebAppCors(t *testing.T) {
+	mux := http.NewServeMux()
+	handlers.NewWebApp(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "token", nil, []string{"https://app.example.com"}).Register(mux)
+
+	req := httptest.NewRequest(http.MethodOptions, "/webapp/v1/stakes/1/claim", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("preflight: got %v %v", rec.Code, rec.Header())
+	}
+
+	// ответ без авторизации тоже должен быть доступен Mini App
+	req = httptest.NewRequest(http.MethodGet, "/webapp/v1/me", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("unauthorized: got %v %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "/webapp/v1/me", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unknown origin must not be allowed: %v", rec.Header())
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+)
+
+func TestEarlyExitQuote(t *testing.T) {
+	ss := services.NewStakeService(nil, nil, nil)
+	now := time.Now()
+	stake := appModels.Stake{
+		Amount:    1000,
+		Balance:   1100,
+		IsActive:  true,
+		StartDate: now.Add(-5 * 24 * time.Hour),
+	}
+	pool := appModels.Pool{
+		MinStakeAmount:   100,
+		EarlyExitPenalty: 10,
+		EarlyExitMinDays: 3,
+		EarlyExitPartial: true,
+		EarlyExitProRata: true,
+	}
+
+	q, err := ss.EarlyExitQuote(&stake, &pool, 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1000 - 10% штрафа + 100 награды
+	if math.Abs(q.Payout-1000) > 1e-9 || q.IsPartial || q.Forfeited != 0 {
+		t.Fatalf("unexpected full quote: %+v", q)
+	}
+
+	q, err = ss.EarlyExitQuote(&stake, &pool, 500, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// половина депозита: 500 - 50 штрафа + 50 награды
+	if math.Abs(q.Payout-500) > 1e-9 || !q.IsPartial || q.Remaining != 500 {
+		t.Fatalf("unexpected partial quote: %+v", q)
+	}
+
+	pool.EarlyExitProRata = false
+	q, err = ss.EarlyExitQuote(&stake, &pool, 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(q.Payout-900) > 1e-9 || math.Abs(q.Forfeited-100) > 1e-9 {
+		t.Fatalf("unexpected quote without reward: %+v", q)
+	}
+
+	if _, err := ss.EarlyExitQuote(&stake, &pool, 950, now); !errors.Is(err, services.ErrRemainingLessThanMinimum) {
+		t.Fatalf("expected min stake error, got %v", err)
+	}
+
+	pool.EarlyExitMinDays = 10
+	if _, err := ss.EarlyExitQuote(&stake, &pool, 0, now); !errors.Is(err, services.ErrEarlyExitLocked) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"tonclient/internal/i18n"
+	"tonclient/internal/tonbot/buttons"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":      i18n.RU,
+		"ru":    i18n.RU,
+		"en":    i18n.EN,
+		"en-US": i18n.EN,
+		"de":    i18n.EN,
+	}
+	for code, want := range cases {
+		if got := i18n.Detect(code); got != want {
+			t.Errorf("Detect(%q) = %v, want %v", code, got, want)
+		}
+	}
+
+	if got := i18n.Resolve(i18n.RU, "en"); got != i18n.RU {
+		t.Errorf("override must win over language code, got %v", got)
+	}
+	if got := i18n.Resolve("", "en"); got != i18n.EN {
+		t.Errorf("empty override must use language code, got %v", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := i18n.T(i18n.EN, "❌ Комиссия должна быть %v.", 5); got != "❌ The commission must be 5." {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := i18n.T(i18n.RU, "❌ Комиссия должна быть %v.", 5); got != "❌ Комиссия должна быть 5." {
+		t.Errorf("unexpected russian text %q", got)
+	}
+	// текст без перевода показывается как есть
+	if got := i18n.T(i18n.EN, "Нет в каталоге"); got != "Нет в каталоге" {
+		t.Errorf("unexpected fallback %q", got)
+	}
+	if got := i18n.T(i18n.EN, buttons.Profile); got == buttons.Profile {
+		t.Errorf("menu key %v has no english label", buttons.Profile)
+	}
+}
+
+func TestReplyKey(t *testing.T) {
+	for _, lang := range i18n.Languages {
+		label := i18n.T(lang, buttons.SelectPool)
+		if got := buttons.ReplyKey(label); got != buttons.SelectPool {
+			t.Errorf("ReplyKey(%q) = %q, want %q", label, got, buttons.SelectPool)
+		}
+	}
+	if got := buttons.ReplyKey("привет"); got != "" {
+		t.Errorf("unexpected key %q for free text", got)
+	}
+}
+
+var formatVerb = regexp.MustCompile(`%(\.\d+)?[vdsf%]`)
+
+func TestCatalogFormatVerbs(t *testing.T) {
+	for key, text := range i18n.Messages(i18n.EN) {
+		want := formatVerb.FindAllString(key, -1)
+		got := formatVerb.FindAllString(text, -1)
+		if len(want) != len(got) {
+			t.Errorf("verbs of %q: %v, want %v", text, got, want)
+			continue
+		}
+		for i := range want {
+			if want[i] != got[i] {
+				t.Errorf("verbs of %q: %v, want %v", text, got, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/util"
+)
+
+func TestCalculateInsuranceInAsset(t *testing.T) {
+	stake := appModels.Stake{
+		Amount:               1000,
+		DepositCreationPrice: 0.1,
+		JettonPriceClosed:    0.05,
+		InsuranceAssetPrice:  1,
+	}
+
+	// потеря 50$ при курсе USDT 1$
+	if res := util.CalculateInsuranceInAsset(&stake); math.Abs(res-50) > 1e-9 {
+		t.Fatalf("expected 50, got %v", res)
+	}
+
+	// компенсация не больше 90% стоимости на входе
+	stake.JettonPriceClosed = 0
+	if res := util.CalculateInsuranceInAsset(&stake); math.Abs(res-90) > 1e-9 {
+		t.Fatalf("expected 90, got %v", res)
+	}
+
+	stake.InsuranceAssetPrice = 0
+	if res := util.CalculateInsuranceInAsset(&stake); res != 0 {
+		t.Fatalf("expected 0 without asset price, got %v", res)
+	}
+}
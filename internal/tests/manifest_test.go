@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"tonclient/internal/config"
+	"tonclient/internal/handlers"
+)
+
+func TestManifestTemplating(t *testing.T) {
+	dir := t.TempDir()
+	defaults := filepath.Join(dir, "tonconnect-manifest.json")
+	if err := os.WriteFile(defaults, []byte(`{"url":"https://t.me/default_bot","name":"DEFAULT","iconUrl":"https://cdn/icon.png"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	icon := filepath.Join(dir, "icon.png")
+	if err := os.WriteFile(icon, []byte("\x89PNG\r\n\x1a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := handlers.ManifestFile
+	handlers.ManifestFile = defaults
+	defer func() { handlers.ManifestFile = prev }()
+
+	m, err := handlers.NewManifest(&config.ManifestConfig{
+		PublicUrl: "https://bot.example.com",
+		Name:      "NESTRAH",
+		IconFile:  icon,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	m.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tonconnect-manifest.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %v", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Cache-Control") == "" {
+		t.Errorf("missing CORS or cache headers: %v", rec.Header())
+	}
+
+	var manifest handlers.TonConnectManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Url != "https://t.me/default_bot" || manifest.Name != "NESTRAH" ||
+		manifest.IconUrl != "https://bot.example.com/tonconnect-icon.png" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tonconnect-icon.png", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("icon: expected 200 image/png, got %v %v", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/tonconnect-manifest.json", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("preflight: expected 204, got %v", rec.Code)
+	}
+}
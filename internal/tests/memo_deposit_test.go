@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"tonclient/internal/services"
+)
+
+func TestMemo(t *testing.T) {
+	memo, err := services.GenerateMemo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services.NormalizeMemo(memo) != memo {
+		t.Fatalf("generated memo %v is not valid", memo)
+	}
+	if services.NormalizeMemo("  "+strings.ToLower(memo)+"\n") != memo {
+		t.Error("memo must be matched regardless of case and spaces")
+	}
+	for _, comment := range []string{"", "hello", "NS-", memo + "X"} {
+		if services.NormalizeMemo(comment) != "" {
+			t.Errorf("comment %q must not be a memo", comment)
+		}
+	}
+}
+
+func TestTransferLink(t *testing.T) {
+	link := services.TransferLink(
+		"EQD6A01mB8tAKJVekRrMjoA3l188LSCF2zrIHoH94tWhZDvL",
+		"EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs",
+		"1500000",
+		"NS-ABC234",
+	)
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "ton" || u.Host != "transfer" || u.Path != "/EQD6A01mB8tAKJVekRrMjoA3l188LSCF2zrIHoH94tWhZDvL" {
+		t.Errorf("unexpected link %v", link)
+	}
+	q := u.Query()
+	if q.Get("jetton") != "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs" || q.Get("amount") != "1500000" || q.Get("text") != "NS-ABC234" {
+		t.Errorf("unexpected query %v", q)
+	}
+
+	if strings.Contains(services.TransferLink("EQD6A01mB8tAKJVekRrMjoA3l188LSCF2zrIHoH94tWhZDvL", "", "1", "NS-ABC234"), "jetton=") {
+		t.Error("TON transfer must not contain jetton parameter")
+	}
+}
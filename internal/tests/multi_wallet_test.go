@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+	"tonclient/internal/util"
+)
+
+func TestPayoutAddr(t *testing.T) {
+	w := &models.WalletTon{Addr: "EQ-default"}
+
+	if addr := util.PayoutAddr(&models.Stake{}, w); addr != w.Addr {
+		t.Errorf("stake without payout address must use default wallet, got %v", addr)
+	}
+	if addr := util.PayoutAddr(&models.Stake{PayoutAddr: "EQ-stake"}, w); addr != "EQ-stake" {
+		t.Errorf("stake payout address must be used, got %v", addr)
+	}
+}
+
+func TestPayoutWalletCooldown(t *testing.T) {
+	t.Setenv("PAYOUT_WALLET_COOLDOWN", "")
+	if d := config.PayoutWalletCooldown(); d != 24*time.Hour {
+		t.Errorf("default cooldown must be 24h, got %v", d)
+	}
+
+	t.Setenv("PAYOUT_WALLET_COOLDOWN", "2h")
+	if d := config.PayoutWalletCooldown(); d != 2*time.Hour {
+		t.Errorf("expected 2h, got %v", d)
+	}
+
+	t.Setenv("PAYOUT_WALLET_COOLDOWN", "soon")
+	if d := config.PayoutWalletCooldown(); d != 24*time.Hour {
+		t.Errorf("invalid value must fall back to 24h, got %v", d)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"tonclient/internal/i18n"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+)
+
+func TestNotificationText(t *testing.T) {
+	data := &models.NotificationData{
+		JettonName: "NESTRAH",
+		Amount:     100,
+		Balance:    104.5,
+		Profit:     4.5,
+		Reward:     1.5,
+		EndDate:    time.Date(2025, 5, 1, 10, 30, 0, 0, time.UTC),
+	}
+
+	text := util.NotificationText(i18n.RU, models.NOTIFY_STAKE_CREATED, data)
+	for _, want := range []string{"Стейк создан", "100 NESTRAH", "1.5% в день", "01.05.2025 10:30"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("stake created text %q does not contain %q", text, want)
+		}
+	}
+
+	owner := *data
+	owner.Owner = true
+	if text := util.NotificationText(i18n.RU, models.NOTIFY_STAKE_CREATED, &owner); !strings.HasPrefix(text, "✅ Новый стейк в вашем пуле NESTRAH") {
+		t.Errorf("unexpected owner text %q", text)
+	}
+
+	data.PriceChange = -32.5
+	data.Coverage = 30
+	if text := util.NotificationText(i18n.RU, models.NOTIFY_INSURANCE, data); !strings.Contains(text, "упала на 32.5%") {
+		t.Errorf("unexpected insurance text %q", text)
+	}
+
+	for _, lang := range i18n.Languages {
+		for _, event := range services.NotificationEvents {
+			if util.NotificationText(lang, event, data) == "" {
+				t.Errorf("no %v template for event %v", lang, event)
+			}
+		}
+	}
+}
+
+func TestNotificationDefault(t *testing.T) {
+	if services.NotificationDefault(models.NOTIFY_DAILY_ACCRUAL) {
+		t.Error("daily accrual must be disabled by default")
+	}
+	if !services.NotificationDefault(models.NOTIFY_MATURED) {
+		t.Error("matured must be enabled by default")
+	}
+}
+
+var templateAction = regexp.MustCompile(`{{-?\s*([^}]*?)\s*-?}}`)
+
+// TestCatalogNotificationTemplates каждый язык переводит шаблоны всех событий с теми же полями
+func TestCatalogNotificationTemplates(t *testing.T) {
+	actions := func(text string) []string {
+		var res []string
+		for _, m := range templateAction.FindAllStringSubmatch(text, -1) {
+			res = append(res, m[1])
+		}
+		slices.Sort(res)
+		return res
+	}
+
+	ru := i18n.Messages(i18n.RU)
+	for _, lang := range i18n.Languages {
+		messages := i18n.Messages(lang)
+		for _, event := range services.NotificationEvents {
+			key := util.NotificationKey(event)
+			text, ok := messages[key]
+			if !ok {
+				t.Errorf("no %v template for event %v", lang, event)
+				continue
+			}
+			if got, want := actions(text), actions(ru[key]); !slices.Equal(got, want) {
+				t.Errorf("actions of %v %v: %v, want %v", lang, key, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+)
+
+func TestCheckPoolLimits(t *testing.T) {
+	ss := services.NewStakeService(nil, nil, nil)
+	pool := &models.Pool{
+		MaxTotalStake: 1000,
+		MaxUserStake:  300,
+		MaxStakers:    2,
+	}
+
+	cases := []struct {
+		name   string
+		totals models.PoolStakeTotals
+		amount float64
+		want   error
+	}{
+		{"fits", models.PoolStakeTotals{Total: 500, Stakers: 1, UserTotal: 0}, 300, nil},
+		{"total exceeded", models.PoolStakeTotals{Total: 900, Stakers: 1}, 200, services.ErrPoolMaxTotalStake},
+		{"user exceeded", models.PoolStakeTotals{Total: 200, Stakers: 1, UserTotal: 200, IsStaker: true}, 150, services.ErrPoolMaxUserStake},
+		{"stakers full", models.PoolStakeTotals{Total: 400, Stakers: 2}, 100, services.ErrPoolMaxStakers},
+		{"existing staker when full", models.PoolStakeTotals{Total: 400, Stakers: 2, UserTotal: 100, IsStaker: true}, 100, nil},
+	}
+
+	for _, c := range cases {
+		err := ss.CheckPoolLimits(pool, &c.totals, c.amount)
+		if !errors.Is(err, c.want) {
+			t.Errorf("%v: expected %v, got %v", c.name, c.want, err)
+		}
+	}
+
+	if err := ss.CheckPoolLimits(&models.Pool{}, &models.PoolStakeTotals{Total: 1e9, Stakers: 1e6}, 1e9); err != nil {
+		t.Errorf("pool without limits: expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"tonclient/internal/i18n"
+	"tonclient/internal/models"
+	"tonclient/internal/util"
+)
+
+func TestPriceAlertLevel(t *testing.T) {
+	cases := []struct {
+		change float64
+		want   int
+	}{
+		{10, models.PRICE_ALERT_NONE},
+		{-24.9, models.PRICE_ALERT_NONE},
+		{-25.5, models.PRICE_ALERT_NEAR},
+		{-30, models.PRICE_ALERT_NEAR},
+		{-30.1, models.PRICE_ALERT_TRIGGERED},
+	}
+	for _, c := range cases {
+		if got := util.PriceAlertLevel(c.change, 30, 5); got != c.want {
+			t.Errorf("change %v: expected level %v, got %v", c.change, c.want, got)
+		}
+	}
+
+	if got := util.PriceAlertLevel(-29, 30, 0); got != models.PRICE_ALERT_NONE {
+		t.Errorf("zero margin must not warn before the threshold, got %v", got)
+	}
+}
+
+func TestPriceAlertText(t *testing.T) {
+	data := &models.NotificationData{JettonName: "NESTRAH", Amount: 100, PriceChange: -26.04, Coverage: 30}
+	if text := util.NotificationText(i18n.RU, models.NOTIFY_PRICE_ALERT, data); !strings.Contains(text, "упала на 26%") {
+		t.Errorf("unexpected near text %q", text)
+	}
+
+	data.PriceChange = -31.25
+	data.Triggered = true
+	if text := util.NotificationText(i18n.RU, models.NOTIFY_PRICE_ALERT, data); !strings.Contains(text, "порог страховки 30% пройден") {
+		t.Errorf("unexpected triggered text %q", text)
+	}
+}
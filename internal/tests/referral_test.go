@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+)
+
+func TestReferralRewards(t *testing.T) {
+	stake := &models.Stake{Id: sql.NullInt64{Int64: 7, Valid: true}, UserId: 3}
+	chain := []models.User{
+		{Id: sql.NullInt64{Int64: 2, Valid: true}},
+		{Id: sql.NullInt64{Int64: 1, Valid: true}},
+		{Id: sql.NullInt64{Int64: 9, Valid: true}},
+	}
+	now := time.Unix(1_800_000_000, 0)
+
+	refs := services.ReferralRewards(stake, chain, []float64{5, 2}, models.REFERRAL_BASE_STAKE, models.REFERRAL_MODE_EVERY, 1000, now)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 levels, got %v", len(refs))
+	}
+	if refs[0].ReferrerUserId.Int64 != 2 || refs[0].Level != 1 || refs[0].RewardAmount != 50 {
+		t.Errorf("unexpected first level %+v", refs[0])
+	}
+	if refs[1].ReferrerUserId.Int64 != 1 || refs[1].Level != 2 || refs[1].RewardAmount != 20 {
+		t.Errorf("unexpected second level %+v", refs[1])
+	}
+	if refs[1].ReferralUserId.Int64 != 3 || refs[1].StakeId.Int64 != 7 {
+		t.Errorf("accrual must reference stake and referral: %+v", refs[1])
+	}
+}
+
+func TestReferralBaseAmount(t *testing.T) {
+	t.Setenv("JETTON_CONTRACT_ADMIN_JETTON", "platform")
+	pool := &models.Pool{JettonMaster: "asset"}
+	stake := &models.Stake{Amount: 100, Commission: 2, CommissionAsset: models.COMMISSION_ASSET_TON}
+	prices := map[string]float64{"asset": 0.5, "platform": 0.25, config.TON_NATIVE_ADDR: 5}
+	price := func(addr string) float64 { return prices[addr] }
+
+	amount, err := services.ReferralBaseAmount(stake, pool, models.REFERRAL_BASE_STAKE, price)
+	if err != nil || amount != 200 {
+		t.Errorf("stake base: expected 200, got %v (%v)", amount, err)
+	}
+	amount, err = services.ReferralBaseAmount(stake, pool, models.REFERRAL_BASE_COMMISSION, price)
+	if err != nil || amount != 40 {
+		t.Errorf("commission base: expected 40, got %v (%v)", amount, err)
+	}
+
+	delete(prices, "asset")
+	if _, err := services.ReferralBaseAmount(stake, pool, models.REFERRAL_BASE_STAKE, price); !errors.Is(err, services.ErrReferralPrice) {
+		t.Errorf("unknown price: expected ErrReferralPrice, got %v", err)
+	}
+}
+
+func TestGenerateReferralCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := services.GenerateReferralCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !services.IsReferralCode(code) {
+			t.Fatalf("generated code %q has invalid format", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+
+	// прежние ссылки с base64 telegram id больше не принимаются
+	for _, code := range []string{"", "MTIzNDU2Nzg=", "NTU1NTU1", "abcdefg0", "ABCDEFGH"} {
+		if services.IsReferralCode(code) {
+			t.Errorf("code %q must be rejected", code)
+		}
+	}
+}
+
+func TestCheckReferralStake(t *testing.T) {
+	start := time.Unix(1_800_000_000, 0)
+	pool := &models.Pool{Period: 30}
+	cfg := &config.ReferralConfig{MinStake: 100, MinHold: 14}
+	stake := &models.Stake{
+		StartDate: start,
+		Period:    30,
+		CloseDate: start.Add(30 * 24 * time.Hour).Add(time.Second),
+	}
+
+	if err := services.CheckReferralStake(stake, pool, 150, cfg, start); err != nil {
+		t.Errorf("matured stake: expected eligible, got %v", err)
+	}
+	if err := services.CheckReferralStake(stake, pool, 50, cfg, start); !errors.Is(err, services.ErrReferralMinStake) {
+		t.Errorf("small stake: expected ErrReferralMinStake, got %v", err)
+	}
+
+	early := *stake
+	early.CloseDate = start.Add(20 * 24 * time.Hour)
+	if err := services.CheckReferralStake(&early, pool, 150, cfg, start); !errors.Is(err, services.ErrReferralEarlyClose) {
+		t.Errorf("early exit: expected ErrReferralEarlyClose, got %v", err)
+	}
+
+	short := &models.Stake{StartDate: start, Period: 7, CloseDate: start.Add(7 * 24 * time.Hour)}
+	if err := services.CheckReferralStake(short, pool, 150, cfg, start); !errors.Is(err, services.ErrReferralHoldTime) {
+		t.Errorf("short period: expected ErrReferralHoldTime, got %v", err)
+	}
+}
+
+func TestCheckReferralWallets(t *testing.T) {
+	chain := []models.User{
+		{Id: sql.NullInt64{Int64: 2, Valid: true}},
+		{Id: sql.NullInt64{Int64: 1, Valid: true}},
+	}
+
+	if err := services.CheckReferralWallets(nil, chain); err != nil {
+		t.Errorf("own wallet: expected eligible, got %v", err)
+	}
+	if err := services.CheckReferralWallets([]uint64{1}, chain); !errors.Is(err, services.ErrReferralSameWallet) {
+		t.Errorf("referrer wallet: expected ErrReferralSameWallet, got %v", err)
+	}
+	if err := services.CheckReferralWallets([]uint64{5}, chain); !errors.Is(err, services.ErrReferralSharedWallet) {
+		t.Errorf("other account wallet: expected ErrReferralSharedWallet, got %v", err)
+	}
+
+	stake := &models.Stake{Id: sql.NullInt64{Int64: 7, Valid: true}, UserId: 3}
+	first := services.ReferralRewards(stake, chain, []float64{5}, models.REFERRAL_BASE_STAKE, models.REFERRAL_MODE_ONCE, 100, time.Now())
+	stake.Id.Int64 = 8
+	second := services.ReferralRewards(stake, chain, []float64{5}, models.REFERRAL_BASE_STAKE, models.REFERRAL_MODE_ONCE, 100, time.Now())
+	if first[0].IdemKey != second[0].IdemKey {
+		t.Errorf("once mode: stakes of one referral must share a key, got %q and %q", first[0].IdemKey, second[0].IdemKey)
+	}
+}
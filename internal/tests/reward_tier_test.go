@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+)
+
+func TestResolveReward(t *testing.T) {
+	ps := services.NewPoolService(nil, nil, nil, nil)
+	now := time.Now()
+	pool := appModels.Pool{Reward: 1, Period: 30}
+	tiers := []appModels.RewardTier{
+		{Period: 30, MinAmount: 1000, Reward: 1.5},
+		{Period: 60, Reward: 2},
+		{Period: 60, Reward: 2.5, StartsAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}},
+		{Period: 60, Reward: 3, StartsAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true}},
+	}
+
+	cases := []struct {
+		amount float64
+		period uint
+		want   float64
+	}{
+		{100, 30, 1},
+		{1000, 30, 1.5},
+		{100, 60, 2.5},
+	}
+	for _, c := range cases {
+		got, err := ps.ResolveReward(&pool, tiers, c.amount, c.period, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Fatalf("amount %v period %v: expected %v, got %v", c.amount, c.period, c.want, got)
+		}
+	}
+
+	if _, err := ps.ResolveReward(&pool, tiers, 100, 7, now); err == nil {
+		t.Fatal("expected error for unknown period")
+	}
+}
+
+func TestCheckStakeTerms(t *testing.T) {
+	ps := services.NewPoolService(nil, nil, nil, nil)
+	now := time.Now()
+	pool := appModels.Pool{Reward: 1, Period: 30}
+	tiers := []appModels.RewardTier{{Period: 60, MinAmount: 1000, Reward: 2}}
+	start := now.Add(-time.Minute)
+
+	stake := appModels.Stake{StartDate: start, Period: 60, Reward: 2}
+	if err := ps.CheckStakeTerms(&pool, tiers, &stake, 1000, now); err != nil {
+		t.Fatal(err)
+	}
+	if !stake.EndDate.Equal(start.Add(60 * 24 * time.Hour)) {
+		t.Fatalf("unexpected end date %v", stake.EndDate)
+	}
+
+	cases := []appModels.Stake{
+		{StartDate: start, Period: 60, Reward: 5},
+		{StartDate: start, Period: 7, Reward: 1},
+		{StartDate: now.Add(-48 * time.Hour), Period: 30, Reward: 1},
+	}
+	for _, c := range cases {
+		if err := ps.CheckStakeTerms(&pool, tiers, &c, 1000, now); !errors.Is(err, services.ErrStakeTerms) {
+			t.Fatalf("period %v reward %v: expected ErrStakeTerms, got %v", c.Period, c.Reward, err)
+		}
+	}
+	// ставка тарифа действует только от его минимальной суммы
+	stake = appModels.Stake{StartDate: start, Period: 60, Reward: 2}
+	if err := ps.CheckStakeTerms(&pool, tiers, &stake, 999, now); err == nil {
+		t.Fatal("expected error for deposit below tier min amount")
+	}
+}
+
+func TestResolveCommission(t *testing.T) {
+	t.Setenv("COMMISSION_STAKE_ASSET", "jetton")
+	t.Setenv("COMMISSION_STAKE_TYPE", "fixed")
+	t.Setenv("COMMISSION_STAKE_AMOUNT", "2")
+	t.Setenv("COMMISSION_REFERRAL_DISCOUNT", "50")
+
+	ps := services.NewPoolService(nil, nil, nil, nil)
+	now := time.Now()
+	pool := appModels.Pool{Reward: 1, Period: 30, JettonMaster: "stake-jetton"}
+	tiers := []appModels.RewardTier{
+		{Period: 60, Reward: 2, CommissionAsset: appModels.COMMISSION_ASSET_STAKE, CommissionType: appModels.COMMISSION_TYPE_PERCENT, CommissionValue: 1},
+		{Period: 90, Reward: 3, CommissionAsset: appModels.COMMISSION_ASSET_TON, CommissionType: appModels.COMMISSION_TYPE_PERCENT, CommissionValue: 10},
+	}
+	// stake-jetton стоит 0.5$, TON - 5$
+	price := func(addr string) float64 {
+		if addr == "stake-jetton" {
+			return 0.5
+		}
+		return 5
+	}
+	user := &appModels.User{}
+	referral := &appModels.User{RefererId: sql.NullInt64{Int64: 1, Valid: true}}
+
+	cases := []struct {
+		user   *appModels.User
+		period uint
+		asset  string
+		want   float64
+	}{
+		{user, 30, appModels.COMMISSION_ASSET_JETTON, 2},
+		{referral, 30, appModels.COMMISSION_ASSET_JETTON, 1},
+		{user, 60, appModels.COMMISSION_ASSET_STAKE, 10},
+		{user, 90, appModels.COMMISSION_ASSET_TON, 10},
+	}
+	for _, c := range cases {
+		got, err := ps.ResolveCommission(&pool, tiers, c.user, 1000, c.period, now, price)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Asset != c.asset || got.Amount != c.want {
+			t.Fatalf("period %v: expected %v %v, got %v %v", c.period, c.want, c.asset, got.Amount, got.Asset)
+		}
+	}
+
+	policy := appModels.CommissionPolicy{Asset: appModels.COMMISSION_ASSET_STAKE, Type: appModels.COMMISSION_TYPE_FIXED, Value: 5}
+	if _, err := services.CalcCommission(policy, 5, 0, 0, 0); !errors.Is(err, services.ErrCommissionAmount) {
+		t.Fatalf("expected ErrCommissionAmount, got %v", err)
+	}
+	policy = appModels.CommissionPolicy{Asset: appModels.COMMISSION_ASSET_TON, Type: appModels.COMMISSION_TYPE_PERCENT, Value: 1}
+	if _, err := services.CalcCommission(policy, 100, 0, 0, 5); !errors.Is(err, services.ErrCommissionPrice) {
+		t.Fatalf("expected ErrCommissionPrice, got %v", err)
+	}
+}
+
+func TestCheckStakeCommission(t *testing.T) {
+	t.Setenv("COMMISSION_STAKE_ASSET", "stake")
+	t.Setenv("COMMISSION_STAKE_TYPE", "percent")
+	t.Setenv("COMMISSION_STAKE_AMOUNT", "1")
+	t.Setenv("COMMISSION_REFERRAL_DISCOUNT", "0")
+
+	ps := services.NewPoolService(nil, nil, nil, nil)
+	pool := appModels.Pool{Reward: 1, Period: 30, JettonMaster: "stake-jetton"}
+	tiers := []appModels.RewardTier{
+		{Period: 60, Reward: 2, CommissionAsset: appModels.COMMISSION_ASSET_TON, CommissionType: appModels.COMMISSION_TYPE_FIXED, CommissionValue: 3},
+	}
+	price := func(string) float64 { return 1 }
+	start := time.Now()
+
+	// 1% удерживается из депозита 1000
+	stake := appModels.Stake{StartDate: start, Period: 30, Amount: 990, Commission: 10, CommissionAsset: appModels.COMMISSION_ASSET_STAKE}
+	if err := ps.CheckStakeCommission(&pool, tiers, nil, &stake, 1000, price); err != nil {
+		t.Fatal(err)
+	}
+	if !services.CommissionPaid(&pool, &stake, nil) {
+		t.Fatal("commission from the stake is paid with the deposit")
+	}
+
+	cases := []appModels.Stake{
+		{StartDate: start, Period: 30, Amount: 1000, Commission: 0, CommissionAsset: appModels.COMMISSION_ASSET_STAKE},
+		{StartDate: start, Period: 30, Amount: 999, Commission: 1, CommissionAsset: appModels.COMMISSION_ASSET_STAKE},
+		{StartDate: start, Period: 60, Amount: 1000, Commission: 1, CommissionAsset: appModels.COMMISSION_ASSET_TON},
+		{StartDate: start, Period: 60, Amount: 1000, Commission: 3, CommissionAsset: appModels.COMMISSION_ASSET_JETTON},
+	}
+	for _, c := range cases {
+		if err := ps.CheckStakeCommission(&pool, tiers, nil, &c, 1000, price); !errors.Is(err, services.ErrCommissionMismatch) {
+			t.Fatalf("commission %v %v: expected ErrCommissionMismatch, got %v", c.Commission, c.CommissionAsset, err)
+		}
+	}
+
+	stake = appModels.Stake{StartDate: start, Period: 60, Amount: 1000, Commission: 3, CommissionAsset: appModels.COMMISSION_ASSET_TON}
+	if err := ps.CheckStakeCommission(&pool, tiers, nil, &stake, 1000, price); err != nil {
+		t.Fatal(err)
+	}
+	if services.CommissionPaid(&pool, &stake, nil) {
+		t.Fatal("commission in TON is not paid without a transfer")
+	}
+	if services.CommissionPaid(&pool, &stake, &appModels.Payload{JettonMaster: "", Received: 2}) {
+		t.Fatal("commission is not paid by a smaller transfer")
+	}
+	if services.CommissionPaid(&pool, &stake, &appModels.Payload{JettonMaster: "stake-jetton", Received: 3}) {
+		t.Fatal("commission is not paid in another asset")
+	}
+	if !services.CommissionPaid(&pool, &stake, &appModels.Payload{JettonMaster: "", Received: 3}) {
+		t.Fatal("commission in TON must be paid by a TON transfer")
+	}
+}
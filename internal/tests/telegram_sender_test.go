@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/util"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := util.NewRateLimiter(&config.TelegramSenderConfig{GlobalRate: 10, ChatInterval: time.Second, ChatBurst: 3})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// подряд в чат уходят ChatBurst сообщений с общим интервалом 100ms, дальше раз в секунду
+	expected := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, time.Second, 2 * time.Second}
+	for i, want := range expected {
+		if got := l.Reserve(1, now); got != want {
+			t.Errorf("message %v: expected wait %v, got %v", i, want, got)
+		}
+	}
+
+	// другой чат ждет только общий лимит
+	if got := l.Reserve(2, now); got != 2100*time.Millisecond {
+		t.Errorf("expected global wait 2.1s, got %v", got)
+	}
+
+	later := now.Add(time.Hour)
+	l.Pause(3, 5*time.Second, later)
+	if got := l.Reserve(3, later); got != 5*time.Second {
+		t.Errorf("expected retry_after wait 5s, got %v", got)
+	}
+}
@@ -66,14 +66,14 @@ func TestTonConnectServiceAndConncect_GenerateConnectUrls(t *testing.T) {
 		}
 	}()
 
-	urls, err := tcs.GenerateConnectUrls(s)
+	urls, err := tcs.GenerateConnectUrls("TEST", s)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	fmt.Println(urls)
 
-	_, err = tcs.Connect(s)
+	_, err = tcs.Connect("TEST", s)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"tonclient/internal/services"
+
+	"github.com/cameo-engineering/tonconnect"
+)
+
+func TestSealSession(t *testing.T) {
+	s, err := tonconnect.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ключ кошелька появляется после подключения
+	s.ClientID = s.ID
+	s.BridgeURL = "https://bridge.tonapi.io/bridge"
+
+	key := sha256.Sum256([]byte("secret"))
+	data, err := services.SealSession(key[:], s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(s.BridgeURL)) {
+		t.Fatal("session must not be stored in plain text")
+	}
+
+	opened, err := services.OpenSession(key[:], data)
+	if err != nil {
+		t.Fatalf("expected session, got %v", err)
+	}
+	if !bytes.Equal(opened.PrivateKey[:], s.PrivateKey[:]) || opened.BridgeURL != s.BridgeURL {
+		t.Fatal("decrypted session differs from original")
+	}
+
+	otherKey := sha256.Sum256([]byte("other"))
+	if _, err := services.OpenSession(otherKey[:], data); !errors.Is(err, services.ErrSessionCorrupt) {
+		t.Errorf("foreign key: expected ErrSessionCorrupt, got %v", err)
+	}
+
+	data[len(data)-1] ^= 0xff
+	if _, err := services.OpenSession(key[:], data); !errors.Is(err, services.ErrSessionCorrupt) {
+		t.Errorf("tampered data: expected ErrSessionCorrupt, got %v", err)
+	}
+}
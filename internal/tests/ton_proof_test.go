@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/wallet"
+)
+
+func TestVerifyTonProof(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateInit, err := wallet.GetStateInit(pub, wallet.V4R2, wallet.DefaultSubwallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateCell, err := tlb.ToCell(stateInit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := stateInit.CalcAddress(0)
+
+	now := time.Unix(1_800_000_000, 0)
+	proof := &models.TonProof{
+		Address:   addr.StringRaw(),
+		StateInit: stateCell.ToBOC(),
+		Timestamp: now.Add(-time.Minute).Unix(),
+		Domain:    "t.me",
+		Payload:   "nonce",
+	}
+	proof.Signature = ed25519.Sign(priv, services.TonProofHash(addr, proof))
+
+	key, err := services.ProofPublicKey(addr, proof.StateInit)
+	if err != nil {
+		t.Fatalf("expected public key from state init, got %v", err)
+	}
+	if !bytes.Equal(key, pub) {
+		t.Fatalf("unexpected public key %x", key)
+	}
+
+	if err := services.VerifyTonProof(proof, key, []string{"t.me"}, now); err != nil {
+		t.Fatalf("expected valid proof, got %v", err)
+	}
+	if err := services.VerifyTonProof(proof, key, []string{"example.com"}, now); !errors.Is(err, services.ErrProofDomain) {
+		t.Errorf("foreign domain: expected ErrProofDomain, got %v", err)
+	}
+	if err := services.VerifyTonProof(proof, key, []string{"t.me"}, now.Add(time.Hour)); !errors.Is(err, services.ErrProofExpired) {
+		t.Errorf("old proof: expected ErrProofExpired, got %v", err)
+	}
+
+	tampered := *proof
+	tampered.Payload = "other"
+	if err := services.VerifyTonProof(&tampered, key, []string{"t.me"}, now); !errors.Is(err, services.ErrProofSignature) {
+		t.Errorf("tampered payload: expected ErrProofSignature, got %v", err)
+	}
+
+	// подпись другого кошелька на тот же адрес не принимается
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	otherState, _ := wallet.GetStateInit(otherPub, wallet.V4R2, wallet.DefaultSubwallet)
+	otherCell, _ := tlb.ToCell(otherState)
+	if _, err := services.ProofPublicKey(addr, otherCell.ToBOC()); !errors.Is(err, services.ErrProofStateInit) {
+		t.Errorf("foreign state init: expected ErrProofStateInit, got %v", err)
+	}
+}
+
+func TestSameAddress(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	addr, err := wallet.AddressFromPubKey(pub, wallet.V4R2, wallet.DefaultSubwallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !services.SameAddress(addr.StringRaw(), addr.Bounce(false).String()) {
+		t.Error("raw and non-bounceable forms of one address must be equal")
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	other, _ := wallet.AddressFromPubKey(otherPub, wallet.V4R2, wallet.DefaultSubwallet)
+	if services.SameAddress(addr.String(), other.String()) {
+		t.Error("different addresses must not be equal")
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"tonclient/internal/models"
+	"tonclient/internal/services"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+func TestParseSignedBoc(t *testing.T) {
+	dst := address.MustParseAddr("EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs")
+	body := cell.BeginCell().MustStoreUInt(42, 32).EndCell()
+	ext := &tlb.ExternalMessage{
+		SrcAddr:   address.NewAddressNone(),
+		DstAddr:   dst,
+		ImportFee: tlb.ZeroCoins,
+		Body:      body,
+	}
+	root, err := tlb.ToCell(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := services.ParseSignedBoc(root.ToBOC())
+	if err != nil {
+		t.Fatalf("expected external message, got %v", err)
+	}
+	if !msg.DstAddr.Equals(dst) {
+		t.Errorf("unexpected destination %v", msg.DstAddr)
+	}
+	if !bytes.Equal(msg.Payload().Hash(), body.Hash()) {
+		t.Error("body hash must match signed body")
+	}
+
+	if _, err := services.ParseSignedBoc(body.ToBOC()); !errors.Is(err, services.ErrSignedBoc) {
+		t.Errorf("plain cell: expected ErrSignedBoc, got %v", err)
+	}
+}
+
+func TestTxOutcome(t *testing.T) {
+	ok := &tlb.Transaction{Description: tlb.TransactionDescriptionOrdinary{
+		ComputePhase: tlb.ComputePhase{Phase: tlb.ComputePhaseVM{Success: true}},
+		ActionPhase:  &tlb.ActionPhase{Success: true},
+	}}
+	if status, _ := services.TxOutcome(ok); status != models.TX_INTENT_CONFIRMED {
+		t.Errorf("successful transaction: expected confirmed, got %v", status)
+	}
+
+	aborted := &tlb.Transaction{Description: tlb.TransactionDescriptionOrdinary{
+		ComputePhase: tlb.ComputePhase{Phase: tlb.ComputePhaseVM{Success: false}},
+		Aborted:      true,
+	}}
+	if status, _ := services.TxOutcome(aborted); status != models.TX_INTENT_FAILED {
+		t.Errorf("aborted transaction: expected failed, got %v", status)
+	}
+
+	noFunds := &tlb.Transaction{Description: tlb.TransactionDescriptionOrdinary{
+		ComputePhase: tlb.ComputePhase{Phase: tlb.ComputePhaseVM{Success: true}},
+		ActionPhase:  &tlb.ActionPhase{Success: false, NoFunds: true},
+	}}
+	if status, _ := services.TxOutcome(noFunds); status != models.TX_INTENT_FAILED {
+		t.Errorf("failed action phase: expected failed, got %v", status)
+	}
+}
+
+func TestReceivedParts(t *testing.T) {
+	if parts := services.ReceivedParts(&models.TxIntent{}); len(parts) != 0 {
+		t.Fatalf("expected no parts, got %v", parts)
+	}
+
+	intent := &models.TxIntent{
+		Parts:    2,
+		Received: `[{"operation_type":12,"master_jetton":"","amount":0.5,"payload":"{}","intent_id":7}]`,
+	}
+	parts := services.ReceivedParts(intent)
+	if len(parts) != 1 || parts[0].OperationType != models.OP_PAID_COMMISSION_STAKE || parts[0].IntentId != 7 {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+}
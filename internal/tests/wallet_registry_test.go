@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/services"
+)
+
+const walletsListFile = "../../tonconnect-wallets.json"
+
+func TestParseWalletsList(t *testing.T) {
+	data, err := os.ReadFile(walletsListFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallets, err := services.ParseWalletsList(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]string)
+	for _, w := range wallets {
+		found[w.AppName] = w.BridgeUrl
+	}
+	for _, name := range []string{"tonkeeper", "telegram-wallet", "mytonwallet", "tonhub"} {
+		if found[name] == "" {
+			t.Errorf("wallet %v must be in the list with sse bridge", name)
+		}
+	}
+
+	list := `[{"app_name":"ext","name":"Extension","universal_url":"","bridge":[{"type":"js","key":"ext"}]}]`
+	if _, err := services.ParseWalletsList([]byte(list)); err == nil {
+		t.Error("wallets without universal link and sse bridge must be skipped")
+	}
+}
+
+func TestWalletRegistryFallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	registry := services.NewWalletRegistry(&config.WalletsRegistryConfig{
+		Url:  failing.URL,
+		File: walletsListFile,
+		Ttl:  time.Hour,
+		Only: []string{"mytonwallet", "telegram-wallet"},
+	})
+	wallets := registry.Wallets()
+	if len(wallets) != 2 {
+		t.Fatalf("expected 2 wallets from fallback file, got %v", len(wallets))
+	}
+	if w, ok := registry.Get("MyTonWallet"); !ok || w.AppName != "mytonwallet" {
+		t.Errorf("wallet must be found by name, got %+v", w)
+	}
+
+	noFile := services.NewWalletRegistry(&config.WalletsRegistryConfig{
+		Url:  failing.URL,
+		File: "missing.json",
+		Ttl:  time.Hour,
+	})
+	if _, ok := noFile.Get("tonkeeper"); !ok {
+		t.Error("builtin wallets must be used when registry and file are unavailable")
+	}
+}
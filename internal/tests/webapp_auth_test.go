@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"tonclient/internal/handlers"
+)
+
+func signInitData(values url.Values, botToken string) string {
+	pairs := make([]string, 0, len(values))
+	for k := range values {
+		pairs = append(pairs, k+"="+values.Get(k))
+	}
+	sort.Strings(pairs)
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	sign := hmac.New(sha256.New, secret.Sum(nil))
+	sign.Write([]byte(strings.Join(pairs, "\n")))
+
+	values.Set("hash", hex.EncodeToString(sign.Sum(nil)))
+	return values.Encode()
+}
+
+func TestValidateInitData(t *testing.T) {
+	const botToken = "123456:TEST"
+	now := time.Unix(1_800_000_000, 0)
+
+	values := url.Values{}
+	values.Set("auth_date", "1799999000")
+	values.Set("query_id", "AAE")
+	values.Set("user", `{"id":42,"username":"staker","first_name":"Test"}`)
+	initData := signInitData(values, botToken)
+
+	user, err := handlers.ValidateInitData(initData, botToken, 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("expected valid init data, got %v", err)
+	}
+	if user.Id != 42 || user.Username != "staker" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	if _, err := handlers.ValidateInitData(initData, "654321:OTHER", 24*time.Hour, now); !errors.Is(err, handlers.ErrInitDataHash) {
+		t.Errorf("wrong bot token: expected ErrInitDataHash, got %v", err)
+	}
+
+	tampered := strings.Replace(initData, "staker", "hacker", 1)
+	if _, err := handlers.ValidateInitData(tampered, botToken, 24*time.Hour, now); !errors.Is(err, handlers.ErrInitDataHash) {
+		t.Errorf("tampered data: expected ErrInitDataHash, got %v", err)
+	}
+
+	if _, err := handlers.ValidateInitData(initData, botToken, time.Minute, now); !errors.Is(err, handlers.ErrInitDataExpired) {
+		t.Errorf("old data: expected ErrInitDataExpired, got %v", err)
+	}
+}
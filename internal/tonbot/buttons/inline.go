@@ -21,6 +21,65 @@ const (
 	BackHistoryListId    = "BACK_LIST_HISTORY"
 	CloseListHistory     = "CLOSE_LIST_HISTORY"
 
+	//admin panel
+	AdminPrefix          = "ADMIN_"
+	AdminMenuId          = "ADMIN_MENU"
+	AdminStats           = "📊 Статистика"
+	AdminStatsId         = "ADMIN_STATS"
+	AdminPools           = "⏸ Пулы"
+	AdminPoolsId         = "ADMIN_POOLS"
+	AdminPoolPauseId     = "ADMIN_POOL_PAUSE"
+	NextPageAdminPools   = "ADMIN_NEXT_PAGE_POOLS"
+	BackPageAdminPools   = "ADMIN_BACK_PAGE_POOLS"
+	AdminPayouts         = "⚠️ Неудачные выплаты"
+	AdminPayoutsId       = "ADMIN_PAYOUTS"
+	AdminPayoutOpenId    = "ADMIN_PAYOUT_OPEN"
+	AdminPayoutRetry     = "🔁 Повторить выплату"
+	AdminPayoutRetryId   = "ADMIN_PAYOUT_RETRY"
+	AdminPayoutCancel    = "✖️ Закрыть без выплаты"
+	AdminPayoutCancelId  = "ADMIN_PAYOUT_CANCEL"
+	NextPageAdminPayouts = "ADMIN_NEXT_PAGE_PAYOUTS"
+	BackPageAdminPayouts = "ADMIN_BACK_PAGE_PAYOUTS"
+	AdminBroadcast       = "📢 Рассылка"
+	AdminBroadcastId     = "ADMIN_BROADCAST"
+	AdminAnnSegmentId    = "ADMIN_ANN_SEGMENT"
+	AdminAnnAll          = "👥 Все пользователи"
+	AdminAnnPoolStakers  = "🏊 Стейкеры пула"
+	AdminAnnPoolOwners   = "👑 Владельцы пулов"
+	AdminAnnUnpaid       = "⏳ С невыплаченными наградами"
+	AdminAnnSend         = "✅ Отправить сейчас"
+	AdminAnnSendId       = "ADMIN_ANN_SEND"
+	AdminAnnSchedule     = "🕒 Запланировать"
+	AdminAnnScheduleId   = "ADMIN_ANN_SCHEDULE"
+	AdminAnnCancel       = "✖️ Отменить объявление"
+	AdminAnnCancelId     = "ADMIN_ANN_CANCEL"
+	AdminAnnList         = "📋 История объявлений"
+	AdminAnnListId       = "ADMIN_ANN_LIST"
+	AdminAnnOpenId       = "ADMIN_ANN_OPEN"
+	NextPageAdminAnn     = "ADMIN_NEXT_PAGE_ANN"
+	BackPageAdminAnn     = "ADMIN_BACK_PAGE_ANN"
+	AdminBack            = "⏪ Назад"
+	CloseAdminId         = "ADMIN_CLOSE"
+
+	//referrals
+	MyReferrals        = "👥 Мои рефералы"
+	MyReferralsId      = "MY_REFERRALS"
+	NextPageReferrals  = "NEXT_PAGE_REFERRALS"
+	BackPageReferrals  = "BACK_PAGE_REFERRALS"
+	CloseListReferrals = "CLOSE_LIST_REFERRALS"
+
+	//notification settings
+	NotificationSettings        = "🔔 Уведомления"
+	NotificationSettingsId      = "NOTIFICATION_SETTINGS"
+	NotificationToggleId        = "NOTIFICATION_TOGGLE"
+	CloseNotificationSettingsId = "CLOSE_NOTIFICATION_SETTINGS"
+
+	//language
+	LanguageSettings   = "🌐 Язык"
+	LanguageSettingsId = "LANGUAGE_SETTINGS"
+	LanguageAuto       = "🌐 Как в Telegram"
+	SetLanguageId      = "SET_LANGUAGE"
+
 	//pool data to button
 	PoolDataButton = "OPEN_POOL"
 
@@ -44,6 +103,47 @@ const (
 	TakeTokensId     = "TAKE_TOKENS"
 	ClosePoolId      = "CLOSE_POOL"
 
+	AddInsuranceReserve   = "🛡 Пополнить страховой резерв"
+	AddInsuranceReserveId = "ADD_INSURANCE_RESERVE"
+	InsuranceAsset        = "🛡 Компенсация в: "
+	InsuranceAssetId      = "INSURANCE_ASSET"
+
+	//early exit setting
+	EarlyExitSetting   = "⚙️ Условия досрочного выхода"
+	EarlyExitSettingId = "EARLY_EXIT_SETTING"
+	EarlyExitPenalty   = "Штраф: "
+	EarlyExitPenaltyId = "EARLY_EXIT_PENALTY"
+	EarlyExitMinDays   = "Мин. срок до выхода: "
+	EarlyExitMinDaysId = "EARLY_EXIT_MIN_DAYS"
+	EarlyExitPartial   = "Частичный вывод: "
+	EarlyExitPartialId = "EARLY_EXIT_PARTIAL"
+	EarlyExitProRata   = "Награда пропорционально сроку: "
+	EarlyExitProRataId = "EARLY_EXIT_PRO_RATA"
+	BackPoolInfo       = "⏪ Вернуться к пулу"
+
+	//reward tiers
+	RewardTiers        = "📊 Тарифы"
+	RewardTiersId      = "REWARD_TIERS"
+	AddRewardTier      = "➕ Добавить тариф"
+	AddRewardTierId    = "ADD_REWARD_TIER"
+	DeleteRewardTierId = "DELETE_REWARD_TIER"
+
+	//pool limits
+	PoolLimits        = "🚧 Лимиты"
+	PoolLimitsId      = "POOL_LIMITS"
+	PoolMaxTotal      = "Макс. в пуле: "
+	PoolMaxTotalId    = "POOL_MAX_TOTAL"
+	PoolMaxUser       = "Макс. на участника: "
+	PoolMaxUserId     = "POOL_MAX_USER"
+	PoolMaxStakers    = "Макс. участников: "
+	PoolMaxStakersId  = "POOL_MAX_STAKERS"
+	PoolWhitelist     = "Белый список: "
+	PoolWhitelistId   = "POOL_WHITELIST"
+	AddWhitelist      = "➕ Добавить в белый список"
+	AddWhitelistId    = "ADD_WHITELIST"
+	DeleteWhitelistId = "DELETE_WHITELIST"
+	NoLimit           = "без лимита"
+
 	//stakes
 	CreateStakeId   = "CREATE_STAKE"
 	TakeProfit      = "💸 Получить награды"
@@ -53,10 +153,34 @@ const (
 	CloseStake      = "🔒 Закрыть стейк досрочно"
 	CloseStakeId    = "CLOSE_STAKE"
 
+	AutoRollover        = "🔁 Авто-перевыпуск: "
+	AutoRolloverId      = "AUTO_ROLLOVER"
+	ConfirmCloseStake   = "✅ Подтвердить вывод"
+	ConfirmCloseStakeId = "CONFIRM_CLOSE_STAKE"
+	PartialCloseStake   = "✂️ Вывести часть"
+	PartialCloseStakeId = "PARTIAL_CLOSE_STAKE"
+
 	//profile
-	SetNumberWallet   = "👛 Изменить номер кошелька"
+	SetNumberWallet   = "➕ Добавить кошелек"
 	SetNumberWalletId = "SET_NUMBER_WALLET"
 
+	//wallets
+	MyWallets              = "👛 Мои кошельки"
+	MyWalletsId            = "MY_WALLETS"
+	WalletInfoId           = "WALLET_INFO"
+	BackWalletList         = "⏪ К списку кошельков"
+	BackWallet             = "⏪ Назад"
+	SetDefaultWallet       = "⭐ Сделать основным"
+	SetDefaultWalletId     = "SET_DEFAULT_WALLET"
+	ConfirmDefaultWallet   = "✅ Подтвердить смену"
+	ConfirmDefaultWalletId = "CONFIRM_DEFAULT_WALLET"
+	CancelDefaultWallet    = "↩️ Отменить смену"
+	CancelDefaultWalletId  = "CANCEL_DEFAULT_WALLET"
+	RenameWallet           = "✏️ Подпись"
+	RenameWalletId         = "RENAME_WALLET"
+	DeleteWallet           = "🗑 Удалить"
+	DeleteWalletId         = "DELETE_WALLET"
+
 	//default button
 	DefCloseId   = "DEF_CLOSE_ID"
 	DefCloseText = "Закрыть ❌"
@@ -79,6 +203,14 @@ const (
 	LinkTonConnect   = "🔁 Реконект кошелька"
 	LinkTonConnectId = "LINK_TON_CONNECT"
 
+	DisconnectTonConnect   = "🔌 Отключить TonConnect"
+	DisconnectTonConnectId = "DISCONNECT_TON_CONNECT"
+
+	//перевод без TonConnect
+	MemoStake          = "💸 Перевод с комментарием"
+	MemoStakeId        = "MEMO_STAKE"
+	OpenTransferWallet = "👛 Открыть в Tonkeeper"
+
 	//listStakes
 	BackListStakesGroupId             = "BACK_LIST_STAKES_GROUP_ID"
 	NextListStakesGroupId             = "NEXT_LIST_STAKES_GROUP_ID"
@@ -1,21 +1,41 @@
 package buttons
 
+import "tonclient/internal/i18n"
+
+// Кнопки клавиатуры меню - ключи каталога i18n, подписи переводятся при отправке
 const (
 	//user menu
-	SelectPool       = "📋 Выбрать пул"
-	Profile          = "🧑‍💼 Профиль"
-	HistoryOperation = "📃 История"
-	TakeAwards       = "💵 Награды"
-	Payments         = "💵 Выплаты"
-	CheckInsurance   = "💰 Компенсация"
-	MyStakes         = "📈 Мои стейки"
+	SelectPool       = "menu.select_pool"
+	Profile          = "menu.profile"
+	HistoryOperation = "menu.history_operation"
+	TakeAwards       = "menu.take_awards"
+	Payments         = "menu.payments"
+	CheckInsurance   = "menu.check_insurance"
+	MyStakes         = "menu.my_stakes"
 
 	//owner pools menu
-	CreatePool = "➕ Создать пул"
-	MyPools    = "📃 Мои пулы"
-	LearnMore  = "❓ Инфо"
+	CreatePool = "menu.create_pool"
+	MyPools    = "menu.my_pools"
+	LearnMore  = "menu.learn_more"
 
 	//default btns
-	Setting      = "⚙️ Настройки"
-	InviteFriend = "🧑‍💼 Пригласить друга"
+	Setting      = "menu.setting"
+	InviteFriend = "menu.invite_friend"
 )
+
+var replyKeys = []string{
+	SelectPool, Profile, HistoryOperation, TakeAwards, Payments, CheckInsurance, MyStakes,
+	CreatePool, MyPools, LearnMore, Setting, InviteFriend,
+}
+
+// ReplyKey ключ кнопки меню по тексту сообщения на любом из языков, пустая строка - не кнопка меню
+func ReplyKey(text string) string {
+	for _, key := range replyKeys {
+		for _, lang := range i18n.Languages {
+			if i18n.T(lang, key) == text {
+				return key
+			}
+		}
+	}
+	return ""
+}
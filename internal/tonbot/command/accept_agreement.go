@@ -34,7 +34,7 @@ func (c *AcceptAgreementCommand) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Аккаунт не активирован! Введите команду /start",
+			util.T(chatId, "❌ Аккаунт не активирован! Введите команду /start"),
 		); err != nil {
 			log.Println(err)
 		}
@@ -46,7 +46,7 @@ func (c *AcceptAgreementCommand) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Что-то пошло не так. Повторите попытку.",
+			util.T(chatId, "❌ Что-то пошло не так. Повторите попытку."),
 		); err != nil {
 			log.Println(err)
 		}
@@ -68,7 +68,7 @@ func (c *AcceptAgreementCommand) Execute(ctx context.Context, callback *models.C
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		"✅ Вы приняли пользовательское соглашение!",
+		util.T(chatId, "✅ Вы приняли пользовательское соглашение!"),
 		keys,
 	); err != nil {
 		log.Println(err)
@@ -7,14 +7,18 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"tonclient/internal/config"
 	"tonclient/internal/messages"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/tonbot/userstate"
 	"tonclient/internal/util"
 
+	"github.com/cameo-engineering/tonconnect"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/xssnick/tonutils-go/address"
 )
 
 type CommandType interface {
@@ -23,6 +27,9 @@ type CommandType interface {
 
 var currentPoolId = make(map[int64]uint64)
 
+// пополняется страховой резерв (USDT/TON), а не резерв токена пула
+var currentIsInsuranceReserve = make(map[int64]bool)
+
 type AddReserve[T CommandType] struct {
 	b   *bot.Bot
 	ps  *services.PoolService
@@ -63,7 +70,7 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 
 	poolId, ok := currentPoolId[chatId]
 	if !ok || poolId == 0 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так, начните операцию сначала!"); err == nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err == nil {
 			log.Error(err)
 		}
 		return
@@ -78,14 +85,14 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Аккаунт не активирован! Введите команду /start"); err == nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован! Введите команду /start")); err == nil {
 			log.Error(err)
 		}
 		return
 	}
 
 	if uint64(user.Id.Int64) != pool.OwnerId {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Данный пул не принадлежит вам!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Данный пул не принадлежит вам!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -94,7 +101,14 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 	amount, err := strconv.ParseFloat(text, 64)
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Сумма должна быть числом! Например: 23"); err == nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Сумма должна быть числом! Например: 23")); err == nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if amount <= 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Сумма должна быть больше нуля!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -118,32 +132,45 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 		Payload:       string(data),
 	}
 
+	isInsurance := currentIsInsuranceReserve[chatId]
+	if isInsurance {
+		if !util.HasInsuranceReserve(pool) {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул выплачивает компенсацию в своем токене. Сначала выберите USDT или TON!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		payload.OperationType = appModels.OP_ADD_INSURANCE_RESERVE
+		payload.JettonMaster = ""
+		if pool.InsuranceAsset == appModels.INSURANCE_ASSET_USDT {
+			payload.JettonMaster = config.USDT_JETTON_MASTER
+		}
+	}
+
 	s, err := c.tcs.LoadSession(fmt.Sprint(chatId))
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Потеряно соединение с TonConnect. Перейдите в профиль и повторите подключение!, затем попробуйте еще раз!"); err != nil {
-			log.Error(err)
-		}
+		util.SendSessionLost(c.b, uint64(chatId), err, util.T(chatId, "попробуйте еще раз!"))
 		return
 	}
 
 	w, err := c.ws.GetByUserId(uint64(user.Id.Int64))
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ У вас не привязан кошелек! Это можно сделать в профиле!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ У вас не привязан кошелек! Это можно сделать в профиле!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 	adminAddr := os.Getenv("WALLET_ADDR")
 
-	btns := util.GenerateButtonWallets(w, c.tcs, false)
+	btns := util.GenerateButtonWallets(util.Lang(uint64(chatId)), w, c.tcs, false)
 
 	markup := util.CreateInlineMarup(1, btns...)
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		messages.SubmitTransaction,
+		util.T(chatId, messages.SubmitTransaction),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -156,7 +183,12 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 	//	return
 	//}
 
-	if _, err := c.tcs.SendJettonTransaction(
+	if isInsurance {
+		if err := c.sendInsuranceReserve(chatId, pool, w, adminAddr, amount, &payload, s); err != nil {
+			log.Error(err)
+			return
+		}
+	} else if _, err := c.tcs.SendJettonTransaction(
 		fmt.Sprint(chatId),
 		pool.JettonWallet,
 		adminAddr,
@@ -177,10 +209,48 @@ func (c *AddReserve[T]) executeMessage(ctx context.Context, msg *models.Message)
 	}
 
 	currentPoolId[chatId] = 0
+	delete(currentIsInsuranceReserve, chatId)
 	userstate.CurrentState[chatId] = -1
 
 }
 
+func (c *AddReserve[T]) sendInsuranceReserve(
+	chatId int64,
+	pool *appModels.Pool,
+	w *appModels.WalletTon,
+	adminAddr string,
+	amount float64,
+	payload *appModels.Payload,
+	s *tonconnect.Session,
+) error {
+	if pool.InsuranceAsset == appModels.INSURANCE_ASSET_TON {
+		_, err := c.tcs.SendTonTransaction(
+			fmt.Sprint(chatId),
+			adminAddr,
+			util.RemoveZeroFloat(amount),
+			payload,
+			s,
+		)
+		return err
+	}
+
+	usdtWallet, err := c.aws.TokenWalletAddress(config.USDT_JETTON_MASTER, address.MustParseAddr(w.Addr))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tcs.SendJettonTransaction(
+		fmt.Sprint(chatId),
+		usdtWallet.Address().String(),
+		adminAddr,
+		w.Addr,
+		fmt.Sprint(amount),
+		payload,
+		s,
+	)
+	return err
+}
+
 func (c *AddReserve[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
 	if err := util.CheckTypeMessage(c.b, callback); err != nil {
 		return
@@ -190,7 +260,7 @@ func (c *AddReserve[T]) executeCallback(ctx context.Context, callback *models.Ca
 	splitData := strings.Split(callback.Data, ":")
 
 	if len(splitData) < 3 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Произошла ошибка! Повторите позже"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Произошла ошибка! Повторите позже")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -198,17 +268,35 @@ func (c *AddReserve[T]) executeCallback(ctx context.Context, callback *models.Ca
 
 	num, err := strconv.ParseFloat(splitData[1], 64)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ ID пула невалидный"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ ID пула невалидный")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	if _, err := util.SendTextMessage(c.b, uint64(chatId), "Введите кол-во токенов, которое хотите добавить в резерв:"); err != nil {
+	isInsurance := splitData[0] == buttons.AddInsuranceReserveId
+	text := util.T(chatId, "Введите кол-во токенов, которое хотите добавить в резерв:")
+	if isInsurance {
+		pool, err := c.ps.GetId(uint64(num))
+		if err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		text = util.T(chatId, "Введите сумму в %v, которую хотите добавить в страховой резерв.\nИз него выплачиваются компенсации стейкерам при падении цены токена.\n\nТекущий страховой резерв: %v %v",
+			util.InsuranceAssetName(util.Lang(uint64(chatId)), pool.InsuranceAsset),
+			util.RemoveZeroFloat(pool.InsuranceReserve),
+			util.InsuranceAssetName(util.Lang(uint64(chatId)), pool.InsuranceAsset),
+		)
+	}
+
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
 		log.Error(err)
 		return
 	}
 
+	currentIsInsuranceReserve[chatId] = isInsurance
 	currentPoolId[chatId] = uint64(num)
 	userstate.CurrentState[chatId] = userstate.EnterAddReserveTokens
 }
@@ -0,0 +1,779 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/userstate"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var (
+	currentPageAdminPools         = make(map[int64]int)
+	currentPageAdminPayouts       = make(map[int64]int)
+	currentPageAdminAnnouncements = make(map[int64]int)
+
+	announcementMu         sync.Mutex
+	announcementDrafts     = make(map[int64]announcementDraft)
+	announcementScheduling = make(map[int64]uint64)
+)
+
+// announcementDraft выбранный администратором сегмент до ввода текста объявления
+type announcementDraft struct {
+	segment string
+	poolId  uint64
+}
+
+// AdminPanel раздел бота для администраторов платформы
+type AdminPanel struct {
+	b   *bot.Bot
+	as  *services.AdminService
+	ps  *services.PoolService
+	ts  *services.TelegramService
+	aws *services.AdminWalletService
+	ws  *services.WalletTonService
+	opS *services.OperationService
+	ans *services.AnnouncementService
+}
+
+func NewAdminPanel(
+	b *bot.Bot,
+	as *services.AdminService,
+	ps *services.PoolService,
+	ts *services.TelegramService,
+	aws *services.AdminWalletService,
+	ws *services.WalletTonService,
+	opS *services.OperationService,
+	ans *services.AnnouncementService,
+) *AdminPanel {
+	return &AdminPanel{
+		b:   b,
+		as:  as,
+		ps:  ps,
+		ts:  ts,
+		aws: aws,
+		ws:  ws,
+		opS: opS,
+		ans: ans,
+	}
+}
+
+// Execute открывает панель по команде /admin
+func (c *AdminPanel) Execute(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Раздел доступен только администраторам платформы")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), util.T(chatId, adminMenuText), adminMenuMarkup()); err != nil {
+		log.Error(err)
+	}
+}
+
+// Callback обрабатывает кнопки панели, кроме повтора выплаты, который идет через очередь выплат
+func (c *AdminPanel) Callback(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		return
+	}
+
+	data := strings.Split(callback.Data, ":")
+	switch data[0] {
+	case buttons.AdminMenuId:
+		userstate.ResetState(chatId)
+		c.edit(ctx, msg, util.T(chatId, adminMenuText), adminMenuMarkup())
+	case buttons.AdminStatsId:
+		c.stats(ctx, msg)
+	case buttons.AdminPoolsId:
+		currentPageAdminPools[chatId] = 0
+		c.pools(ctx, msg)
+	case buttons.NextPageAdminPools:
+		currentPageAdminPools[chatId]++
+		c.pools(ctx, msg)
+	case buttons.BackPageAdminPools:
+		currentPageAdminPools[chatId] = max(currentPageAdminPools[chatId]-1, 0)
+		c.pools(ctx, msg)
+	case buttons.AdminPoolPauseId:
+		c.togglePool(ctx, msg, data)
+	case buttons.AdminPayoutsId:
+		currentPageAdminPayouts[chatId] = 0
+		c.payouts(ctx, msg)
+	case buttons.NextPageAdminPayouts:
+		currentPageAdminPayouts[chatId]++
+		c.payouts(ctx, msg)
+	case buttons.BackPageAdminPayouts:
+		currentPageAdminPayouts[chatId] = max(currentPageAdminPayouts[chatId]-1, 0)
+		c.payouts(ctx, msg)
+	case buttons.AdminPayoutOpenId:
+		c.payout(ctx, msg, data)
+	case buttons.AdminPayoutCancelId:
+		c.cancelPayout(ctx, msg, data)
+	case buttons.AdminBroadcastId:
+		userstate.ResetState(chatId)
+		c.edit(ctx, msg, util.T(chatId, "<b>📢 Рассылка</b>\n\nВыберите получателей объявления."), segmentsMarkup())
+	case buttons.AdminAnnSegmentId:
+		c.selectSegment(ctx, msg, data)
+	case buttons.AdminAnnSendId:
+		c.sendAnnouncement(ctx, msg, data)
+	case buttons.AdminAnnScheduleId:
+		c.scheduleAnnouncement(ctx, msg, data)
+	case buttons.AdminAnnCancelId:
+		c.cancelAnnouncement(ctx, msg, data)
+	case buttons.AdminAnnListId:
+		currentPageAdminAnnouncements[chatId] = 0
+		c.announcements(ctx, msg)
+	case buttons.NextPageAdminAnn:
+		currentPageAdminAnnouncements[chatId]++
+		c.announcements(ctx, msg)
+	case buttons.BackPageAdminAnn:
+		currentPageAdminAnnouncements[chatId] = max(currentPageAdminAnnouncements[chatId]-1, 0)
+		c.announcements(ctx, msg)
+	case buttons.AdminAnnOpenId:
+		if a, err := c.openAnnouncement(msg, data); err == nil {
+			c.edit(ctx, msg, c.announcementText(util.Lang(uint64(chatId)), a), announcementMarkup(a))
+		}
+	case buttons.CloseAdminId:
+		userstate.ResetState(chatId)
+		delete(currentPageAdminPools, chatId)
+		delete(currentPageAdminPayouts, chatId)
+		delete(currentPageAdminAnnouncements, chatId)
+		if err := util.DeleteMessage(ctx, c.b, uint64(chatId), msg.ID); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// EnterAnnouncementPool принимает id пула для сегмента стейкеров пула
+func (c *AdminPanel) EnterAnnouncementPool(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		userstate.ResetState(chatId)
+		return
+	}
+	poolId, err := strconv.ParseUint(strings.TrimSpace(msg.Text), 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Введите числовой ID пула")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	pool, err := c.ps.GetId(poolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Введите другой ID")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	announcementMu.Lock()
+	announcementDrafts[chatId] = announcementDraft{segment: appModels.ANNOUNCEMENT_SEGMENT_POOL_STAKERS, poolId: poolId}
+	announcementMu.Unlock()
+
+	userstate.CurrentState[chatId] = userstate.EnterAdminBroadcast
+	if _, err := util.SendTextMessage(
+		c.b,
+		uint64(chatId),
+		util.T(chatId, "Получатели: активные стейкеры пула %v #%v\n\n%v", pool.JettonName, poolId, util.T(chatId, enterAnnouncementText)),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+// EnterBroadcast принимает текст объявления, сохраняет черновик и показывает предпросмотр
+func (c *AdminPanel) EnterBroadcast(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		userstate.ResetState(chatId)
+		return
+	}
+	if strings.TrimSpace(msg.Text) == "" {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Сообщение пустое, отправьте текст объявления")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	announcementMu.Lock()
+	draft, ok := announcementDrafts[chatId]
+	announcementMu.Unlock()
+	if !ok {
+		userstate.ResetState(chatId)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Получатели не выбраны, начните рассылку заново")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	// предпросмотр в том виде, в котором сообщение получат пользователи
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), msg.Text); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось показать сообщение. Проверьте HTML-разметку и отправьте текст снова.")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	a, err := c.ans.CreateDraft(uint64(chatId), draft.segment, draft.poolId, msg.Text)
+	if err != nil {
+		log.Error("Failed to create announcement: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось сохранить объявление")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	userstate.ResetState(chatId)
+	announcementMu.Lock()
+	delete(announcementDrafts, chatId)
+	announcementMu.Unlock()
+
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), c.announcementText(util.Lang(uint64(chatId)), a), announcementMarkup(a)); err != nil {
+		log.Error(err)
+	}
+}
+
+// EnterAnnouncementTime принимает время отложенной рассылки
+func (c *AdminPanel) EnterAnnouncementTime(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		userstate.ResetState(chatId)
+		return
+	}
+
+	announcementMu.Lock()
+	id, ok := announcementScheduling[chatId]
+	announcementMu.Unlock()
+	if !ok {
+		userstate.ResetState(chatId)
+		return
+	}
+
+	at, err := services.ParseAnnouncementTime(strings.TrimSpace(msg.Text), time.Now())
+	if err != nil {
+		text := util.T(chatId, "❌ Неверный формат. Введите время в формате ДД.ММ.ГГГГ ЧЧ:ММ")
+		if errors.Is(err, services.ErrAnnouncementTime) {
+			text = util.T(chatId, "❌ Время рассылки должно быть в будущем")
+		}
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	userstate.ResetState(chatId)
+	announcementMu.Lock()
+	delete(announcementScheduling, chatId)
+	announcementMu.Unlock()
+
+	a, err := c.ans.Schedule(id, at)
+	if err != nil {
+		log.Error("Failed to schedule announcement: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Объявление уже отправлено или отменено")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		c.announcementText(util.Lang(uint64(chatId)), a)+util.T(chatId, "\n\n🕒 Рассылка запланирована на %v", at.Format(services.AnnouncementTimeLayout)),
+		announcementMarkup(a),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+// RetryPayout повторно отправляет неудачную выплату. Вызывается из очереди выплат
+func (c *AdminPanel) RetryPayout(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	if !c.as.IsAdmin(uint64(chatId)) {
+		return
+	}
+
+	payout, err := c.openPayout(msg, strings.Split(callback.Data, ":"))
+	if err != nil {
+		return
+	}
+
+	hash, sendErr := util.SendRefund(c.aws, c.ws, payout.UserId, payout.JettonMaster, payout.Amount)
+	encoded := ""
+	if sendErr == nil {
+		encoded = base64.StdEncoding.EncodeToString(hash)
+	}
+	if err := c.as.RetryResult(payout, encoded, sendErr); err != nil {
+		log.Error("Failed to save payout retry: ", err)
+	}
+
+	if sendErr != nil {
+		log.Error("Failed to retry payout: ", sendErr)
+		c.edit(ctx, msg, payoutText(util.Lang(uint64(chatId)), payout)+util.T(chatId, "\n\n❌ Повторная отправка не удалась"), payoutMarkup(payout))
+		return
+	}
+
+	if _, err := c.opS.Create(payout.UserId, appModels.OP_RETURNING_TOKENS, fmt.Sprintf("Возврат. Hash операции: %v", encoded)); err != nil {
+		log.Error(err)
+	}
+	if tg, err := c.ts.GetByUserId(payout.UserId); err == nil {
+		if _, err := util.SendTextMessage(
+			c.b,
+			tg.TelegramId,
+			util.T(tg.TelegramId, "✅ Возврат %v %v отправлен на ваш кошелек", util.RemoveZeroFloat(payout.Amount), payoutAssetName(payout.JettonMaster)),
+		); err != nil {
+			log.Error(err)
+		}
+	}
+	c.edit(ctx, msg, payoutText(util.Lang(uint64(chatId)), payout)+util.T(chatId, "\n\n✅ Выплата отправлена"), backMarkup())
+}
+
+func (c *AdminPanel) stats(ctx context.Context, msg *models.Message) {
+	stats, err := c.as.Stats()
+	if err != nil {
+		c.edit(ctx, msg, util.T(msg.Chat.ID, "❌ Не удалось загрузить статистику"), backMarkup())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(util.T(msg.Chat.ID, "<b>📊 Статистика платформы</b>\n\n"))
+	sb.WriteString(util.T(msg.Chat.ID, "Пользователей: <b>%v</b>\n", stats.Users))
+	sb.WriteString(util.T(msg.Chat.ID, "Стейков: <b>%v</b> (активных: %v)\n", stats.Stakes, stats.ActiveStakes))
+	sb.WriteString(util.T(msg.Chat.ID, "Пулов: <b>%v</b> (активных: %v)\n", stats.Pools, stats.ActivePools))
+	sb.WriteString(util.T(msg.Chat.ID, "Неудачных выплат: <b>%v</b>\n\n", stats.FailedPayout))
+	sb.WriteString(util.T(msg.Chat.ID, "<b>TVL по токенам:</b>\n"))
+	if len(stats.Tvl) == 0 {
+		sb.WriteString(util.T(msg.Chat.ID, "Активных стейков нет"))
+	}
+	for _, tvl := range stats.Tvl {
+		sb.WriteString(util.T(msg.Chat.ID, " •	%v: <b>%v</b> (%v стейков)\n", tvl.JettonName, util.RemoveZeroFloat(tvl.Amount), tvl.Stakes))
+	}
+
+	c.edit(ctx, msg, sb.String(), backMarkup())
+}
+
+func (c *AdminPanel) pools(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	page := util.GetCurrentPage(chatId, currentPageAdminPools)
+	pools := c.ps.AllLimit(page*numberElementPage, numberElementPage)
+	if pools == nil {
+		c.edit(ctx, msg, util.T(chatId, "❌ Не удалось загрузить пулы"), backMarkup())
+		return
+	}
+
+	btns := make([]models.InlineKeyboardButton, 0, len(*pools))
+	for _, pool := range *pools {
+		text := util.T(chatId, "⏸ Приостановить %v #%v", pool.JettonName, pool.Id.Int64)
+		if pool.AdminPaused {
+			text = util.T(chatId, "▶️ Снять паузу %v #%v", pool.JettonName, pool.Id.Int64)
+		} else if !pool.IsActive {
+			text = util.T(chatId, "⏸ Закрыт владельцем %v #%v", pool.JettonName, pool.Id.Int64)
+		}
+		btns = append(btns, util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminPoolPauseId, pool.Id.Int64), text))
+	}
+
+	markup := util.GenerateNextBackMenu(
+		page,
+		totalPages(c.ps.CountAll()),
+		buttons.NextPageAdminPools,
+		buttons.BackPageAdminPools,
+		buttons.AdminMenuId,
+		btns...,
+	)
+	c.edit(ctx, msg, util.T(chatId, "<b>⏸ Пулы</b>\n\nПриостановленный администратором пул не принимает стейки, владелец не может открыть его сам."), markup)
+}
+
+func (c *AdminPanel) togglePool(ctx context.Context, msg *models.Message, data []string) {
+	if len(data) < 2 {
+		return
+	}
+	poolId, err := strconv.ParseUint(data[1], 10, 64)
+	if err != nil {
+		return
+	}
+	pool, err := c.ps.GetId(poolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(msg.Chat.ID), util.T(msg.Chat.ID, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	pool, err = c.as.SetPoolPaused(poolId, !pool.AdminPaused)
+	if err != nil {
+		log.Error("Failed to pause pool: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(msg.Chat.ID), util.T(msg.Chat.ID, "❌ Не удалось изменить статус пула")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	util.Notify(c.b, pool.OwnerId, appModels.NOTIFY_POOL_PAUSED, &appModels.NotificationData{
+		JettonName: pool.JettonName,
+		Owner:      true,
+		Paused:     pool.AdminPaused,
+		Active:     pool.IsActive,
+	})
+	c.pools(ctx, msg)
+}
+
+func (c *AdminPanel) payouts(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	page := util.GetCurrentPage(chatId, currentPageAdminPayouts)
+	payouts, err := c.as.FailedPayouts(page*numberElementPage, numberElementPage)
+	if err != nil {
+		c.edit(ctx, msg, util.T(chatId, "❌ Не удалось загрузить выплаты"), backMarkup())
+		return
+	}
+
+	btns := make([]models.InlineKeyboardButton, 0, len(payouts))
+	for _, payout := range payouts {
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v", buttons.AdminPayoutOpenId, payout.Id.Int64),
+			fmt.Sprintf("#%v %v %v · %v", payout.Id.Int64, util.RemoveZeroFloat(payout.Amount), payoutAssetName(payout.JettonMaster), payout.CreatedAt.Format("02.01 15:04")),
+		))
+	}
+
+	text := util.T(chatId, "<b>⚠️ Неудачные выплаты</b>\n\nВыберите выплату, чтобы повторить ее или закрыть.")
+	if len(payouts) == 0 && page == 0 {
+		text = util.T(chatId, "<b>⚠️ Неудачные выплаты</b>\n\nНеотправленных выплат нет.")
+	}
+	markup := util.GenerateNextBackMenu(
+		page,
+		totalPages(c.as.CountFailedPayouts()),
+		buttons.NextPageAdminPayouts,
+		buttons.BackPageAdminPayouts,
+		buttons.AdminMenuId,
+		btns...,
+	)
+	c.edit(ctx, msg, text, markup)
+}
+
+func (c *AdminPanel) payout(ctx context.Context, msg *models.Message, data []string) {
+	payout, err := c.openPayout(msg, data)
+	if err != nil {
+		return
+	}
+	c.edit(ctx, msg, payoutText(util.Lang(uint64(msg.Chat.ID)), payout), payoutMarkup(payout))
+}
+
+func (c *AdminPanel) cancelPayout(ctx context.Context, msg *models.Message, data []string) {
+	payout, err := c.openPayout(msg, data)
+	if err != nil {
+		return
+	}
+	if err := c.as.CancelFailedPayout(uint64(payout.Id.Int64)); err != nil {
+		log.Error("Failed to cancel payout: ", err)
+		return
+	}
+	c.edit(ctx, msg, payoutText(util.Lang(uint64(msg.Chat.ID)), payout)+util.T(msg.Chat.ID, "\n\n✖️ Выплата закрыта без отправки"), backMarkup())
+}
+
+func (c *AdminPanel) openPayout(msg *models.Message, data []string) (*appModels.FailedPayout, error) {
+	if len(data) < 2 {
+		return nil, errors.New("payout id is missing")
+	}
+	id, err := strconv.ParseUint(data[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	payout, err := c.as.OpenFailedPayout(id)
+	if err != nil {
+		text := util.T(msg.Chat.ID, "❌ Выплата не найдена")
+		if errors.Is(err, services.ErrFailedPayoutClosed) {
+			text = util.T(msg.Chat.ID, "❌ Выплата уже отправлена или закрыта")
+		}
+		if _, err := util.SendTextMessage(c.b, uint64(msg.Chat.ID), text); err != nil {
+			log.Error(err)
+		}
+		return nil, err
+	}
+	return payout, nil
+}
+
+func (c *AdminPanel) selectSegment(ctx context.Context, msg *models.Message, data []string) {
+	if len(data) < 2 {
+		return
+	}
+	chatId := msg.Chat.ID
+	segment := data[1]
+	if segment == appModels.ANNOUNCEMENT_SEGMENT_POOL_STAKERS {
+		userstate.CurrentState[chatId] = userstate.EnterAnnouncementPool
+		c.edit(ctx, msg, util.T(chatId, "<b>📢 Рассылка</b>\n\nВведите ID пула, стейкерам которого нужно отправить объявление."), backMarkup())
+		return
+	}
+
+	announcementMu.Lock()
+	announcementDrafts[chatId] = announcementDraft{segment: segment}
+	announcementMu.Unlock()
+
+	userstate.CurrentState[chatId] = userstate.EnterAdminBroadcast
+	c.edit(ctx, msg, util.T(chatId, "<b>📢 Рассылка</b>\n\nПолучатели: %v\n\n%v", segmentName(util.Lang(uint64(chatId)), segment, sql.NullInt64{}), util.T(chatId, enterAnnouncementText)), backMarkup())
+}
+
+func (c *AdminPanel) sendAnnouncement(ctx context.Context, msg *models.Message, data []string) {
+	a, err := c.openAnnouncement(msg, data)
+	if err != nil {
+		return
+	}
+	a, err = c.ans.SendNow(uint64(a.Id.Int64))
+	if err != nil {
+		log.Error("Failed to start announcement: ", err)
+		c.edit(ctx, msg, util.T(msg.Chat.ID, "❌ Объявление уже отправлено или отменено"), backMarkup())
+		return
+	}
+	c.edit(ctx, msg, c.announcementText(util.Lang(uint64(msg.Chat.ID)), a)+util.T(msg.Chat.ID, "\n\n📢 Рассылка запущена, по завершении придет отчет"), announcementMarkup(a))
+}
+
+func (c *AdminPanel) scheduleAnnouncement(ctx context.Context, msg *models.Message, data []string) {
+	a, err := c.openAnnouncement(msg, data)
+	if err != nil {
+		return
+	}
+	chatId := msg.Chat.ID
+	announcementMu.Lock()
+	announcementScheduling[chatId] = uint64(a.Id.Int64)
+	announcementMu.Unlock()
+
+	userstate.CurrentState[chatId] = userstate.EnterAnnouncementTime
+	c.edit(ctx, msg, c.announcementText(util.Lang(uint64(chatId)), a)+util.T(chatId, "\n\n🕒 Введите время рассылки в формате ДД.ММ.ГГГГ ЧЧ:ММ"), backMarkup())
+}
+
+func (c *AdminPanel) cancelAnnouncement(ctx context.Context, msg *models.Message, data []string) {
+	a, err := c.openAnnouncement(msg, data)
+	if err != nil {
+		return
+	}
+	a, err = c.ans.Cancel(uint64(a.Id.Int64))
+	if err != nil {
+		log.Error("Failed to cancel announcement: ", err)
+		c.edit(ctx, msg, util.T(msg.Chat.ID, "❌ Объявление уже отправлено или отменено"), backMarkup())
+		return
+	}
+	c.edit(ctx, msg, c.announcementText(util.Lang(uint64(msg.Chat.ID)), a), announcementMarkup(a))
+}
+
+func (c *AdminPanel) announcements(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	page := util.GetCurrentPage(chatId, currentPageAdminAnnouncements)
+	list, err := c.ans.Recent(page*numberElementPage, numberElementPage)
+	if err != nil {
+		c.edit(ctx, msg, util.T(chatId, "❌ Не удалось загрузить объявления"), backMarkup())
+		return
+	}
+
+	btns := make([]models.InlineKeyboardButton, 0, len(list))
+	for _, a := range list {
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v", buttons.AdminAnnOpenId, a.Id.Int64),
+			fmt.Sprintf("#%v %v · %v", a.Id.Int64, announcementStatusName(util.Lang(uint64(chatId)), a.Status), a.CreatedAt.Format("02.01 15:04")),
+		))
+	}
+
+	text := util.T(chatId, "<b>📋 История объявлений</b>\n\nВыберите объявление, чтобы посмотреть статистику доставки.")
+	if len(list) == 0 && page == 0 {
+		text = util.T(chatId, "<b>📋 История объявлений</b>\n\nОбъявлений пока нет.")
+	}
+	markup := util.GenerateNextBackMenu(
+		page,
+		totalPages(c.ans.CountAll()),
+		buttons.NextPageAdminAnn,
+		buttons.BackPageAdminAnn,
+		buttons.AdminBroadcastId,
+		btns...,
+	)
+	c.edit(ctx, msg, text, markup)
+}
+
+func (c *AdminPanel) openAnnouncement(msg *models.Message, data []string) (*appModels.Announcement, error) {
+	if len(data) < 2 {
+		return nil, errors.New("announcement id is missing")
+	}
+	id, err := strconv.ParseUint(data[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.ans.GetById(id)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(msg.Chat.ID), util.T(msg.Chat.ID, "❌ Объявление не найдено")); err != nil {
+			log.Error(err)
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// announcementText карточка объявления со статусом и статистикой доставки
+func (c *AdminPanel) announcementText(lang string, a *appModels.Announcement) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, "<b>📢 Объявление #%v</b>\n\n", a.Id.Int64))
+	sb.WriteString(i18n.T(lang, "Получатели: %v\n", segmentName(lang, a.Segment, a.PoolId)))
+	sb.WriteString(i18n.T(lang, "Статус: %v\n", announcementStatusName(lang, a.Status)))
+	if a.ScheduledAt.Valid && a.Status == appModels.ANNOUNCEMENT_SCHEDULED {
+		sb.WriteString(i18n.T(lang, "Отправка: %v\n", a.ScheduledAt.Time.Format(services.AnnouncementTimeLayout)))
+	}
+	switch a.Status {
+	case appModels.ANNOUNCEMENT_DRAFT, appModels.ANNOUNCEMENT_SCHEDULED:
+		if n, err := c.ans.Recipients(a); err == nil {
+			sb.WriteString(i18n.T(lang, "Получателей сейчас: <b>%v</b>\n", n))
+		}
+	default:
+		sb.WriteString(i18n.T(lang, "Всего: <b>%v</b>\nДоставлено: %v\nЗаблокировали бота: %v\nОшибок: %v\n",
+			a.Total, a.Delivered, a.Blocked, a.Failed,
+		))
+	}
+	return sb.String()
+}
+
+func (c *AdminPanel) edit(ctx context.Context, msg *models.Message, text string, markup *models.InlineKeyboardMarkup) {
+	var err error
+	if markup == nil {
+		err = util.EditTextMessage(ctx, c.b, uint64(msg.Chat.ID), msg.ID, text)
+	} else {
+		err = util.EditTextMessageMarkup(ctx, c.b, uint64(msg.Chat.ID), msg.ID, text, markup)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+const adminMenuText = "<b>🛠 Панель администратора</b>\n\nВыберите раздел."
+
+func adminMenuMarkup() *models.InlineKeyboardMarkup {
+	return util.MenuWithBackButton(
+		buttons.CloseAdminId,
+		"Закрыть ❌",
+		util.CreateDefaultButton(buttons.AdminStatsId, buttons.AdminStats),
+		util.CreateDefaultButton(buttons.AdminPoolsId, buttons.AdminPools),
+		util.CreateDefaultButton(buttons.AdminPayoutsId, buttons.AdminPayouts),
+		util.CreateDefaultButton(buttons.AdminBroadcastId, buttons.AdminBroadcast),
+	)
+}
+
+const enterAnnouncementText = "Отправьте текст объявления. Поддерживается HTML-разметка Telegram."
+
+func segmentsMarkup() *models.InlineKeyboardMarkup {
+	segment := func(segment, text string) models.InlineKeyboardButton {
+		return util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminAnnSegmentId, segment), text)
+	}
+	return util.MenuWithBackButton(
+		buttons.AdminMenuId,
+		buttons.AdminBack,
+		segment(appModels.ANNOUNCEMENT_SEGMENT_ALL, buttons.AdminAnnAll),
+		segment(appModels.ANNOUNCEMENT_SEGMENT_POOL_STAKERS, buttons.AdminAnnPoolStakers),
+		segment(appModels.ANNOUNCEMENT_SEGMENT_POOL_OWNERS, buttons.AdminAnnPoolOwners),
+		segment(appModels.ANNOUNCEMENT_SEGMENT_UNPAID_REWARDS, buttons.AdminAnnUnpaid),
+		util.CreateDefaultButton(buttons.AdminAnnListId, buttons.AdminAnnList),
+	)
+}
+
+func announcementMarkup(a *appModels.Announcement) *models.InlineKeyboardMarkup {
+	id := a.Id.Int64
+	btns := make([]models.InlineKeyboardButton, 0, 3)
+	switch a.Status {
+	case appModels.ANNOUNCEMENT_DRAFT, appModels.ANNOUNCEMENT_SCHEDULED:
+		btns = append(
+			btns,
+			util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminAnnSendId, id), buttons.AdminAnnSend),
+			util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminAnnScheduleId, id), buttons.AdminAnnSchedule),
+			util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminAnnCancelId, id), buttons.AdminAnnCancel),
+		)
+	case appModels.ANNOUNCEMENT_SENDING:
+		btns = append(btns, util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminAnnCancelId, id), buttons.AdminAnnCancel))
+	}
+	return util.MenuWithBackButton(buttons.AdminAnnListId, buttons.AdminBack, btns...)
+}
+
+func segmentName(lang, segment string, poolId sql.NullInt64) string {
+	switch segment {
+	case appModels.ANNOUNCEMENT_SEGMENT_ALL:
+		return i18n.T(lang, "все пользователи")
+	case appModels.ANNOUNCEMENT_SEGMENT_POOL_STAKERS:
+		if poolId.Valid {
+			return i18n.T(lang, "активные стейкеры пула #%v", poolId.Int64)
+		}
+		return i18n.T(lang, "активные стейкеры пула")
+	case appModels.ANNOUNCEMENT_SEGMENT_POOL_OWNERS:
+		return i18n.T(lang, "владельцы пулов")
+	case appModels.ANNOUNCEMENT_SEGMENT_UNPAID_REWARDS:
+		return i18n.T(lang, "пользователи с невыплаченными наградами")
+	}
+	return segment
+}
+
+func announcementStatusName(lang, status string) string {
+	switch status {
+	case appModels.ANNOUNCEMENT_DRAFT:
+		return i18n.T(lang, "📝 черновик")
+	case appModels.ANNOUNCEMENT_SCHEDULED:
+		return i18n.T(lang, "🕒 запланировано")
+	case appModels.ANNOUNCEMENT_SENDING:
+		return i18n.T(lang, "📤 рассылается")
+	case appModels.ANNOUNCEMENT_DONE:
+		return i18n.T(lang, "✅ разослано")
+	case appModels.ANNOUNCEMENT_CANCELLED:
+		return i18n.T(lang, "✖️ отменено")
+	}
+	return status
+}
+
+func backMarkup() *models.InlineKeyboardMarkup {
+	return util.MenuWithBackButton(buttons.AdminMenuId, buttons.AdminBack)
+}
+
+func payoutMarkup(payout *appModels.FailedPayout) *models.InlineKeyboardMarkup {
+	return util.MenuWithBackButton(
+		buttons.AdminPayoutsId,
+		buttons.AdminBack,
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminPayoutRetryId, payout.Id.Int64), buttons.AdminPayoutRetry),
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.AdminPayoutCancelId, payout.Id.Int64), buttons.AdminPayoutCancel),
+	)
+}
+
+func payoutText(lang string, payout *appModels.FailedPayout) string {
+	return i18n.T(lang, "<b>Выплата #%v</b>\n\nПользователь: %v\nСумма: <b>%v %v</b>\nНазначение: %v\nПопыток: %v\nСоздана: %v\nОшибка: %v",
+		payout.Id.Int64,
+		payout.UserId,
+		util.RemoveZeroFloat(payout.Amount),
+		payoutAssetName(payout.JettonMaster),
+		html.EscapeString(payout.Reason),
+		payout.Attempts,
+		payout.CreatedAt.Format("02.01.2006 15:04"),
+		html.EscapeString(payout.Error),
+	)
+}
+
+func payoutAssetName(jettonMaster string) string {
+	switch jettonMaster {
+	case "":
+		return "TON"
+	case config.USDT_JETTON_MASTER:
+		return "USDT"
+	}
+	if len(jettonMaster) > 10 {
+		return jettonMaster[:4] + "…" + jettonMaster[len(jettonMaster)-4:]
+	}
+	return jettonMaster
+}
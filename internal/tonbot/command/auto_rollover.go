@@ -0,0 +1,85 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type AutoRollover struct {
+	b  *bot.Bot
+	ss *services.StakeService
+	ps *services.PoolService
+	us *services.UserService
+}
+
+func NewAutoRolloverCommand(b *bot.Bot, ss *services.StakeService, ps *services.PoolService, us *services.UserService) *AutoRollover {
+	return &AutoRollover{
+		b:  b,
+		ss: ss,
+		ps: ps,
+		us: us,
+	}
+}
+
+func (c *AutoRollover) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+
+	chatId := callback.From.ID
+	splitData := strings.Split(callback.Data, ":")
+	if len(splitData) != 3 {
+		return
+	}
+
+	stakeId, err := strconv.ParseUint(splitData[2], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу обработать данную кнопку")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	stake, err := c.ss.GetById(stakeId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Стейк не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil || uint64(u.Id.Int64) != stake.UserId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Это не ваш стейк!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if !stake.IsActive {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Стейк уже закрыт!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	stake.AutoRollover = !stake.AutoRollover
+	if err := c.ss.Update(stake); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Настройка не была сохранена. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	callback.Data = fmt.Sprintf("%v:%v:%v", buttons.OpenStakeInfo, splitData[1], stakeId)
+	NewOpenStakeInfoCommand(c.b, c.ss, c.ps, buttons.BackListStakesGroupId).Execute(ctx, callback)
+}
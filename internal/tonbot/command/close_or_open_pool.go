@@ -46,7 +46,7 @@ func (c *CloseOrOpenPool) Execute(ctx context.Context, callback *models.Callback
 	messageId := msg.ID
 	splitText := strings.Split(callback.Data, ":")
 	if len(splitText) < 3 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так, повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -54,7 +54,7 @@ func (c *CloseOrOpenPool) Execute(ctx context.Context, callback *models.Callback
 
 	poolId, err := strconv.ParseInt(splitText[1], 10, 64)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Не верный ID пула!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не верный ID пула!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -63,21 +63,28 @@ func (c *CloseOrOpenPool) Execute(ctx context.Context, callback *models.Callback
 	pool, err := c.ps.GetId(uint64(poolId))
 	if err != nil {
 		log.Error("GetId: ", err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Пул не найден. Возможно он был удален!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
 	if !pool.IsCommissionPaid {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ В текущем пуле не оплачена комиссия! Сначала оплатите комиссию!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ В текущем пуле не оплачена комиссия! Сначала оплатите комиссию!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if !pool.IsActive && pool.AdminPaused {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул приостановлен администратором платформы. Открыть его сейчас нельзя!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
 	if !pool.IsActive && pool.Reserve == 0 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Статус не был изменен. Пополните резерв, чтобы можно было открыть пул!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Статус не был изменен. Пополните резерв, чтобы можно было открыть пул!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -86,14 +93,14 @@ func (c *CloseOrOpenPool) Execute(ctx context.Context, callback *models.Callback
 	u, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
 		log.Error("GetByTelegramChatId: ", err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Аккаунт не активирован. Введите команду /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
 	if uint64(u.Id.Int64) != pool.OwnerId {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Вы не владелец этого пула!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Вы не владелец этого пула!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -143,7 +150,7 @@ func (c *CloseOrOpenPool) editStatus(
 	sufData string,
 ) error {
 	if err := c.ps.SetActive(poolId, isActive); err != nil {
-		if _, err := util.SendTextMessage(c.b, chatId, "❌ Статус не был изменен. Повторите попытку позже!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, chatId, util.T(chatId, "❌ Статус не был изменен. Повторите попытку позже!")); err != nil {
 			log.Error(err)
 		}
 		return err
@@ -167,8 +174,8 @@ func (c *CloseOrOpenPool) editStatus(
 		c.b,
 		chatId,
 		messageId,
-		util.PoolInfo(pool, c.ss, jettonData),
-		util.GenerateOwnerPoolInlineKeyboard(int64(poolId), btnId, pool.IsActive, pool.IsCommissionPaid, sufData),
+		util.PoolInfo(util.Lang(uint64(chatId)), pool, c.ss, jettonData, c.ps.GetRewardTiers(poolId)),
+		util.GenerateOwnerPoolInlineKeyboard(util.Lang(uint64(chatId)), int64(poolId), btnId, pool.IsActive, pool.IsCommissionPaid, pool.InsuranceAsset, sufData),
 	); err != nil {
 		log.Error(err)
 	}
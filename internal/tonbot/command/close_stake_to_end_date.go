@@ -3,53 +3,80 @@ package command
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/userstate"
 	"tonclient/internal/util"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 )
 
-type CloseStake struct {
-	b   *bot.Bot
-	aws *services.AdminWalletService
-	ws  *services.WalletTonService
-	ss  *services.StakeService
-	ps  *services.PoolService
-	ops *services.OperationService
+var currentPartialCloseStakeId = make(map[int64]uint64)
+
+type CloseStake[T CommandType] struct {
+	b      *bot.Bot
+	aws    *services.AdminWalletService
+	ws     *services.WalletTonService
+	ss     *services.StakeService
+	ps     *services.PoolService
+	us     *services.UserService
+	ops    *services.OperationService
+	as     *services.AdminService
+	payout func(ctx context.Context, f func()) error
 }
 
-func NewCloseStakeCommand(
+func NewCloseStakeCommand[T CommandType](
 	b *bot.Bot,
 	aws *services.AdminWalletService,
 	ws *services.WalletTonService,
 	ss *services.StakeService,
 	ps *services.PoolService,
+	us *services.UserService,
 	ops *services.OperationService,
-) *CloseStake {
-	return &CloseStake{
-		b:   b,
-		aws: aws,
-		ws:  ws,
-		ss:  ss,
-		ps:  ps,
-		ops: ops,
+	as *services.AdminService,
+	payout func(ctx context.Context, f func()) error,
+) *CloseStake[T] {
+	return &CloseStake[T]{
+		b:      b,
+		aws:    aws,
+		ws:     ws,
+		ss:     ss,
+		ps:     ps,
+		us:     us,
+		ops:    ops,
+		as:     as,
+		payout: payout,
 	}
 }
 
-func (c *CloseStake) Execute(ctx context.Context, callback *models.CallbackQuery) {
+func (c *CloseStake[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.executeMessage(ctx, v)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		c.executeCallback(ctx, v)
+		return
+	}
+}
+
+func (c *CloseStake[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
 	if err := util.CheckTypeMessage(c.b, callback); err != nil {
 		return
 	}
 
 	chatId := callback.From.ID
 	splitData := strings.Split(callback.Data, ":")
-	if len(splitData) != 2 {
+	if len(splitData) < 2 {
 		return
 	}
 
@@ -58,32 +85,175 @@ func (c *CloseStake) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу обработать данную кнопку",
+			util.T(chatId, "❌ Не могу обработать данную кнопку"),
 		); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
-	stake, err := c.ss.GetById(stakeId)
-	if err != nil {
+	if splitData[0] == buttons.ConfirmCloseStakeId {
+		c.confirm(ctx, chatId, stakeId, splitData[2:])
+		return
+	}
+
+	stake, p, ok := c.getStakeAndPool(chatId, stakeId)
+	if !ok {
+		return
+	}
+
+	switch splitData[0] {
+	case buttons.CloseStakeId:
+		c.sendQuote(ctx, chatId, callback.Message.Message.ID, stake, p, 0)
+	case buttons.PartialCloseStakeId:
+		if !p.EarlyExitPartial {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ В этом пуле нельзя вывести часть депозита!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Стейк не найден! Возможно он был удален!",
+			util.T(chatId, "Введите сколько %v хотите вывести (в стейке должно остаться не меньше %v):",
+				p.JettonName,
+				util.RemoveZeroFloat(p.MinStakeAmount),
+			),
 		); err != nil {
-			log.Println(err)
+			log.Error(err)
+			return
 		}
+		currentPartialCloseStakeId[chatId] = stakeId
+		userstate.CurrentState[chatId] = userstate.EnterPartialCloseAmount
+	}
+}
+
+// confirm ставит досрочный выход в очередь выплат. args - сумма частичного вывода
+// и депозит стейка на момент расчета, по которому отсекается повторное нажатие
+func (c *CloseStake[T]) confirm(ctx context.Context, chatId int64, stakeId uint64, args []string) {
+	amount, deposit := 0., 0.
+	if len(args) > 1 {
+		var err error
+		if amount, err = strconv.ParseFloat(args[0], 64); err != nil {
+			log.Error(err)
+			return
+		}
+		if deposit, err = strconv.ParseFloat(args[1], 64); err != nil {
+			log.Error(err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if err := c.payout(ctx, func() {
+		// статус стейка проверяется уже в очереди: предыдущая выплата могла его закрыть
+		stake, p, ok := c.getStakeAndPool(chatId, stakeId)
+		if !ok {
+			return
+		}
+		if deposit > 0 && deposit != stake.Amount {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Депозит стейка изменился, запросите расчет заново!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		c.close(chatId, stake, p, amount)
+	}); err != nil {
+		log.Error("Failed to enqueue early exit payout: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось отправить токены. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func (c *CloseStake[T]) executeMessage(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	stakeId, ok := currentPartialCloseStakeId[chatId]
+	if !ok || stakeId == 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
 		return
 	}
 
-	if stake.IsRewardPaid || stake.IsInsurancePaid {
+	amount, err := strconv.ParseFloat(msg.Text, 64)
+	if err != nil || amount <= 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Сумма должна быть положительным числом! Например: 100")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	stake, p, ok := c.getStakeAndPool(chatId, stakeId)
+	if !ok {
+		return
+	}
+
+	if amount >= stake.Amount {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Токены уже получены!",
+			util.T(chatId, "❌ Сумма должна быть меньше депозита (%v %v)!", util.RemoveZeroFloat(stake.Amount), p.JettonName),
 		); err != nil {
-			log.Println(err)
+			log.Error(err)
+		}
+		return
+	}
+
+	delete(currentPartialCloseStakeId, chatId)
+	userstate.ResetState(chatId)
+
+	c.sendQuote(ctx, chatId, 0, stake, p, amount)
+}
+
+// sendQuote показывает стейкеру точную выплату перед подтверждением.
+// messageId == 0 - отправить новым сообщением
+func (c *CloseStake[T]) sendQuote(ctx context.Context, chatId int64, messageId int, stake *appModels.Stake, p *appModels.Pool, amount float64) {
+	quote, err := c.ss.EarlyExitQuote(stake, p, amount, time.Now())
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), earlyExitErrorText(util.Lang(uint64(chatId)), err, stake, p)); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	confirmId := fmt.Sprintf("%v:%v", buttons.ConfirmCloseStakeId, stake.Id.Int64)
+	if quote.IsPartial {
+		confirmId = fmt.Sprintf("%v:%v:%v", confirmId, util.RemoveZeroFloat(quote.Amount), util.RemoveZeroFloat(stake.Amount))
+	}
+	btns := []models.InlineKeyboardButton{util.CreateDefaultButton(confirmId, buttons.ConfirmCloseStake)}
+	if p.EarlyExitPartial && !quote.IsPartial {
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v", buttons.PartialCloseStakeId, stake.Id.Int64),
+			buttons.PartialCloseStake,
+		))
+	}
+	btns = append(btns, util.CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText))
+
+	text := util.T(chatId, "<b>🔒 Досрочное закрытие стейка</b>\n\n") + util.EarlyExitQuoteText(util.Lang(uint64(chatId)), quote, p.JettonName)
+	markup := util.CreateInlineMarup(1, btns...)
+	if messageId == 0 {
+		if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(chatId), messageId, text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *CloseStake[T]) close(chatId int64, stake *appModels.Stake, p *appModels.Pool, amount float64) {
+	now := time.Now()
+	quote, err := c.ss.EarlyExitQuote(stake, p, amount, now)
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), earlyExitErrorText(util.Lang(uint64(chatId)), err, stake, p)); err != nil {
+			log.Error(err)
 		}
 		return
 	}
@@ -93,98 +263,150 @@ func (c *CloseStake) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ У вас не привязан кошелек!",
+			util.T(chatId, "❌ У вас не привязан кошелек!"),
 		); err != nil {
 			log.Println(err)
 		}
 		return
 	}
 
-	p, err := c.ps.GetId(stake.PoolId)
+	jettonData, err := c.aws.DataJetton(p.JettonMaster)
 	if err != nil {
-		if _, err := util.SendTextMessage(
-			c.b,
-			uint64(chatId),
-			"❌ Не смог найти нужный пул!",
-		); err != nil {
-			log.Println(err)
+		log.Println(err)
+		return
+	}
+
+	closePrice := 0.
+	if !quote.IsPartial {
+		closePrice = util.GetCurrentPriceJettonAddr(p.JettonMaster)
+	}
+
+	// выход фиксируется до отправки, чтобы повторное подтверждение не выплатило его еще раз
+	if err := c.ss.ApplyEarlyExit(stake, p, quote, closePrice, now); err != nil {
+		log.Println("error update stake id ", stake.Id.Int64, "error: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось отправить токены. Повторите попытку позже!")); err != nil {
+			log.Error(err)
 		}
 		return
 	}
 
-	adminAmount := stake.Balance - stake.Amount
-	p.Reserve -= adminAmount
 	stakes := c.ss.GetPoolStakes(stake.PoolId)
 	p.TempReserve = p.Reserve - util.CalculateSumStakesFromPool(&stakes, p)
-
 	if err := c.ps.Update(p); err != nil {
 		log.Println(err)
 	}
 
-	jettonData, err := c.aws.DataJetton(p.JettonMaster)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
 	hashBytes, err := c.aws.SendJetton(
 		p.JettonMaster,
-		w.Addr,
+		util.PayoutAddr(stake, w),
 		"",
-		util.RemoveZeroFloat(stake.Amount),
+		util.RemoveZeroFloat(quote.Payout),
 		jettonData.Decimals,
 	)
 	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	stake.IsRewardPaid = true
-	if err := c.ss.Update(stake); err != nil {
-		log.Error(err)
+		log.Println("failed to send early exit payout, stake id ", stake.Id.Int64, "error: ", err)
+		util.RecordFailedPayout(c.as, stake.UserId, p.JettonMaster, quote.Payout, fmt.Sprintf("Досрочное закрытие стейка %v", stake.Id.Int64), err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось отправить токены. Выплата будет произведена вручную, обратитесь в поддержку!")); err != nil {
+			log.Error(err)
+		}
 		return
 	}
 
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		fmt.Sprintf("💸 %f %v были отправлены на ваш привязанный кошелек: %v", stake.Amount, p.JettonName, w.Addr),
+		util.T(chatId, "💸 %v %v были отправлены на ваш привязанный кошелек: %v", util.RemoveZeroFloat(quote.Payout), p.JettonName, util.PayoutAddr(stake, w)),
 	); err != nil {
 		log.Println(err)
-		return
-	}
-
-	closePrice := util.GetCurrentPriceJettonAddr(p.JettonMaster)
-
-	stake.IsActive = false
-	stake.CloseDate = time.Now()
-	stake.IsRewardPaid = true
-	stake.EndDate = time.Now()
-	stake.JettonPriceClosed = closePrice
-	if err := c.ss.Update(stake); err != nil {
-		log.Println("error update stake id ", stake.Id.Int64, "error: ", err)
-		return
 	}
 
 	hash := base64.StdEncoding.EncodeToString(hashBytes)
-
+	description := fmt.Sprintf("Досрочное закрытие стейка. Hash: %v", hash)
+	if quote.IsPartial {
+		description = fmt.Sprintf("Частичный вывод %v %v из стейка. Hash: %v", util.RemoveZeroFloat(quote.Amount), p.JettonName, hash)
+	}
 	if _, err := c.ops.Create(
 		stake.UserId,
 		appModels.OP_EARLY_CLOSOURE,
-		fmt.Sprintf("Досрочное закрытие стейка. Hash: %v", hash),
+		description,
 	); err != nil {
 		log.Println(err)
 	}
 
+	if quote.Forfeited <= 0 {
+		return
+	}
+
 	if _, err := c.aws.SendJetton(
 		p.JettonMaster,
 		c.aws.GetUserAdminAddr(),
 		"",
-		util.RemoveZeroFloat(adminAmount),
+		util.RemoveZeroFloat(quote.Forfeited),
 		jettonData.Decimals,
 	); err != nil {
 		log.Println(err)
 		return
 	}
+}
 
+func (c *CloseStake[T]) getStakeAndPool(chatId int64, stakeId uint64) (*appModels.Stake, *appModels.Pool, bool) {
+	stake, err := c.ss.GetById(stakeId)
+	if err != nil {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Стейк не найден! Возможно он был удален!"),
+		); err != nil {
+			log.Println(err)
+		}
+		return nil, nil, false
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil || uint64(u.Id.Int64) != stake.UserId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Это не ваш стейк!")); err != nil {
+			log.Println(err)
+		}
+		return nil, nil, false
+	}
+
+	if stake.IsRewardPaid || stake.IsInsurancePaid {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Токены уже получены!"),
+		); err != nil {
+			log.Println(err)
+		}
+		return nil, nil, false
+	}
+
+	p, err := c.ps.GetId(stake.PoolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Не смог найти нужный пул!"),
+		); err != nil {
+			log.Println(err)
+		}
+		return nil, nil, false
+	}
+
+	return stake, p, true
+}
+
+func earlyExitErrorText(lang string, err error, stake *appModels.Stake, p *appModels.Pool) string {
+	switch {
+	case errors.Is(err, services.ErrEarlyExitLocked):
+		return i18n.T(lang, "❌ Досрочный выход будет доступен с %v",
+			stake.StartDate.Add(time.Duration(p.EarlyExitMinDays)*24*time.Hour).Format("02 January 2006 15:04:05"),
+		)
+	case errors.Is(err, services.ErrPartialExitNotAllowed):
+		return i18n.T(lang, "❌ В этом пуле нельзя вывести часть депозита!")
+	case errors.Is(err, services.ErrRemainingLessThanMinimum):
+		return i18n.T(lang, "❌ В стейке должно остаться не меньше %v %v!", util.RemoveZeroFloat(p.MinStakeAmount), p.JettonName)
+	default:
+		return i18n.T(lang, "❌ Стейк уже закрыт!")
+	}
 }
@@ -68,7 +68,7 @@ func (c *CreatePool[T]) executeMessage(ctx context.Context, msg *models.Message)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"Отлично! Давайте создадим новый пул\n\n1. Введите <b>адрес вашего токена</b> <b>(Jetton Master Address)</b>:\n",
+			util.T(chatId, "Отлично! Давайте создадим новый пул\n\n1. Введите <b>адрес вашего токена</b> <b>(Jetton Master Address)</b>:\n"),
 		); err != nil {
 			log.Error("Failed to send message: ", err)
 			return
@@ -81,7 +81,7 @@ func (c *CreatePool[T]) executeMessage(ctx context.Context, msg *models.Message)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Аккаунт не активирован. Чтобы активировать аккаунт введите команду /start"); err != nil {
+			util.T(chatId, "❌ Аккаунт не активирован. Чтобы активировать аккаунт введите команду /start")); err != nil {
 			log.Error(err)
 		}
 		userstate.ResetState(chatId)
@@ -92,7 +92,7 @@ func (c *CreatePool[T]) executeMessage(ctx context.Context, msg *models.Message)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Привяжите кошелек ваш кошелек! Для этого откройте: <b>Профиль</b>",
+			util.T(chatId, "❌ Привяжите кошелек ваш кошелек! Для этого откройте: <b>Профиль</b>"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -119,7 +119,7 @@ func (c *CreatePool[T]) executeMessage(ctx context.Context, msg *models.Message)
 		c.enterMinAmountStake(msg)
 		break
 	default:
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите команду!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите команду!")); err != nil {
 			log.Error(err)
 		}
 	}
@@ -133,7 +133,7 @@ func (c *CreatePool[T]) sendTransactionCreatingPool(pool *appModels.Pool, chatId
 		if _, err := util.SendTextMessageMarkup(
 			c.b,
 			uint64(chatId),
-			"❌ Что-то пошло не так. Повторите попытку",
+			util.T(chatId, "❌ Что-то пошло не так. Повторите попытку"),
 			markup); err != nil {
 			log.Error(err)
 		}
@@ -150,6 +150,10 @@ func (c *CreatePool[T]) sendTransactionCreatingPool(pool *appModels.Pool, chatId
 
 	s, err := c.tcs.LoadSession(fmt.Sprint(chatId))
 	if err != nil {
+		if !services.IsSessionLost(err) {
+			util.SendSessionLost(c.b, uint64(chatId), err, "")
+			return err
+		}
 		if err := util.RequestRepeatTonConnect(c.b, chatId, markup, c.tcs); err != nil {
 			log.Error(err)
 			return err
@@ -193,7 +197,7 @@ func (c *CreatePool[T]) sendTransactionCreatingPool(pool *appModels.Pool, chatId
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		"🔁 Пул создается! Пожалуйста подождите...",
+		util.T(chatId, "🔁 Пул создается! Пожалуйста подождите..."),
 	); err != nil {
 		log.Error(err)
 		return err
@@ -209,7 +213,7 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Сумма должна быть числом! Например: 1",
+			util.T(chatId, "❌ Сумма должна быть числом! Например: 1"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -218,7 +222,7 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 
 	pool, ok := currentCreatingPool[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите операцию!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите операцию!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -234,8 +238,7 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf(
-				"❌ Максимальный объем пула не должен привышать %v",
+			util.T(chatId, "❌ Максимальный объем пула не должен привышать %v",
 				util.RemoveZeroFloat(maxPool),
 			),
 		); err != nil {
@@ -253,8 +256,7 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf(
-				"❌ Минимальный объем пула должен быть больше %v",
+			util.T(chatId, "❌ Минимальный объем пула должен быть больше %v",
 				util.RemoveZeroFloat(minPool),
 			),
 		); err != nil {
@@ -267,8 +269,7 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf(
-				"❌ Вы указали минимальный стейк %v. Минимальная сумма пула должна быть %v",
+			util.T(chatId, "❌ Вы указали минимальный стейк %v. Минимальная сумма пула должна быть %v",
 				util.RemoveZeroFloat(pool.MinStakeAmount),
 				util.RemoveZeroFloat(minPoolReserve),
 			),
@@ -291,13 +292,13 @@ func (c *CreatePool[T]) enterAmountToken(msg *models.Message, w *appModels.Walle
 	pool.IsActive = false
 	currentCreatingPool[chatId] = pool
 
-	btns := util.GenerateButtonWallets(w, c.tcs, true)
+	btns := util.GenerateButtonWallets(util.Lang(uint64(chatId)), w, c.tcs, true)
 
 	markup := util.CreateInlineMarup(1, btns...)
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		messages.SubmitTransaction,
+		util.T(chatId, messages.SubmitTransaction),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -318,7 +319,7 @@ func (c *CreatePool[T]) enterMinAmountStake(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Укажите минимальный размер стейка в цифрах! Например: 1",
+			util.T(chatId, "❌ Укажите минимальный размер стейка в цифрах! Например: 1"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -329,7 +330,7 @@ func (c *CreatePool[T]) enterMinAmountStake(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Минимальный стейк не может чем 1",
+			util.T(chatId, "❌ Минимальный стейк не может чем 1"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -338,13 +339,13 @@ func (c *CreatePool[T]) enterMinAmountStake(msg *models.Message) {
 
 	pool, ok := currentCreatingPool[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите операцию!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите операцию!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	resp := fmt.Sprintf("✅ Отлично! Вы указали %v минимальный стейк.\n\nУкажите сумму которая будет зарезервирована:", num)
+	resp := util.T(chatId, "✅ Отлично! Вы указали %v минимальный стейк.\n\nУкажите сумму которая будет зарезервирована:", num)
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
@@ -367,7 +368,7 @@ func (c *CreatePool[T]) enterInsuranceCoating(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Укажите страховое покрытие в цифрах! Например: 1",
+			util.T(chatId, "❌ Укажите страховое покрытие в цифрах! Например: 1"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -378,7 +379,7 @@ func (c *CreatePool[T]) enterInsuranceCoating(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Страховое покрытие не может быть меньше чем 1 и не больше 50",
+			util.T(chatId, "❌ Страховое покрытие не может быть меньше чем 1 и не больше 50"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -387,13 +388,13 @@ func (c *CreatePool[T]) enterInsuranceCoating(msg *models.Message) {
 
 	pool, ok := currentCreatingPool[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите операцию!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите операцию!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	resp := fmt.Sprintf("✅ Отлично! Вы указали %v%% за страховое покрытие.\n\nУкажите размер минимального стейка:", num)
+	resp := util.T(chatId, "✅ Отлично! Вы указали %v%% за страховое покрытие.\n\nУкажите размер минимального стейка:", num)
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
@@ -413,7 +414,7 @@ func (c *CreatePool[T]) enterProfit(msg *models.Message) {
 	text := msg.Text
 	num, err := strconv.ParseFloat(text, 64)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Укажите число! Например: 1"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Укажите число! Например: 1")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -423,7 +424,7 @@ func (c *CreatePool[T]) enterProfit(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Доходность не может быть меньше чем 0.1 и больше чем 1!",
+			util.T(chatId, "❌ Доходность не может быть меньше чем 0.1 и больше чем 1!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -432,13 +433,13 @@ func (c *CreatePool[T]) enterProfit(msg *models.Message) {
 
 	pool, ok := currentCreatingPool[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите операцию!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите операцию!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	resp := fmt.Sprintf("✅ Отлично! Доходность <b>%v%%</b> указана!\n\nУкажите страховое покрытие в процентах: \nСработает, если цена упадет на указанное кол-во процентов.", num)
+	resp := util.T(chatId, "✅ Отлично! Доходность <b>%v%%</b> указана!\n\nУкажите страховое покрытие в процентах: \nСработает, если цена упадет на указанное кол-во процентов.", num)
 	if _, err := util.SendTextMessage(c.b, uint64(chatId), resp); err != nil {
 		log.Error(err)
 		return
@@ -453,7 +454,7 @@ func (c *CreatePool[T]) enterCustomPeriodHold(msg *models.Message) {
 	text := msg.Text
 	numPeriod, err := strconv.ParseInt(text, 10, 64)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Укажите срок холда в целых цифрах! Например: 7"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Укажите срок холда в целых цифрах! Например: 7")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -463,7 +464,7 @@ func (c *CreatePool[T]) enterCustomPeriodHold(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Срок холда не может быть меньше чем 7 и не больше 356 дней!",
+			util.T(chatId, "❌ Срок холда не может быть меньше чем 7 и не больше 356 дней!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -476,7 +477,7 @@ func (c *CreatePool[T]) enterCustomPeriodHold(msg *models.Message) {
 func (c *CreatePool[T]) installPeriodPool(chatId, period int64) {
 	pool, ok := currentCreatingPool[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите операцию сначала!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -484,10 +485,9 @@ func (c *CreatePool[T]) installPeriodPool(chatId, period int64) {
 
 	pool.Period = uint(period)
 	currentCreatingPool[chatId] = pool
-	text := fmt.Sprintf(
-		"✅ Отлично. Вы выбрали <b>%v %v</b>.\n\n Укажите <b>доходность для участников</b> (%% в день). Например: 0.5 или 3.\n",
+	text := util.T(chatId, "✅ Отлично. Вы выбрали <b>%v %v</b>.\n\n Укажите <b>доходность для участников</b> (%% в день). Например: 0.5 или 3.\n",
 		period,
-		util.SuffixDay(int(period)),
+		util.SuffixDay(util.Lang(uint64(chatId)), int(period)),
 	)
 	if _, err := util.SendTextMessage(
 		c.b,
@@ -507,7 +507,7 @@ func (c *CreatePool[T]) enterJettonMaster(msg *models.Message, chatId int64, use
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Невалидный адрес! Повторите попытку!",
+			util.T(chatId, "❌ Невалидный адрес! Повторите попытку!"),
 		); err != nil {
 			log.Error(err)
 			userstate.ResetState(chatId)
@@ -519,7 +519,7 @@ func (c *CreatePool[T]) enterJettonMaster(msg *models.Message, chatId int64, use
 	newPool.OwnerId = uint64(user.Id.Int64)
 	jettonData, err := c.aws.DataJetton(jettonAddr)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите попытку!")); err != nil {
 			log.Error(err)
 			return
 		}
@@ -528,7 +528,7 @@ func (c *CreatePool[T]) enterJettonMaster(msg *models.Message, chatId int64, use
 	newPool.JettonName = jettonData.Name
 	currentCreatingPool[chatId] = newPool
 
-	text := fmt.Sprintf("✅ Отлично! Выбранный токен <b>%v</b>.\n\nВыберите срок холда:", jettonData.Name)
+	text := util.T(chatId, "✅ Отлично! Выбранный токен <b>%v</b>.\n\nВыберите срок холда:", jettonData.Name)
 
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
@@ -549,7 +549,7 @@ func (c *CreatePool[T]) executeCallback(ctx context.Context, callback *models.Ca
 	chatId := msg.Chat.ID
 	state, ok := userstate.CurrentState[chatId]
 	if !ok || state == -1 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите операцию сначала!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -571,7 +571,7 @@ func (c *CreatePool[T]) executeCallback(ctx context.Context, callback *models.Ca
 			if _, err := util.SendTextMessage(
 				c.b,
 				uint64(chatId),
-				"❌ Ваш аккаунт не активирован! Введите команду /start",
+				util.T(chatId, "❌ Ваш аккаунт не активирован! Введите команду /start"),
 			); err != nil {
 				log.Error(err)
 			}
@@ -580,14 +580,14 @@ func (c *CreatePool[T]) executeCallback(ctx context.Context, callback *models.Ca
 		w, err := c.ws.GetByUserId(uint64(user.Id.Int64))
 		if err != nil {
 			log.Error(err)
-			if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Кошелек не привязан. Перейдите в профиль и привяжите его"); err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Кошелек не привязан. Перейдите в профиль и привяжите его")); err != nil {
 				log.Error(err)
 			}
 			return
 		}
 		pool, ok := currentCreatingPool[chatId]
 		if !ok {
-			if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите операцию сначала!"); err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!")); err != nil {
 				log.Error(err)
 			}
 			return
@@ -595,7 +595,7 @@ func (c *CreatePool[T]) executeCallback(ctx context.Context, callback *models.Ca
 
 		if err := c.sendTransactionCreatingPool(&pool, chatId, w); err != nil {
 			log.Error(err)
-			if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите операцию сначала!"); err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!")); err != nil {
 				log.Error(err)
 			}
 			return
@@ -612,14 +612,14 @@ func (c *CreatePool[T]) getHoldPeriod(data string, chatId uint64) int {
 	case buttons.SixtyDaysId:
 		return 60
 	case buttons.EnterCustomPeriodId:
-		if _, err := util.SendTextMessage(c.b, chatId, "Введите свой срок холда в днях: "); err != nil {
+		if _, err := util.SendTextMessage(c.b, chatId, util.T(chatId, "Введите свой срок холда в днях: ")); err != nil {
 			log.Error(err)
 			return 0
 		}
 		userstate.CurrentState[int64(chatId)] = userstate.EnterCustomPeriodHold
 		break
 	default:
-		if _, err := util.SendTextMessage(c.b, chatId, "❌ Неизвестная мне команда!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, chatId, util.T(chatId, "❌ Неизвестная мне команда!")); err != nil {
 			log.Error(err)
 		}
 		break
@@ -5,22 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
-	"tonclient/internal/messages"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/tonbot/userstate"
 	"tonclient/internal/util"
 
+	"github.com/cameo-engineering/tonconnect"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/xssnick/tonutils-go/address"
 )
 
 var currentStakePoolId = make(map[int64]uint64)
+var currentStakePeriod = make(map[int64]uint)
 
 type CreateStakeCommand[T CommandType] struct {
 	b   *bot.Bot
@@ -75,7 +77,7 @@ func (c *CreateStakeCommand[T]) executeMessage(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Что-то пошло не так. Повторите операцию сначала!",
+			util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!"),
 		); err != nil {
 			log.Error(err)
 			return
@@ -89,7 +91,7 @@ func (c *CreateStakeCommand[T]) executeMessage(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Вводите только цифры! Например: 1.5",
+			util.T(chatId, "❌ Вводите только цифры! Например: 1.5"),
 		); err != nil {
 			log.Error(err)
 			return
@@ -107,7 +109,7 @@ func (c *CreateStakeCommand[T]) executeMessage(msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf("❌ Сумма стейка должна быть больше чем %v", util.RemoveZeroFloat(p.MinStakeAmount)),
+			util.T(chatId, "❌ Сумма стейка должна быть больше чем %v", util.RemoveZeroFloat(p.MinStakeAmount)),
 		); err != nil {
 			log.Error(err)
 		}
@@ -129,124 +131,104 @@ func (c *CreateStakeCommand[T]) executeMessage(msg *models.Message) {
 		return
 	}
 
-	currentPrice := util.GetCurrentPriceJettonAddr(p.JettonMaster)
+	if err := c.checkPoolLimits(p, uint64(u.Id.Int64), tokens); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.PoolLimitErrorText(util.Lang(uint64(chatId)), err, p)); err != nil {
+			log.Error(err)
+		}
+		return
+	}
 
 	createDate := time.Now()
-	endDate := createDate.Add(time.Duration(p.Period) * time.Hour * 24)
-
-	newStake := &appModels.Stake{
-		UserId:               uint64(u.Id.Int64),
-		PoolId:               pooldId,
-		Amount:               tokens,
-		Balance:              tokens,
-		IsCommissionPaid:     false,
-		StartDate:            createDate,
-		IsActive:             true,
-		EndDate:              endDate,
-		DepositCreationPrice: currentPrice,
+	period, ok := currentStakePeriod[chatId]
+	if !ok || period == 0 {
+		period = p.Period
 	}
-
-	w, err := c.ws.GetByUserId(uint64(u.Id.Int64))
+	tiers := c.ps.GetRewardTiers(pooldId)
+	reward, err := c.ps.ResolveReward(p, tiers, tokens, period, createDate)
 	if err != nil {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Привяжите кошелек!",
+			util.T(chatId, "❌ Для срока %v %v нет тарифа на такую сумму. Посмотрите условия пула и увеличьте сумму!", period, util.SuffixDay(util.Lang(uint64(chatId)), int(period))),
 		); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	s, err := c.tcs.LoadSession(fmt.Sprint(chatId))
+	commission, err := c.ps.ResolveCommission(p, tiers, u, tokens, period, createDate, util.GetCurrentPriceJettonAddr)
 	if err != nil {
-		if _, err := util.SendTextMessage(
-			c.b,
-			uint64(chatId),
-			"❌ Привяжите свой кошелей заново!",
-		); err != nil {
+		log.Error(err)
+		text := util.T(chatId, "❌ Не удалось рассчитать комиссию за стейк. Повторите попытку позже!")
+		if errors.Is(err, services.ErrCommissionAmount) {
+			text = util.T(chatId, "❌ Сумма стейка не покрывает комиссию. Увеличьте сумму!")
+		}
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	adminJettonMaster := os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
+	// комиссия из стейка удерживается с депозита, в стейк идет остаток
+	amount := tokens
+	if commission.Asset == appModels.COMMISSION_ASSET_STAKE {
+		amount = tokens - commission.Amount
+	}
+
+	currentPrice := util.GetCurrentPriceJettonAddr(p.JettonMaster)
 
-	jettonAddr, err := c.aws.TokenWalletAddress(adminJettonMaster, address.MustParseAddr(w.Addr))
+	endDate := createDate.Add(time.Duration(period) * time.Hour * 24)
+
+	newStake := &appModels.Stake{
+		UserId:               uint64(u.Id.Int64),
+		PoolId:               pooldId,
+		Amount:               amount,
+		Balance:              amount,
+		IsCommissionPaid:     false,
+		StartDate:            createDate,
+		IsActive:             true,
+		EndDate:              endDate,
+		DepositCreationPrice: currentPrice,
+		Reward:               reward,
+		Period:               period,
+		Commission:           commission.Amount,
+		CommissionAsset:      commission.Asset,
+	}
+
+	w, err := c.ws.GetByUserId(uint64(u.Id.Int64))
 	if err != nil {
-		log.Error(err)
+		c.offerMemoStake(chatId, newStake, util.T(chatId, "❌ Кошелек не привязан. Привяжите кошелек в профиле или оплатите стейк переводом с комментарием с любого кошелька"), false)
 		return
 	}
 
-	jsonData, err := json.Marshal(newStake)
+	newStake.DepositAddr = w.Addr
+	newStake.PayoutAddr = w.Addr
+
+	s, err := c.tcs.LoadSession(fmt.Sprint(chatId))
 	if err != nil {
 		log.Error(err)
+		if !services.IsSessionLost(err) {
+			util.SendSessionLost(c.b, uint64(chatId), err, "")
+			return
+		}
+		c.offerMemoStake(chatId, newStake, util.T(chatId, "❌ Соединение с кошельком через TonConnect истекло или было отключено. Переподключите кошелек и повторите стейк или оплатите его переводом с комментарием"), true)
 		return
 	}
 
-	commission, err := strconv.ParseFloat(os.Getenv("COMMISSION_STAKE_AMOUNT"), 64)
+	jsonData, err := json.Marshal(newStake)
 	if err != nil {
-		commission = 1.
-	}
-
-	payload := appModels.Payload{
-		OperationType: appModels.OP_PAID_COMMISSION_STAKE,
-		JettonMaster:  adminJettonMaster,
-		Amount:        commission,
-		Payload:       string(jsonData),
-	}
-
-	btns := util.GenerateButtonWallets(w, c.tcs, true)
-	//jettonData, err := c.aws.DataJetton(p.JettonMaster)
-	//if err != nil {
-	//	log.Error(err)
-	//	return
-	//}
-
-	markup := util.CreateInlineMarup(1, btns...)
-	if _, err := util.SendTextMessageMarkup(
-		c.b,
-		uint64(chatId),
-		fmt.Sprintf(
-			messages.PaidCommission,
-			commission,
-			os.Getenv("JETTON_NAME_COIN"),
-		),
-		markup,
-	); err != nil {
 		log.Error(err)
 		return
 	}
 
-	//adminJettonName := os.Getenv("JETTON_NAME_COIN")
-
-	if _, err := c.tcs.SendJettonTransaction(
-		fmt.Sprint(chatId),
-		jettonAddr.Address().String(),
-		c.aws.GetAdminWalletAddr().String(),
-		w.Addr,
-		fmt.Sprint(commission),
-		&payload,
-		s,
-	); err != nil {
+	if err := c.payStake(chatId, p, commission, tokens, w, s, string(jsonData)); err != nil {
 		log.Error(err)
-		//if _, err := util.SendTextMessage(
-		//	c.b,
-		//	uint64(chatId),
-		//	fmt.Sprintf(
-		//		`❌ Транзакция %v %v на оплату комиссии при создании стейка %v %v не была подтверждена!`,
-		//		commission,
-		//		adminJettonName,
-		//		newStake.Amount,
-		//		jettonData.Name,
-		//	),
-		//); err != nil {
-		//	log.Error(err)
-		//}
 		return
 	}
 
 	delete(userstate.CurrentState, chatId)
+	delete(currentStakePeriod, chatId)
 }
 
 func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery) {
@@ -258,8 +240,8 @@ func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery)
 	chatId := msg.Chat.ID
 	splitData := strings.Split(callback.Data, ":")
 
-	if len(splitData) != 2 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Не могу выполнить эту команду!"); err != nil {
+	if len(splitData) != 2 && len(splitData) != 3 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу выполнить эту команду!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -275,7 +257,7 @@ func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу найти данный пул! возможно он был удален!",
+			util.T(chatId, "❌ Не могу найти данный пул! возможно он был удален!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -286,7 +268,7 @@ func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Нельзя стейкнуть в закрытый пул!",
+			util.T(chatId, "❌ Нельзя стейкнуть в закрытый пул!"),
 		); err != nil {
 			log.Error(err)
 			return
@@ -298,18 +280,51 @@ func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Нельзя сделать стейк, так как резерв пуст!",
+			util.T(chatId, "❌ Нельзя сделать стейк, так как резерв пуст!"),
 		); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	// пул заполнен или пользователь не в белом списке - не спрашиваем сумму
+	if err := c.checkPoolLimits(pool, uint64(u.Id.Int64), 0); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.PoolLimitErrorText(util.Lang(uint64(chatId)), err, pool)); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	tiers := c.ps.GetRewardTiers(uint64(poolId))
+	periods := util.StakePeriods(pool, tiers)
+	period := periods[0]
+	if len(splitData) == 3 {
+		num, err := strconv.ParseUint(splitData[2], 10, 64)
+		if err != nil || !slices.Contains(periods, uint(num)) {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Такой срок холда в пуле недоступен!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		period = uint(num)
+	} else if len(periods) > 1 {
+		c.sendPeriods(chatId, pool, tiers, periods)
+		return
+	}
+
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		fmt.Sprintf(
-			"Введите кол-во токенов, которое хотите стейкнуть. Минимальный стейк в данном пуле %v %v.",
+		util.T(chatId, "Срок холда: %v %v.\n%v\nКомиссия за стейк: %v.\n\nВведите кол-во токенов, которое хотите стейкнуть. Минимальный стейк в данном пуле %v %v.",
+			period,
+			util.SuffixDay(util.Lang(uint64(chatId)), int(period)),
+			util.RewardTiersInfo(util.Lang(uint64(chatId)), pool, tiers, period),
+			util.CommissionPolicyInfo(util.Lang(uint64(chatId)), pool, c.ps.CommissionPolicy(tiers, pool.MinStakeAmount, period, time.Now())),
 			util.RemoveZeroFloat(pool.MinStakeAmount),
 			pool.JettonName,
 		),
@@ -319,22 +334,151 @@ func (c *CreateStakeCommand[T]) executeCallback(callback *models.CallbackQuery)
 	}
 
 	currentStakePoolId[chatId] = uint64(poolId)
+	currentStakePeriod[chatId] = period
 	userstate.CurrentState[chatId] = userstate.CreateStake
 }
 
+// payStake отправляет на подпись одну транзакцию: перевод комиссии и депозит стейка.
+// Комиссия из стейка входит в депозит на всю сумму tokens
+func (c *CreateStakeCommand[T]) payStake(
+	chatId int64,
+	p *appModels.Pool,
+	commission *appModels.StakeCommission,
+	tokens float64,
+	w *appModels.WalletTon,
+	s *tonconnect.Session,
+	stakeData string,
+) error {
+	sender := address.MustParseAddr(w.Addr)
+	adminAddr := c.aws.GetAdminWalletAddr().String()
+
+	depositWallet, err := c.aws.TokenWalletAddress(p.JettonMaster, sender)
+	if err != nil {
+		return err
+	}
+	deposit := services.Transfer{
+		JettonWallet: depositWallet.Address().String(),
+		Amount:       strconv.FormatFloat(tokens, 'f', -1, 64),
+		Payload: &appModels.Payload{
+			OperationType: appModels.OP_STAKE,
+			JettonMaster:  p.JettonMaster,
+			Amount:        tokens,
+			Payload:       stakeData,
+		},
+	}
+
+	text := util.T(chatId, "✅ Подтвердите в кошельке стейк %v %v.\nКомиссия: %v",
+		util.RemoveZeroFloat(tokens),
+		p.JettonName,
+		util.StakeCommissionInfo(util.Lang(uint64(chatId)), p, commission),
+	)
+	transfers := []services.Transfer{deposit}
+	if commission.Asset != appModels.COMMISSION_ASSET_STAKE && commission.Amount > 0 {
+		master := services.CommissionJettonMaster(p, commission.Asset)
+		var jettonWallet string
+		if master != "" {
+			commissionWallet, err := c.aws.TokenWalletAddress(master, sender)
+			if err != nil {
+				return err
+			}
+			jettonWallet = commissionWallet.Address().String()
+		}
+		transfers = []services.Transfer{
+			{
+				JettonWallet: jettonWallet,
+				Amount:       strconv.FormatFloat(commission.Amount, 'f', -1, 64),
+				Payload: &appModels.Payload{
+					OperationType: appModels.OP_PAID_COMMISSION_STAKE,
+					JettonMaster:  master,
+					Amount:        commission.Amount,
+					Payload:       stakeData,
+				},
+			},
+			deposit,
+		}
+		text += util.T(chatId, "\nКомиссия и депозит отправлены одной транзакцией - ее нужно подтвердить один раз.")
+	}
+	text += util.T(chatId, "\n\n❗Транзакция должна быть подтверждена в течении 10 минут")
+
+	markup := util.CreateInlineMarup(1, util.GenerateButtonWallets(util.Lang(uint64(chatId)), w, c.tcs, true)...)
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
+		return err
+	}
+
+	key := fmt.Sprint(chatId)
+	if len(transfers) == 1 {
+		_, err = c.tcs.SendJettonTransaction(key, deposit.JettonWallet, adminAddr, w.Addr, deposit.Amount, deposit.Payload, s)
+		return err
+	}
+	_, err = c.tcs.SendTransfers(key, adminAddr, w.Addr, transfers, s)
+	return err
+}
+
+// offerMemoStake предлагает оплатить стейк переводом в казну с кодом в комментарии, без TonConnect
+func (c *CreateStakeCommand[T]) offerMemoStake(chatId int64, stake *appModels.Stake, text string, reconnect bool) {
+	pendingMemoStake[chatId] = stake
+	delete(userstate.CurrentState, chatId)
+	delete(currentStakePeriod, chatId)
+
+	btns := []models.InlineKeyboardButton{util.CreateDefaultButton(buttons.MemoStakeId, buttons.MemoStake)}
+	if reconnect {
+		btns = append(btns, util.CreateDefaultButton(buttons.LinkTonConnectId, buttons.LinkTonConnect))
+	}
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, util.CreateInlineMarup(1, btns...)); err != nil {
+		log.Error(err)
+	}
+}
+
+// checkPoolLimits белый список и лимиты пула на момент создания стейка.
+// При поступлении депозита лимиты проверяются повторно
+func (c *CreateStakeCommand[T]) checkPoolLimits(pool *appModels.Pool, userId uint64, amount float64) error {
+	if pool.IsWhitelist {
+		tg, _ := c.ts.GetByUserId(userId)
+		w, _ := c.ws.GetByUserId(userId)
+		if !c.ps.IsWhitelisted(pool, tg, w) {
+			return services.ErrNotWhitelisted
+		}
+	}
+
+	return c.ss.CanStake(pool, userId, amount)
+}
+
+func (c *CreateStakeCommand[T]) sendPeriods(chatId int64, pool *appModels.Pool, tiers []appModels.RewardTier, periods []uint) {
+	btns := make([]models.InlineKeyboardButton, 0, len(periods))
+	for _, period := range periods {
+		text := fmt.Sprintf("%v %v", period, util.SuffixDay(util.Lang(uint64(chatId)), int(period)))
+		if reward, err := c.ps.ResolveReward(pool, tiers, pool.MinStakeAmount, period, time.Now()); err == nil {
+			text += util.T(chatId, " — %v%% в день", util.RemoveZeroFloat(reward))
+		}
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.CreateStakeId, pool.Id.Int64, period),
+			text,
+		))
+	}
+	btns = append(btns, util.CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText))
+
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		util.T(chatId, "Выберите срок холда:"),
+		util.CreateInlineMarup(1, btns...),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
 func (c *CreateStakeCommand[T]) checkSumStakes(
 	currentAmountStake float64,
 	currentSumStakes float64,
 	pool *appModels.Pool,
 	chatId uint64,
 ) error {
-	tenProcientFromSum := (pool.Reserve - currentSumStakes) * 0.05
+	tenProcientFromSum := util.MaxStakeAmount(pool, currentSumStakes)
 	if tenProcientFromSum < currentAmountStake {
 		if _, err := util.SendTextMessage(
 			c.b,
 			chatId,
-			fmt.Sprintf(
-				"❌ Недостаточно резерва. Максимальная сумма стейка не должна быть больше: %v",
+			util.T(chatId, "❌ Недостаточно резерва. Максимальная сумма стейка не должна быть больше: %v",
 				util.RemoveZeroFloat(tenProcientFromSum),
 			),
 		); err != nil {
@@ -53,7 +53,7 @@ func (c *DeletePool) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Пул не найден! возможно он был удален!",
+			util.T(chatId, "❌ Пул не найден! возможно он был удален!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -64,7 +64,7 @@ func (c *DeletePool) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Невозможно удалить пул. Резерв должен быть пуст и пул закрыт!",
+			util.T(chatId, "❌ Невозможно удалить пул. Резерв должен быть пуст и пул закрыт!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -83,7 +83,7 @@ func (c *DeletePool) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf("❌ Дождитесь пока все заберут свои награды. Пользователей осталось: %d", count),
+			util.T(chatId, "❌ Дождитесь пока все заберут свои награды. Пользователей осталось: %d", count),
 		); err != nil {
 			log.Error(err)
 		}
@@ -96,7 +96,7 @@ func (c *DeletePool) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ошибка при удалении пула. Повторите попытку!",
+			util.T(chatId, "❌ Ошибка при удалении пула. Повторите попытку!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -106,7 +106,7 @@ func (c *DeletePool) Execute(ctx context.Context, callback *models.CallbackQuery
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		"✅ Пул успешно удален!",
+		util.T(chatId, "✅ Пул успешно удален!"),
 	); err != nil {
 		log.Error(err)
 	}
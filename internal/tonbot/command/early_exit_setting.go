@@ -0,0 +1,293 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/callbacksuf"
+	"tonclient/internal/tonbot/userstate"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var currentEarlyExitPoolId = make(map[int64]uint64)
+
+type EarlyExitSetting[T CommandType] struct {
+	b  *bot.Bot
+	ps *services.PoolService
+	us *services.UserService
+	ss *services.StakeService
+}
+
+func NewEarlyExitSettingCommand[T CommandType](b *bot.Bot, ps *services.PoolService, us *services.UserService,
+	ss *services.StakeService) *EarlyExitSetting[T] {
+	return &EarlyExitSetting[T]{
+		b:  b,
+		ps: ps,
+		us: us,
+		ss: ss,
+	}
+}
+
+func (c *EarlyExitSetting[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.executeMessage(ctx, v)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		c.executeCallback(ctx, v)
+		return
+	}
+}
+
+func (c *EarlyExitSetting[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	splitData := strings.Split(callback.Data, ":")
+	if len(splitData) < 3 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	poolId, err := strconv.ParseUint(splitData[1], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не верный ID пула!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	pool, ok := c.getOwnerPool(chatId, poolId)
+	if !ok {
+		return
+	}
+
+	if splitData[0] == buttons.EarlyExitSettingId {
+		c.render(ctx, chatId, msg.ID, pool, splitData[2])
+		return
+	}
+
+	if !c.checkNoActiveStakes(chatId, poolId) {
+		return
+	}
+
+	switch splitData[0] {
+	case buttons.EarlyExitPenaltyId:
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "Введите штраф за досрочный выход в % от выводимой суммы (от 0 до 100). Например: 10"),
+		); err != nil {
+			log.Error(err)
+			return
+		}
+		currentEarlyExitPoolId[chatId] = poolId
+		userstate.CurrentState[chatId] = userstate.EnterEarlyExitPenalty
+		return
+	case buttons.EarlyExitMinDaysId:
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "Введите через сколько дней после старта стейка разрешен досрочный выход (от 0 до %v):", pool.Period),
+		); err != nil {
+			log.Error(err)
+			return
+		}
+		currentEarlyExitPoolId[chatId] = poolId
+		userstate.CurrentState[chatId] = userstate.EnterEarlyExitMinDays
+		return
+	case buttons.EarlyExitPartialId:
+		pool.EarlyExitPartial = !pool.EarlyExitPartial
+	case buttons.EarlyExitProRataId:
+		pool.EarlyExitProRata = !pool.EarlyExitProRata
+	default:
+		return
+	}
+
+	if err := c.ps.Update(pool); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Настройки не были сохранены. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	c.render(ctx, chatId, msg.ID, pool, splitData[2])
+}
+
+func (c *EarlyExitSetting[T]) executeMessage(ctx context.Context, msg *models.Message) {
+	chatId := msg.Chat.ID
+	poolId, ok := currentEarlyExitPoolId[chatId]
+	if !ok || poolId == 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
+		return
+	}
+
+	pool, ok := c.getOwnerPool(chatId, poolId)
+	if !ok {
+		return
+	}
+
+	if !c.checkNoActiveStakes(chatId, poolId) {
+		return
+	}
+
+	switch userstate.CurrentState[chatId] {
+	case userstate.EnterEarlyExitPenalty:
+		num, err := strconv.ParseFloat(msg.Text, 64)
+		if err != nil || num < 0 || num > 100 {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Штраф должен быть числом от 0 до 100! Например: 10")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		pool.EarlyExitPenalty = num
+	case userstate.EnterEarlyExitMinDays:
+		num, err := strconv.ParseUint(msg.Text, 10, 64)
+		if err != nil || num > uint64(pool.Period) {
+			if _, err := util.SendTextMessage(
+				c.b,
+				uint64(chatId),
+				util.T(chatId, "❌ Укажите целое число дней от 0 до %v!", pool.Period),
+			); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		pool.EarlyExitMinDays = uint(num)
+	default:
+		return
+	}
+
+	if err := c.ps.Update(pool); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Настройки не были сохранены. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	delete(currentEarlyExitPoolId, chatId)
+	userstate.ResetState(chatId)
+
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		util.T(chatId, "✅ Условия сохранены!\n")+earlyExitSettingText(util.Lang(uint64(chatId)), pool),
+		earlyExitSettingMarkup(util.Lang(uint64(chatId)), pool, callbacksuf.My),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *EarlyExitSetting[T]) getOwnerPool(chatId int64, poolId uint64) (*appModels.Pool, bool) {
+	pool, err := c.ps.GetId(poolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	if uint64(u.Id.Int64) != pool.OwnerId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Вы не владелец этого пула!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	return pool, true
+}
+
+// условия досрочного выхода применяются в момент выхода, поэтому менять их под уже открытыми стейками нельзя
+func (c *EarlyExitSetting[T]) checkNoActiveStakes(chatId int64, poolId uint64) bool {
+	if c.ss.CountStakesPoolIdAndStatus(poolId, true) > 0 {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Условия досрочного выхода нельзя менять, пока в пуле есть активные стейки!"),
+		); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
+		return false
+	}
+	return true
+}
+
+func (c *EarlyExitSetting[T]) render(ctx context.Context, chatId int64, messageId int, pool *appModels.Pool, suf string) {
+	if err := util.EditTextMessageMarkup(
+		ctx,
+		c.b,
+		uint64(chatId),
+		messageId,
+		earlyExitSettingText(util.Lang(uint64(chatId)), pool),
+		earlyExitSettingMarkup(util.Lang(uint64(chatId)), pool, suf),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func earlyExitSettingText(lang string, pool *appModels.Pool) string {
+	return i18n.T(lang, "<b>⚙️ Условия досрочного выхода из пула %v</b>\n\n%v\n\nУсловия можно менять, пока в пуле нет активных стейков.",
+		pool.JettonName,
+		util.EarlyExitRules(lang, pool),
+	)
+}
+
+func earlyExitSettingMarkup(lang string, pool *appModels.Pool, suf string) *models.InlineKeyboardMarkup {
+	poolId := pool.Id.Int64
+	penalty := util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.EarlyExitPenaltyId, poolId, suf),
+		fmt.Sprintf("%v%v%%", i18n.T(lang, buttons.EarlyExitPenalty), util.RemoveZeroFloat(pool.EarlyExitPenalty)),
+	)
+	minDays := util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.EarlyExitMinDaysId, poolId, suf),
+		fmt.Sprintf("%v%v %v", i18n.T(lang, buttons.EarlyExitMinDays), pool.EarlyExitMinDays, util.SuffixDay(lang, int(pool.EarlyExitMinDays))),
+	)
+	partial := util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.EarlyExitPartialId, poolId, suf),
+		i18n.T(lang, buttons.EarlyExitPartial)+onOff(lang, pool.EarlyExitPartial),
+	)
+	proRata := util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.EarlyExitProRataId, poolId, suf),
+		i18n.T(lang, buttons.EarlyExitProRata)+onOff(lang, pool.EarlyExitProRata),
+	)
+	back := util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.PoolDataButton, poolId, suf),
+		buttons.BackPoolInfo,
+	)
+
+	return util.CreateInlineMarup(1, penalty, minDays, partial, proRata, back)
+}
+
+func onOff(lang string, b bool) string {
+	if b {
+		return i18n.T(lang, "вкл ✅")
+	}
+	return i18n.T(lang, "выкл ❌")
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"tonclient/internal/i18n"
 	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/util"
 
@@ -28,15 +29,15 @@ func (c *Info) Execute(ctx context.Context, msg *models.Message) {
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		c.generateInfo(),
+		c.generateInfo(util.Lang(uint64(chatId))),
 		markup,
 	); err != nil {
 		log.Infoln(err)
 	}
 }
 
-func (c *Info) generateInfo() string {
-	return `
+func (c *Info) generateInfo(lang string) string {
+	return i18n.T(lang, `
 <b>Что такое стейкинг?</b>
 Стейкинг — это процесс блокировки ваших криптоактивов на определенный срок. 
 Взамен вы получаете вознаграждение — это похоже на процент по вкладу в банке, но с криптовалютной доходностью.
@@ -77,5 +78,5 @@ func (c *Info) generateInfo() string {
 ➖➖➖➖➖➖➖➖➖
 ❓ <b>Остались вопросы?</b>
 Задайте их в поддержке: @NestrahDev
-`
+`)
 }
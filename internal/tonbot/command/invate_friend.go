@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/util"
 
 	"github.com/go-telegram/bot"
@@ -14,23 +18,26 @@ import (
 type InviteFriendCommand struct {
 	b  *bot.Bot
 	us *services.UserService
+	rs *services.ReferalService
 }
 
-func NewInviteFriendCommand(b *bot.Bot, us *services.UserService) *InviteFriendCommand {
+func NewInviteFriendCommand(b *bot.Bot, us *services.UserService, rs *services.ReferalService) *InviteFriendCommand {
 	return &InviteFriendCommand{
 		b:  b,
 		us: us,
+		rs: rs,
 	}
 }
 
 func (c *InviteFriendCommand) Execute(ctx context.Context, msg *models.Message) {
 	chatId := msg.Chat.ID
 
-	if _, err := c.us.GetByTelegramChatId(uint64(chatId)); err != nil {
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
 		if _, er := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ваш профиль не найден. Введите команду: /start и повторите попытку",
+			util.T(chatId, "❌ Ваш профиль не найден. Введите команду: /start и повторите попытку"),
 		); er != nil {
 			log.Error(err)
 			return
@@ -38,33 +45,80 @@ func (c *InviteFriendCommand) Execute(ctx context.Context, msg *models.Message)
 		return
 	}
 
-	referalCode := util.GenerateReferralTelegramCode(fmt.Sprint(chatId))
+	referalCode, err := c.us.ReferralCode(u)
+	if err != nil {
+		log.Error("Failed to get referral code: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось получить реферальную ссылку. Попробуйте позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
 
 	url := fmt.Sprint("https://t.me/StakeNestrahBot?start=", referalCode)
 
-	if _, err := util.SendTextMessage(
+	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		fmt.Sprint(generateMessage(), "Ваша реферальная ссылка: ", url),
+		fmt.Sprint(generateMessage(util.Lang(uint64(chatId))), c.statsMessage(util.Lang(uint64(chatId)), uint64(u.Id.Int64)), util.T(chatId, "Ваша реферальная ссылка: "), url),
+		util.CreateInlineMarup(1, util.CreateDefaultButton(buttons.MyReferralsId, buttons.MyReferrals)),
 	); err != nil {
 		log.Error(err)
 		return
 	}
 }
 
-func generateMessage() string {
+func generateMessage(lang string) string {
 	coinName := os.Getenv("JETTON_NAME_COIN")
 	if coinName == "" {
 		coinName = "NESTRAH"
 	}
-	referalBonus := os.Getenv("REFERAL_BONUS")
-	if referalBonus == "" {
-		referalBonus = "2"
+	cfg := config.LoadReferralConfig()
+
+	levels := ""
+	for i, percent := range cfg.Levels {
+		levels += i18n.T(lang, " •	%v уровень: <b>%v%%</b>\n", i+1, util.RemoveZeroFloat(percent))
 	}
 
-	return fmt.Sprintf(
-		"Пригласи друга и получи <b>%v%%</b> от суммы его первого стейка.!\n\nНачислим в %v коинах",
-		referalBonus,
+	reward := i18n.T(lang, "от суммы каждого закрытого стейка приглашенных")
+	switch {
+	case cfg.Base == appModels.REFERRAL_BASE_COMMISSION && cfg.Mode == appModels.REFERRAL_MODE_ONCE:
+		reward = i18n.T(lang, "от комиссии за первый стейк каждого приглашенного")
+	case cfg.Base == appModels.REFERRAL_BASE_COMMISSION:
+		reward = i18n.T(lang, "от комиссии за каждый стейк приглашенных")
+	case cfg.Mode == appModels.REFERRAL_MODE_ONCE:
+		reward = i18n.T(lang, "от суммы первого закрытого стейка каждого приглашенного")
+	}
+	conditions := i18n.T(lang, "Стейк засчитывается, если он не закрыт досрочно")
+	if cfg.MinStake > 0 {
+		conditions += i18n.T(lang, ", его сумма не меньше %v %v", util.RemoveZeroFloat(cfg.MinStake), coinName)
+	}
+	if cfg.MinHold > 0 {
+		conditions += i18n.T(lang, ", срок не меньше %v дн.", cfg.MinHold)
+	}
+	conditions += i18n.T(lang, ", а друг стейкает со своего кошелька, не связанного с вашим или другими аккаунтами.")
+
+	return i18n.T(
+		lang,
+		"Пригласи друзей и получай награду %v. "+
+			"Награда начисляется и за друзей, которых пригласили они:\n%v\n"+
+			"%v\n\n"+
+			"Награды копятся на реферальном балансе и выплачиваются в %v коинах на привязанный кошелек, когда баланс достигнет %v.\n\n",
+		reward,
+		levels,
+		conditions,
 		coinName,
+		util.RemoveZeroFloat(cfg.Threshold),
+	)
+}
+
+func (c *InviteFriendCommand) statsMessage(lang string, userId uint64) string {
+	stats, err := c.rs.Stats(userId)
+	if err != nil || stats.Accruals == 0 {
+		return ""
+	}
+	return i18n.T(lang, "Реферальный баланс: <b>%v</b>\nВыплачено: %v\nРефералов с начислениями: %v\n\n",
+		util.RemoveZeroFloat(stats.Balance),
+		util.RemoveZeroFloat(stats.Paid),
+		stats.Users,
 	)
 }
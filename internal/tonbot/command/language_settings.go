@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"tonclient/internal/i18n"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// LanguageSettings выбор языка бота. Без выбора язык определяется по языку Telegram
+type LanguageSettings struct {
+	b  *bot.Bot
+	ts *services.TelegramService
+}
+
+func NewLanguageSettings(b *bot.Bot, ts *services.TelegramService) *LanguageSettings {
+	return &LanguageSettings{
+		b:  b,
+		ts: ts,
+	}
+}
+
+func (c *LanguageSettings) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	chatId := callback.Message.Message.Chat.ID
+
+	tg, err := c.ts.GetTelegramId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Профиль не найден. Введите команду /start, чтобы создать профиль!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	btns := make([]models.InlineKeyboardButton, 0, len(i18n.Languages)+1)
+	for _, lang := range i18n.Languages {
+		text := i18n.Name(lang)
+		if tg.Language == lang {
+			text = "✅ " + text
+		}
+		btns = append(btns, util.CreateDefaultButton(buttons.SetLanguageId+":"+lang, text))
+	}
+	auto := util.T(chatId, buttons.LanguageAuto)
+	if tg.Language == "" {
+		auto = "✅ " + auto
+	}
+	btns = append(btns, util.CreateDefaultButton(buttons.SetLanguageId+":", auto))
+
+	markup := util.MenuWithBackButton(buttons.DefCloseId, buttons.DefCloseText, btns...)
+	text := util.T(chatId, "<b>🌐 Язык</b>\n\nВыберите язык бота. Если язык не выбран, он определяется по языку Telegram.")
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+// Set сохраняет язык из данных кнопки, пустой язык - определение по языку Telegram
+func (c *LanguageSettings) Set(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+
+	lang := strings.TrimPrefix(callback.Data, buttons.SetLanguageId+":")
+	if err := c.ts.SetLanguage(uint64(chatId), lang); err != nil {
+		log.Error("Failed to set language: ", err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите попытку.")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	util.ForgetLanguage(uint64(chatId))
+
+	// кнопки меню обновятся, когда пользователь снова откроет меню
+	btn1 := util.CreateDefaultButton(buttons.RoleButtonUserId, buttons.RoleButtonUserText)
+	btn2 := util.CreateDefaultButton(buttons.RoleButtonOwnerTokensId, buttons.RoleButtonOwnerTokensText)
+	markup := util.CreateInlineMarup(2, btn1, btn2)
+
+	text := util.T(chatId, "✅ Язык бота: %v\n\nВыберите роль, чтобы открыть меню на новом языке.", i18n.Name(util.Lang(uint64(chatId))))
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(chatId), msg.ID, text, markup); err != nil {
+		log.Error(err)
+	}
+}
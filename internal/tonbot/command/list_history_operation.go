@@ -37,7 +37,7 @@ func (c *ListHistoryOperation) executeMessage(ctx context.Context, msg *models.M
 
 	u, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Аккаунт не активирован! Введите команду /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован! Введите команду /start")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -59,7 +59,7 @@ func (c *ListHistoryOperation) executeMessage(ctx context.Context, msg *models.M
 		if _, err := util.SendTextMessageMarkup(
 			c.b,
 			uint64(chatId),
-			"<b>Список ваших операций</b>\n\n Выберите операцию из списка, чтобы узнать подробности.",
+			util.T(chatId, "<b>Список ваших операций</b>\n\n Выберите операцию из списка, чтобы узнать подробности."),
 			markup); err != nil {
 			log.Error(err)
 			return
@@ -85,7 +85,7 @@ func (c *ListHistoryOperation) generateOperationList(chatId int64, u *appModel.U
 		buttons.NextPageHistory,
 		buttons.BackPageHistory,
 		buttons.CloseListHistory,
-		util.GenerateOperationButtons(operations)...,
+		util.GenerateOperationButtons(util.Lang(uint64(chatId)), operations)...,
 	)
 
 	return markup, nil
@@ -110,7 +110,7 @@ func (c *ListHistoryOperation) NextPage(ctx context.Context, callback *models.Ca
 
 	u, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Аккаунт не активирован! Введите команду /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован! Введите команду /start")); err != nil {
 			log.Error(err)
 		}
 		return
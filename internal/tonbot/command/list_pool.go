@@ -51,14 +51,14 @@ func (c *ListPoolCommand) Execute(ctx context.Context, msg *models.Message) {
 		buttons.NextPagePool,
 		buttons.BackPagePool,
 		buttons.CloseListPool,
-		util.GeneratePoolButtons(pools, c.aws, callbacksuf.All, c.ss)...,
+		util.GeneratePoolButtons(util.Lang(uint64(chatId)), pools, c.aws, callbacksuf.All, c.ss)...,
 	)
 
 	if err := util.EditMessageMarkup(ctx, c.b, uint64(chatId), msg.ID, markup); err != nil {
 		if _, err := util.SendTextMessageMarkup(
 			c.b,
 			uint64(chatId),
-			"Выберите пул из списка, чтобы узнать подробную информацию о нем или сделать стейк.\n\nСозданные пулы: ",
+			util.T(chatId, "Выберите пул из списка, чтобы узнать подробную информацию о нем или сделать стейк.\n\nСозданные пулы: "),
 			markup); err != nil {
 			log.Error(err)
 		}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"tonclient/internal/config"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// стейк, который пользователь решил оплатить переводом с комментарием
+var pendingMemoStake = make(map[int64]*appModels.Stake)
+
+type MemoStake struct {
+	b   *bot.Bot
+	ps  *services.PoolService
+	aws *services.AdminWalletService
+	is  *services.TxIntentService
+}
+
+func NewMemoStakeCommand(b *bot.Bot, ps *services.PoolService, aws *services.AdminWalletService, is *services.TxIntentService) *MemoStake {
+	return &MemoStake{
+		b:   b,
+		ps:  ps,
+		aws: aws,
+		is:  is,
+	}
+}
+
+func (c *MemoStake) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	chatId := callback.Message.Message.Chat.ID
+
+	stake, ok := pendingMemoStake[chatId]
+	if !ok {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	pool, err := c.ps.GetId(stake.PoolId)
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу найти данный пул! возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	jsonData, err := json.Marshal(stake)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// комиссия из стейка входит в перевод депозита
+	deposit := &appModels.Payload{
+		OperationType: appModels.OP_STAKE,
+		JettonMaster:  pool.JettonMaster,
+		Amount:        stake.Amount,
+		Payload:       string(jsonData),
+	}
+	parts := []*appModels.Payload{deposit}
+	if stake.CommissionAsset == appModels.COMMISSION_ASSET_STAKE {
+		deposit.Amount += stake.Commission
+	} else if stake.Commission > 0 {
+		commission := &appModels.Payload{
+			OperationType: appModels.OP_PAID_COMMISSION_STAKE,
+			JettonMaster:  services.CommissionJettonMaster(pool, stake.CommissionAsset),
+			Amount:        stake.Commission,
+			Payload:       string(jsonData),
+		}
+		parts = []*appModels.Payload{commission, deposit}
+	}
+
+	intents, err := c.is.CreateMemo(uint64(chatId), parts...)
+	if err != nil || len(intents) == 0 {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось сформировать перевод. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	delete(pendingMemoStake, chatId)
+
+	// суммы и названия для каждого jetton: при совпадении jetton комиссия и депозит идут одним переводом
+	amounts := make(map[string]float64)
+	names := map[string]string{
+		services.CommissionJettonMaster(pool, stake.CommissionAsset): util.CommissionAssetName(pool, stake.CommissionAsset),
+		pool.JettonMaster: pool.JettonName,
+	}
+	for _, p := range parts {
+		amounts[p.JettonMaster] += p.Amount
+	}
+
+	treasury := c.aws.GetAdminWalletAddr().String()
+	memo := intents[0].Memo
+
+	lines := make([]string, 0, len(intents))
+	for i, intent := range intents {
+		line := fmt.Sprintf("%v. <b>%v %v</b>", i+1, util.RemoveZeroFloat(amounts[intent.JettonMaster]), names[intent.JettonMaster])
+		if intent.JettonMaster != "" {
+			line += fmt.Sprintf("\nJetton: <code>%v</code>", intent.JettonMaster)
+		}
+		lines = append(lines, line)
+	}
+
+	text := util.T(chatId, `💸 <b>Оплата стейка переводом</b>
+
+Отправьте с любого кошелька на адрес казны:
+<code>%v</code>
+
+%v
+
+Комментарий к каждому переводу (обязательно!): <code>%v</code>
+
+Код действует до %v (UTC). Стейк будет создан, когда поступят все переводы. Выплаты пойдут на ваш основной кошелек, привязанный в профиле.`,
+		treasury,
+		strings.Join(lines, "\n\n"),
+		memo,
+		intents[0].ExpiresAt.Time.UTC().Format("02.01.2006 15:04"),
+	)
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
+		log.Error(err)
+		return
+	}
+
+	for i, intent := range intents {
+		link := services.TransferLink(treasury, intent.JettonMaster, intent.AmountUnits, memo)
+		markup := util.CreateInlineMarup(1, models.InlineKeyboardButton{
+			Text: buttons.OpenTransferWallet,
+			URL:  services.TonkeeperTransferLink(treasury, intent.JettonMaster, intent.AmountUnits, memo),
+		})
+		qrUrl := config.QrCodeUrl(link)
+		if qrUrl == "" {
+			text := util.T(chatId, "%v. %v %v\n\nОткройте ссылку в кошельке:\n<code>%v</code>",
+				i+1,
+				util.RemoveZeroFloat(amounts[intent.JettonMaster]),
+				names[intent.JettonMaster],
+				link,
+			)
+			if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
+				log.Error(err)
+			}
+			continue
+		}
+
+		caption := util.T(chatId, "%v. %v %v\n\nОтсканируйте QR-код кошельком или откройте ссылку:\n<code>%v</code>",
+			i+1,
+			util.RemoveZeroFloat(amounts[intent.JettonMaster]),
+			names[intent.JettonMaster],
+			link,
+		)
+		if _, err := util.SendPhotoUrlMarkup(c.b, uint64(chatId), qrUrl, caption, markup); err != nil {
+			log.Error(err)
+		}
+	}
+}
@@ -43,7 +43,7 @@ func (c *MyPools) Execute(ctx context.Context, msg *models.Message) {
 
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Ваш аккаунт не активирован. Чтобы активировать аккаунт введите /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Ваш аккаунт не активирован. Чтобы активировать аккаунт введите /start")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -59,14 +59,14 @@ func (c *MyPools) Execute(ctx context.Context, msg *models.Message) {
 		buttons.NextPageMyPool,
 		buttons.BackPageMyPool,
 		buttons.CloseListPool,
-		util.GeneratePoolButtons(pools, c.aws, callbacksuf.My, c.ss)...,
+		util.GeneratePoolButtons(util.Lang(uint64(chatId)), pools, c.aws, callbacksuf.My, c.ss)...,
 	)
 
 	if err := util.EditMessageMarkup(ctx, c.b, uint64(chatId), msg.ID, markup); err != nil {
 		if _, err := util.SendTextMessageMarkup(
 			c.b,
 			uint64(chatId),
-			"Выберите пул из списка, чтобы узнать подробную информацию о нем или управлять ими.\n\nВаши пулы: ",
+			util.T(chatId, "Выберите пул из списка, чтобы узнать подробную информацию о нем или управлять ими.\n\nВаши пулы: "),
 			markup); err != nil {
 			log.Error(err)
 		}
@@ -77,7 +77,7 @@ func (c *MyPools) NextPage(ctx context.Context, callback *models.CallbackQuery)
 	chatId := callback.From.ID
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Ваш аккаунт не активирован. Чтобы активировать аккаунт введите /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Ваш аккаунт не активирован. Чтобы активировать аккаунт введите /start")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"context"
+	"math"
+	"strings"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var currentPageReferrals = make(map[int64]int)
+
+// MyReferrals список приглашенных пользователей с их стейками и наградами за них
+type MyReferrals struct {
+	b  *bot.Bot
+	us *services.UserService
+	rs *services.ReferalService
+}
+
+func NewMyReferrals(b *bot.Bot, us *services.UserService, rs *services.ReferalService) *MyReferrals {
+	return &MyReferrals{
+		b:  b,
+		us: us,
+		rs: rs,
+	}
+}
+
+func (c *MyReferrals) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+
+	chatId := callback.Message.Message.Chat.ID
+	currentPageReferrals[chatId] = 0
+
+	text, markup, err := c.page(chatId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось загрузить список рефералов. Попробуйте позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *MyReferrals) NextPage(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+
+	chatId := callback.Message.Message.Chat.ID
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	_, total, err := c.us.GetReferralsInfo(u, uint64(chatId), 0, 0)
+	if err != nil {
+		return
+	}
+
+	currentPageReferrals = util.NextPageV2(
+		callback,
+		currentPageReferrals,
+		totalPages(total),
+		c.b,
+		func() {
+			c.edit(ctx, callback)
+		},
+	)
+}
+
+func (c *MyReferrals) BackPage(ctx context.Context, callback *models.CallbackQuery) {
+	currentPageReferrals = util.BackPageV2(
+		callback,
+		currentPageReferrals,
+		c.b,
+		func() {
+			c.edit(ctx, callback)
+		},
+	)
+}
+
+func (c *MyReferrals) Close(ctx context.Context, callback *models.CallbackQuery) {
+	currentPageReferrals = util.CloseList(ctx, callback, currentPageReferrals, c.b)
+}
+
+func (c *MyReferrals) edit(ctx context.Context, callback *models.CallbackQuery) {
+	msg := callback.Message.Message
+	text, markup, err := c.page(msg.Chat.ID)
+	if err != nil {
+		return
+	}
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(msg.Chat.ID), msg.ID, text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *MyReferrals) page(chatId int64) (string, *models.InlineKeyboardMarkup, error) {
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		return "", nil, err
+	}
+
+	page := util.GetCurrentPage(chatId, currentPageReferrals)
+	refs, total, err := c.us.GetReferralsInfo(u, uint64(chatId), page*numberElementPage, numberElementPage)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(util.T(chatId, "<b>👥 Мои рефералы</b>\n\n"))
+	if stats, err := c.rs.Stats(uint64(u.Id.Int64)); err == nil {
+		sb.WriteString(util.T(chatId, "Приглашено: <b>%v</b>\nРеферальный баланс: <b>%v</b>\nВыплачено: <b>%v</b>\n\n",
+			total,
+			util.RemoveZeroFloat(stats.Balance),
+			util.RemoveZeroFloat(stats.Paid),
+		))
+	}
+
+	if total == 0 {
+		sb.WriteString(util.T(chatId, "Вы еще никого не пригласили. Поделитесь реферальной ссылкой с друзьями!"))
+	}
+	for i, ref := range refs {
+		name := ref.Username
+		if name == "" {
+			name = util.T(chatId, "без имени")
+		} else {
+			name = "@" + name
+		}
+		sb.WriteString(util.T(chatId, "%v. %v с %v\n •	Стейков: %v, активных: %v\n •	Награда за реферала: <b>%v</b>\n\n",
+			page*numberElementPage+i+1,
+			name,
+			ref.CreatedAt.Format("02.01.2006"),
+			ref.Stakes,
+			ref.ActiveStakes,
+			util.RemoveZeroFloat(ref.Earned),
+		))
+	}
+
+	markup := util.GenerateNextBackMenu(
+		page,
+		totalPages(total),
+		buttons.NextPageReferrals,
+		buttons.BackPageReferrals,
+		buttons.CloseListReferrals,
+	)
+	return sb.String(), markup, nil
+}
+
+func totalPages(total int) int {
+	return int(math.Ceil(float64(total) / float64(numberElementPage)))
+}
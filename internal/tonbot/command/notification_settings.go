@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const notificationSettingsText = "<b>🔔 Уведомления</b>\n\nВыберите события, о которых бот будет присылать сообщения. Нажмите на событие, чтобы включить или отключить его."
+
+// NotificationSettings экран включения и отключения уведомлений о событиях
+type NotificationSettings struct {
+	b  *bot.Bot
+	us *services.UserService
+	ns *services.NotificationService
+}
+
+func NewNotificationSettings(b *bot.Bot, us *services.UserService, ns *services.NotificationService) *NotificationSettings {
+	return &NotificationSettings{
+		b:  b,
+		us: us,
+		ns: ns,
+	}
+}
+
+func (c *NotificationSettings) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	chatId := callback.Message.Message.Chat.ID
+
+	markup, err := c.markup(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось загрузить настройки уведомлений. Попробуйте позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), util.T(chatId, notificationSettingsText), markup); err != nil {
+		log.Error(err)
+	}
+}
+
+// Toggle переключает событие из данных кнопки и обновляет экран
+func (c *NotificationSettings) Toggle(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+
+	data := strings.Split(callback.Data, ":")
+	if len(data) != 2 {
+		return
+	}
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		return
+	}
+	if _, err := c.ns.Toggle(uint64(u.Id.Int64), data[1]); err != nil {
+		log.Error("Failed to toggle notification: ", err)
+		return
+	}
+
+	markup, err := c.markup(uint64(chatId))
+	if err != nil {
+		return
+	}
+	if err := util.EditMessageMarkup(ctx, c.b, uint64(chatId), msg.ID, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *NotificationSettings) Close(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	if err := util.DeleteMessage(ctx, c.b, uint64(msg.Chat.ID), msg.ID); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *NotificationSettings) markup(chatId uint64) (*models.InlineKeyboardMarkup, error) {
+	u, err := c.us.GetByTelegramChatId(chatId)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := c.ns.Settings(uint64(u.Id.Int64))
+	if err != nil {
+		return nil, err
+	}
+
+	btns := make([]models.InlineKeyboardButton, 0, len(services.NotificationEvents))
+	for _, event := range services.NotificationEvents {
+		mark := "❌"
+		if settings[event] {
+			mark = "✅"
+		}
+		btns = append(btns, util.CreateDefaultButton(buttons.NotificationToggleId+":"+event, mark+" "+util.T(chatId, util.NotificationEventName(event))))
+	}
+	return util.MenuWithBackButton(buttons.CloseNotificationSettingsId, "Закрыть ❌", btns...), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
@@ -38,7 +39,7 @@ func (c *OpenInfoOperation) Execute(ctx context.Context, callback *models.Callba
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу обработать эту кнопку!",
+			util.T(chatId, "❌ Не могу обработать эту кнопку!"),
 		); err != nil {
 			log.Error(err)
 			return
@@ -51,7 +52,7 @@ func (c *OpenInfoOperation) Execute(ctx context.Context, callback *models.Callba
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу открыть данную операцию!",
+			util.T(chatId, "❌ Не могу открыть данную операцию!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -63,14 +64,14 @@ func (c *OpenInfoOperation) Execute(ctx context.Context, callback *models.Callba
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Операция не найдена! Возможно она была удалена!",
+			util.T(chatId, "❌ Операция не найдена! Возможно она была удалена!"),
 		); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	info := c.generateTextOperation(op)
+	info := c.generateTextOperation(util.Lang(uint64(chatId)), op)
 	backListHistory := util.CreateDefaultButton(buttons.BackHistoryListId, buttons.BackHistoryList)
 	markup := util.CreateInlineMarup(1, backListHistory)
 	if err := util.EditTextMessageMarkup(
@@ -86,13 +87,13 @@ func (c *OpenInfoOperation) Execute(ctx context.Context, callback *models.Callba
 	}
 }
 
-func (c *OpenInfoOperation) generateTextOperation(op *appModels.Operation) string {
-	text := `
+func (c *OpenInfoOperation) generateTextOperation(lang string, op *appModels.Operation) string {
+	text := i18n.T(lang, `
 	<b>%v</b>
 
 	<b>Детали</b>: %v
 	<b>Время выполнения операции</b>: %v
-	`
+	`)
 
-	return fmt.Sprintf(text, op.Name, op.Description, op.CreatedAt.Format("02.01.2006 15:04:05"))
+	return fmt.Sprintf(text, i18n.T(lang, op.Name), op.Description, op.CreatedAt.Format("02.01.2006 15:04:05"))
 }
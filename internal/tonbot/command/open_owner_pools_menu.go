@@ -36,7 +36,7 @@ func (c *OpenOwnerPoolsMenu) Execute(ctx context.Context, callback *models.Callb
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		"Роль была успешно поменена. Воспользуйтесь меню 👇",
+		util.T(chatId, "Роль была успешно поменена. Воспользуйтесь меню 👇"),
 		markup); err != nil {
 		log.Error("Failed to send markup: ", err)
 		return
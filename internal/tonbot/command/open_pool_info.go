@@ -46,7 +46,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 
 	splitData := strings.Split(data, ":")
 	if len(splitData) < 3 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -56,7 +56,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
 		log.Error("[OpenPoolInfoCommand.Execute]", err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Ваш аккаунт не активирован, чтобы активировать аккаунт введите команду /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Ваш аккаунт не активирован, чтобы активировать аккаунт введите команду /start")); err != nil {
 			log.Error("[OpenPoolInfoCommand.Execute]", err)
 		}
 		return
@@ -68,7 +68,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Что-то пошло не так, попробуйте снова",
+			util.T(chatId, "❌ Что-то пошло не так, попробуйте снова"),
 		); err != nil {
 			log.Error("[OpenPoolInfoCommand.Execute]", err)
 		}
@@ -81,7 +81,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не смог найти выбранный пул. Возможно он был удален. Выберите другой",
+			util.T(chatId, "❌ Не смог найти выбранный пул. Возможно он был удален. Выберите другой"),
 		); err != nil {
 			log.Error("[OpenPoolInfoCommand.Execute]", err)
 		}
@@ -94,7 +94,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 		return
 	}
 
-	poolInfo := util.PoolInfo(pool, c.ss, jettonData)
+	poolInfo := util.PoolInfo(util.Lang(uint64(chatId)), pool, c.ss, jettonData, c.ps.GetRewardTiers(uint64(poolId)))
 	dataBtn := fmt.Sprintf("%v:%v", buttons.CreateStakeId, poolId)
 	btn := util.CreateDefaultButton(dataBtn, buttons.StakePoolTokensText)
 	var markup *models.InlineKeyboardMarkup
@@ -106,7 +106,7 @@ func (c *OpenPoolInfoCommand) Execute(ctx context.Context, callback *models.Call
 		} else {
 			buttonId = buttons.BackPoolListId
 		}
-		markup = util.GenerateOwnerPoolInlineKeyboard(poolId, buttonId, pool.IsActive, pool.IsCommissionPaid, splitData[2])
+		markup = util.GenerateOwnerPoolInlineKeyboard(util.Lang(uint64(chatId)), poolId, buttonId, pool.IsActive, pool.IsCommissionPaid, pool.InsuranceAsset, splitData[2])
 	} else {
 		markup = util.MenuWithBackButton(buttons.BackPoolListId, buttons.BackPoolList, btn)
 	}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"fmt"
 	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/util"
 
@@ -22,20 +21,24 @@ func (c *OpenSetting) Execute(ctx context.Context, msg *models.Message) {
 	chatId := msg.Chat.ID
 	btn1 := util.CreateDefaultButton(buttons.RoleButtonUserId, buttons.RoleButtonUserText)
 	btn2 := util.CreateDefaultButton(buttons.RoleButtonOwnerTokensId, buttons.RoleButtonOwnerTokensText)
+	btn3 := util.CreateDefaultButton(buttons.NotificationSettingsId, buttons.NotificationSettings)
+	btn4 := util.CreateDefaultButton(buttons.LanguageSettingsId, buttons.LanguageSettings)
 
-	markup := util.CreateInlineMarup(2, btn1, btn2)
+	markup := util.CreateInlineMarup(2, btn1, btn2, btn3, btn4)
 
-	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), c.generateMessageResponse(), markup); err != nil {
+	if _, err := util.SendTextMessageMarkup(c.b, uint64(chatId), c.generateMessageResponse(chatId), markup); err != nil {
 		log.Error(err)
 		return
 	}
 }
 
-func (c *OpenSetting) generateMessageResponse() string {
-	res := `
+func (c *OpenSetting) generateMessageResponse(chatId int64) string {
+	res := util.T(chatId, `
 <b>%v</b>
 
-Тут вы можете выбрать роль. При выборе роли, клавиатура будет изменена в соответствии с ролью`
+Тут вы можете выбрать роль. При выборе роли, клавиатура будет изменена в соответствии с ролью.
 
-	return fmt.Sprintf(res, buttons.Setting)
+В разделе «Уведомления» можно выбрать, о каких событиях бот будет присылать сообщения, в разделе «Язык» - язык бота`)
+
+	return util.T(chatId, res, util.T(chatId, buttons.Setting))
 }
@@ -2,11 +2,14 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
 	"strings"
 	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
@@ -51,7 +54,7 @@ func (c *OpenStakeInfo) Execute(ctx context.Context, callback *models.CallbackQu
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Стейк не найден. Возможно он был удален!",
+			util.T(chatId, "❌ Стейк не найден. Возможно он был удален!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -64,17 +67,31 @@ func (c *OpenStakeInfo) Execute(ctx context.Context, callback *models.CallbackQu
 		return
 	}
 
-	info := c.generateInfo(stake, jettonName, p)
-	btns := make([]models.InlineKeyboardButton, 0, 3)
+	info := c.generateInfo(util.Lang(uint64(chatId)), stake, jettonName, p)
+	btns := make([]models.InlineKeyboardButton, 0, 4)
 
 	buttonId := fmt.Sprintf("%v:%v", c.backBtn, jettonName)
 	backBtn := util.CreateDefaultButton(buttonId, buttons.BackStakesFromGroup)
 
 	if stake.EndDate.After(time.Now()) && stake.IsActive {
-		info += "\n\n<b>При досрочном закрытии</b>:\n- Нет компенсации падения цены\n- Процент за стейкинг не начисляется"
+		if quote, err := c.ss.EarlyExitQuote(stake, p, 0, time.Now()); err == nil {
+			info += util.T(chatId, "\n\n<b>При досрочном закрытии</b>:\n") + util.EarlyExitQuoteText(util.Lang(uint64(chatId)), quote, p.JettonName)
+		} else if errors.Is(err, services.ErrEarlyExitLocked) {
+			info += util.T(chatId, "\n\n<b>Досрочное закрытие доступно с</b>: %v",
+				stake.StartDate.Add(time.Duration(p.EarlyExitMinDays)*24*time.Hour).Format("02 January 2006 15:04:05"),
+			)
+		}
 		idBtn := fmt.Sprintf("%v:%v", buttons.CloseStakeId, stake.Id.Int64)
 		btn := util.CreateDefaultButton(idBtn, buttons.CloseStake)
 		btns = append(btns, btn)
+		if stake.AutoRollover {
+			info += util.T(chatId, "\n\n🔁 По окончании срока депозит с наградой будет автоматически застейкан повторно, если в пуле хватит резерва.")
+		}
+		rolloverBtn := util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.AutoRolloverId, jettonName, stake.Id.Int64),
+			util.T(chatId, buttons.AutoRollover)+onOff(util.Lang(uint64(chatId)), stake.AutoRollover),
+		)
+		btns = append(btns, rolloverBtn)
 	}
 
 	if !stake.IsActive {
@@ -109,18 +126,18 @@ func (c *OpenStakeInfo) Execute(ctx context.Context, callback *models.CallbackQu
 	}
 }
 
-func (c *OpenStakeInfo) generateInfo(stake *appModels.Stake, jettonName string, pool *appModels.Pool) string {
+func (c *OpenStakeInfo) generateInfo(lang string, stake *appModels.Stake, jettonName string, pool *appModels.Pool) string {
 	currentPrice := util.GetCurrentPriceJettonAddr(pool.JettonMaster)
-	status := "⌛ Активен"
+	status := i18n.T(lang, "⌛ Активен")
 	if !stake.IsActive {
 		if !stake.IsCommissionPaid && !stake.IsRewardPaid {
-			status = "✅ Выплачен"
+			status = i18n.T(lang, "✅ Выплачен")
 		} else {
-			status = "✅ Завершен"
+			status = i18n.T(lang, "✅ Завершен")
 		}
 	}
 
-	text := `
+	text := i18n.T(lang, `
 	<b> 📦 Стейк с токеном [%v]</b>
 	<b>💰 Ставка:</b> +%v%% за %v %v
 	<b>🛡 Компенсация:</b> При снижении цены %v более чем на %v%%
@@ -132,17 +149,18 @@ func (c *OpenStakeInfo) generateInfo(stake *appModels.Stake, jettonName string,
 	<b>📅Финиш:</b> %v
 	<b>⏳ Статус:</b> %v
 	<b>🎁 Доход</b> +%v %v
-	`
+	`)
 	profit := stake.Balance - stake.Amount
+	reward, period := util.StakeTerms(stake, pool)
 	//leftDay := stake.StartDate.Add(time.Duration(pool.Period) * 24 * time.Hour).Sub(time.Now())
 	procientPriceEdit := int(util.CalculateProcientEditPrice(currentPrice, stake.DepositCreationPrice))
 	timeFormat := "02 January 2006 15:04:05"
 	formatText := fmt.Sprintf(
 		text,
 		jettonName,
-		util.RemoveZeroFloat(pool.Reward*float64(pool.Period)),
-		pool.Period,
-		util.SuffixDay(int(pool.Period)),
+		util.RemoveZeroFloat(reward*float64(period)),
+		period,
+		util.SuffixDay(lang, int(period)),
 		jettonName,
 		pool.InsuranceCoating,
 		util.RemoveZeroFloat(stake.Amount),
@@ -150,47 +168,104 @@ func (c *OpenStakeInfo) generateInfo(stake *appModels.Stake, jettonName string,
 		util.RemoveZeroFloat(currentPrice),
 		procientPriceEdit,
 		stake.StartDate.Format(timeFormat),
-		stake.StartDate.Add(time.Duration(pool.Period)*24*time.Hour).Format(timeFormat),
+		stake.StartDate.Add(time.Duration(period)*24*time.Hour).Format(timeFormat),
 		status,
 		util.RemoveZeroFloat(profit),
 		pool.JettonName,
 	)
 
+	if stake.IsActive {
+		formatText += coverageInfo(lang, stake, pool, currentPrice)
+	}
+
 	if !stake.IsActive {
-		formatText += fmt.Sprintf(
-			"\n\n<b>📉 Цена на момент закрытия стейка</b>: %v$ (%v%%)",
+		formatText += i18n.T(lang, "\n\n<b>📉 Цена на момент закрытия стейка</b>: %v$ (%v%%)",
 			util.RemoveZeroFloat(stake.JettonPriceClosed),
 			int(util.CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice)),
 		)
 		if !stake.IsInsurancePaid && !stake.IsRewardPaid {
 			paid := 0.
 			precientEdit := util.CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice)
-			if precientEdit < float64(pool.InsuranceCoating)*-1 {
+			if precientEdit < float64(pool.InsuranceCoating)*-1 && util.HasInsuranceReserve(pool) {
+				assetName := util.InsuranceAssetName(lang, pool.InsuranceAsset)
+				price := stake.InsuranceAssetPrice
+				if price <= 0 {
+					price = util.GetInsuranceAssetPrice(pool.InsuranceAsset)
+				}
+				settled := *stake
+				settled.InsuranceAssetPrice = price
+				insurance := util.CalculateInsuranceInAsset(&settled)
+				formatText += i18n.T(lang, "\n<b>💥 Компенсация за падение на %.0f%%</b>: %v %v (курс %v $)",
+					math.Ceil(precientEdit),
+					util.RemoveZeroFloat(insurance),
+					assetName,
+					util.RemoveZeroFloat(price),
+				)
+				formatText += i18n.T(lang, "\n<b>💎 К выплате</b>: %v %v + %v %v",
+					util.RemoveZeroFloat(stake.Balance),
+					pool.JettonName,
+					util.RemoveZeroFloat(insurance),
+					assetName,
+				)
+				return formatText
+			} else if precientEdit < float64(pool.InsuranceCoating)*-1 {
 				insurance := util.CalculateInsurance(pool, stake)
 				paid += insurance + stake.Balance
-				formatText += fmt.Sprintf(
-					"\n<b>💥 Компенсация за падение на %.0f%%</b>: %v %v",
+				formatText += i18n.T(lang, "\n<b>💥 Компенсация за падение на %.0f%%</b>: %v %v",
 					math.Ceil(precientEdit),
 					util.RemoveZeroFloat(insurance),
 					pool.JettonName,
 				)
 			} else {
 				paid += stake.Balance
-				formatText += fmt.Sprintf(
-					"\n<b>💥 Выплата с процентами(%.0f%%)</b>: %v %v",
+				formatText += i18n.T(lang, "\n<b>💥 Выплата с процентами(%.0f%%)</b>: %v %v",
 					math.Ceil(precientEdit),
 					util.RemoveZeroFloat(paid),
 					pool.JettonName,
 				)
 			}
-			formatText += fmt.Sprintf(
-				"\n<b>💎 К выплате</b>: %v %v", util.RemoveZeroFloat(paid), pool.JettonName)
+			formatText += i18n.T(lang, "\n<b>💎 К выплате</b>: %v %v", util.RemoveZeroFloat(paid), pool.JettonName)
 		}
 	}
 
 	return formatText
 }
 
+// coverageInfo состояние страховки по текущей цене для активного стейка
+func coverageInfo(lang string, stake *appModels.Stake, pool *appModels.Pool, currentPrice float64) string {
+	if currentPrice <= 0 || stake.DepositCreationPrice <= 0 {
+		return i18n.T(lang, "\n\n<b>🛡 Текущее покрытие</b>: цена токена сейчас недоступна")
+	}
+
+	change := util.CalculateProcientEditPrice(currentPrice, stake.DepositCreationPrice)
+	switch util.PriceAlertLevel(change, pool.InsuranceCoating, config.LoadPriceWatchConfig().NearMargin) {
+	case appModels.PRICE_ALERT_TRIGGERED:
+		settled := *stake
+		settled.JettonPriceClosed = currentPrice
+		insurance := util.CalculateInsurance(pool, &settled)
+		assetName := pool.JettonName
+		if util.HasInsuranceReserve(pool) {
+			settled.InsuranceAssetPrice = util.GetInsuranceAssetPrice(pool.InsuranceAsset)
+			insurance = util.CalculateInsuranceInAsset(&settled)
+			assetName = util.InsuranceAssetName(lang, pool.InsuranceAsset)
+		}
+		return i18n.T(lang, "\n\n<b>🛡 Текущее покрытие</b>: порог страховки пройден (падение %.1f%% при пороге %v%%).\nКомпенсация по текущей цене: %v %v. Итог считается по цене на момент окончания стейка.",
+			math.Abs(change),
+			pool.InsuranceCoating,
+			util.RemoveZeroFloat(insurance),
+			assetName,
+		)
+	case appModels.PRICE_ALERT_NEAR:
+		return i18n.T(lang, "\n\n<b>🛡 Текущее покрытие</b>: ⚠️ цена близка к порогу страховки (падение %.1f%%, порог %v%%)",
+			math.Abs(change),
+			pool.InsuranceCoating,
+		)
+	}
+	return i18n.T(lang, "\n\n<b>🛡 Текущее покрытие</b>: ✅ страховка не срабатывает, до порога %.1f%%",
+		change+float64(pool.InsuranceCoating),
+	)
+}
+
 func (c *OpenStakeInfo) getDataFromCallback(data string) (jettonName string, stakeId uint64, err error) {
 	splitData := strings.Split(data, ":")
 	jettonName = splitData[1]
@@ -25,7 +25,7 @@ func (c *OpenUserMenuCommand) Execute(ctx context.Context, callback *models.Call
 		message = callback.Message.Message
 		break
 	case models.MaybeInaccessibleMessageTypeInaccessibleMessage:
-		_, err := util.SendTextMessage(c.bt, uint64(callback.From.ID), "❌ Я не могу обработать это сообщение")
+		_, err := util.SendTextMessage(c.bt, uint64(callback.From.ID), util.T(callback.From.ID, "❌ Я не могу обработать это сообщение"))
 		if err != nil {
 			return
 		}
@@ -53,7 +53,7 @@ func (c *OpenUserMenuCommand) Execute(ctx context.Context, callback *models.Call
 	if _, err := util.SendTextMessageMarkup(
 		c.bt,
 		uint64(chatId),
-		"Вы открыли меню пользователя 👇. Сменить меню, вы можете в настройках",
+		util.T(chatId, "Вы открыли меню пользователя 👇. Сменить меню, вы можете в настройках"),
 		keys); err != nil {
 		log.Error("Failed to send message open user menu: ", err)
 		return
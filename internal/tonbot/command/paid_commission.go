@@ -54,7 +54,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 	chatId := msg.Chat.ID
 	dataSplit := strings.Split(callback.Data, ":")
 	if len(dataSplit) < 2 {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -62,7 +62,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 
 	poolId, err := strconv.ParseInt(dataSplit[1], 10, 64)
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -70,7 +70,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Аккаунт не активирован. Введите команду /start"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -79,7 +79,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 	w, err := c.ws.GetByUserId(uint64(user.Id.Int64))
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ У вас не привязан кошелек!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ У вас не привязан кошелек!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -88,14 +88,14 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 	pool, err := c.ps.GetId(uint64(poolId))
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Пул не найден! Возможно он был удален!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден! Возможно он был удален!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
 	if pool.IsCommissionPaid {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Комиссия за этот пул уже оплачена!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Комиссия за этот пул уже оплачена!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -105,7 +105,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 
 	jettonAddr, err := c.aws.TokenWalletAddress(jettonMasterAdmin, address.MustParseAddr(w.Addr))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -114,6 +114,10 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 	s, err := c.tcs.LoadSession(fmt.Sprint(chatId))
 	if err != nil {
 		log.Error(err)
+		if !services.IsSessionLost(err) {
+			util.SendSessionLost(c.b, uint64(chatId), err, "")
+			return
+		}
 		repeatBtn := util.CreateDefaultButton(buttons.RepeatCreatePoolId, buttons.Repeat)
 		markup := util.CreateInlineMarup(1, repeatBtn)
 		if err := util.RequestRepeatTonConnect(c.b, chatId, markup, c.tcs); err != nil {
@@ -125,7 +129,7 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 	jsonData, err := json.Marshal(pool)
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так! Повторите попытку!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так! Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -138,13 +142,13 @@ func (c *PaidCommission) Execute(ctx context.Context, callback *models.CallbackQ
 		Payload:       string(jsonData),
 	}
 
-	btns := util.GenerateButtonWallets(w, c.tcs, true)
+	btns := util.GenerateButtonWallets(util.Lang(uint64(chatId)), w, c.tcs, true)
 
 	markup := util.CreateInlineMarup(1, btns...)
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		fmt.Sprintf(messages.PaidCommission,
+		util.T(chatId, messages.PaidCommission,
 			os.Getenv("COMMISSION_AMOUNT"),
 			os.Getenv("JETTON_NAME_COIN"),
 		),
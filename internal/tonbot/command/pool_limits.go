@@ -0,0 +1,360 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/callbacksuf"
+	"tonclient/internal/tonbot/userstate"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var currentPoolLimitsPoolId = make(map[int64]uint64)
+
+type PoolLimits[T CommandType] struct {
+	b  *bot.Bot
+	ps *services.PoolService
+	us *services.UserService
+	ss *services.StakeService
+}
+
+func NewPoolLimitsCommand[T CommandType](b *bot.Bot, ps *services.PoolService, us *services.UserService,
+	ss *services.StakeService) *PoolLimits[T] {
+	return &PoolLimits[T]{
+		b:  b,
+		ps: ps,
+		us: us,
+		ss: ss,
+	}
+}
+
+func (c *PoolLimits[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.executeMessage(v)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		c.executeCallback(ctx, v)
+		return
+	}
+}
+
+func (c *PoolLimits[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	splitData := strings.Split(callback.Data, ":")
+	if len(splitData) < 3 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	id, err := strconv.ParseUint(splitData[1], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу обработать данную кнопку")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if splitData[0] == buttons.DeleteWhitelistId {
+		entry, err := c.ps.GetWhitelistById(id)
+		if err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Запись не найдена. Возможно она уже удалена!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		pool, ok := c.getOwnerPool(chatId, entry.PoolId)
+		if !ok {
+			return
+		}
+		if err := c.ps.DeleteWhitelist(id); err != nil {
+			log.Error(err)
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Запись не была удалена. Повторите попытку позже!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		c.render(ctx, chatId, msg.ID, pool, splitData[2])
+		return
+	}
+
+	pool, ok := c.getOwnerPool(chatId, id)
+	if !ok {
+		return
+	}
+
+	switch splitData[0] {
+	case buttons.PoolLimitsId:
+		c.render(ctx, chatId, msg.ID, pool, splitData[2])
+		return
+	case buttons.PoolMaxTotalId:
+		c.askValue(chatId, id, userstate.EnterPoolMaxTotal,
+			util.T(chatId, "Введите максимальную сумму всех стейков в пуле в %v (0 - без лимита):", pool.JettonName))
+		return
+	case buttons.PoolMaxUserId:
+		c.askValue(chatId, id, userstate.EnterPoolMaxUser,
+			util.T(chatId, "Введите максимальную сумму стейков одного участника в %v (0 - без лимита):", pool.JettonName))
+		return
+	case buttons.PoolMaxStakersId:
+		c.askValue(chatId, id, userstate.EnterPoolMaxStakers,
+			util.T(chatId, "Введите максимальное число участников пула (0 - без лимита):"))
+		return
+	case buttons.AddWhitelistId:
+		c.askValue(chatId, id, userstate.EnterWhitelistEntry,
+			util.T(chatId, "Отправьте адрес кошелька, Telegram ID или @username участника. Можно несколько - каждый с новой строки."))
+		return
+	case buttons.PoolWhitelistId:
+		pool.IsWhitelist = !pool.IsWhitelist
+	default:
+		return
+	}
+
+	if err := c.ps.Update(pool); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Настройки не были сохранены. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	c.render(ctx, chatId, msg.ID, pool, splitData[2])
+}
+
+func (c *PoolLimits[T]) executeMessage(msg *models.Message) {
+	chatId := msg.Chat.ID
+	poolId, ok := currentPoolLimitsPoolId[chatId]
+	if !ok || poolId == 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
+		return
+	}
+
+	pool, ok := c.getOwnerPool(chatId, poolId)
+	if !ok {
+		return
+	}
+
+	text := strings.TrimSpace(strings.ReplaceAll(msg.Text, ",", "."))
+	successText := util.T(chatId, "✅ Лимиты сохранены!\n")
+	switch userstate.CurrentState[chatId] {
+	case userstate.EnterPoolMaxTotal, userstate.EnterPoolMaxUser:
+		num, err := strconv.ParseFloat(text, 64)
+		if err != nil || num < 0 {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Введите число не меньше 0! Например: 100000")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		if userstate.CurrentState[chatId] == userstate.EnterPoolMaxTotal {
+			pool.MaxTotalStake = num
+		} else {
+			pool.MaxUserStake = num
+		}
+	case userstate.EnterPoolMaxStakers:
+		num, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Введите целое число не меньше 0! Например: 100")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		pool.MaxStakers = uint(num)
+	case userstate.EnterWhitelistEntry:
+		added := 0
+		for _, line := range strings.Split(msg.Text, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			entry, err := services.ParseWhitelistEntry(line)
+			if err != nil {
+				if _, err := util.SendTextMessage(
+					c.b,
+					uint64(chatId),
+					util.T(chatId, "❌ Не удалось распознать <code>%v</code>. Укажите адрес кошелька, Telegram ID или @username", strings.TrimSpace(line)),
+				); err != nil {
+					log.Error(err)
+				}
+				continue
+			}
+			entry.PoolId = poolId
+			if err := c.ps.AddWhitelist(entry); err != nil {
+				log.Error(err)
+				continue
+			}
+			added++
+		}
+		if added == 0 {
+			return
+		}
+		successText = util.T(chatId, "✅ Добавлено в белый список: %v\n", added)
+	default:
+		return
+	}
+
+	if userstate.CurrentState[chatId] != userstate.EnterWhitelistEntry {
+		if err := c.ps.Update(pool); err != nil {
+			log.Error(err)
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Настройки не были сохранены. Повторите попытку позже!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+	}
+
+	delete(currentPoolLimitsPoolId, chatId)
+	userstate.ResetState(chatId)
+
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		successText+c.text(util.Lang(uint64(chatId)), pool),
+		c.markup(util.Lang(uint64(chatId)), pool, callbacksuf.My),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *PoolLimits[T]) askValue(chatId int64, poolId uint64, state int, text string) {
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
+		log.Error(err)
+		return
+	}
+	currentPoolLimitsPoolId[chatId] = poolId
+	userstate.CurrentState[chatId] = state
+}
+
+func (c *PoolLimits[T]) getOwnerPool(chatId int64, poolId uint64) (*appModels.Pool, bool) {
+	pool, err := c.ps.GetId(poolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	if uint64(u.Id.Int64) != pool.OwnerId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Вы не владелец этого пула!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	return pool, true
+}
+
+func (c *PoolLimits[T]) render(ctx context.Context, chatId int64, messageId int, pool *appModels.Pool, suf string) {
+	if err := util.EditTextMessageMarkup(
+		ctx,
+		c.b,
+		uint64(chatId),
+		messageId,
+		c.text(util.Lang(uint64(chatId)), pool),
+		c.markup(util.Lang(uint64(chatId)), pool, suf),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *PoolLimits[T]) text(lang string, pool *appModels.Pool) string {
+	var total float64
+	var stakers int
+	if totals, err := c.ss.GetPoolTotals(uint64(pool.Id.Int64), 0); err == nil {
+		total = totals.Total
+		stakers = totals.Stakers
+	}
+
+	limits := util.PoolLimits(lang, pool, total, stakers)
+	if limits == "" {
+		limits = i18n.T(lang, " •	Лимиты не установлены\n")
+	}
+
+	whitelist := ""
+	if pool.IsWhitelist {
+		whitelist = i18n.T(lang, "\nНажмите на запись, чтобы удалить ее из белого списка.")
+	}
+
+	return i18n.T(
+		lang,
+		"<b>🚧 Лимиты пула %v</b>\n\n%v\nЛимиты проверяются при открытии стейка и при подтверждении депозита, "+
+			"уже открытые стейки они не затрагивают.%v",
+		pool.JettonName,
+		limits,
+		whitelist,
+	)
+}
+
+func (c *PoolLimits[T]) markup(lang string, pool *appModels.Pool, suf string) *models.InlineKeyboardMarkup {
+	poolId := pool.Id.Int64
+	btns := make([]models.InlineKeyboardButton, 0, 7)
+	btns = append(
+		btns,
+		util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.PoolMaxTotalId, poolId, suf),
+			i18n.T(lang, buttons.PoolMaxTotal)+limitValue(lang, pool.MaxTotalStake),
+		),
+		util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.PoolMaxUserId, poolId, suf),
+			i18n.T(lang, buttons.PoolMaxUser)+limitValue(lang, pool.MaxUserStake),
+		),
+		util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.PoolMaxStakersId, poolId, suf),
+			i18n.T(lang, buttons.PoolMaxStakers)+limitValue(lang, float64(pool.MaxStakers)),
+		),
+		util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.PoolWhitelistId, poolId, suf),
+			i18n.T(lang, buttons.PoolWhitelist)+onOff(lang, pool.IsWhitelist),
+		),
+	)
+
+	if pool.IsWhitelist {
+		for _, e := range c.ps.GetWhitelist(uint64(poolId)) {
+			btns = append(btns, util.CreateDefaultButton(
+				fmt.Sprintf("%v:%v:%v", buttons.DeleteWhitelistId, e.Id.Int64, suf),
+				fmt.Sprintf("❌ %v", e.Value),
+			))
+		}
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.AddWhitelistId, poolId, suf),
+			buttons.AddWhitelist,
+		))
+	}
+
+	btns = append(btns, util.CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.PoolDataButton, poolId, suf),
+		buttons.BackPoolInfo,
+	))
+
+	return util.CreateInlineMarup(1, btns...)
+}
+
+func limitValue(lang string, v float64) string {
+	if v <= 0 {
+		return i18n.T(lang, buttons.NoLimit)
+	}
+	return util.RemoveZeroFloat(v)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
@@ -38,7 +39,7 @@ func (c *ProfileCommand) Execute(ctx context.Context, msg *models.Message) {
 	user, err := c.us.GetByTelegramChatId(uint64(chatId))
 	if err != nil {
 		if err.Error() == "user not found" {
-			if _, er := util.SendTextMessage(c.b, uint64(chatId), "❌ Профиль не найден. Введите команду /start, чтобы создать профиль!"); er != nil {
+			if _, er := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Профиль не найден. Введите команду /start, чтобы создать профиль!")); er != nil {
 				log.Error("Failed send message", er)
 			}
 			return
@@ -51,7 +52,7 @@ func (c *ProfileCommand) Execute(ctx context.Context, msg *models.Message) {
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Вы не приняли пользовательское соглашение!",
+			util.T(chatId, "❌ Вы не приняли пользовательское соглашение!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -63,16 +64,18 @@ func (c *ProfileCommand) Execute(ctx context.Context, msg *models.Message) {
 	w, err := c.ws.GetByUserId(uint64(user.Id.Int64))
 	if err != nil {
 		log.Error(err)
-		tonAddr = "Не указан"
+		tonAddr = util.T(chatId, "Не указан")
 	} else {
 		tonAddr = w.Addr
 	}
 
-	text := c.generateMessage(user, tonAddr)
+	text := c.generateMessage(util.Lang(uint64(chatId)), user, tonAddr)
+	walletsBtn := util.CreateDefaultButton(buttons.MyWalletsId, buttons.MyWallets)
 	setWalAddrBtn := util.CreateDefaultButton(buttons.SetNumberWalletId, buttons.SetNumberWallet)
 	conTonW := util.CreateDefaultButton(buttons.LinkTonConnectId, buttons.LinkTonConnect)
+	disconTonW := util.CreateDefaultButton(buttons.DisconnectTonConnectId, buttons.DisconnectTonConnect)
 
-	markup := util.MenuWithBackButton(buttons.DefCloseId, buttons.DefCloseText, setWalAddrBtn, conTonW)
+	markup := util.MenuWithBackButton(buttons.DefCloseId, buttons.DefCloseText, walletsBtn, setWalAddrBtn, conTonW, disconTonW)
 
 	if _, err = util.SendTextMessageMarkup(c.b, uint64(chatId), text, markup); err != nil {
 		log.Error("Failed send message", err)
@@ -80,14 +83,14 @@ func (c *ProfileCommand) Execute(ctx context.Context, msg *models.Message) {
 	}
 }
 
-func (c *ProfileCommand) generateMessage(u *appModels.User, tonAddr string) string {
+func (c *ProfileCommand) generateMessage(lang string, u *appModels.User, tonAddr string) string {
 
-	text := `
+	text := i18n.T(lang, `
 <b>👤 Ваш профиль NESTRAH</b>
 
-<b>TON-адрес</b>: %v
+<b>Кошелек для выплат</b>: %v
 <b>Дата регистрации</b>: %v
-`
+`)
 	res := fmt.Sprintf(
 		text,
 		tonAddr,
@@ -0,0 +1,315 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/callbacksuf"
+	"tonclient/internal/tonbot/userstate"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var currentRewardTierPoolId = make(map[int64]uint64)
+
+type RewardTiers[T CommandType] struct {
+	b  *bot.Bot
+	ps *services.PoolService
+	us *services.UserService
+}
+
+func NewRewardTiersCommand[T CommandType](b *bot.Bot, ps *services.PoolService, us *services.UserService) *RewardTiers[T] {
+	return &RewardTiers[T]{
+		b:  b,
+		ps: ps,
+		us: us,
+	}
+}
+
+func (c *RewardTiers[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.executeMessage(v)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		c.executeCallback(ctx, v)
+		return
+	}
+}
+
+func (c *RewardTiers[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	splitData := strings.Split(callback.Data, ":")
+	if len(splitData) < 3 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	id, err := strconv.ParseUint(splitData[1], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу обработать данную кнопку")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	switch splitData[0] {
+	case buttons.RewardTiersId:
+		pool, ok := c.getOwnerPool(chatId, id)
+		if !ok {
+			return
+		}
+		c.render(ctx, chatId, msg.ID, pool, splitData[2])
+	case buttons.AddRewardTierId:
+		if _, ok := c.getOwnerPool(chatId, id); !ok {
+			return
+		}
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(
+				chatId,
+				"Введите тариф в формате:\n<code>срок ставка [мин. сумма] [дата начала] [комиссия]</code>\n\n"+
+					"срок - в днях, ставка - % в день, дата начала - ДД.ММ.ГГГГ.\n"+
+					"комиссия за стейк - <code>сумма@валюта</code> или <code>процент%@валюта</code>, "+
+					"валюта: ton, jetton (токен платформы) или stake (удерживается из стейка).\n"+
+					"Например: <code>30 1.5 1000 01.12.2026 0.5%@stake</code>\n\n"+
+					"Ставка фиксируется при открытии стейка, поэтому новые тарифы не меняют уже открытые стейки.",
+			),
+		); err != nil {
+			log.Error(err)
+			return
+		}
+		currentRewardTierPoolId[chatId] = id
+		userstate.CurrentState[chatId] = userstate.EnterRewardTier
+	case buttons.DeleteRewardTierId:
+		tier, err := c.ps.GetRewardTierById(id)
+		if err != nil {
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Тариф не найден. Возможно он уже удален!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		pool, ok := c.getOwnerPool(chatId, tier.PoolId)
+		if !ok {
+			return
+		}
+		if err := c.ps.DeleteRewardTier(id); err != nil {
+			log.Error(err)
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Тариф не был удален. Повторите попытку позже!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		c.render(ctx, chatId, msg.ID, pool, splitData[2])
+	}
+}
+
+func (c *RewardTiers[T]) executeMessage(msg *models.Message) {
+	chatId := msg.Chat.ID
+	poolId, ok := currentRewardTierPoolId[chatId]
+	if !ok || poolId == 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
+		return
+	}
+
+	pool, ok := c.getOwnerPool(chatId, poolId)
+	if !ok {
+		return
+	}
+
+	tier, err := parseRewardTier(msg.Text)
+	if err != nil {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Неверный формат. Пример: <code>30 1.5 1000 01.12.2026 2@ton</code> (мин. сумма, дата и комиссия не обязательны)"),
+		); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	tier.PoolId = poolId
+
+	if err := c.ps.AddRewardTier(tier); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Тариф не был сохранен. Срок и ставка должны быть больше нуля!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	delete(currentRewardTierPoolId, chatId)
+	userstate.ResetState(chatId)
+
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		util.T(chatId, "✅ Тариф добавлен!\n")+c.text(util.Lang(uint64(chatId)), pool),
+		c.markup(util.Lang(uint64(chatId)), pool, callbacksuf.My),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *RewardTiers[T]) getOwnerPool(chatId int64, poolId uint64) (*appModels.Pool, bool) {
+	pool, err := c.ps.GetId(poolId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	if uint64(u.Id.Int64) != pool.OwnerId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Вы не владелец этого пула!")); err != nil {
+			log.Error(err)
+		}
+		return nil, false
+	}
+
+	return pool, true
+}
+
+func (c *RewardTiers[T]) render(ctx context.Context, chatId int64, messageId int, pool *appModels.Pool, suf string) {
+	if err := util.EditTextMessageMarkup(
+		ctx,
+		c.b,
+		uint64(chatId),
+		messageId,
+		c.text(util.Lang(uint64(chatId)), pool),
+		c.markup(util.Lang(uint64(chatId)), pool, suf),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *RewardTiers[T]) text(lang string, pool *appModels.Pool) string {
+	return i18n.T(
+		lang,
+		"<b>📊 Тарифы пула %v</b>\n\n%v\nБазовые срок и ставка пула действуют, если не подошел ни один тариф. "+
+			"Из подходящих тарифов применяется самый поздний по дате начала, при равной дате - с большей мин. суммой.",
+		pool.JettonName,
+		util.RewardTiersInfo(lang, pool, c.ps.GetRewardTiers(uint64(pool.Id.Int64)), 0),
+	)
+}
+
+func (c *RewardTiers[T]) markup(lang string, pool *appModels.Pool, suf string) *models.InlineKeyboardMarkup {
+	tiers := c.ps.GetRewardTiers(uint64(pool.Id.Int64))
+	btns := make([]models.InlineKeyboardButton, 0, len(tiers)+2)
+	for _, t := range tiers {
+		text := fmt.Sprintf("❌ %v %v, %v%%", t.Period, util.SuffixDay(lang, int(t.Period)), util.RemoveZeroFloat(t.Reward))
+		if t.MinAmount > 0 {
+			text += i18n.T(lang, ", от %v", util.RemoveZeroFloat(t.MinAmount))
+		}
+		if t.StartsAt.Valid {
+			text += i18n.T(lang, ", с %v", t.StartsAt.Time.Format("02.01.2006"))
+		}
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v:%v", buttons.DeleteRewardTierId, t.Id.Int64, suf),
+			text,
+		))
+	}
+	btns = append(
+		btns,
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.AddRewardTierId, pool.Id.Int64, suf), buttons.AddRewardTier),
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.PoolDataButton, pool.Id.Int64, suf), buttons.BackPoolInfo),
+	)
+
+	return util.CreateInlineMarup(1, btns...)
+}
+
+// parseRewardTier разбирает строку "срок ставка [мин. сумма] [дата начала] [комиссия]"
+func parseRewardTier(text string) (*appModels.RewardTier, error) {
+	fields := strings.Fields(strings.ReplaceAll(text, ",", "."))
+	if len(fields) < 2 || len(fields) > 5 {
+		return nil, fmt.Errorf("invalid tier format: %v", text)
+	}
+
+	period, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	reward, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	tier := &appModels.RewardTier{
+		Period: uint(period),
+		Reward: reward,
+	}
+
+	for _, f := range fields[2:] {
+		if startsAt, err := time.ParseInLocation("02.01.2006", f, time.Local); err == nil {
+			tier.StartsAt = sql.NullTime{Time: startsAt, Valid: true}
+			continue
+		}
+		if strings.ContainsAny(f, "%@") {
+			if err := parseTierCommission(f, tier); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		minAmount, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		tier.MinAmount = minAmount
+	}
+
+	return tier, nil
+}
+
+// parseTierCommission разбирает комиссию тарифа "сумма[%][@валюта]": 2@ton, 0.5%@stake, 3%, @jetton
+func parseTierCommission(text string, tier *appModels.RewardTier) error {
+	value, asset, _ := strings.Cut(strings.ToLower(text), "@")
+	switch asset {
+	case "", appModels.COMMISSION_ASSET_JETTON, appModels.COMMISSION_ASSET_TON, appModels.COMMISSION_ASSET_STAKE:
+		tier.CommissionAsset = asset
+	default:
+		return fmt.Errorf("unknown commission asset: %v", asset)
+	}
+	if value == "" {
+		return nil
+	}
+
+	tier.CommissionType = appModels.COMMISSION_TYPE_FIXED
+	if v, ok := strings.CutSuffix(value, "%"); ok {
+		tier.CommissionType = appModels.COMMISSION_TYPE_PERCENT
+		value = v
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return fmt.Errorf("invalid commission: %v", text)
+	}
+	tier.CommissionValue = amount
+	return nil
+}
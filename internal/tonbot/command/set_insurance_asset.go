@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/callbacksuf"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type SetInsuranceAsset struct {
+	b   *bot.Bot
+	ps  *services.PoolService
+	us  *services.UserService
+	ss  *services.StakeService
+	aws *services.AdminWalletService
+}
+
+func NewSetInsuranceAssetCommand(b *bot.Bot, ps *services.PoolService, us *services.UserService,
+	ss *services.StakeService, aws *services.AdminWalletService) *SetInsuranceAsset {
+	return &SetInsuranceAsset{
+		b:   b,
+		ps:  ps,
+		us:  us,
+		ss:  ss,
+		aws: aws,
+	}
+}
+
+func (c *SetInsuranceAsset) Execute(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+	splitData := strings.Split(callback.Data, ":")
+	if len(splitData) < 3 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	poolId, err := strconv.ParseInt(splitData[1], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не верный ID пула!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	pool, err := c.ps.GetId(uint64(poolId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Пул не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if uint64(u.Id.Int64) != pool.OwnerId {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Вы не владелец этого пула!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if c.ss.CountStakesPoolIdAndStatus(uint64(poolId), true) > 0 {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Валюту компенсации нельзя менять, пока в пуле есть активные стейки!"),
+		); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if pool.InsuranceReserve > 0 {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ В страховом резерве есть %v %v. Валюту компенсации можно сменить только при пустом страховом резерве!",
+				util.RemoveZeroFloat(pool.InsuranceReserve),
+				util.InsuranceAssetName(util.Lang(uint64(chatId)), pool.InsuranceAsset),
+			),
+		); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	nextAsset := nextInsuranceAsset(pool.InsuranceAsset)
+	if err := c.ps.SetInsuranceAsset(uint64(poolId), nextAsset); err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Валюта компенсации не была изменена. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	pool.InsuranceAsset = nextAsset
+
+	jettonData, err := c.aws.DataJetton(pool.JettonMaster)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	var btnId string
+	if splitData[2] == callbacksuf.My {
+		btnId = buttons.BackMyPoolListId
+	} else {
+		btnId = buttons.BackPoolListId
+	}
+
+	if err := util.EditTextMessageMarkup(
+		ctx,
+		c.b,
+		uint64(chatId),
+		msg.ID,
+		util.PoolInfo(util.Lang(uint64(chatId)), pool, c.ss, jettonData, c.ps.GetRewardTiers(uint64(poolId))),
+		util.GenerateOwnerPoolInlineKeyboard(util.Lang(uint64(chatId)), poolId, btnId, pool.IsActive, pool.IsCommissionPaid, pool.InsuranceAsset, splitData[2]),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func nextInsuranceAsset(asset string) string {
+	switch asset {
+	case appModels.INSURANCE_ASSET_USDT:
+		return appModels.INSURANCE_ASSET_TON
+	case appModels.INSURANCE_ASSET_TON:
+		return appModels.INSURANCE_ASSET_JETTON
+	default:
+		return appModels.INSURANCE_ASSET_USDT
+	}
+}
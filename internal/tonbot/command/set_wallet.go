@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"fmt"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
 	"tonclient/internal/tonbot/userstate"
@@ -60,11 +59,11 @@ func (s *SetWalletCommand[T]) executeCallback(ctx context.Context, callback *mod
 	btnClose := util.CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText)
 	markup := util.CreateInlineMarup(1, btnClose)
 
-	resp := `
+	resp := util.T(chatId, `
 	<b>Привязка кошелька</b>
 
-	Отправьте адрес кошелька. На привязанный адрес будут, отправляться средства и операции.
-	`
+	Отправьте адрес кошелька. Первый привязанный кошелек становится основным: на него будут отправляться выплаты.
+	`)
 
 	if _, err := util.SendTextMessageMarkup(
 		s.b,
@@ -84,7 +83,7 @@ func (s *SetWalletCommand[T]) executeMessage(ctx context.Context, msg *models.Me
 	text := msg.Text
 	state, ok := userstate.CurrentState[chatId]
 	if !ok {
-		if _, err := util.SendTextMessage(s.b, uint64(chatId), "❌ Что-то пошло не так. Выберите повторно операцию."); err != nil {
+		if _, err := util.SendTextMessage(s.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Выберите повторно операцию.")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -107,7 +106,7 @@ func (s *SetWalletCommand[T]) enterAddrWallet(chatId uint64, text string) {
 	if err := s.aws.CheckValidAddr(text); err != nil {
 		btnClose := util.CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText)
 		markup := util.CreateInlineMarup(1, btnClose)
-		if _, err := util.SendTextMessageMarkup(s.b, chatId, "❌ Невалидный адрес кошелька! Повторите попытку!", markup); err != nil {
+		if _, err := util.SendTextMessageMarkup(s.b, chatId, util.T(chatId, "❌ Невалидный адрес кошелька! Повторите попытку!"), markup); err != nil {
 			log.Error(err)
 			return
 		}
@@ -117,10 +116,14 @@ func (s *SetWalletCommand[T]) enterAddrWallet(chatId uint64, text string) {
 	markup := util.CreateInlineMarup(1, closeButton)
 	w, _ := s.ws.FindWalletByAddr(text)
 	if w != nil {
+		resp := util.T(chatId, "❌ Номер кошелька уже привязан к другому аккаунту! Повторите попытку")
+		if user, err := s.us.GetByTelegramChatId(chatId); err == nil && w.UserId == uint64(user.Id.Int64) {
+			resp = util.T(chatId, "❌ Этот кошелек уже добавлен в ваш профиль!")
+		}
 		if _, err := util.SendTextMessageMarkup(
 			s.b,
 			chatId,
-			"❌ Номер кошелька уже привязан к другому аккаунту! Повторите попытку",
+			resp,
 			markup,
 		); err != nil {
 			log.Error(err)
@@ -138,26 +141,22 @@ func (s *SetWalletCommand[T]) connectWallet(chatId uint64, addr string) {
 		return
 	}
 
-	user, err := s.us.GetByTelegramChatId(chatId)
-	if err != nil {
-		log.Error(err)
-		if _, err := util.SendTextMessage(s.b, chatId, "❌ Ваш аккаунт не найден! Напишите команду /start, чтобы активировать аккаунт, затем повторите попытку!"); err != nil {
+	// привязываем только адрес, владение которым подтверждено ton_proof
+	if !services.SameAddress(res.Addr, addr) {
+		if _, err := util.SendTextMessage(
+			s.b,
+			chatId,
+			util.T(chatId, "❌ Подключенный кошелек не совпадает с введенным адресом. Подключите кошелек с этим адресом!"),
+		); err != nil {
 			log.Error(err)
 		}
 		return
 	}
-	currentWall, err := s.ws.GetByUserId(uint64(user.Id.Int64))
-	if err == nil {
-		currentWall.Addr = addr
-		currentWall.Name = res.WalletName
-		err := s.ws.Update(currentWall)
-		if err != nil {
-			if _, er := util.SendTextMessage(s.b, chatId, "❌ Не удалось обновить кошелк. Повторите попытку!"); er != nil {
-				log.Error(err)
-			}
-		}
-		resp := fmt.Sprintf("✅ Кошелек %v, был успешно изменен. Имя кошелька: %v", addr, currentWall.Name)
-		if _, err := util.SendTextMessage(s.b, chatId, resp); err != nil {
+
+	user, err := s.us.GetByTelegramChatId(chatId)
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(s.b, chatId, util.T(chatId, "❌ Ваш аккаунт не найден! Напишите команду /start, чтобы активировать аккаунт, затем повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -166,19 +165,24 @@ func (s *SetWalletCommand[T]) connectWallet(chatId uint64, addr string) {
 	if err != nil {
 		log.Error(err)
 		if err.Error() == "address already exists" {
-			if _, err := util.SendTextMessage(s.b, chatId, "❌ Адрес кошелька привязан к другому аккаунту!"); err != nil {
+			if _, err := util.SendTextMessage(s.b, chatId, util.T(chatId, "❌ Адрес кошелька уже привязан!")); err != nil {
 				log.Error(err)
 				return
 			}
 			return
 		}
-		if _, err := util.SendTextMessage(s.b, chatId, "❌ Что-то пошло не так. Попробуйте повторить попытку!"); err != nil {
+		if _, err := util.SendTextMessage(s.b, chatId, util.T(chatId, "❌ Что-то пошло не так. Попробуйте повторить попытку!")); err != nil {
 			log.Error(err)
 		}
 		return
 	}
 
-	resp := fmt.Sprintf("✅ Кошелек %v, был успешно подключен. Имя кошелька: %v", addr, wall.Name)
+	resp := util.T(chatId, "✅ Кошелек %v, был успешно подключен. Имя кошелька: %v", addr, wall.Name)
+	if wall.IsDefault {
+		resp += util.T(chatId, "\n\nНа этот кошелек будут отправляться выплаты.")
+	} else {
+		resp += util.T(chatId, "\n\nВыплаты по-прежнему идут на основной кошелек. Сменить его можно в разделе <b>%v</b>.", util.T(chatId, buttons.MyWallets))
+	}
 	if _, err := util.SendTextMessage(s.b, chatId, resp); err != nil {
 		log.Error(err)
 	}
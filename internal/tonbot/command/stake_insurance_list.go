@@ -96,7 +96,7 @@ func (c *StakeInsuranceList[T]) executeCallback(ctx context.Context, callback *m
 		fmt.Sprintf("%v:%v", buttons.InsuranceNextPageJettonName, jettonName),
 		fmt.Sprintf("%v:%v", buttons.InsuranceBackPageJettonName, jettonName),
 		buttons.InsuranceCloseGroup,
-		util.GenerateStakeListByGroup(*stakes, jettonName, buttons.InsuranceOpenStakeInfo)...,
+		util.GenerateStakeListByGroup(uint64(chatId), *stakes, jettonName, buttons.InsuranceOpenStakeInfo)...,
 	)
 
 	btns := markup.InlineKeyboard
@@ -108,7 +108,7 @@ func (c *StakeInsuranceList[T]) executeCallback(ctx context.Context, callback *m
 		c.b,
 		uint64(chatId),
 		messageId,
-		fmt.Sprintf("Список стейков по токену %v", jettonName),
+		util.T(chatId, "Список стейков по токену %v", jettonName),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -121,7 +121,7 @@ func (c *StakeInsuranceList[T]) exc(ctx context.Context, chatId int64, messageId
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ваш аккаунт не активировать. Введите команду /start",
+			util.T(chatId, "❌ Ваш аккаунт не активировать. Введите команду /start"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -146,7 +146,7 @@ func (c *StakeInsuranceList[T]) exc(ctx context.Context, chatId int64, messageId
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		"Список стейков в которых вы можете получить выплату:",
+		util.T(chatId, "Список стейков в которых вы можете получить выплату:"),
 		makup); err != nil {
 		log.Error(err)
 	}
@@ -255,7 +255,7 @@ func (c *StakeInsuranceList[T]) generateMarkup(chatId int64, u *appModels.User,
 		buttons.InsuranceNextPageGroup,
 		buttons.InsuranceBackPageGroup,
 		buttons.InsuranceCloseGroup,
-		util.GenerateGroupButtons(groups, buttons.InsuranceOpenGroupId)...,
+		util.GenerateGroupButtons(uint64(chatId), groups, buttons.InsuranceOpenGroupId)...,
 	)
 	return markup
 }
@@ -95,7 +95,7 @@ func (c *StakeProfitList[T]) executeCallback(ctx context.Context, callback *mode
 		fmt.Sprintf("%v:%v", buttons.ProfitNextPageJettonName, jettonName),
 		fmt.Sprintf("%v:%v", buttons.ProfitBackPageJettonName, jettonName),
 		buttons.CloseListStakesGroupId,
-		util.GenerateStakeListByGroup(*stakes, jettonName, buttons.ProfitOpenStakeInfo)...,
+		util.GenerateStakeListByGroup(uint64(chatId), *stakes, jettonName, buttons.ProfitOpenStakeInfo)...,
 	)
 
 	btns := markup.InlineKeyboard
@@ -107,7 +107,7 @@ func (c *StakeProfitList[T]) executeCallback(ctx context.Context, callback *mode
 		c.b,
 		uint64(chatId),
 		messageId,
-		fmt.Sprintf("Список стейков по токену %v", jettonName),
+		util.T(chatId, "Список стейков по токену %v", jettonName),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -120,7 +120,7 @@ func (c *StakeProfitList[T]) exc(ctx context.Context, chatId int64, messageId in
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ваш аккаунт не активировать. Введите команду /start",
+			util.T(chatId, "❌ Ваш аккаунт не активировать. Введите команду /start"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -145,7 +145,7 @@ func (c *StakeProfitList[T]) exc(ctx context.Context, chatId int64, messageId in
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		"Список стейков в которых вы можете получить награду:",
+		util.T(chatId, "Список стейков в которых вы можете получить награду:"),
 		makup); err != nil {
 		log.Error(err)
 	}
@@ -255,7 +255,7 @@ func (c *StakeProfitList[T]) generateMarkup(chatId int64, u *appModels.User, gro
 		buttons.ProfitNextPageGroup,
 		buttons.ProfitBackPageGroup,
 		buttons.ProfitCloseGroup,
-		util.GenerateGroupButtons(groups, buttons.ProfitOpenGroupId)...,
+		util.GenerateGroupButtons(uint64(chatId), groups, buttons.ProfitOpenGroupId)...,
 	)
 	return markup
 }
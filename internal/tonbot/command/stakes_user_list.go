@@ -86,7 +86,7 @@ func (c *StakesUserList[T]) executeCallback(callback *models.CallbackQuery) {
 		fmt.Sprintf("%v:%v", buttons.NextPageStakesFromGroupJettonName, jettonName),
 		fmt.Sprintf("%v:%v", buttons.BackPageStakesFromGroupJettonName, jettonName),
 		buttons.CloseListStakesGroupId,
-		util.GenerateStakeListByGroup(*stakes, jettonName, buttons.OpenStakeInfo)...,
+		util.GenerateStakeListByGroup(uint64(chatId), *stakes, jettonName, buttons.OpenStakeInfo)...,
 	)
 
 	btns := markup.InlineKeyboard
@@ -119,7 +119,7 @@ func (c *StakesUserList[T]) executeMessage(msg *models.Message) {
 	if _, err := util.SendTextMessageMarkup(
 		c.b,
 		uint64(chatId),
-		"Список ваших стейков. Выберите стейк из списка, чтобы посмотреть информацию: ",
+		util.T(chatId, "Список ваших стейков. Выберите стейк из списка, чтобы посмотреть информацию: "),
 		markup); err != nil {
 		log.Error(err)
 		return
@@ -133,7 +133,7 @@ func (c *StakesUserList[T]) getGroupList(chatId int64) (*models.InlineKeyboardMa
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Аккаунт не активирован. Введите команду /start",
+			util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -154,7 +154,7 @@ func (c *StakesUserList[T]) getGroupList(chatId int64) (*models.InlineKeyboardMa
 		buttons.NextListStakesGroupId,
 		buttons.BackListStakesGroupId,
 		buttons.CloseListStakesGroupId,
-		util.GenerateGroupButtons(groups, buttons.OpenGroupId)...,
+		util.GenerateGroupButtons(uint64(chatId), groups, buttons.OpenGroupId)...,
 	)
 
 	return markup, nil
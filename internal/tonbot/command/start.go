@@ -40,7 +40,7 @@ func (c *StartCommand) Execute(ctx context.Context, msg *models.Message) {
 	if _, err := util.SendTextMessage(
 		c.bt,
 		uint64(chatId),
-		generateStartResponse(),
+		util.T(chatId, generateStartResponse()),
 	); err != nil {
 		log.Error(err)
 		return
@@ -57,28 +57,25 @@ func (c *StartCommand) Execute(ctx context.Context, msg *models.Message) {
 				CreatedAt: time.Now(),
 			}
 			if len(text) > 1 {
-				id, err := util.DecodeReferralTelegramCode(text[1])
+				refererId, err := c.referer(text[1])
 				if err != nil {
-					log.Debugln("Failed to decode referral telegram code: ", err)
+					log.Debugln("Failed to apply referral code: ", err)
 					if _, err := util.SendTextMessage(
 						c.bt,
 						uint64(chatId),
-						"❌ Реферальный код не был применен. Возможно он не действителен!"); err != nil {
+						util.T(chatId, "❌ Реферальный код не был применен. Возможно он не действителен!")); err != nil {
 						log.Error(err)
 					}
-					return
-				}
-
-				if chatId != id {
+				} else if refererId != uint64(chatId) {
 					newUser.RefererId = sql.NullInt64{
-						Int64: id,
+						Int64: int64(refererId),
 						Valid: true,
 					}
 				} else {
 					if _, err := util.SendTextMessage(
 						c.bt,
 						uint64(chatId),
-						"Ваш реферальный код не был применен! Нельзя использовать свою же ссылку для получения бонусов!",
+						util.T(chatId, "Ваш реферальный код не был применен! Нельзя использовать свою же ссылку для получения бонусов!"),
 					); err != nil {
 						log.Error(err)
 					}
@@ -88,7 +85,7 @@ func (c *StartCommand) Execute(ctx context.Context, msg *models.Message) {
 			newUser, err := c.us.CreateUser(newUser)
 			if err != nil {
 				log.Error("Failed to create user: ", err)
-				if _, err := util.SendTextMessage(c.bt, uint64(chatId), "Ошибка при создании профиля. Введите команду /start, чтобы попробовать снова!"); err != nil {
+				if _, err := util.SendTextMessage(c.bt, uint64(chatId), util.T(chatId, "Ошибка при создании профиля. Введите команду /start, чтобы попробовать снова!")); err != nil {
 					log.Error(err)
 					return
 				}
@@ -108,13 +105,26 @@ func (c *StartCommand) Execute(ctx context.Context, msg *models.Message) {
 	}
 }
 
+// referer telegram id владельца реферального кода
+func (c *StartCommand) referer(code string) (uint64, error) {
+	u, err := c.us.GetByReferralCode(code)
+	if err != nil {
+		return 0, err
+	}
+	tg, err := c.ts.GetByUserId(uint64(u.Id.Int64))
+	if err != nil {
+		return 0, err
+	}
+	return tg.TelegramId, nil
+}
+
 func (c *StartCommand) createTelegram(user *appModel.User, chatId int64, msg *models.Message) error {
 	if !user.Id.Valid {
 		log.Error("userId invalid")
 		if _, err := util.SendTextMessage(
 			c.bt,
 			uint64(chatId),
-			"❌ Ошибка при создании профиля, попробуйте повторить попытку введя команду: /start"); err != nil {
+			util.T(chatId, "❌ Ошибка при создании профиля, попробуйте повторить попытку введя команду: /start")); err != nil {
 			log.Error(err)
 			return err
 		}
@@ -123,12 +133,16 @@ func (c *StartCommand) createTelegram(user *appModel.User, chatId int64, msg *mo
 	}
 
 	userid := user.Id.Int64
-	if _, err := c.ts.CreateTelegram(uint64(userid), msg.Chat.Username, uint64(chatId)); err != nil {
+	var languageCode string
+	if msg.From != nil {
+		languageCode = msg.From.LanguageCode
+	}
+	if _, err := c.ts.CreateTelegram(uint64(userid), msg.Chat.Username, uint64(chatId), languageCode); err != nil {
 		log.Error(err)
 		if _, err := util.SendTextMessage(
 			c.bt,
 			uint64(chatId),
-			"❌ Ошибка при создании профиля, попробуйте повторить попытку введя команду: /start"); err != nil {
+			util.T(chatId, "❌ Ошибка при создании профиля, попробуйте повторить попытку введя команду: /start")); err != nil {
 			log.Error(err)
 			return err
 		}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -23,6 +24,7 @@ type TakeInsuranceFromStake struct {
 	ops *services.OperationService
 	ws  *services.WalletTonService
 	aws *services.AdminWalletService
+	as  *services.AdminService
 }
 
 func NewTakeInsuranceFromStake(
@@ -34,6 +36,7 @@ func NewTakeInsuranceFromStake(
 	ops *services.OperationService,
 	ws *services.WalletTonService,
 	aws *services.AdminWalletService,
+	as *services.AdminService,
 ) *TakeInsuranceFromStake {
 	return &TakeInsuranceFromStake{
 		b:   b,
@@ -44,6 +47,7 @@ func NewTakeInsuranceFromStake(
 		ops: ops,
 		ws:  ws,
 		aws: aws,
+		as:  as,
 	}
 }
 
@@ -71,7 +75,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Стейк не найден. Возможно он был удален.",
+			util.T(chatId, "❌ Стейк не найден. Возможно он был удален."),
 		); err != nil {
 			log.Error(err)
 		}
@@ -82,7 +86,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Дождитесь закрытия стейка!",
+			util.T(chatId, "❌ Дождитесь закрытия стейка!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -93,7 +97,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Возмещение или вознаграждение уже были выплачены!",
+			util.T(chatId, "❌ Возмещение или вознаграждение уже были выплачены!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -106,7 +110,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Пул не был найден.",
+			util.T(chatId, "❌ Пул не был найден."),
 		); err != nil {
 			log.Error(err)
 		}
@@ -119,7 +123,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ваш аккаунт не активирован! Введите команду /start",
+			util.T(chatId, "❌ Ваш аккаунт не активирован! Введите команду /start"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -132,7 +136,7 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ У вас не привязан кошелек! Возмещение не возможно!",
+			util.T(chatId, "❌ У вас не привязан кошелек! Возмещение не возможно!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -145,73 +149,75 @@ func (c *TakeInsuranceFromStake) Execute(ctx context.Context, callback *models.C
 		return
 	}
 
-	insurance := util.CalculateInsurance(pool, stake)
-	amount := stake.Balance + insurance
-	profit := stake.Balance - stake.Amount
-
-	if pool.Reserve < amount {
+	res, err := util.ClaimInsurance(c.aws, c.as, c.ss, c.ps, stake, pool, w, jettonData.Decimals)
+	if errors.Is(err, util.ErrNotEnoughReserve) {
 		util.SendMessageOwnerAndUserIfBadReserve(
 			uint64(chatId),
 			pool.OwnerId,
 			uint64(pool.Id.Int64),
 			jettonData.Name,
 			c.b,
-			c.ts,
 		)
 		return
 	}
-
-	boc, err := c.aws.SendJetton(
-		pool.JettonMaster,
-		w.Addr,
-		"",
-		util.RemoveZeroFloat(amount),
-		jettonData.Decimals,
-	)
-	if err != nil {
+	if errors.Is(err, util.ErrPayoutDeferred) && res == nil {
 		log.Error(err)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Ошибка. Пока что вывод не доступен. Попробуйте позже",
+			util.T(chatId, "❌ Не удалось отправить токены. Выплата будет произведена вручную, обратитесь в поддержку!"),
 		); err != nil {
 			log.Error(err)
-
 		}
 		return
 	}
-
-	stake.IsInsurancePaid = true
-	if err := c.ss.Update(stake); err != nil {
+	if err != nil && !errors.Is(err, util.ErrPayoutDeferred) {
 		log.Error(err)
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Ошибка. Пока что вывод не доступен. Попробуйте позже"),
+		); err != nil {
+			log.Error(err)
+		}
 		return
 	}
 
-	pool.Reserve -= profit + insurance
-	stakes := c.ss.GetPoolStakes(stake.PoolId)
-	pool.TempReserve = pool.Reserve - util.CalculateSumStakesFromPool(&stakes, pool)
-	if err := c.ps.Update(pool); err != nil {
+	hash := base64.StdEncoding.EncodeToString(res.Boc)
+	desc := fmt.Sprintf(
+		"\n-Получение страховки.\n-Сумма: %v %v.\n-Hash: %v",
+		util.RemoveZeroFloat(res.Amount),
+		jettonData.Name,
+		hash,
+	)
+	text := util.T(chatId, "✅ Вам отправлено %v %v\nHash операции: %v", util.RemoveZeroFloat(res.Amount), jettonData.Name, hash)
+	if err != nil {
+		// баланс передан администратору, компенсация ниже могла быть отправлена
 		log.Error(err)
-		return
+		desc = fmt.Sprintf("\n-Получение страховки.\n-Сумма %v %v не отправлена: %v", util.RemoveZeroFloat(res.Amount), jettonData.Name, err)
+		text = util.T(chatId, "❌ Не удалось отправить %v %v. Выплата будет произведена вручную, обратитесь в поддержку!", util.RemoveZeroFloat(res.Amount), jettonData.Name)
+	}
+	if res.Insurance > 0 {
+		assetName := util.InsuranceAssetName(util.Lang(uint64(chatId)), pool.InsuranceAsset)
+		if res.InsuranceErr != nil {
+			log.Error(res.InsuranceErr)
+			text += util.T(chatId, "\n\n❌ Компенсацию %v %v отправить не удалось. Обратитесь в поддержку, выплата будет произведена вручную.",
+				util.RemoveZeroFloat(res.Insurance),
+				assetName,
+			)
+			desc += fmt.Sprintf("\n-Компенсация %v %v не отправлена: %v", util.RemoveZeroFloat(res.Insurance), assetName, res.InsuranceErr)
+		} else {
+			insuranceHash := base64.StdEncoding.EncodeToString(res.InsuranceBoc)
+			text += util.T(chatId, "\n\n✅ Компенсация: %v %v\nHash операции: %v", util.RemoveZeroFloat(res.Insurance), assetName, insuranceHash)
+			desc += fmt.Sprintf("\n-Компенсация: %v %v.\n-Hash: %v", util.RemoveZeroFloat(res.Insurance), assetName, insuranceHash)
+		}
 	}
 
-	hash := base64.StdEncoding.EncodeToString(boc)
-
-	if _, err := c.ops.Create(
-		uint64(u.Id.Int64),
-		appModels.OP_CLAIM_INSURANCE,
-		fmt.Sprintf("\n-Получение страховки.\n-Сумма: %v %v.\n-Hash: %v", amount, jettonData.Name, hash),
-	); err != nil {
+	if _, err := c.ops.Create(uint64(u.Id.Int64), appModels.OP_CLAIM_INSURANCE, desc); err != nil {
 		log.Error(err)
-		return
 	}
 
-	if _, err := util.SendTextMessage(
-		c.b,
-		uint64(chatId),
-		fmt.Sprintf("✅ Вам отправлено %v %v\nHash операции: %v", amount, jettonData.Name, hash),
-	); err != nil {
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), text); err != nil {
 		log.Error(err)
 	}
-
 }
@@ -22,6 +22,7 @@ type TakeProfitFromStake struct {
 	ps  *services.PoolService
 	ws  *services.WalletTonService
 	aws *services.AdminWalletService
+	as  *services.AdminService
 	ops *services.OperationService
 	ts  *services.TelegramService
 }
@@ -32,6 +33,7 @@ func NewTakeProfitFromStake(
 	ps *services.PoolService,
 	ws *services.WalletTonService,
 	aws *services.AdminWalletService,
+	as *services.AdminService,
 	ss *services.StakeService,
 	ops *services.OperationService,
 	ts *services.TelegramService,
@@ -42,6 +44,7 @@ func NewTakeProfitFromStake(
 		ps:  ps,
 		ws:  ws,
 		aws: aws,
+		as:  as,
 		ss:  ss,
 		ops: ops,
 		ts:  ts,
@@ -59,7 +62,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Что-то пошло не так. Повторите попытку позже!",
+			util.T(chatId, "❌ Что-то пошло не так. Повторите попытку позже!"),
 		); err != nil {
 			log.Println(err)
 		}
@@ -70,7 +73,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Стейк не найден! Возможно он был удален!",
+			util.T(chatId, "❌ Стейк не найден! Возможно он был удален!"),
 		); err != nil {
 			log.Println(err)
 		}
@@ -81,7 +84,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Дождитесь закрытия стейка!",
+			util.T(chatId, "❌ Дождитесь закрытия стейка!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -92,7 +95,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Награда или возмещение уже были получены!",
+			util.T(chatId, "❌ Награда или возмещение уже были получены!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -111,7 +114,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Аккаунт не активирован. Введите команду /start!",
+			util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start!"),
 		); err != nil {
 			log.Println(err)
 		}
@@ -123,7 +126,7 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не возможно получить награды, так как у вас не привязан кошелек!",
+			util.T(chatId, "❌ Не возможно получить награды, так как у вас не привязан кошелек!"),
 		); err != nil {
 			log.Println(err)
 		}
@@ -136,55 +139,46 @@ func (c *TakeProfitFromStake) Execute(ctx context.Context, callback *models.Call
 		return
 	}
 
-	if stake.Balance > pool.Reserve {
+	res, err := util.ClaimReward(c.aws, c.as, c.ss, c.ps, stake, pool, w, jettonaData.Decimals)
+	if errors.Is(err, util.ErrNotEnoughReserve) {
 		util.SendMessageOwnerAndUserIfBadReserve(
 			uint64(chatId),
 			pool.OwnerId,
 			uint64(pool.Id.Int64),
 			jettonaData.Name,
 			c.b,
-			c.ts,
 		)
-		stake.IsRewardPaid = false
-		if err := c.ss.Update(stake); err != nil {
-			log.Error(err)
-			return
-		}
 		return
 	}
-
-	boc, err := c.aws.SendJetton(jettonMaster, w.Addr, "", util.RemoveZeroFloat(stake.Balance), jettonaData.Decimals)
-	if err != nil {
+	if errors.Is(err, util.ErrPayoutDeferred) {
+		log.Error(err)
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ На данный момент вывод не возможен. Повторите попытку позже!",
+			util.T(chatId, "❌ Не удалось отправить токены. Выплата будет произведена вручную, обратитесь в поддержку!"),
 		); err != nil {
 			log.Println(err)
 		}
 		return
 	}
-
-	stake.IsRewardPaid = true
-	if err := c.ss.Update(stake); err != nil {
+	if err != nil {
 		log.Error(err)
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ На данный момент вывод не возможен. Повторите попытку позже!"),
+		); err != nil {
+			log.Println(err)
+		}
 		return
 	}
 
-	pool.Reserve -= stake.Balance - stake.Amount
-	stakes := c.ss.GetPoolStakes(stake.PoolId)
-	pool.TempReserve = pool.Reserve - util.CalculateSumStakesFromPool(&stakes, pool)
-	if err := c.ps.Update(pool); err != nil {
-		log.Error("update pool err: ", err.Error())
-		return
-	}
-
-	hash := base64.StdEncoding.EncodeToString(boc)
+	hash := base64.StdEncoding.EncodeToString(res.Boc)
 
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		"💸 Токены были отправлены. Hash: "+hash,
+		util.T(chatId, "💸 Токены были отправлены. Hash: ")+hash,
 	); err != nil {
 		log.Println(err)
 	}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/util"
@@ -55,7 +57,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу обработать данную кнопку! Попробуйте позже!",
+			util.T(chatId, "❌ Не могу обработать данную кнопку! Попробуйте позже!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -67,7 +69,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Не могу обработать данную кнопку! Попробуйте позже!",
+			util.T(chatId, "❌ Не могу обработать данную кнопку! Попробуйте позже!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -79,7 +81,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Аккаунт не активирован. Введите /start",
+			util.T(chatId, "❌ Аккаунт не активирован. Введите /start"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -91,7 +93,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Пул не найден! Возможно он был удален!",
+			util.T(chatId, "❌ Пул не найден! Возможно он был удален!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -102,7 +104,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Резерв пуст",
+			util.T(chatId, "❌ Резерв пуст"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -113,7 +115,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Вы не создатель пула",
+			util.T(chatId, "❌ Вы не создатель пула"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -124,7 +126,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Комиссия не оплачена!",
+			util.T(chatId, "❌ Комиссия не оплачена!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -135,7 +137,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Сначала вы должны закрыть пул!",
+			util.T(chatId, "❌ Сначала вы должны закрыть пул!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -151,7 +153,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		}
 		if !s.IsRewardPaid && !s.IsInsurancePaid {
 			editPriceProcient := util.CalculateProcientEditPrice(s.JettonPriceClosed, s.DepositCreationPrice)
-			if editPriceProcient < float64(p.InsuranceCoating)*-1 {
+			if editPriceProcient < float64(p.InsuranceCoating)*-1 && !util.HasInsuranceReserve(p) {
 				insurance := util.CalculateInsurance(p, &s)
 				amount := s.Balance + insurance
 				noPaymentSum += amount
@@ -163,8 +165,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 
 	stakes := c.ss.CountStakesPoolIdAndStatus(uint64(poolId), true)
 	if stakes > 0 {
-		text := fmt.Sprintf(
-			"❌ Нельзя вывести токены пока есть активные стейки. Вывод будет доступен, когда стейки будут закрыты! Активных стейков: %d. Дата завершения последнего стейка: %v",
+		text := util.T(chatId, "❌ Нельзя вывести токены пока есть активные стейки. Вывод будет доступен, когда стейки будут закрыты! Активных стейков: %d. Дата завершения последнего стейка: %v",
 			stakes,
 			lastDate,
 		)
@@ -181,7 +182,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 	jettonData, err := c.aws.DataJetton(p.JettonMaster)
 	if err != nil {
 		log.Error(err)
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ Что-то пошло не так. Повторите попытку"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так. Повторите попытку")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -194,8 +195,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			fmt.Sprintf(
-				"❌ Недостаточно резерва для выплаты стейкерам. Нужно выплатить %v %v стейкерам",
+			util.T(chatId, "❌ Недостаточно резерва для выплаты стейкерам. Нужно выплатить %v %v стейкерам",
 				util.RemoveZeroFloat(noPaymentSum),
 				jettonData.Name,
 			),
@@ -207,7 +207,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 
 	w, err := c.ws.GetByUserId(uint64(u.Id.Int64))
 	if err != nil {
-		if _, err := util.SendTextMessage(c.b, uint64(chatId), "❌ У вас не привязан кошелек!"); err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ У вас не привязан кошелек!")); err != nil {
 			log.Error(err)
 		}
 		return
@@ -230,7 +230,7 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Произошла ошибка при выводе средств, повторите попытку позже!",
+			util.T(chatId, "❌ Произошла ошибка при выводе средств, повторите попытку позже!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -238,25 +238,31 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 	}
 	p.Reserve = 0
 	p.TempReserve = p.Reserve
+
+	insuranceText := ""
+	if util.HasInsuranceReserve(p) {
+		insuranceText = c.takeInsuranceReserve(util.Lang(uint64(chatId)), p, &sumStakes, w)
+	}
+
 	if err := c.ps.Update(p); err != nil {
 		log.Error(err)
 		return
 	}
 
-	resp := fmt.Sprintf(
-		"✅ Снятие средст прошло успешно! Снято: %v %v.",
+	resp := util.T(chatId, "✅ Снятие средст прошло успешно! Снято: %v %v.",
 		util.RemoveZeroFloat(currentReserve),
 		jettonData.Name,
 	)
 
 	if oldPrice > currentReserve {
-		resp += fmt.Sprintf(
-			"\n\nСумма может быть меньше, так как с резерва зарезервировано %v %v стейкерам",
+		resp += util.T(chatId, "\n\nСумма может быть меньше, так как с резерва зарезервировано %v %v стейкерам",
 			util.RemoveZeroFloat(noPaymentSum),
 			jettonData.Name,
 		)
 	}
 
+	resp += insuranceText
+
 	if _, err := util.SendTextMessage(c.b, uint64(chatId), resp); err != nil {
 		log.Error(err)
 		return
@@ -273,3 +279,37 @@ func (c *TakeTokens) Execute(ctx context.Context, callback *models.CallbackQuery
 		return
 	}
 }
+
+// takeInsuranceReserve возвращает владельцу свободную часть страхового резерва, оставляя невыплаченные компенсации
+func (c *TakeTokens) takeInsuranceReserve(lang string, p *appModels.Pool, stakes *[]appModels.Stake, w *appModels.WalletTon) string {
+	assetName := util.InsuranceAssetName(lang, p.InsuranceAsset)
+	obligations := util.CalculateInsuranceObligations(stakes, p)
+	free := p.InsuranceReserve - obligations
+	if free <= 0 {
+		return ""
+	}
+
+	var err error
+	if p.InsuranceAsset == appModels.INSURANCE_ASSET_TON {
+		_, err = c.aws.SendTon(w.Addr, "", util.RemoveZeroFloat(free))
+	} else {
+		_, err = c.aws.SendJetton(
+			config.USDT_JETTON_MASTER,
+			w.Addr,
+			"",
+			util.RemoveZeroFloat(free),
+			config.USDT_DECIMALS,
+		)
+	}
+	if err != nil {
+		log.Error(err)
+		return i18n.T(lang, "\n\n❌ Страховой резерв %v %v вывести не удалось. Повторите попытку позже!", util.RemoveZeroFloat(free), assetName)
+	}
+
+	p.InsuranceReserve -= free
+	res := i18n.T(lang, "\n\n✅ Выведено из страхового резерва: %v %v.", util.RemoveZeroFloat(free), assetName)
+	if obligations > 0 {
+		res += i18n.T(lang, "\nЗарезервировано на компенсации стейкерам: %v %v", util.RemoveZeroFloat(obligations), assetName)
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"tonclient/internal/services"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type TonConnectDisconnect[T CommandType] struct {
+	b   *bot.Bot
+	tcs *services.TonConnectService
+}
+
+func NewTonConnectDisconnect[T CommandType](b *bot.Bot, tcs *services.TonConnectService) *TonConnectDisconnect[T] {
+	return &TonConnectDisconnect[T]{
+		b:   b,
+		tcs: tcs,
+	}
+}
+
+func (c *TonConnectDisconnect[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.disconnect(v.Chat.ID)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		if err := util.CheckTypeMessage(c.b, v); err != nil {
+			return
+		}
+		c.disconnect(v.Message.Message.Chat.ID)
+		return
+	}
+}
+
+func (c *TonConnectDisconnect[T]) disconnect(chatId int64) {
+	err := c.tcs.Disconnect(fmt.Sprint(chatId))
+	if services.IsSessionLost(err) {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Кошелек не подключен через TonConnect")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось отключить кошелек. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	if _, err := util.SendTextMessage(
+		c.b,
+		uint64(chatId),
+		util.T(chatId, "✅ Кошелек отключен от TonConnect. Привязанные адреса сохранены, для новых операций подключите кошелек заново в профиле"),
+	); err != nil {
+		log.Error(err)
+	}
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"tonclient/internal/services"
 	"tonclient/internal/util"
 
@@ -35,7 +36,7 @@ func (c *TonConnectRepeat) Execute(ctx context.Context, callback *models.Callbac
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		"‼️ Подключайте уже привязанный кошелек! Так как все выводы будут идти на него!",
+		util.T(chatId, "‼️ Подключайте уже привязанный кошелек! Так как все выводы будут идти на него!"),
 	); err != nil {
 		log.Error(err)
 		return
@@ -47,7 +48,7 @@ func (c *TonConnectRepeat) Execute(ctx context.Context, callback *models.Callbac
 		if _, err := util.SendTextMessage(
 			c.b,
 			uint64(chatId),
-			"❌ Повторите попытку! Что-то пошло не так!",
+			util.T(chatId, "❌ Повторите попытку! Что-то пошло не так!"),
 		); err != nil {
 			log.Error(err)
 			return
@@ -67,6 +68,20 @@ func (c *TonConnectRepeat) Execute(ctx context.Context, callback *models.Callbac
 		return
 	}
 
+	if !services.SameAddress(res.Addr, w.Addr) {
+		if err := c.tcs.DeleteSession(fmt.Sprint(chatId)); err != nil {
+			log.Error(err)
+		}
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Подключен другой кошелек! Подключите кошелек ")+w.Addr,
+		); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
 	w.Name = res.WalletName
 	if err := c.ws.Update(w); err != nil {
 		log.Error(err)
@@ -76,7 +91,7 @@ func (c *TonConnectRepeat) Execute(ctx context.Context, callback *models.Callbac
 	if _, err := util.SendTextMessage(
 		c.b,
 		uint64(chatId),
-		"✅ Кошелек был успешно привязан!",
+		util.T(chatId, "✅ Кошелек был успешно привязан!"),
 	); err != nil {
 		log.Error(err)
 		return
@@ -0,0 +1,346 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/i18n"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+	"tonclient/internal/tonbot/buttons"
+	"tonclient/internal/tonbot/userstate"
+	"tonclient/internal/util"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const walletLabelMaxLen = 64
+
+var currentLabelWalletId = make(map[int64]uint64)
+
+type Wallets[T CommandType] struct {
+	b  *bot.Bot
+	ws *services.WalletTonService
+	us *services.UserService
+}
+
+func NewWalletsCommand[T CommandType](b *bot.Bot, ws *services.WalletTonService, us *services.UserService) *Wallets[T] {
+	return &Wallets[T]{
+		b:  b,
+		ws: ws,
+		us: us,
+	}
+}
+
+func (c *Wallets[T]) Execute(ctx context.Context, args T) {
+	if v, ok := any(args).(*models.Message); ok {
+		c.executeMessage(v)
+		return
+	}
+
+	if v, ok := any(args).(*models.CallbackQuery); ok {
+		c.executeCallback(ctx, v)
+		return
+	}
+}
+
+func (c *Wallets[T]) executeCallback(ctx context.Context, callback *models.CallbackQuery) {
+	if err := util.CheckTypeMessage(c.b, callback); err != nil {
+		return
+	}
+	msg := callback.Message.Message
+	chatId := msg.Chat.ID
+
+	userId, ok := c.getUserId(chatId)
+	if !ok {
+		return
+	}
+
+	splitData := strings.Split(callback.Data, ":")
+	switch splitData[0] {
+	case buttons.MyWalletsId:
+		c.renderList(ctx, chatId, msg.ID, userId)
+		return
+	case buttons.CancelDefaultWalletId:
+		if err := c.ws.CancelDefaultRequest(userId); err != nil {
+			log.Error(err)
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось отменить смену кошелька. Повторите попытку позже!")); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		c.renderList(ctx, chatId, msg.ID, userId)
+		return
+	}
+
+	if len(splitData) < 2 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, повторите попытку!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	walletId, err := strconv.ParseUint(splitData[1], 10, 64)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не могу обработать данную кнопку")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	w, err := c.ws.GetUserWallet(userId, walletId)
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Кошелек не найден. Возможно он был удален!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	switch splitData[0] {
+	case buttons.WalletInfoId:
+		c.renderWallet(ctx, chatId, msg.ID, w)
+	case buttons.SetDefaultWalletId:
+		c.askConfirmDefault(ctx, chatId, msg.ID, userId, w)
+	case buttons.ConfirmDefaultWalletId:
+		c.confirmDefault(ctx, chatId, msg.ID, userId, w)
+	case buttons.RenameWalletId:
+		currentLabelWalletId[chatId] = walletId
+		userstate.CurrentState[chatId] = userstate.EnterWalletLabel
+		markup := util.CreateInlineMarup(1, util.CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText))
+		if _, err := util.SendTextMessageMarkup(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "Введите подпись для кошелька (до %v символов), например: Основной, Ledger, Для наград", walletLabelMaxLen),
+			markup,
+		); err != nil {
+			log.Error(err)
+		}
+	case buttons.DeleteWalletId:
+		if err := c.ws.DeleteUserWallet(userId, walletId); err != nil {
+			resp := util.T(chatId, "❌ Кошелек не был удален. Повторите попытку позже!")
+			if errors.Is(err, services.ErrDeleteDefaultWallet) {
+				resp = util.T(chatId, "❌ Основной кошелек удалить нельзя: на него отправляются выплаты. Сначала сделайте основным другой кошелек.")
+			} else {
+				log.Error(err)
+			}
+			if _, err := util.SendTextMessage(c.b, uint64(chatId), resp); err != nil {
+				log.Error(err)
+			}
+			return
+		}
+		c.renderList(ctx, chatId, msg.ID, userId)
+	}
+}
+
+func (c *Wallets[T]) executeMessage(msg *models.Message) {
+	chatId := msg.Chat.ID
+	walletId, ok := currentLabelWalletId[chatId]
+	if !ok || walletId == 0 {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Что-то пошло не так, начните операцию сначала!")); err != nil {
+			log.Error(err)
+		}
+		userstate.ResetState(chatId)
+		return
+	}
+
+	label := strings.TrimSpace(msg.Text)
+	if label == "" || len([]rune(label)) > walletLabelMaxLen {
+		if _, err := util.SendTextMessage(
+			c.b,
+			uint64(chatId),
+			util.T(chatId, "❌ Подпись должна быть от 1 до %v символов!", walletLabelMaxLen),
+		); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	userId, ok := c.getUserId(chatId)
+	if !ok {
+		return
+	}
+
+	w, err := c.ws.SetLabel(userId, walletId, label)
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Подпись не сохранена. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	userstate.ResetState(chatId)
+	delete(currentLabelWalletId, chatId)
+
+	if _, err := util.SendTextMessageMarkup(
+		c.b,
+		uint64(chatId),
+		c.walletText(util.Lang(uint64(chatId)), w),
+		c.walletMarkup(w),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *Wallets[T]) askConfirmDefault(ctx context.Context, chatId int64, msgId int, userId uint64, w *appModels.WalletTon) {
+	if w.IsDefault {
+		c.renderWallet(ctx, chatId, msgId, w)
+		return
+	}
+
+	text := util.T(chatId, "Сделать основным кошелек <b>%v</b>?\n\n<code>%v</code>\n\nНа него будут отправляться выплаты по всем невыплаченным стейкам.",
+		walletTitle(w),
+		w.Addr,
+	)
+	if current, err := c.ws.GetByUserId(userId); err == nil && current.IsDefault {
+		text += util.T(chatId, "\n\n⏳ Для безопасности смена вступит в силу через %v. До этого выплаты идут на <code>%v</code>.",
+			formatCooldown(util.Lang(uint64(chatId)), config.PayoutWalletCooldown()),
+			current.Addr,
+		)
+	}
+
+	markup := util.CreateInlineMarup(
+		1,
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.ConfirmDefaultWalletId, w.Id.Int64), buttons.ConfirmDefaultWallet),
+		util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.WalletInfoId, w.Id.Int64), buttons.BackWallet),
+	)
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(chatId), msgId, text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *Wallets[T]) confirmDefault(ctx context.Context, chatId int64, msgId int, userId uint64, w *appModels.WalletTon) {
+	at, err := c.ws.RequestDefault(userId, uint64(w.Id.Int64))
+	if err != nil {
+		log.Error(err)
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Не удалось сменить основной кошелек. Повторите попытку позже!")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	resp := util.T(chatId, "✅ Кошелек %v теперь основной. Выплаты будут отправляться на него.", walletTitle(w))
+	if at.After(time.Now()) {
+		resp = util.T(chatId, "⏳ Кошелек %v станет основным %v. Отменить смену можно в разделе <b>%v</b>.",
+			walletTitle(w),
+			at.Format("02 January 2006 15:04"),
+			util.T(chatId, buttons.MyWallets),
+		)
+	}
+	if _, err := util.SendTextMessage(c.b, uint64(chatId), resp); err != nil {
+		log.Error(err)
+	}
+
+	c.renderList(ctx, chatId, msgId, userId)
+}
+
+func (c *Wallets[T]) renderList(ctx context.Context, chatId int64, msgId int, userId uint64) {
+	wallets := c.ws.GetUserWallets(userId)
+
+	var sb strings.Builder
+	sb.WriteString(util.T(chatId, "<b>👛 Мои кошельки</b>\n\n⭐ - основной кошелек, на него отправляются выплаты.\n"))
+	btns := make([]models.InlineKeyboardButton, 0, len(wallets)+2)
+	hasPending := false
+	for i, w := range wallets {
+		mark := ""
+		if w.IsDefault {
+			mark = "⭐ "
+		}
+		sb.WriteString(fmt.Sprintf("\n%v. %v%v - <code>%v</code>", i+1, mark, walletTitle(&w), w.Addr))
+		if !w.IsDefault && w.DefaultAt.Valid {
+			hasPending = true
+			sb.WriteString(util.T(chatId, "\n    ⏳ станет основным %v", w.DefaultAt.Time.Format("02 January 2006 15:04")))
+		}
+		btns = append(btns, util.CreateDefaultButton(
+			fmt.Sprintf("%v:%v", buttons.WalletInfoId, w.Id.Int64),
+			mark+walletTitle(&w),
+		))
+	}
+	if len(wallets) == 0 {
+		sb.WriteString(util.T(chatId, "\nКошельки не привязаны."))
+	}
+
+	if hasPending {
+		btns = append(btns, util.CreateDefaultButton(buttons.CancelDefaultWalletId, buttons.CancelDefaultWallet))
+	}
+	btns = append(btns, util.CreateDefaultButton(buttons.SetNumberWalletId, buttons.SetNumberWallet))
+
+	markup := util.MenuWithBackButton(buttons.DefCloseId, buttons.DefCloseText, btns...)
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(chatId), msgId, sb.String(), markup); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *Wallets[T]) renderWallet(ctx context.Context, chatId int64, msgId int, w *appModels.WalletTon) {
+	if err := util.EditTextMessageMarkup(ctx, c.b, uint64(chatId), msgId, c.walletText(util.Lang(uint64(chatId)), w), c.walletMarkup(w)); err != nil {
+		log.Error(err)
+	}
+}
+
+func (c *Wallets[T]) walletText(lang string, w *appModels.WalletTon) string {
+	status := i18n.T(lang, "⭐ Основной - на него отправляются выплаты")
+	if !w.IsDefault {
+		status = i18n.T(lang, "Дополнительный")
+		if w.DefaultAt.Valid {
+			status = i18n.T(lang, "⏳ Станет основным %v", w.DefaultAt.Time.Format("02 January 2006 15:04"))
+		}
+	}
+
+	return i18n.T(lang, "<b>👛 %v</b>\n\n<b>Адрес</b>: <code>%v</code>\n<b>Приложение</b>: %v\n<b>Статус</b>: %v",
+		walletTitle(w),
+		w.Addr,
+		w.Name,
+		status,
+	)
+}
+
+func (c *Wallets[T]) walletMarkup(w *appModels.WalletTon) *models.InlineKeyboardMarkup {
+	id := w.Id.Int64
+	btns := make([]models.InlineKeyboardButton, 0, 4)
+	if !w.IsDefault && !w.DefaultAt.Valid {
+		btns = append(btns, util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.SetDefaultWalletId, id), buttons.SetDefaultWallet))
+	}
+	btns = append(btns, util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.RenameWalletId, id), buttons.RenameWallet))
+	if !w.IsDefault {
+		btns = append(btns, util.CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.DeleteWalletId, id), buttons.DeleteWallet))
+	}
+
+	return util.MenuWithBackButton(buttons.MyWalletsId, buttons.BackWalletList, btns...)
+}
+
+func (c *Wallets[T]) getUserId(chatId int64) (uint64, bool) {
+	u, err := c.us.GetByTelegramChatId(uint64(chatId))
+	if err != nil {
+		if _, err := util.SendTextMessage(c.b, uint64(chatId), util.T(chatId, "❌ Аккаунт не активирован. Введите команду /start!")); err != nil {
+			log.Error(err)
+		}
+		return 0, false
+	}
+	return uint64(u.Id.Int64), true
+}
+
+// walletTitle подпись кошелька, а если ее нет - название приложения и начало адреса
+func walletTitle(w *appModels.WalletTon) string {
+	if w.Label != "" {
+		return w.Label
+	}
+	addr := w.Addr
+	if len(addr) > 10 {
+		addr = addr[:4] + "..." + addr[len(addr)-4:]
+	}
+	if w.Name != "" {
+		return fmt.Sprintf("%v %v", w.Name, addr)
+	}
+	return addr
+}
+
+func formatCooldown(lang string, d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return i18n.T(lang, "%v ч.", int(d.Hours()))
+	}
+	return i18n.T(lang, "%v мин.", int(d.Minutes()))
+}
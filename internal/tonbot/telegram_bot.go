@@ -2,7 +2,6 @@ package tonbot
 
 import (
 	"context"
-	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -12,7 +11,9 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 	"tonclient/internal/config"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/schedulers"
 	"tonclient/internal/services"
@@ -31,7 +32,6 @@ import (
 var log = config.InitLogger()
 var sendJettonInsurance = make(chan func())
 var sendJettonProfit = make(chan func())
-var sendJettonClosingTimeStake = make(chan func())
 var sendJettonClosePool = make(chan func())
 
 type TgBot struct {
@@ -45,12 +45,17 @@ type TgBot struct {
 	tcs   *services.TonConnectService
 	opS   *services.OperationService
 	rs    *services.ReferalService
+	is    *services.TxIntentService
+	as    *services.AdminService
+	ans   *services.AnnouncementService
+	ns    *services.NotificationService
 }
 
 func NewTgBot(token string, us *services.UserService, ts *services.TelegramService,
 	ps *services.PoolService, aws *services.AdminWalletService, ss *services.StakeService,
 	ws *services.WalletTonService, tcs *services.TonConnectService,
-	opS *services.OperationService, rs *services.ReferalService) *TgBot {
+	opS *services.OperationService, rs *services.ReferalService, is *services.TxIntentService,
+	as *services.AdminService, ans *services.AnnouncementService, ns *services.NotificationService) *TgBot {
 	return &TgBot{
 		token: token,
 		us:    us,
@@ -62,6 +67,10 @@ func NewTgBot(token string, us *services.UserService, ts *services.TelegramServi
 		tcs:   tcs,
 		opS:   opS,
 		rs:    rs,
+		is:    is,
+		as:    as,
+		ans:   ans,
+		ns:    ns,
 	}
 }
 
@@ -78,9 +87,13 @@ func (t *TgBot) StartBot(ch chan appModels.SubmitTransaction) error {
 		return err
 	}
 
+	util.StartSender(ctx, t.ts, t.ns)
+
 	go t.checkingOperation(tgbot, ch)
 	go t.createCron(tgbot)
 	go checkSendJettonOperation(ctx)
+	go t.checkTxIntents(ctx, tgbot)
+	go t.sendAnnouncements(ctx, tgbot)
 
 	tgbot.Start(ctx)
 
@@ -96,9 +109,12 @@ func (t *TgBot) createCron(b *bot.Bot) {
 		t.ps,
 		t.rs,
 		t.aws,
+		t.as,
 		t.ws,
 		t.ts,
+		t.opS,
 		stakes,
+		EnqueuePayout,
 	)
 
 	c := cron.New()
@@ -107,6 +123,16 @@ func (t *TgBot) createCron(b *bot.Bot) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if _, err := c.AddFunc("@every 20s", t.is.TrackPending); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := c.AddFunc("@every 10m", t.payoutReferrals(b)); err != nil {
+		log.Fatal(err)
+	}
+	watcher := schedulers.NewPriceWatcher(b, t.ss, t.ps)
+	if _, err := c.AddFunc(fmt.Sprintf("@every %v", config.LoadPriceWatchConfig().Interval), watcher.CheckActiveStakes()); err != nil {
+		log.Fatal(err)
+	}
 	c.Start()
 
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -134,35 +160,71 @@ func (t *TgBot) checkMessageBonusStakes(ctx context.Context, b *bot.Bot, ch chan
 			if !ok {
 				continue
 			}
-			tg, err := t.ts.GetByUserId(notification.Stake.UserId)
-			if err != nil {
+			util.NotifyMarkup(b, notification.Stake.UserId, notification.Event, notification.Msg, nil)
+		}
+	}
+}
+
+func (t *TgBot) checkTxIntents(ctx context.Context, b *bot.Bot) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case intent := <-t.is.Updates():
+			if intent.Status == appModels.TX_INTENT_PARTIAL {
+				t.combinedPartial(&intent, b)
 				continue
 			}
-			if _, err := util.SendTextMessage(
-				b,
-				tg.TelegramId,
-				notification.Msg,
-			); err != nil {
-				log.Error(err)
+			text := util.TxIntentMessage(util.Lang(intent.TelegramId), &intent)
+			if text == "" {
 				continue
 			}
+			util.QueueTextMessage(b, intent.TelegramId, text)
 		}
 	}
 }
 
+// handleMyChatMember отмечает пользователей, которые заблокировали бота или разблокировали его
+func (t *TgBot) handleMyChatMember(member *models.ChatMemberUpdated) {
+	if member.Chat.Type != models.ChatTypePrivate {
+		return
+	}
+	var blocked bool
+	switch member.NewChatMember.Type {
+	case models.ChatMemberTypeBanned:
+		blocked = true
+	case models.ChatMemberTypeMember:
+		blocked = false
+	default:
+		return
+	}
+	if err := t.ts.SetBlocked(uint64(member.Chat.ID), blocked); err != nil {
+		log.Error(err)
+	}
+}
+
 func (t *TgBot) handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	if update == nil {
 		return
 	}
 
+	if update.MyChatMember != nil {
+		go t.handleMyChatMember(update.MyChatMember)
+		return
+	}
+
 	if update.Message != nil {
 		msg := update.Message
+		if msg.From != nil {
+			util.RememberLanguage(uint64(msg.From.ID), msg.From.LanguageCode)
+		}
 		go t.handleMessage(ctx, b, msg)
 		return
 	}
 
 	if update.CallbackQuery != nil {
 		callback := update.CallbackQuery
+		util.RememberLanguage(uint64(callback.From.ID), callback.From.LanguageCode)
 
 		go t.handleCallback(ctx, b, callback)
 
@@ -179,6 +241,7 @@ func (t *TgBot) handleMessage(ctx context.Context, b *bot.Bot, msg *models.Messa
 	if msg.Chat.Type == models.ChatTypePrivate {
 		text := msg.Text
 		chatId := msg.Chat.ID
+		key := buttons.ReplyKey(text)
 
 		if strings.HasPrefix(text, "/start") {
 			userstate.ResetState(chatId)
@@ -187,77 +250,89 @@ func (t *TgBot) handleMessage(ctx context.Context, b *bot.Bot, msg *models.Messa
 			return
 		}
 
-		if text == buttons.LearnMore {
+		if text == "/disconnect" {
+			userstate.ResetState(chatId)
+			command.NewTonConnectDisconnect[*models.Message](b, t.tcs).Execute(ctx, msg)
+			return
+		}
+
+		if text == "/admin" {
+			userstate.ResetState(chatId)
+			t.adminPanel(b).Execute(ctx, msg)
+			return
+		}
+
+		if key == buttons.LearnMore {
 			cmd := command.NewInfoCommand(b)
 			cmd.Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.InviteFriend {
+		if key == buttons.InviteFriend {
 			userstate.ResetState(chatId)
-			cmd := command.NewInviteFriendCommand(b, t.us)
+			cmd := command.NewInviteFriendCommand(b, t.us, t.rs)
 			cmd.Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.MyPools {
+		if key == buttons.MyPools {
 			userstate.ResetState(chatId)
 			command.NewMyPoolsCommand(b, t.us, t.ps, t.aws, t.ss).Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.SelectPool {
+		if key == buttons.SelectPool {
 			userstate.ResetState(chatId)
 			command.NewListPoolCommand(b, t.ps, t.aws, t.ss).Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.Setting {
+		if key == buttons.Setting {
 			userstate.ResetState(chatId)
 			command.NewOpenSetting(b).Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.HistoryOperation {
+		if key == buttons.HistoryOperation {
 			command.NewListHistoryOperation(b, t.us, t.opS).Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.Profile {
+		if key == buttons.Profile {
 			userstate.ResetState(chatId)
 			command.NewProfileCommand(b, t.us, t.ws, t.aws, t.ps, t.ss).Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.CreatePool {
+		if key == buttons.CreatePool {
 			userstate.ResetState(chatId)
 			cmd := command.NewCreatePoolCommand[*models.Message](b, t.ps, t.us, t.tcs, t.aws, t.ws)
 			cmd.Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.MyStakes {
+		if key == buttons.MyStakes {
 			userstate.ResetState(chatId)
 			cmd := command.NewStakesUserList[*models.Message](b, t.us, t.ss)
 			cmd.Execute(ctx, msg)
 			return
 		}
 
-		if text == buttons.TakeAwards {
+		if key == buttons.TakeAwards {
 			userstate.ResetState(chatId)
 			cmd := command.NewStakeProfitList[*models.Message](b, t.us, t.ss, t.ps)
 			cmd.Execute(ctx, msg)
 			return
 		}
 
-		//if text == buttons.CheckInsurance {
+		//if key == buttons.CheckInsurance {
 		//	userstate.ResetState(chatId)
 		//	cmd := command.NewStakeInsuranceList[*models.Message](b, t.us, t.ss, t.ps)
 		//	cmd.Execute(ctx, msg)
 		//	return
 		//}
 
-		if text == buttons.Payments {
+		if key == buttons.Payments {
 			userstate.ResetState(chatId)
 			cmd := command.NewStakeInsuranceList[*models.Message](b, t.us, t.ss, t.ps)
 			cmd.Execute(ctx, msg)
@@ -297,10 +372,56 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 		return
 	}
 
-	if strings.HasPrefix(data, buttons.CloseStakeId) {
-		sendJettonClosingTimeStake <- func() {
-			command.NewCloseStakeCommand(b, t.aws, t.ws, t.ss, t.ps, t.opS).Execute(ctx, callback)
-		}
+	if strings.HasPrefix(data, buttons.CloseStakeId) || strings.HasPrefix(data, buttons.PartialCloseStakeId) {
+		command.NewCloseStakeCommand[*models.CallbackQuery](b, t.aws, t.ws, t.ss, t.ps, t.us, t.opS, t.as, EnqueuePayout).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.RewardTiersId) ||
+		strings.HasPrefix(data, buttons.AddRewardTierId) ||
+		strings.HasPrefix(data, buttons.DeleteRewardTierId) {
+		command.NewRewardTiersCommand[*models.CallbackQuery](b, t.ps, t.us).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.PoolLimitsId) ||
+		strings.HasPrefix(data, buttons.PoolMaxTotalId) ||
+		strings.HasPrefix(data, buttons.PoolMaxUserId) ||
+		strings.HasPrefix(data, buttons.PoolMaxStakersId) ||
+		strings.HasPrefix(data, buttons.PoolWhitelistId) ||
+		strings.HasPrefix(data, buttons.AddWhitelistId) ||
+		strings.HasPrefix(data, buttons.DeleteWhitelistId) {
+		command.NewPoolLimitsCommand[*models.CallbackQuery](b, t.ps, t.us, t.ss).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.MyWalletsId) ||
+		strings.HasPrefix(data, buttons.WalletInfoId) ||
+		strings.HasPrefix(data, buttons.SetDefaultWalletId) ||
+		strings.HasPrefix(data, buttons.ConfirmDefaultWalletId) ||
+		strings.HasPrefix(data, buttons.CancelDefaultWalletId) ||
+		strings.HasPrefix(data, buttons.RenameWalletId) ||
+		strings.HasPrefix(data, buttons.DeleteWalletId) {
+		command.NewWalletsCommand[*models.CallbackQuery](b, t.ws, t.us).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.AutoRolloverId) {
+		command.NewAutoRolloverCommand(b, t.ss, t.ps, t.us).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.ConfirmCloseStakeId) {
+		command.NewCloseStakeCommand[*models.CallbackQuery](b, t.aws, t.ws, t.ss, t.ps, t.us, t.opS, t.as, EnqueuePayout).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.EarlyExitSettingId) ||
+		strings.HasPrefix(data, buttons.EarlyExitPenaltyId) ||
+		strings.HasPrefix(data, buttons.EarlyExitMinDaysId) ||
+		strings.HasPrefix(data, buttons.EarlyExitPartialId) ||
+		strings.HasPrefix(data, buttons.EarlyExitProRataId) {
+		command.NewEarlyExitSettingCommand[*models.CallbackQuery](b, t.ps, t.us, t.ss).Execute(ctx, callback)
 		return
 	}
 
@@ -464,6 +585,63 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 		return
 	}
 
+	if strings.HasPrefix(data, buttons.AdminPayoutRetryId+":") {
+		sendJettonProfit <- func() {
+			t.adminPanel(b).RetryPayout(ctx, callback)
+		}
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.AdminPrefix) {
+		t.adminPanel(b).Callback(ctx, callback)
+		return
+	}
+
+	if data == buttons.NotificationSettingsId {
+		command.NewNotificationSettings(b, t.us, t.ns).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.NotificationToggleId+":") {
+		command.NewNotificationSettings(b, t.us, t.ns).Toggle(ctx, callback)
+		return
+	}
+
+	if data == buttons.CloseNotificationSettingsId {
+		command.NewNotificationSettings(b, t.us, t.ns).Close(ctx, callback)
+		return
+	}
+
+	if data == buttons.LanguageSettingsId {
+		command.NewLanguageSettings(b, t.ts).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.SetLanguageId+":") {
+		command.NewLanguageSettings(b, t.ts).Set(ctx, callback)
+		return
+	}
+
+	if data == buttons.MyReferralsId {
+		command.NewMyReferrals(b, t.us, t.rs).Execute(ctx, callback)
+		return
+	}
+
+	if data == buttons.NextPageReferrals {
+		command.NewMyReferrals(b, t.us, t.rs).NextPage(ctx, callback)
+		return
+	}
+
+	if data == buttons.BackPageReferrals {
+		command.NewMyReferrals(b, t.us, t.rs).BackPage(ctx, callback)
+		return
+	}
+
+	if data == buttons.CloseListReferrals {
+		command.NewMyReferrals(b, t.us, t.rs).Close(ctx, callback)
+		return
+	}
+
 	if data == buttons.NextPageHistory {
 		command.NewListHistoryOperation(b, t.us, t.opS).NextPage(ctx, callback)
 		return
@@ -497,6 +675,16 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 		return
 	}
 
+	if data == buttons.MemoStakeId {
+		command.NewMemoStakeCommand(b, t.ps, t.aws, t.is).Execute(ctx, callback)
+		return
+	}
+
+	if data == buttons.DisconnectTonConnectId {
+		command.NewTonConnectDisconnect[*models.CallbackQuery](b, t.tcs).Execute(ctx, callback)
+		return
+	}
+
 	if strings.HasPrefix(data, buttons.PaidCommissionId) {
 		command.NewPaidCommissionCommand(b, t.aws, t.tcs, t.ps, t.ws, t.us).Execute(ctx, callback)
 		return
@@ -507,6 +695,16 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 		return
 	}
 
+	if strings.HasPrefix(data, buttons.AddInsuranceReserveId) {
+		command.NewAddReserveCommand[*models.CallbackQuery](b, t.ps, t.tcs, t.us, t.ws, t.aws).Execute(ctx, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, buttons.InsuranceAssetId) {
+		command.NewSetInsuranceAssetCommand(b, t.ps, t.us, t.ss, t.aws).Execute(ctx, callback)
+		return
+	}
+
 	if strings.HasPrefix(data, buttons.AddReserveId) {
 		command.NewAddReserveCommand[*models.CallbackQuery](b, t.ps, t.tcs, t.us, t.ws, t.aws).Execute(ctx, callback)
 		return
@@ -552,6 +750,7 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 				t.opS,
 				t.ws,
 				t.aws,
+				t.as,
 			).Execute(ctx, callback)
 		}
 		return
@@ -569,7 +768,7 @@ func (t *TgBot) handleCallback(ctx context.Context, b *bot.Bot, callback *models
 
 	if strings.HasPrefix(data, buttons.TakeProfitId) {
 		sendJettonProfit <- func() {
-			command.NewTakeProfitFromStake(b, t.us, t.ps, t.ws, t.aws, t.ss, t.opS, t.ts).Execute(ctx, callback)
+			command.NewTakeProfitFromStake(b, t.us, t.ps, t.ws, t.aws, t.as, t.ss, t.opS, t.ts).Execute(ctx, callback)
 		}
 		return
 	}
@@ -622,6 +821,30 @@ func (t *TgBot) handleState(ctx context.Context, state int, b *bot.Bot, msg *mod
 	case userstate.CreateStake:
 		command.NewCreateStackeCommand[*models.Message](b, t.ps, t.us, t.tcs, t.ss, t.ts, t.aws, t.ws).Execute(ctx, msg)
 		break
+	case userstate.EnterPartialCloseAmount:
+		command.NewCloseStakeCommand[*models.Message](b, t.aws, t.ws, t.ss, t.ps, t.us, t.opS, t.as, EnqueuePayout).Execute(ctx, msg)
+		break
+	case userstate.EnterEarlyExitPenalty, userstate.EnterEarlyExitMinDays:
+		command.NewEarlyExitSettingCommand[*models.Message](b, t.ps, t.us, t.ss).Execute(ctx, msg)
+		break
+	case userstate.EnterRewardTier:
+		command.NewRewardTiersCommand[*models.Message](b, t.ps, t.us).Execute(ctx, msg)
+		break
+	case userstate.EnterPoolMaxTotal, userstate.EnterPoolMaxUser, userstate.EnterPoolMaxStakers, userstate.EnterWhitelistEntry:
+		command.NewPoolLimitsCommand[*models.Message](b, t.ps, t.us, t.ss).Execute(ctx, msg)
+		break
+	case userstate.EnterWalletLabel:
+		command.NewWalletsCommand[*models.Message](b, t.ws, t.us).Execute(ctx, msg)
+		break
+	case userstate.EnterAdminBroadcast:
+		t.adminPanel(b).EnterBroadcast(ctx, msg)
+		break
+	case userstate.EnterAnnouncementPool:
+		t.adminPanel(b).EnterAnnouncementPool(ctx, msg)
+		break
+	case userstate.EnterAnnouncementTime:
+		t.adminPanel(b).EnterAnnouncementTime(ctx, msg)
+		break
 	default:
 		log.Error(state)
 		return
@@ -651,14 +874,32 @@ func (t *TgBot) checkingOperation(b *bot.Bot, ch chan appModels.SubmitTransactio
 }
 
 func (t *TgBot) processOperation(b *bot.Bot, tr appModels.SubmitTransaction) {
+	if tr.Comment != "" {
+		t.memoTransfer(b, &tr)
+		return
+	}
+
 	var payload appModels.Payload
 	if err := json.Unmarshal(tr.Payload, &payload); err != nil {
 		log.Error("Unmarshal: ", err)
 		return
 	}
+	// payload собирает отправитель: засчитываем только то, что действительно пришло в казну в jetton из payload
+	received, err := t.is.ReceivedAmount(&tr, payload.JettonMaster)
+	if err != nil {
+		log.Error("Transfer ", tr.OperationType, " from ", tr.SenderAddr, " is not accepted: ", err)
+		return
+	}
+	payload.Received = received
+
+	// комиссия и депозит, подписанные одной транзакцией, сверяются по общему intent
+	if payload.IntentId != 0 && (tr.OperationType == appModels.OP_STAKE || tr.OperationType == appModels.OP_PAID_COMMISSION_STAKE) {
+		t.combinedPart(&payload, b)
+		return
+	}
 	switch tr.OperationType {
 	case appModels.OP_STAKE:
-		t.stake(&payload, b)
+		t.stake(&payload, nil, b)
 		break
 	case appModels.OP_PAID_COMMISSION_STAKE:
 		t.commissionStakePaid(&payload, b)
@@ -673,6 +914,9 @@ func (t *TgBot) processOperation(b *bot.Bot, tr appModels.SubmitTransaction) {
 	case appModels.OP_ADMIN_ADD_RESERVE:
 		t.addReserve(&payload, b)
 		break
+	case appModels.OP_ADD_INSURANCE_RESERVE:
+		t.addInsuranceReserve(&payload, b)
+		break
 	case appModels.OP_ADMIN_CLOSE_POOL:
 		break
 	case appModels.OP_GET_USER_STAKES:
@@ -688,60 +932,293 @@ func (t *TgBot) processOperation(b *bot.Bot, tr appModels.SubmitTransaction) {
 	}
 }
 
-func (t *TgBot) commissionStakePaid(payload *appModels.Payload, b *bot.Bot) {
+// combinedPart перевод из транзакции комиссия + депозит. Стейк создается, когда поступили оба перевода
+func (t *TgBot) combinedPart(payload *appModels.Payload, b *bot.Bot) {
+	intent, complete, err := t.is.ReceivePart(payload)
+	if errors.Is(err, services.ErrIntentClosed) {
+		t.refundClosedPart(intent, payload, b)
+		return
+	}
+	if err != nil {
+		// payload собирает отправитель, поэтому получателя возврата проверяет администратор
+		log.Errorf(
+			"Transfer of tx intent %v is not matched: %v. Operation %v, received %v %v",
+			payload.IntentId, err, payload.OperationType, payload.Received, payload.JettonMaster,
+		)
+		var stake appModels.Stake
+		if err := json.Unmarshal([]byte(payload.Payload), &stake); err != nil {
+			log.Error("Failed to unmarshal stake data:", err)
+		}
+		util.RecordFailedPayout(
+			t.as,
+			stake.UserId,
+			payload.JettonMaster,
+			payload.Received,
+			fmt.Sprintf("Перевод по неизвестной транзакции %v", payload.IntentId),
+			err,
+		)
+		return
+	}
+	if !complete {
+		log.Infoln("Transfer of tx intent", intent.Id.Int64, "received, waiting for the rest")
+		return
+	}
+
+	parts := services.ReceivedParts(intent)
+	var deposit, commission *appModels.Payload
+	for i := range parts {
+		switch parts[i].OperationType {
+		case appModels.OP_STAKE:
+			deposit = &parts[i]
+		case appModels.OP_PAID_COMMISSION_STAKE:
+			commission = &parts[i]
+		}
+	}
+	if deposit == nil {
+		return
+	}
+	if t.stake(deposit, commission, b) && commission != nil {
+		var stake appModels.Stake
+		if err := json.Unmarshal([]byte(deposit.Payload), &stake); err == nil {
+			if _, err := t.opS.Create(stake.UserId, appModels.OP_PAID_COMMISSION_STAKE, "Оплата комиссии за стейк одной транзакцией с депозитом"); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// combinedPartial из транзакции комиссия + депозит поступила только часть переводов.
+// Без депозита комиссия засчитывается и депозит запрашивается отдельно, без комиссии депозит возвращается
+func (t *TgBot) combinedPartial(intent *appModels.TxIntent, b *bot.Bot) {
+	for _, p := range services.ReceivedParts(intent) {
+		switch p.OperationType {
+		case appModels.OP_PAID_COMMISSION_STAKE:
+			// комиссия уже переносилась в транзакцию депозита, запрошенного отдельно, но депозит так и не поступил
+			if p.IntentId != intent.Id.Int64 {
+				t.refundPart(&p, "❌ Депозит стейка не поступил, стейк не создан. Комиссия возвращена на ваш кошелек", b)
+				continue
+			}
+			p.IntentId = 0
+			t.commissionStakePaid(&p, b)
+		case appModels.OP_STAKE:
+			t.refundPart(&p, "❌ Комиссия за стейк не поступила, стейк не создан. Депозит возвращен на ваш кошелек", b)
+		}
+	}
+}
+
+// refundClosedPart возвращает владельцу транзакции перевод, поступивший после ее закрытия
+func (t *TgBot) refundClosedPart(intent *appModels.TxIntent, payload *appModels.Payload, b *bot.Bot) {
+	log.Errorf(
+		"Transfer of closed tx intent %v is returned. Operation %v, received %v %v",
+		intent.Id.Int64, payload.OperationType, payload.Received, payload.JettonMaster,
+	)
+	u, err := t.us.GetByTelegramChatId(intent.TelegramId)
+	if err != nil {
+		util.RecordFailedPayout(
+			t.as,
+			0,
+			payload.JettonMaster,
+			payload.Received,
+			fmt.Sprintf("Перевод после закрытия транзакции %v", intent.Id.Int64),
+			err,
+		)
+		return
+	}
+	if err := t.returnTokens(uint64(u.Id.Int64), payload.JettonMaster, payload.Received); err != nil {
+		return
+	}
+	util.QueueTextMessage(
+		b,
+		intent.TelegramId,
+		util.T(intent.TelegramId, "❌ Перевод поступил после закрытия транзакции и возвращен на ваш кошелек"),
+	)
+}
+
+// refundPart возвращает перевод, который не может быть засчитан, и сообщает об этом пользователю.
+// Возвращается только сумма, фактически пришедшая в казну
+func (t *TgBot) refundPart(payload *appModels.Payload, text string, b *bot.Bot) {
 	var stake appModels.Stake
 	if err := json.Unmarshal([]byte(payload.Payload), &stake); err != nil {
 		log.Error("Failed to unmarshal stake data:", err)
 		return
 	}
+	if payload.Received <= 0 {
+		log.Error("Nothing to refund for transfer of tx intent ", payload.IntentId)
+		return
+	}
+	if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
+		log.Error("Failed to return tokens:", err)
+		return
+	}
 
-	pool, err := t.ps.GetId(stake.PoolId)
+	tg, err := t.ts.GetByUserId(stake.UserId)
 	if err != nil {
-		log.Error("Failed to get pool id:", err)
-		err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Amount)
+		log.Error(err)
+		return
+	}
+	if _, err := util.SendTextMessage(b, tg.TelegramId, util.T(tg.TelegramId, text)); err != nil {
+		log.Error(err)
+	}
+}
+
+// memoTransfer перевод напрямую в казну с кодом в комментарии
+func (t *TgBot) memoTransfer(b *bot.Bot, tr *appModels.SubmitTransaction) {
+	intent, complete, err := t.is.MatchMemo(tr)
+	if errors.Is(err, services.ErrMemoNotFound) {
+		log.Infoln("Transfer with unknown memo:", tr.Comment, tr.SenderAddr)
+		return
+	}
+	if errors.Is(err, services.ErrMemoAmount) {
+		util.QueueTextMessage(
+			b,
+			intent.TelegramId,
+			util.T(intent.TelegramId, "❌ Перевод с комментарием %v меньше ожидаемой суммы и не засчитан. Обратитесь в поддержку", intent.Memo),
+		)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to match memo:", err)
+		return
+	}
+
+	if !complete {
+		util.QueueTextMessage(
+			b,
+			intent.TelegramId,
+			util.T(intent.TelegramId, "✅ Перевод с комментарием %v получен. Ожидаем остальные переводы с этим кодом", intent.Memo),
+		)
+		return
+	}
+
+	var payload appModels.Payload
+	if err := json.Unmarshal([]byte(intent.Payload), &payload); err != nil {
+		log.Error("Unmarshal: ", err)
+		return
+	}
+	switch payload.OperationType {
+	case appModels.OP_STAKE:
+		t.memoStake(&payload, intent.SenderAddr, b)
+	}
+}
+
+// memoStake создает стейк, оплаченный переводом с комментарием. Адрес отправителя депозита
+// становится адресом выплат, а если у пользователя нет кошельков - его кошельком
+func (t *TgBot) memoStake(payload *appModels.Payload, senderAddr string, b *bot.Bot) {
+	var stake appModels.Stake
+	if err := json.Unmarshal([]byte(payload.Payload), &stake); err != nil {
+		log.Error("Failed to unmarshal stake data:", err)
+		return
+	}
+
+	// отправитель может быть горячим кошельком биржи, поэтому он только записывается в стейк,
+	// а выплаты идут на основной кошелек пользователя. Без кошелька адрес выплат выбирается при выводе
+	stake.DepositAddr = senderAddr
+	stake.PayoutAddr = ""
+	w, err := t.ws.GetByUserId(stake.UserId)
+	if err == nil {
+		stake.PayoutAddr = w.Addr
+	}
+
+	data, err := json.Marshal(stake)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	payload.Payload = string(data)
+	// payload сохранен ботом при выдаче кода, суммы депозита и комиссии сверены при сопоставлении переводов
+	payload.Received = payload.Amount
+	var commission *appModels.Payload
+	if stake.CommissionAsset != appModels.COMMISSION_ASSET_STAKE && stake.Commission > 0 {
+		pool, err := t.ps.GetId(stake.PoolId)
 		if err != nil {
+			log.Error("Failed to get pool id:", err)
 			return
 		}
+		commission = &appModels.Payload{
+			OperationType: appModels.OP_PAID_COMMISSION_STAKE,
+			JettonMaster:  services.CommissionJettonMaster(pool, stake.CommissionAsset),
+			Amount:        stake.Commission,
+			Received:      stake.Commission,
+		}
+	}
+	if !t.stake(payload, commission, b) || w != nil {
 		return
 	}
 
 	tg, err := t.ts.GetByUserId(stake.UserId)
 	if err != nil {
-		err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Amount)
-		if err != nil {
-			return
+		return
+	}
+	util.QueueTextMessage(
+		b,
+		tg.TelegramId,
+		util.T(tg.TelegramId, "⚠️ Кошелек для выплат не привязан. Привяжите его в профиле, подтвердив владение через TonConnect, чтобы получить выплаты по стейку"),
+	)
+}
+
+// commissionStakePaid комиссия за стейк пришла без депозита: депозит запрашивается отдельной транзакцией,
+// в intent которой комиссия сразу засчитана. Комиссия, не совпадающая с условиями пула, возвращается
+func (t *TgBot) commissionStakePaid(payload *appModels.Payload, b *bot.Bot) {
+	var stake appModels.Stake
+	if err := json.Unmarshal([]byte(payload.Payload), &stake); err != nil {
+		log.Error("Failed to unmarshal stake data:", err)
+		return
+	}
+
+	pool, err := t.ps.GetId(stake.PoolId)
+	if err != nil {
+		log.Error("Failed to get pool id:", err)
+		if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
+			log.Error("Failed to return tokens:", err)
 		}
+		return
 	}
 
-	payload.OperationType = appModels.OP_STAKE
-	payload.JettonMaster = pool.JettonMaster
-	payload.Amount = stake.Amount
+	user, _ := t.us.GetById(stake.UserId)
+	err = t.ps.CheckStakeCommission(pool, t.ps.GetRewardTiers(stake.PoolId), user, &stake, stake.Amount, util.GetCurrentPriceJettonAddr)
+	if err == nil && (stake.CommissionAsset == appModels.COMMISSION_ASSET_STAKE || !services.CommissionPaid(pool, &stake, payload)) {
+		err = services.ErrCommissionNotPaid
+	}
+	if err != nil {
+		log.Error("Stake commission is not accepted:", err)
+		t.refundPart(payload, "❌ Комиссия за стейк не совпадает с условиями пула. Стейк не создан, комиссия возвращена на ваш кошелек", b)
+		return
+	}
 
+	tg, err := t.ts.GetByUserId(stake.UserId)
+	if err != nil {
+		log.Error("Failed to get telegram:", err)
+		if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
+			log.Error("Failed to return tokens:", err)
+		}
+		return
+	}
+
+	// депозит запросить не у кого - комиссия возвращается
 	w, err := t.ws.GetByUserId(stake.UserId)
 	if err != nil {
 		log.Error("Failed to get user wallet:", err)
+		t.refundPart(payload, "❌ Кошелек не привязан, стейк не создан. Комиссия возвращена на ваш кошелек", b)
 		return
 	}
 
 	s, err := t.tcs.LoadSession(fmt.Sprint(tg.TelegramId))
 	if err != nil {
-		if _, err := util.SendTextMessage(
-			b,
-			tg.TelegramId,
-			"❌ Привяжите свой кошелей заново! Потом повторите стейк. Комиссия уже учтена, он будет в списке ваших стейков",
-		); err != nil {
-			log.Error(err)
+		log.Error(err)
+		if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
+			log.Error("Failed to return tokens:", err)
 		}
+		util.SendSessionLost(b, tg.TelegramId, err, "повторите стейк. Комиссия возвращена на ваш кошелек")
 		return
 	}
 
-	btns := util.GenerateButtonWallets(w, t.tcs, true)
+	btns := util.GenerateButtonWallets(util.Lang(tg.TelegramId), w, t.tcs, true)
 
 	markup := util.CreateInlineMarup(1, btns...)
 	if _, err := util.SendTextMessageMarkup(
 		b,
 		tg.TelegramId,
-		fmt.Sprintf("✅ Комиссия принята. Подтвердите свой стейк в кошельке. %v %v", stake.Amount, pool.JettonName),
+		util.T(tg.TelegramId, "✅ Комиссия принята. Подтвердите свой стейк в кошельке. %v %v", stake.Amount, pool.JettonName),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -754,90 +1231,125 @@ func (t *TgBot) commissionStakePaid(payload *appModels.Payload, b *bot.Bot) {
 		return
 	}
 
-	if _, err := t.tcs.SendJettonTransaction(
+	transfers := []services.Transfer{
+		{Payload: payload, Paid: true},
+		{
+			JettonWallet: jettonAddr.Address().String(),
+			Amount:       strconv.FormatFloat(stake.Amount, 'f', -1, 64),
+			Payload: &appModels.Payload{
+				OperationType: appModels.OP_STAKE,
+				JettonMaster:  pool.JettonMaster,
+				Amount:        stake.Amount,
+				Payload:       payload.Payload,
+				Source:        payload.Source,
+			},
+		},
+	}
+	if _, err := t.tcs.SendTransfers(
 		fmt.Sprint(tg.TelegramId),
-		jettonAddr.Address().String(),
 		t.aws.GetAdminWalletAddr().String(),
 		w.Addr,
-		fmt.Sprintf("%f", stake.Amount),
-		payload,
+		transfers,
 		s,
-	); err != nil {
-		log.Error(err)
-		//if _, err := util.SendTextMessage(
-		//	b,
-		//	tg.TelegramId,
-		//	fmt.Sprintf(
-		//		"❌ Транзакция %f %v стейкинга не была подтверждена!",
-		//		stake.Amount,
-		//		pool.JettonName,
-		//	),
-		//); err != nil {
-		//	log.Error(err)
-		//}
-		return
-	}
-	if _, err := t.opS.Create(
-		stake.UserId,
-		appModels.OP_PAID_COMMISSION_STAKE,
-		fmt.Sprintf("Оплата комиссии за стейк %v", pool.JettonName),
 	); err != nil {
 		log.Error(err)
 		return
 	}
 }
 
-func (t *TgBot) stake(payload *appModels.Payload, b *bot.Bot) {
+// stake создает стейк по поступившему депозиту payload. commission - комиссия, поступившая отдельным переводом.
+// Возвращает true, если стейк создан
+func (t *TgBot) stake(payload *appModels.Payload, commission *appModels.Payload, b *bot.Bot) bool {
 	var stake appModels.Stake
 	if err := json.Unmarshal([]byte(payload.Payload), &stake); err != nil {
 		log.Error("Failed to unmarshal stake data:", err)
-		return
+		return false
 	}
 	log.Infoln("начало создания стейка")
-	stake.IsCommissionPaid = true
 
 	log.Infoln("Поиск пула")
 	pool, err := t.ps.GetId(stake.PoolId)
 	if err != nil {
 		log.Error("Failed to get pool id:", err)
-		if err := t.returnTokens(stake.UserId, pool.JettonMaster, stake.Amount); err != nil {
+		if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
 			log.Error("Failed to return tokens:", err)
 		}
-		return
+		return false
+	}
+	if payload.JettonMaster != pool.JettonMaster {
+		log.Errorf("Stake deposit in %v does not match jetton of pool %v", payload.JettonMaster, pool.Id.Int64)
+		if err := t.returnTokens(stake.UserId, payload.JettonMaster, payload.Received); err != nil {
+			log.Error("Failed to return tokens:", err)
+		}
+		return false
 	}
 
 	log.Infoln("Получение инфы о стейке")
 	jettodData, err := t.aws.DataJetton(pool.JettonMaster)
 	if err != nil {
 		log.Error("Failed to get jettod data:", err)
-		return
+		return false
 	}
 
 	log.Infoln("поиск телеграмов")
-	tgOwnerPool, err := t.ts.GetByUserId(pool.OwnerId)
-	if err != nil {
-		log.Error("Failed to get user wall:", err)
-	}
 	tgStaker, err := t.ts.GetByUserId(stake.UserId)
 	if err != nil {
 		log.Error("Failed to get user wall:", err)
 	}
 
-	pool.TempReserve -= stake.Amount * 20
-	log.Println(pool.TempReserve)
-	if err := t.ps.Update(pool); err != nil {
-		log.Error("Failed to update pool:", err)
-	}
 	stake.StartPoolDeposit = stake.Amount * 20
 
 	log.Infoln("Сохранение стейка")
-	_, err = t.ss.CreateStake(&stake)
-	if err != nil {
-		log.Error("Failed to create stake:", err)
-		if err := t.returnTokens(stake.UserId, pool.JettonMaster, stake.Amount); err != nil {
+	// срок, ставку и комиссию из payload собирает отправитель, они сверяются с условиями пула.
+	// Лимиты пула проверяются повторно при поступлении депозита, т.к. с момента создания стейка пул мог заполниться
+	tiers := t.ps.GetRewardTiers(stake.PoolId)
+	createErr := t.ps.CheckStakeTerms(pool, tiers, &stake, payload.Received, time.Now())
+	if createErr == nil {
+		user, _ := t.us.GetById(stake.UserId)
+		createErr = t.ps.CheckStakeCommission(pool, tiers, user, &stake, payload.Received, util.GetCurrentPriceJettonAddr)
+	}
+	if createErr == nil && !services.CommissionPaid(pool, &stake, commission) {
+		createErr = services.ErrCommissionNotPaid
+	}
+	// флаг оплаты комиссии ставится, только когда комиссия поступила
+	stake.IsCommissionPaid = createErr == nil
+	if createErr == nil && pool.IsWhitelist {
+		w, _ := t.ws.GetByUserId(stake.UserId)
+		if !t.ps.IsWhitelisted(pool, tgStaker, w) {
+			createErr = services.ErrNotWhitelisted
+		}
+	}
+	if createErr == nil {
+		_, createErr = t.ss.CreateStakeWithinLimits(&stake)
+	}
+	if createErr != nil {
+		log.Error("Failed to create stake:", createErr)
+		// стейк не создан - возвращается весь пришедший депозит вместе с удержанной из него комиссией
+		// и комиссия, пришедшая отдельным переводом
+		if err := t.returnTokens(stake.UserId, pool.JettonMaster, payload.Received); err != nil {
 			log.Error("Failed to return tokens:", err)
 		}
-		return
+		if commission != nil && commission.Received > 0 {
+			if err := t.returnTokens(stake.UserId, commission.JettonMaster, commission.Received); err != nil {
+				log.Error("Failed to return commission:", err)
+			}
+		}
+		if tgStaker != nil {
+			if _, err := util.SendTextMessage(
+				b,
+				tgStaker.TelegramId,
+				util.T(tgStaker.TelegramId, "%v\nСтейк не создан, токены возвращены на ваш кошелек.", util.PoolLimitErrorText(util.Lang(tgStaker.TelegramId), createErr, pool)),
+			); err != nil {
+				log.Error("Failed to send message:", err)
+			}
+		}
+		return false
+	}
+
+	pool.TempReserve -= stake.Amount * 20
+	log.Println(pool.TempReserve)
+	if err := t.ps.Update(pool); err != nil {
+		log.Error("Failed to update pool:", err)
 	}
 
 	description := fmt.Sprintf("Стейк в jetton: %v. Кол-во: %v", jettodData.Name, stake.Amount)
@@ -846,102 +1358,101 @@ func (t *TgBot) stake(payload *appModels.Payload, b *bot.Bot) {
 	_, err = t.opS.Create(stake.UserId, appModels.OP_STAKE, description)
 	if err != nil {
 		log.Error("Failed to create stake:", err)
-		return
+		return true
 	}
 
 	log.Infoln("Отправка сообщений в ТГ")
 
-	if tgOwnerPool != nil {
-		if _, err := util.SendTextMessage(b, tgOwnerPool.TelegramId, "✅ Новый стейк"); err != nil {
-			log.Error("Failed to send message:", err)
-			return
-		}
+	reward, _ := util.StakeTerms(&stake, pool)
+	data := &appModels.NotificationData{
+		JettonName: pool.JettonName,
+		Amount:     stake.Amount,
+		Reward:     reward,
+		EndDate:    stake.EndDate,
 	}
-	if tgStaker != nil {
-		if _, err := util.SendTextMessage(b, tgStaker.TelegramId, "✅ Стейк создан!"); err != nil {
-			log.Error("Failed to send message:", err)
+	util.Notify(b, stake.UserId, appModels.NOTIFY_STAKE_CREATED, data)
+	ownerData := *data
+	ownerData.Owner = true
+	util.Notify(b, pool.OwnerId, appModels.NOTIFY_STAKE_CREATED, &ownerData)
+
+	log.Infoln("Создание стейка завершено")
+	return true
+}
+
+// payoutReferrals ставит в очередь выплат реферальные балансы, достигшие порога
+func (t *TgBot) payoutReferrals(b *bot.Bot) func() {
+	return func() {
+		ids, err := t.rs.DueReferrers()
+		if err != nil {
 			return
 		}
+		for _, userId := range ids {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := EnqueuePayout(ctx, func() { t.payoutReferral(b, userId) }); err != nil {
+				log.Error("Failed to enqueue referral payout: ", err)
+			}
+			cancel()
+		}
 	}
-
-	log.Infoln("Создание стейка завершено")
 }
 
-func (t *TgBot) sendBonus(b *bot.Bot, referalId uint64, stake *appModels.Stake, tgStaker *appModels.Telegram) error {
-	u, err := t.us.GetByTelegramChatId(referalId)
+// payoutReferral выплачивает весь реферальный баланс одним переводом в токенах платформы
+func (t *TgBot) payoutReferral(b *bot.Bot, userId uint64) {
+	w, err := t.ws.GetByUserId(userId)
 	if err != nil {
-		log.Error("Failed to get user :", err)
-		return err
+		// без кошелька баланс копится до его привязки
+		return
 	}
-	w, err := t.ws.GetByUserId(uint64(u.Id.Int64))
-	if err != nil {
-		log.Error("Failed to get user:", err)
-		return err
+
+	refs, amount, err := t.rs.ReservePayout(userId)
+	if err != nil || len(refs) == 0 {
+		return
 	}
-	jettonAdminAddr := os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
-	if jettonAdminAddr == "" {
-		return err
+	release := func() {
+		if err := t.rs.ReleasePayout(refs); err != nil {
+			log.Error("Failed to release referral payout: ", err)
+		}
 	}
-	bonus := os.Getenv("REFERAL_BONUS")
-	if bonus == "" {
-		bonus = "2"
+	if amount < config.LoadReferralConfig().Threshold {
+		release()
+		return
 	}
-	bonusNum, err := strconv.ParseFloat(bonus, 64)
+
+	jettonMaster := os.Getenv("JETTON_CONTRACT_ADMIN_JETTON")
+	jetData, err := t.aws.DataJetton(jettonMaster)
 	if err != nil {
-		log.Error("Failed to parse bonus:", err)
-		return err
-	}
-	decimal := os.Getenv("JETTON_DECIMAL")
-	if decimal == "" {
-		decimal = "9"
+		log.Error("Failed to get jetton data:", err)
+		release()
+		return
 	}
-	decimalNum, err := strconv.Atoi(decimal)
+	hash, err := t.aws.SendJetton(jettonMaster, w.Addr, "", util.RemoveZeroFloat(amount), jetData.Decimals)
 	if err != nil {
-		log.Error("Failed to parse decimal:", err)
-		return err
-	}
-	bonusAmount := stake.Amount * (bonusNum / 100)
-	if _, err := t.aws.SendJetton(
-		jettonAdminAddr,
-		w.Addr,
-		"",
-		util.RemoveZeroFloat(bonusAmount),
-		decimalNum,
-	); err != nil {
-		log.Error("Failed to send bonus:", err)
-		return err
-	}
-	tokenName := os.Getenv("JETTON_NAME_COIN")
-	if tokenName == "" {
-		tokenName = "NESTRAH"
+		log.Error("Failed to send referral payout:", err)
+		release()
+		return
 	}
 
-	if tgStaker != nil {
-		if _, err := util.SendTextMessage(
-			b,
-			referalId,
-			fmt.Sprintf("✅ Вы получили бонус %v %v, за пользователя %v. Токены были отправлены на привязанный кошелек", util.RemoveZeroFloat(bonusAmount), tokenName, tgStaker.Username),
-		); err != nil {
-			log.Error("Failed to send bonus:", err)
-			return err
-		}
+	txHash := base64.StdEncoding.EncodeToString(hash)
+	if err := t.rs.CompletePayout(refs, txHash); err != nil {
+		log.Error(err)
 	}
-
-	if err := t.rs.Save(&appModels.Referral{
-		ReferrerUserId: u.Id,
-		ReferralUserId: sql.NullInt64{
-			Int64: int64(stake.UserId),
-			Valid: true,
-		},
-		FirstStakeId: stake.Id,
-		RewardGiven:  true,
-		RewardAmount: bonusAmount,
-	}); err != nil {
-		log.Error("Failed to save referral:", err)
-		return err
+	if _, err := t.opS.Create(
+		userId,
+		appModels.OP_REFERRAL_PAYOUT,
+		fmt.Sprintf("Выплата реферальных наград: %v %v. Hash операции: %v", util.RemoveZeroFloat(amount), jetData.Name, txHash),
+	); err != nil {
+		log.Error(err)
 	}
 
-	return nil
+	tg, err := t.ts.GetByUserId(userId)
+	if err != nil {
+		return
+	}
+	util.QueueTextMessage(
+		b,
+		tg.TelegramId,
+		util.T(tg.TelegramId, "✅ Реферальные награды %v %v отправлены на ваш кошелек", util.RemoveZeroFloat(amount), jetData.Name),
+	)
 }
 
 func (t *TgBot) createPool(payload *appModels.Payload, b *bot.Bot) {
@@ -976,15 +1487,18 @@ func (t *TgBot) createPool(payload *appModels.Payload, b *bot.Bot) {
 		return
 	}
 
+	lang := util.Lang(telegram.TelegramId)
 	text := fmt.Sprint(
-		"✅ Пул был успешно создан! Оплатите комиссию, чтобы активировать его!\n\n",
-		util.PoolInfo(&pool, t.ss, jettonData),
+		i18n.T(lang, "✅ Пул был успешно создан! Оплатите комиссию, чтобы активировать его!\n\n"),
+		util.PoolInfo(lang, &pool, t.ss, jettonData, t.ps.GetRewardTiers(uint64(pool.Id.Int64))),
 	)
 	markup := util.GenerateOwnerPoolInlineKeyboard(
+		lang,
 		pool.Id.Int64,
 		buttons.BackMyPoolListId,
 		pool.IsActive,
 		pool.IsCommissionPaid,
+		pool.InsuranceAsset,
 		callbacksuf.My,
 	)
 
@@ -1011,16 +1525,32 @@ func (t *TgBot) addReserve(payload *appModels.Payload, b *bot.Bot) {
 	pool, err := t.ps.GetId(addReserve.PoolId)
 	if err != nil {
 		log.Errorf("Failed to get pool id: %v", err)
-		if err := t.returnTokens(pool.OwnerId, pool.JettonMaster, addReserve.Amount); err != nil {
+		// владельца пула не найти, поэтому возврат проводит администратор
+		util.RecordFailedPayout(
+			t.as,
+			0,
+			payload.JettonMaster,
+			payload.Received,
+			fmt.Sprintf("Пополнение резерва несуществующего пула %v", addReserve.PoolId),
+			err,
+		)
+		return
+	}
+
+	// засчитываем фактически пришедшую сумму и только в токене пула
+	amount := payload.Received
+	if payload.JettonMaster != pool.JettonMaster {
+		log.Errorf("Unexpected reserve jetton %v for pool %v", payload.JettonMaster, pool.Id.Int64)
+		if err := t.returnTokens(pool.OwnerId, payload.JettonMaster, amount); err != nil {
 			log.Error("Failed to return tokens:", err)
 		}
 		return
 	}
 
-	newReserve, err := t.ps.AddReserve(addReserve.PoolId, addReserve.Amount)
+	newReserve, err := t.ps.AddReserve(addReserve.PoolId, amount)
 	if err != nil {
 		log.Errorf("Failed to add reserve: %v", err)
-		if err := t.returnTokens(pool.OwnerId, pool.JettonMaster, addReserve.Amount); err != nil {
+		if err := t.returnTokens(pool.OwnerId, pool.JettonMaster, amount); err != nil {
 			log.Error("Failed to return tokens:", err)
 		}
 		return
@@ -1035,7 +1565,7 @@ func (t *TgBot) addReserve(payload *appModels.Payload, b *bot.Bot) {
 	if _, err := util.SendTextMessage(
 		b,
 		tg.TelegramId,
-		fmt.Sprint("✅ Резерв пополнен. Новый баланс резерва: ", newReserve)); err != nil {
+		fmt.Sprint(util.T(tg.TelegramId, "✅ Резерв пополнен. Новый баланс резерва: "), newReserve)); err != nil {
 		log.Error("Failed to send telegram:", err)
 		return
 	}
@@ -1046,7 +1576,7 @@ func (t *TgBot) addReserve(payload *appModels.Payload, b *bot.Bot) {
 		return
 	}
 
-	desc := fmt.Sprintf("Пополнение в пул с jetton: %v на сумму: %v", jettonData.Name, addReserve.Amount)
+	desc := fmt.Sprintf("Пополнение в пул с jetton: %v на сумму: %v", jettonData.Name, util.RemoveZeroFloat(amount))
 	_, err = t.opS.Create(pool.OwnerId, appModels.OP_PAY_COMMISION, desc)
 	if err != nil {
 		log.Error("Failed to create operation creating pool:", err)
@@ -1054,6 +1584,85 @@ func (t *TgBot) addReserve(payload *appModels.Payload, b *bot.Bot) {
 	}
 }
 
+func (t *TgBot) addInsuranceReserve(payload *appModels.Payload, b *bot.Bot) {
+	var addReserve appModels.AddReserve
+	if err := json.Unmarshal([]byte(payload.Payload), &addReserve); err != nil {
+		log.Errorf("Failed to unmarshal payload data: %v", err)
+		return
+	}
+
+	pool, err := t.ps.GetId(addReserve.PoolId)
+	if err != nil {
+		log.Errorf("Failed to get pool id: %v", err)
+		util.RecordFailedPayout(
+			t.as,
+			0,
+			payload.JettonMaster,
+			payload.Received,
+			fmt.Sprintf("Пополнение страхового резерва несуществующего пула %v", addReserve.PoolId),
+			err,
+		)
+		return
+	}
+
+	// засчитываем фактически пришедшую сумму и только в валюте страхового резерва пула
+	master := config.USDT_JETTON_MASTER
+	if pool.InsuranceAsset == appModels.INSURANCE_ASSET_TON {
+		master = ""
+	}
+	if payload.JettonMaster != master {
+		log.Errorf("Unexpected insurance reserve asset %v for pool %v", payload.JettonMaster, pool.Id.Int64)
+		if err := t.returnTokens(pool.OwnerId, payload.JettonMaster, payload.Received); err != nil {
+			log.Error("Failed to return tokens:", err)
+		}
+		return
+	}
+	amount := payload.Received
+
+	newReserve, err := t.ps.AddInsuranceReserve(addReserve.PoolId, amount)
+	if err != nil {
+		log.Errorf("Failed to add insurance reserve: %v", err)
+		if err := t.returnInsuranceAsset(pool.OwnerId, pool.InsuranceAsset, amount); err != nil {
+			log.Error("Failed to return insurance asset:", err)
+		}
+		return
+	}
+
+	assetName := util.InsuranceAssetName(i18n.Default, pool.InsuranceAsset)
+
+	tg, err := t.ts.GetByUserId(pool.OwnerId)
+	if err != nil {
+		log.Error("Failed to get user wall:", err)
+		return
+	}
+
+	if _, err := util.SendTextMessage(
+		b,
+		tg.TelegramId,
+		util.T(
+			tg.TelegramId,
+			"✅ Страховой резерв пополнен. Новый баланс страхового резерва: %v %v",
+			util.RemoveZeroFloat(newReserve),
+			util.InsuranceAssetName(util.Lang(tg.TelegramId), pool.InsuranceAsset),
+		)); err != nil {
+		log.Error("Failed to send telegram:", err)
+		return
+	}
+
+	desc := fmt.Sprintf("Пополнение страхового резерва пула %v на сумму: %v %v", pool.JettonName, util.RemoveZeroFloat(amount), assetName)
+	if _, err := t.opS.Create(pool.OwnerId, appModels.OP_ADD_INSURANCE_RESERVE, desc); err != nil {
+		log.Error("Failed to create operation add insurance reserve:", err)
+		return
+	}
+}
+
+func (t *TgBot) returnInsuranceAsset(userId uint64, asset string, amount float64) error {
+	if asset != appModels.INSURANCE_ASSET_TON {
+		return t.returnTokens(userId, config.USDT_JETTON_MASTER, amount)
+	}
+	return t.returnTokens(userId, "", amount)
+}
+
 func (t *TgBot) payCommission(payload *appModels.Payload, b *bot.Bot) error {
 	var pool appModels.Pool
 	if err := json.Unmarshal([]byte(payload.Payload), &pool); err != nil {
@@ -1079,14 +1688,18 @@ func (t *TgBot) payCommission(payload *appModels.Payload, b *bot.Bot) error {
 			payload.JettonMaster,
 			payload.Amount); err != nil {
 			log.Error("Failed to return tokens:", err)
-			if _, err := util.SendTextMessage(b, tg.TelegramId, "❌ Комиссия должна быть %v."); err != nil {
+			if _, err := util.SendTextMessage(b, tg.TelegramId, util.T(tg.TelegramId, "❌ Комиссия должна быть %v.", config.COMMISSION_AMOUNT)); err != nil {
 				log.Error("Failed to send telegram:", err)
 			}
 		}
 		return err
 	}
 
-	pool.IsActive = true
+	// в payload снимок пула на момент оплаты, приостановку администратора берем текущую
+	if current, err := t.ps.GetId(uint64(pool.Id.Int64)); err == nil {
+		pool.AdminPaused = current.AdminPaused
+	}
+	pool.IsActive = !pool.AdminPaused
 	pool.IsCommissionPaid = true
 
 	if err := t.ps.Update(&pool); err != nil {
@@ -1103,7 +1716,7 @@ func (t *TgBot) payCommission(payload *appModels.Payload, b *bot.Bot) error {
 	if _, err := util.SendTextMessage(
 		b,
 		tg.TelegramId,
-		"✅ Комиссия принята. Теперь ваш пул активен! Активность вы так же можете менять в настройках пула!",
+		util.T(tg.TelegramId, "✅ Комиссия принята. Теперь ваш пул активен! Активность вы так же можете менять в настройках пула!"),
 	); err != nil {
 		log.Error("Failed to send telegram:", err)
 		return err
@@ -1136,36 +1749,168 @@ func (t *TgBot) payCommission(payload *appModels.Payload, b *bot.Bot) error {
 }
 
 func (t *TgBot) returnTokens(userId uint64, jettonMaster string, amount float64) error {
-	userWall, err := t.ws.GetByUserId(userId)
+	// пустой jetton master - комиссия, оплаченная в TON
+	hash, err := util.SendRefund(t.aws, t.ws, userId, jettonMaster, amount)
 	if err != nil {
-		log.Error("Failed to get user wall:", err)
+		log.Error("Failed to return tokens:", err)
+		if _, er := t.as.RecordFailedPayout(userId, jettonMaster, amount, "Возврат токенов", err); er != nil {
+			log.Error("Failed to record failed payout:", er)
+		}
 		return err
 	}
 
-	jetData, err := t.aws.DataJetton(jettonMaster)
+	_, err = t.opS.Create(userId, appModels.OP_RETURNING_TOKENS, fmt.Sprintf("Возврат. Hash операции: %v", base64.StdEncoding.EncodeToString(hash)))
 	if err != nil {
-		log.Error("Failed to get jetton data:", err)
 		return err
 	}
 
-	hash, err := t.aws.SendJetton(
-		jettonMaster,
-		userWall.Addr,
-		"",
-		util.RemoveZeroFloat(amount),
-		jetData.Decimals,
-	)
-	if err != nil {
-		log.Error("Failed to send jetton data:", err)
-		return err
+	return nil
+}
+
+func (t *TgBot) adminPanel(b *bot.Bot) *command.AdminPanel {
+	return command.NewAdminPanel(b, t.as, t.ps, t.ts, t.aws, t.ws, t.opS, t.ans)
+}
+
+const (
+	// пауза между сообщениями рассылки, чтобы часть общего лимита Bot API оставалась остальным сообщениям
+	announcementDelay = 50 * time.Millisecond
+	// проверка запланированных объявлений, если воркер не разбудили раньше
+	announcementInterval = 30 * time.Second
+	announcementBatch    = 100
+)
+
+// sentDelivery результат отправки объявления, который не удалось сохранить
+type sentDelivery struct {
+	status string
+	err    error
+}
+
+type deliveryKey struct {
+	announcementId int64
+	telegramId     uint64
+}
+
+// sendAnnouncements рассылает объявления, время которых наступило, по одному за раз
+func (t *TgBot) sendAnnouncements(ctx context.Context, b *bot.Bot) {
+	ticker := time.NewTicker(announcementInterval)
+	defer ticker.Stop()
+	// отправленные, но не сохраненные доставки: при следующем проходе они сохраняются без повторной отправки
+	unsaved := make(map[deliveryKey]sentDelivery)
+	for {
+		list, err := t.ans.Due(time.Now())
+		if err == nil {
+			for i := range list {
+				t.sendAnnouncement(ctx, b, &list[i], unsaved)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-t.ans.Wake():
+		}
 	}
+}
 
-	_, err = t.opS.Create(userId, appModels.OP_RETURNING_TOKENS, fmt.Sprintf("Возврат. Hash операции: %v", base64.StdEncoding.EncodeToString(hash)))
-	if err != nil {
-		return err
+func (t *TgBot) sendAnnouncement(ctx context.Context, b *bot.Bot, a *appModels.Announcement, unsaved map[deliveryKey]sentDelivery) {
+	if t.ans.IsCancelled(a.Id.Int64) {
+		return
+	}
+	if err := t.ans.Start(a); err != nil {
+		log.Error("Failed to start announcement: ", err)
+		return
 	}
 
-	return nil
+	for {
+		if t.ans.IsCancelled(a.Id.Int64) {
+			return
+		}
+		deliveries, err := t.ans.PendingDeliveries(a, announcementBatch)
+		if err != nil {
+			return
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+		for i := range deliveries {
+			if !t.deliverAnnouncement(ctx, b, a, &deliveries[i], unsaved) {
+				return
+			}
+		}
+	}
+
+	if err := t.ans.Finish(a); err != nil {
+		log.Error("Failed to finish announcement: ", err)
+		return
+	}
+	if _, err := util.SendTextMessage(
+		b,
+		a.AuthorId,
+		util.T(
+			a.AuthorId,
+			"✅ Объявление #%v разослано. Доставлено: %v из %v, заблокировали бота: %v, ошибок: %v",
+			a.Id.Int64, a.Delivered, a.Total, a.Blocked, a.Failed,
+		),
+	); err != nil {
+		log.Error(err)
+	}
+}
+
+// deliverAnnouncement отправляет объявление получателю, при 429 ждет retry_after и повторяет.
+// false, если бот останавливается или результат не удалось сохранить
+func (t *TgBot) deliverAnnouncement(
+	ctx context.Context,
+	b *bot.Bot,
+	a *appModels.Announcement,
+	d *appModels.AnnouncementDelivery,
+	unsaved map[deliveryKey]sentDelivery,
+) bool {
+	key := deliveryKey{announcementId: d.AnnouncementId, telegramId: d.TelegramId}
+	sent, ok := unsaved[key]
+	for !ok {
+		_, err := util.SendTextMessage(b, d.TelegramId, a.Text)
+		status, retryAfter := util.DeliveryStatus(err)
+		if status != appModels.DELIVERY_PENDING {
+			sent = sentDelivery{status: status, err: err}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(max(retryAfter, time.Second)):
+		}
+	}
+
+	if err := t.ans.MarkDelivery(d, sent.status, sent.err); err != nil {
+		// строка осталась в ожидании и снова попадет в выборку, поэтому рассылка прерывается,
+		// а получатель не получит объявление повторно
+		log.Error("Failed to save announcement delivery: ", err)
+		unsaved[key] = sent
+		return false
+	}
+	delete(unsaved, key)
+	if ok {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(announcementDelay):
+		return true
+	}
+}
+
+// EnqueuePayout ставит выплату в ту же очередь, что и выплаты наград из бота,
+// чтобы отправки с админского кошелька не шли параллельно
+func EnqueuePayout(ctx context.Context, f func()) error {
+	select {
+	case sendJettonProfit <- f:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func checkSendJettonOperation(ctx context.Context) {
@@ -1186,11 +1931,6 @@ func checkSendJettonOperation(ctx context.Context) {
 				continue
 			}
 			f()
-		case f, ok := <-sendJettonClosingTimeStake:
-			if !ok {
-				continue
-			}
-			f()
 		case <-ctx.Done():
 			return
 		}
@@ -20,6 +20,28 @@ const (
 
 	//stakes
 	CreateStake
+	EnterPartialCloseAmount
+
+	//early exit setting
+	EnterEarlyExitPenalty
+	EnterEarlyExitMinDays
+
+	//reward tiers
+	EnterRewardTier
+
+	//pool limits
+	EnterPoolMaxTotal
+	EnterPoolMaxUser
+	EnterPoolMaxStakers
+	EnterWhitelistEntry
+
+	//wallets
+	EnterWalletLabel
+
+	//admin
+	EnterAdminBroadcast
+	EnterAnnouncementPool
+	EnterAnnouncementTime
 )
 
 func ResetState(chatId int64) {
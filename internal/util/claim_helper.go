@@ -0,0 +1,235 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"tonclient/internal/config"
+	appModels "tonclient/internal/models"
+	"tonclient/internal/services"
+)
+
+var (
+	ErrStakeStillActive = errors.New("stake is still active")
+	ErrStakeAlreadyPaid = errors.New("reward or insurance already paid")
+	ErrNotEnoughReserve = errors.New("not enough pool reserve")
+	// ErrPayoutDeferred баланс стейка не отправлен и выплачивается администратором.
+	// ClaimInsurance при этом может вернуть результат с отправленной компенсацией
+	ErrPayoutDeferred = errors.New("payout failed and is left to the administrator")
+)
+
+// ClaimResult итог выплаты по закрытому стейку
+type ClaimResult struct {
+	Amount       float64 // выплачено в токене пула
+	Boc          []byte
+	Insurance    float64 // компенсация из страхового резерва в USDT/TON
+	InsuranceBoc []byte
+	InsuranceErr error // баланс выплачен, но компенсацию отправить не удалось - она передана администратору
+}
+
+// IsInsuranceCase цена токена при закрытии упала ниже покрытия пула - стейку положена компенсация
+func IsInsuranceCase(stake *appModels.Stake, pool *appModels.Pool) bool {
+	return CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice) < float64(pool.InsuranceCoating)*-1
+}
+
+// PayoutAddr адрес выплаты по стейку: записанный при создании или основной кошелек для старых стейков
+func PayoutAddr(stake *appModels.Stake, w *appModels.WalletTon) string {
+	if stake.PayoutAddr != "" {
+		return stake.PayoutAddr
+	}
+	return w.Addr
+}
+
+func checkClaimable(stake *appModels.Stake) error {
+	if stake.IsActive {
+		return ErrStakeStillActive
+	}
+	if stake.IsRewardPaid || stake.IsInsurancePaid {
+		return ErrStakeAlreadyPaid
+	}
+	return nil
+}
+
+// ClaimReward отправляет на кошелек баланс закрытого стейка (депозит + награда)
+func ClaimReward(
+	aws *services.AdminWalletService,
+	as *services.AdminService,
+	ss *services.StakeService,
+	ps *services.PoolService,
+	stake *appModels.Stake,
+	pool *appModels.Pool,
+	w *appModels.WalletTon,
+	decimals int,
+) (*ClaimResult, error) {
+	if err := checkClaimable(stake); err != nil {
+		return nil, err
+	}
+	if stake.Balance > pool.Reserve {
+		return nil, ErrNotEnoughReserve
+	}
+
+	// стейк отмечается выплаченным до отправки, чтобы повторный запрос не отправил токены еще раз
+	stake.IsRewardPaid = true
+	if err := ss.Update(stake); err != nil {
+		return nil, err
+	}
+	pool.Reserve -= stake.Balance - stake.Amount
+
+	boc, sendErr := aws.SendJetton(pool.JettonMaster, PayoutAddr(stake, w), "", RemoveZeroFloat(stake.Balance), decimals)
+	if sendErr != nil {
+		sendErr = recordStakePayout(as, stake, pool.JettonMaster, stake.Balance, "Выплата стейка", sendErr)
+	}
+
+	updateTempReserve(ss, pool)
+	if err := ps.Update(pool); err != nil {
+		log.Error("Failed to update pool:", err)
+	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &ClaimResult{Amount: stake.Balance, Boc: boc}, nil
+}
+
+// ClaimInsurance выплачивает баланс стейка и компенсацию падения цены.
+// При страховом резерве компенсация уходит в USDT/TON, иначе - в токене пула вместе с балансом
+func ClaimInsurance(
+	aws *services.AdminWalletService,
+	as *services.AdminService,
+	ss *services.StakeService,
+	ps *services.PoolService,
+	stake *appModels.Stake,
+	pool *appModels.Pool,
+	w *appModels.WalletTon,
+	decimals int,
+) (*ClaimResult, error) {
+	if err := checkClaimable(stake); err != nil {
+		return nil, err
+	}
+	if HasInsuranceReserve(pool) {
+		return claimInsuranceFromReserve(aws, as, ss, ps, stake, pool, w, decimals)
+	}
+
+	insurance := CalculateInsurance(pool, stake)
+	amount := stake.Balance + insurance
+	profit := stake.Balance - stake.Amount
+	if pool.Reserve < amount {
+		return nil, ErrNotEnoughReserve
+	}
+
+	stake.IsInsurancePaid = true
+	if err := ss.Update(stake); err != nil {
+		return nil, err
+	}
+	pool.Reserve -= profit + insurance
+
+	boc, sendErr := aws.SendJetton(pool.JettonMaster, PayoutAddr(stake, w), "", RemoveZeroFloat(amount), decimals)
+	if sendErr != nil {
+		sendErr = recordStakePayout(as, stake, pool.JettonMaster, amount, "Выплата стейка с компенсацией", sendErr)
+	}
+
+	updateTempReserve(ss, pool)
+	if err := ps.Update(pool); err != nil {
+		log.Error("Failed to update pool:", err)
+	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &ClaimResult{Amount: amount, Boc: boc}, nil
+}
+
+func claimInsuranceFromReserve(
+	aws *services.AdminWalletService,
+	as *services.AdminService,
+	ss *services.StakeService,
+	ps *services.PoolService,
+	stake *appModels.Stake,
+	pool *appModels.Pool,
+	w *appModels.WalletTon,
+	decimals int,
+) (*ClaimResult, error) {
+	if stake.InsuranceAssetPrice <= 0 {
+		stake.InsuranceAssetPrice = GetInsuranceAssetPrice(pool.InsuranceAsset)
+	}
+	insurance := CalculateInsuranceInAsset(stake)
+	profit := stake.Balance - stake.Amount
+
+	if insurance <= 0 || pool.Reserve < profit || pool.InsuranceReserve < insurance {
+		return nil, ErrNotEnoughReserve
+	}
+
+	stake.IsInsurancePaid = true
+	if err := ss.Update(stake); err != nil {
+		return nil, err
+	}
+	// компенсация уже причитается стейкеру: при неудачной отправке ее выплачивает администратор
+	pool.Reserve -= profit
+	pool.InsuranceReserve -= insurance
+
+	boc, sendErr := aws.SendJetton(pool.JettonMaster, PayoutAddr(stake, w), "", RemoveZeroFloat(stake.Balance), decimals)
+	if sendErr != nil {
+		sendErr = recordStakePayout(as, stake, pool.JettonMaster, stake.Balance, "Выплата стейка", sendErr)
+	}
+
+	res := &ClaimResult{Amount: stake.Balance, Boc: boc, Insurance: insurance}
+	insuranceMaster := config.USDT_JETTON_MASTER
+	if pool.InsuranceAsset == appModels.INSURANCE_ASSET_TON {
+		insuranceMaster = ""
+		res.InsuranceBoc, res.InsuranceErr = aws.SendTon(PayoutAddr(stake, w), "", RemoveZeroFloat(insurance))
+	} else {
+		res.InsuranceBoc, res.InsuranceErr = aws.SendJetton(
+			config.USDT_JETTON_MASTER,
+			PayoutAddr(stake, w),
+			"",
+			RemoveZeroFloat(insurance),
+			config.USDT_DECIMALS,
+		)
+	}
+	if res.InsuranceErr != nil {
+		RecordFailedPayout(as, stake.UserId, insuranceMaster, insurance, fmt.Sprintf("Компенсация по стейку %v", stake.Id.Int64), res.InsuranceErr)
+	}
+
+	updateTempReserve(ss, pool)
+	if err := ps.Update(pool); err != nil {
+		log.Error("Failed to update pool:", err)
+	}
+
+	// компенсация могла уйти и без баланса: результат возвращается вместе с ошибкой
+	return res, sendErr
+}
+
+// RecordFailedPayout сохраняет неотправленную выплату для повтора администратором
+func RecordFailedPayout(as *services.AdminService, userId uint64, jettonMaster string, amount float64, reason string, sendErr error) {
+	if _, err := as.RecordFailedPayout(userId, jettonMaster, amount, reason, sendErr); err != nil {
+		log.Error("Failed to record failed payout:", err)
+	}
+}
+
+// recordStakePayout сохраняет неотправленную выплату по уже закрытому стейку
+func recordStakePayout(as *services.AdminService, stake *appModels.Stake, jettonMaster string, amount float64, reason string, sendErr error) error {
+	RecordFailedPayout(as, stake.UserId, jettonMaster, amount, fmt.Sprintf("%v %v", reason, stake.Id.Int64), sendErr)
+	return fmt.Errorf("%w: %v", ErrPayoutDeferred, sendErr)
+}
+
+func updateTempReserve(ss *services.StakeService, pool *appModels.Pool) {
+	stakes := ss.GetPoolStakes(uint64(pool.Id.Int64))
+	pool.TempReserve = pool.Reserve - CalculateSumStakesFromPool(&stakes, pool)
+}
+
+// SendRefund отправляет токены или TON (пустой jettonMaster) на основной кошелек пользователя
+func SendRefund(aws *services.AdminWalletService, ws *services.WalletTonService, userId uint64, jettonMaster string, amount float64) ([]byte, error) {
+	w, err := ws.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if jettonMaster == "" {
+		return aws.SendTon(w.Addr, "", RemoveZeroFloat(amount))
+	}
+
+	jetData, err := aws.DataJetton(jettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	return aws.SendJetton(jettonMaster, w.Addr, "", RemoveZeroFloat(amount), jetData.Decimals)
+}
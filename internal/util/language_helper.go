@@ -0,0 +1,123 @@
+package util
+
+import (
+	"sync"
+	"tonclient/internal/i18n"
+
+	"github.com/go-telegram/bot/models"
+)
+
+var (
+	languages     sync.Map // telegram id -> язык бота
+	languageCodes sync.Map // telegram id -> последний language_code из обновлений Telegram
+)
+
+// Lang язык бота для чата: выбранный в настройках или определенный по language_code
+func Lang(chatId uint64) string {
+	if lang, ok := languages.Load(chatId); ok {
+		return lang.(string)
+	}
+
+	var code string
+	if c, ok := languageCodes.Load(chatId); ok {
+		code = c.(string)
+	}
+
+	senderMu.RLock()
+	ts := senderTs
+	senderMu.RUnlock()
+	if ts == nil {
+		return i18n.Detect(code)
+	}
+	lang, err := ts.Language(chatId)
+	if err != nil {
+		// профиль еще не создан
+		return i18n.Detect(code)
+	}
+	languages.Store(chatId, lang)
+	return lang
+}
+
+// UserLang язык бота для пользователя по его id, для уведомлений вне обработки обновлений
+func UserLang(userId uint64) string {
+	senderMu.RLock()
+	ts := senderTs
+	senderMu.RUnlock()
+	if ts == nil {
+		return i18n.Default
+	}
+	tg, err := ts.GetByUserId(userId)
+	if err != nil {
+		return i18n.Default
+	}
+	return Lang(tg.TelegramId)
+}
+
+// T текст из каталога на языке чата, см. i18n.T
+func T[I int64 | uint64](chatId I, key string, args ...any) string {
+	return i18n.T(Lang(uint64(chatId)), key, args...)
+}
+
+// RememberLanguage сохраняет language_code из обновления, если он изменился с прошлого раза
+func RememberLanguage(chatId uint64, code string) {
+	if code == "" {
+		return
+	}
+	if prev, ok := languageCodes.Swap(chatId, code); ok && prev.(string) == code {
+		return
+	}
+
+	senderMu.RLock()
+	ts := senderTs
+	senderMu.RUnlock()
+	if ts == nil {
+		return
+	}
+	if err := ts.SetLanguageCode(chatId, code); err != nil {
+		log.Error("Failed to save language code: ", err)
+		return
+	}
+	languages.Delete(chatId)
+}
+
+// ForgetLanguage сбрасывает язык чата из кэша после смены в настройках
+func ForgetLanguage(chatId uint64) {
+	languages.Delete(chatId)
+}
+
+// localizeMarkup переводит подписи кнопок на язык чата. Подписи, которых нет в каталоге
+// (названия пулов, суммы), остаются как есть
+func localizeMarkup(chatId uint64, markup models.ReplyMarkup) models.ReplyMarkup {
+	lang := Lang(chatId)
+
+	switch m := markup.(type) {
+	case *models.InlineKeyboardMarkup:
+		if m == nil {
+			return markup
+		}
+		keyboard := make([][]models.InlineKeyboardButton, len(m.InlineKeyboard))
+		for i, row := range m.InlineKeyboard {
+			keyboard[i] = make([]models.InlineKeyboardButton, len(row))
+			for j, btn := range row {
+				btn.Text = i18n.T(lang, btn.Text)
+				keyboard[i][j] = btn
+			}
+		}
+		return &models.InlineKeyboardMarkup{InlineKeyboard: keyboard}
+	case *models.ReplyKeyboardMarkup:
+		if m == nil {
+			return markup
+		}
+		localized := *m
+		localized.Keyboard = make([][]models.KeyboardButton, len(m.Keyboard))
+		for i, row := range m.Keyboard {
+			localized.Keyboard[i] = make([]models.KeyboardButton, len(row))
+			for j, btn := range row {
+				btn.Text = i18n.T(lang, btn.Text)
+				localized.Keyboard[i][j] = btn
+			}
+		}
+		return &localized
+	}
+	return markup
+}
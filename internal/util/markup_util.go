@@ -5,6 +5,7 @@ import (
 	"math"
 	"os"
 	"strings"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
@@ -134,17 +135,28 @@ func MenuWithBackButton(buttonBackId, buttonBackText string, buttons ...models.I
 	}
 }
 
-func GenerateButtonWallets(w *appModels.WalletTon, tcs *services.TonConnectService, buyJettonBtn bool) []models.InlineKeyboardButton {
+// ConnectWalletsMarkup клавиатура подключения: по два кошелька в ряд и кнопка закрытия
+func ConnectWalletsMarkup(links []appModels.WalletConnectLink) *models.InlineKeyboardMarkup {
+	btns := make([]models.InlineKeyboardButton, 0, len(links))
+	for _, l := range links {
+		btns = append(btns, CreateUrlInlineButton(l.Wallet.Name, l.Url))
+	}
+
+	markup := CreateInlineMarup(2, btns...)
+	markup.InlineKeyboard = append(markup.InlineKeyboard, []models.InlineKeyboardButton{
+		CreateDefaultButton(buttons.DefCloseId, buttons.DefCloseText),
+	})
+	return markup
+}
+
+func GenerateButtonWallets(lang string, w *appModels.WalletTon, tcs *services.TonConnectService, buyJettonBtn bool) []models.InlineKeyboardButton {
 	lowwerWalletNma := strings.ToLower(w.Name)
 	var btns []models.InlineKeyboardButton
 
-	if lowwerWalletNma == "tonkeeper" {
-		txt := fmt.Sprintf("%v %v", buttons.OpenWallet, w.Name)
-		btn := CreateUrlInlineButton(txt, tcs.GetWalletUniversalLink(lowwerWalletNma))
-		btns = append(btns, btn)
-	} else {
-		btn := CreateUrlInlineButton(buttons.OpenWallet, tcs.GetWalletUniversalLink(lowwerWalletNma))
-		btns = append(btns, btn)
+	// кнопка открытия кошелька есть только для кошельков из реестра TON Connect
+	if link := tcs.GetWalletUniversalLink(lowwerWalletNma); link != "" {
+		txt := fmt.Sprintf("%v %v", i18n.T(lang, buttons.OpenWallet), w.Name)
+		btns = append(btns, CreateUrlInlineButton(txt, link))
 	}
 
 	if buyJettonBtn {
@@ -154,7 +166,7 @@ func GenerateButtonWallets(w *appModels.WalletTon, tcs *services.TonConnectServi
 			"https://app.ston.fi/swap?chartVisible=true&chartInterval=1w&ft=TON&tt=%v",
 			jettonMaster,
 		)
-		text := fmt.Sprintf("Купить %v", jettonName)
+		text := i18n.T(lang, "Купить %v", jettonName)
 		btn := CreateUrlInlineButton(text, urlBuyJetton)
 		btns = append(btns, btn)
 	}
@@ -26,6 +26,18 @@ func CalculateProcientEditPrice(currentPrice, oldPrice float64) float64 {
 	return (subCurrentPriceAndOld / oldPrice) * 100
 }
 
+// PriceAlertLevel уровень падения цены относительно порога страховки coverage (в %).
+// NEAR - до порога осталось не больше nearMargin процентных пунктов
+func PriceAlertLevel(priceChange float64, coverage uint, nearMargin float64) int {
+	switch {
+	case priceChange < float64(coverage)*-1:
+		return appModels.PRICE_ALERT_TRIGGERED
+	case priceChange < float64(coverage)*-1+nearMargin:
+		return appModels.PRICE_ALERT_NEAR
+	}
+	return appModels.PRICE_ALERT_NONE
+}
+
 func CalculateInsurance(pool *appModels.Pool, stake *appModels.Stake) float64 {
 	internalValue := stake.Amount * stake.DepositCreationPrice
 	currentValue := stake.Amount * stake.JettonPriceClosed
@@ -34,6 +46,42 @@ func CalculateInsurance(pool *appModels.Pool, stake *appModels.Stake) float64 {
 	return math.Min(stake.StartPoolDeposit*0.9, insurance)
 }
 
+// HasInsuranceReserve пул выплачивает компенсацию из отдельного резерва в USDT или TON
+func HasInsuranceReserve(pool *appModels.Pool) bool {
+	return pool.InsuranceAsset == appModels.INSURANCE_ASSET_USDT || pool.InsuranceAsset == appModels.INSURANCE_ASSET_TON
+}
+
+// CalculateInsuranceInAsset считает компенсацию в активе страхового резерва по цене на момент закрытия стейка
+func CalculateInsuranceInAsset(stake *appModels.Stake) float64 {
+	if stake.InsuranceAssetPrice <= 0 {
+		return 0
+	}
+	internalValue := stake.Amount * stake.DepositCreationPrice
+	currentValue := stake.Amount * stake.JettonPriceClosed
+	loss := math.Min(internalValue-currentValue, internalValue*0.9)
+	if loss < 0 {
+		return 0
+	}
+	return loss / stake.InsuranceAssetPrice
+}
+
+// CalculateInsuranceObligations сумма компенсаций в активе страхового резерва, которые еще не выплачены
+func CalculateInsuranceObligations(stakes *[]appModels.Stake, p *appModels.Pool) float64 {
+	res := 0.
+	if !HasInsuranceReserve(p) {
+		return res
+	}
+	for _, stake := range *stakes {
+		if stake.IsActive || stake.IsRewardPaid || stake.IsInsurancePaid {
+			continue
+		}
+		if CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice) < float64(p.InsuranceCoating)*-1 {
+			res += CalculateInsuranceInAsset(&stake)
+		}
+	}
+	return res
+}
+
 func RemoveZeroFloat(number float64) string {
 	num, _ := strconv.ParseFloat(fmt.Sprintf("%.9f", number), 64)
 	str := strconv.FormatFloat(num, 'f', -1, 64)
@@ -44,7 +92,7 @@ func CalculateSumStakesFromPool(stakes *[]appModels.Stake, p *appModels.Pool) fl
 	res := 0.
 	for _, stake := range *stakes {
 		if !stake.IsActive && !stake.IsRewardPaid && !stake.IsInsurancePaid {
-			if CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice) < float64(p.InsuranceCoating)*-1 {
+			if !HasInsuranceReserve(p) && CalculateProcientEditPrice(stake.JettonPriceClosed, stake.DepositCreationPrice) < float64(p.InsuranceCoating)*-1 {
 				am := CalculateInsurance(p, &stake)
 				profit := stake.Balance - stake.Amount
 				res += am + profit
@@ -65,6 +113,28 @@ func CalculateSumStakesFromPool(stakes *[]appModels.Stake, p *appModels.Pool) fl
 	return res
 }
 
+// AvailableReserve свободный резерв пула за вычетом обязательств по стейкам
+func AvailableReserve(p *appModels.Pool, stakes []appModels.Stake) float64 {
+	res := p.Reserve - CalculateSumStakesFromPool(&stakes, p)
+	if res < 0 {
+		return 0
+	}
+	return res
+}
+
+// StakeTerms ставка (% в день) и срок холда, действовавшие при открытии стейка
+func StakeTerms(stake *appModels.Stake, pool *appModels.Pool) (float64, uint) {
+	if stake.Reward > 0 && stake.Period > 0 {
+		return stake.Reward, stake.Period
+	}
+	return pool.Reward, pool.Period
+}
+
+// MaxStakeAmount максимальная сумма нового стейка: не больше 5% от свободного резерва пула
+func MaxStakeAmount(p *appModels.Pool, sumStakes float64) float64 {
+	return (p.Reserve - sumStakes) * 0.05
+}
+
 func ReplaceThreeZerosToK(num int64) string {
 	kCount := 0
 	n := float64(num)
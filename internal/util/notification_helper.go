@@ -0,0 +1,112 @@
+package util
+
+import (
+	"math"
+	"strings"
+	"text/template"
+	"time"
+	"tonclient/internal/i18n"
+	appModel "tonclient/internal/models"
+	"tonclient/internal/services"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var notificationFuncs = template.FuncMap{
+	"num": RemoveZeroFloat,
+	// pct модуль изменения цены с одним знаком после запятой
+	"pct":  func(v float64) string { return RemoveZeroFloat(math.Round(math.Abs(v)*10) / 10) },
+	"date": func(t time.Time) string { return t.Format("02.01.2006 15:04") },
+}
+
+// notificationTemplates шаблоны уведомлений по языкам и событиям из каталога i18n
+var notificationTemplates = parseNotificationTemplates()
+
+func parseNotificationTemplates() map[string]map[string]*template.Template {
+	res := make(map[string]map[string]*template.Template, len(i18n.Languages))
+	for _, lang := range i18n.Languages {
+		res[lang] = make(map[string]*template.Template)
+		messages := i18n.Messages(lang)
+		for _, event := range services.NotificationEvents {
+			if text, ok := messages[NotificationKey(event)]; ok {
+				res[lang][event] = notificationTemplate(text)
+			}
+		}
+	}
+	return res
+}
+
+// NotificationKey ключ шаблона уведомления о событии в каталоге i18n
+func NotificationKey(event string) string {
+	return "notify." + event
+}
+
+func notificationTemplate(text string) *template.Template {
+	return template.Must(template.New("").Funcs(notificationFuncs).Parse(text))
+}
+
+// NotificationText текст уведомления о событии по шаблону на языке lang
+func NotificationText(lang, event string, data *appModel.NotificationData) string {
+	tmpl, ok := notificationTemplates[lang][event]
+	if !ok {
+		if tmpl, ok = notificationTemplates[i18n.Default][event]; !ok {
+			return ""
+		}
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		log.Error("Failed to render notification: ", err)
+		return ""
+	}
+	return sb.String()
+}
+
+// NotificationEventName название события на экране настроек, ключ каталога i18n
+func NotificationEventName(event string) string {
+	switch event {
+	case appModel.NOTIFY_STAKE_CREATED:
+		return "Создание стейка"
+	case appModel.NOTIFY_DAILY_ACCRUAL:
+		return "Ежедневные начисления"
+	case appModel.NOTIFY_MATURITY_SOON:
+		return "Скорое окончание стейка"
+	case appModel.NOTIFY_MATURED:
+		return "Окончание стейка"
+	case appModel.NOTIFY_INSURANCE:
+		return "Срабатывание страховки"
+	case appModel.NOTIFY_PRICE_ALERT:
+		return "Падение цены к порогу страховки"
+	case appModel.NOTIFY_POOL_RESERVE:
+		return "Низкий резерв пула"
+	case appModel.NOTIFY_POOL_PAUSED:
+		return "Приостановка пула"
+	}
+	return event
+}
+
+// Notify ставит в очередь уведомление о событии по шаблону на языке пользователя, если он его не отключил
+func Notify(b *bot.Bot, userId uint64, event string, data *appModel.NotificationData) {
+	NotifyMarkup(b, userId, event, NotificationText(UserLang(userId), event, data), nil)
+}
+
+// NotifyMarkup уведомление о событии с готовым текстом и кнопками. Текст готовится на языке UserLang
+func NotifyMarkup(b *bot.Bot, userId uint64, event, text string, markup models.ReplyMarkup) {
+	if text == "" {
+		return
+	}
+	senderMu.RLock()
+	ts, ns := senderTs, senderNs
+	senderMu.RUnlock()
+	if ts == nil {
+		return
+	}
+	if ns != nil && !ns.IsEnabled(userId, event) {
+		return
+	}
+	tg, err := ts.GetByUserId(userId)
+	if err != nil {
+		return
+	}
+	QueueTextMessageMarkup(b, tg.TelegramId, text, markup)
+}
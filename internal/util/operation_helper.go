@@ -2,23 +2,60 @@ package util
 
 import (
 	"fmt"
+	"tonclient/internal/i18n"
 	appModel "tonclient/internal/models"
+	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
 
 	"github.com/go-telegram/bot/models"
 )
 
-func GenerateOperationButtons(operation []appModel.Operation) []models.InlineKeyboardButton {
+func GenerateOperationButtons(lang string, operation []appModel.Operation) []models.InlineKeyboardButton {
 	res := make([]models.InlineKeyboardButton, 0, 5)
 	for _, op := range operation {
 		if !op.Id.Valid {
 			continue
 		}
 		opId := op.Id.Int64
-		text := fmt.Sprintf("%v %v", op.Name, op.CreatedAt.Format("02.01.2006 15:04:05"))
+		text := fmt.Sprintf("%v %v", services.OperationName(lang, op.NumOperation), op.CreatedAt.Format("02.01.2006 15:04:05"))
 		idButton := fmt.Sprintf("%v:%v", buttons.OpenOperationHistory, opId)
 		res = append(res, CreateDefaultButton(idButton, text))
 	}
 
 	return res
 }
+
+// TxIntentMessage сообщение пользователю о судьбе транзакции, отправленной на подпись
+func TxIntentMessage(lang string, intent *appModel.TxIntent) string {
+	name := services.OperationName(lang, int(intent.OperationType))
+	switch intent.Status {
+	case appModel.TX_INTENT_REJECTED:
+		return i18n.T(lang, "❌ Транзакция «%v» отклонена в кошельке. Средства не списаны", name)
+	case appModel.TX_INTENT_SIGNED:
+		return i18n.T(lang, "⏳ Транзакция «%v» подписана и отправлена в сеть. Сообщим, когда она будет выполнена", name)
+	case appModel.TX_INTENT_CONFIRMED:
+		return i18n.T(
+			lang,
+			"✅ Транзакция «%v» выполнена в сети. Зачисление произойдет после поступления средств\n\n<a href=\"https://tonviewer.com/transaction/%v\">Открыть в обозревателе</a>",
+			name,
+			intent.TxHash,
+		)
+	case appModel.TX_INTENT_FAILED:
+		if intent.Memo != "" {
+			return i18n.T(lang, "❌ Код %v для операции «%v» истек: переводы с ним не поступили вовремя. Если вы уже отправили средства, обратитесь в поддержку", intent.Memo, name)
+		}
+		if intent.MsgHash == "" {
+			return i18n.T(lang, "❌ Транзакция «%v» не была подписана: кошелек не ответил или произошла ошибка. Средства не списаны, повторите попытку", name)
+		}
+		if intent.TxHash == "" {
+			return i18n.T(lang, "❌ Транзакция «%v» не появилась в сети и истекла. Средства не списаны, повторите попытку", name)
+		}
+		return i18n.T(
+			lang,
+			"❌ Транзакция «%v» не выполнена в сети. Проверьте баланс кошелька и повторите попытку\n\n<a href=\"https://tonviewer.com/transaction/%v\">Открыть в обозревателе</a>",
+			name,
+			intent.TxHash,
+		)
+	}
+	return ""
+}
@@ -1,10 +1,15 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"tonclient/internal/dyor"
+	"tonclient/internal/i18n"
 	appModels "tonclient/internal/models"
 	"tonclient/internal/services"
 	"tonclient/internal/tonbot/buttons"
@@ -15,26 +20,27 @@ import (
 	"golang.org/x/text/message"
 )
 
-func generateNamePool(pool *appModels.Pool, aws *services.AdminWalletService, subSum float64) string {
+func generateNamePool(lang string, pool *appModels.Pool, aws *services.AdminWalletService, subSum float64) string {
 	jettonData, err := aws.DataJetton(pool.JettonMaster)
 	currentReserve := pool.Reserve - subSum
 	if currentReserve < 0 {
 		currentReserve = 0
 	}
 	if err != nil {
-		return "Без названия"
+		return i18n.T(lang, "Без названия")
 	}
-	return fmt.Sprintf(
+	return i18n.T(
+		lang,
 		"%v (%d %v / %v%% / резерв %v)",
 		jettonData.Name,
 		pool.Period,
-		SuffixDay(int(pool.Period)),
+		SuffixDay(lang, int(pool.Period)),
 		pool.Reward,
 		ReplaceThreeZerosToK(int64(math.Floor(currentReserve))),
 	)
 }
 
-func GeneratePoolButtons(pool *[]appModels.Pool, aws *services.AdminWalletService, suf string, ss *services.StakeService) []models.InlineKeyboardButton {
+func GeneratePoolButtons(lang string, pool *[]appModels.Pool, aws *services.AdminWalletService, suf string, ss *services.StakeService) []models.InlineKeyboardButton {
 	res := make([]models.InlineKeyboardButton, 0, len(*pool))
 	for _, p := range *pool {
 		if !p.Id.Valid {
@@ -48,22 +54,24 @@ func GeneratePoolButtons(pool *[]appModels.Pool, aws *services.AdminWalletServic
 			res,
 			CreateDefaultButton(
 				fmt.Sprintf("%v:%d:%v", buttons.PoolDataButton, poolId, suf),
-				generateNamePool(&p, aws, subSubStake),
+				generateNamePool(lang, &p, aws, subSubStake),
 			),
 		)
 	}
 	return res
 }
 
-func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModels.JettonData) string {
+func PoolInfo(lang string, p *appModels.Pool, ss *services.StakeService, jettonData *appModels.JettonData, tiers []appModels.RewardTier) string {
 	allStakesPool := ss.GetPoolStakes(uint64(p.Id.Int64))
 	var sumAmount float64
 	subReserve := 0.
 
+	stakers := make(map[uint64]bool)
 	if allStakesPool != nil {
 		for _, stake := range allStakesPool {
 			if stake.IsActive {
 				sumAmount += stake.Amount
+				stakers[stake.UserId] = true
 			}
 		}
 		subReserve = CalculateSumStakesFromPool(&allStakesPool, p)
@@ -88,9 +96,9 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 
 	var status string
 	if p.IsActive {
-		status = "✅ Активен"
+		status = i18n.T(lang, "✅ Активен")
 	} else {
-		status = "⏳ Пул не активен"
+		status = i18n.T(lang, "⏳ Пул не активен")
 	}
 
 	jettonInfo, err := tonfi.GetAssetByAddr(p.JettonMaster)
@@ -120,6 +128,52 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 		}
 	}
 
+	insuranceText := i18n.T(lang, "До 90% от суммы стейка.")
+	insuranceReserveText := ""
+	if HasInsuranceReserve(p) {
+		assetName := InsuranceAssetName(lang, p.InsuranceAsset)
+		obligations := 0.
+		if allStakesPool != nil {
+			obligations = CalculateInsuranceObligations(&allStakesPool, p)
+		}
+		freeInsurance := p.InsuranceReserve - obligations
+		if freeInsurance < 0 {
+			freeInsurance = 0
+		}
+		insuranceText = i18n.T(
+			lang,
+			"До 90%% от стоимости стейка в $ на момент входа. Выплачивается в %v по курсу на момент закрытия стейка.",
+			assetName,
+		)
+		insuranceReserveText = i18n.T(
+			lang,
+			"\n🛡 Страховой резерв (%v):\n •	Зарезервировано на выплаты: %v %v\n •	Свободно: %v %v\n",
+			assetName,
+			foramter.Sprintf("%v", RemoveZeroFloat(obligations)),
+			assetName,
+			foramter.Sprintf("%v", RemoveZeroFloat(freeInsurance)),
+			assetName,
+		)
+	}
+
+	limitsText := PoolLimits(lang, p, sumAmount, len(stakers))
+	if limitsText != "" {
+		limitsText = i18n.T(lang, "\n<b>🚧 Лимиты:</b>\n") + limitsText
+	}
+
+	rewardText := i18n.T(lang, "%v%% в день начисляется на застейканую сумму.", RemoveZeroFloat(p.Reward))
+	periodText := fmt.Sprintf("%v %v", p.Period, SuffixDay(lang, int(p.Period)))
+	if len(tiers) > 0 {
+		rewardText = i18n.T(lang, "Ставка в день начисляется на застейканую сумму и фиксируется при открытии стейка:\n") +
+			strings.TrimSuffix(RewardTiersInfo(lang, p, tiers, 0), "\n")
+		periods := StakePeriods(p, tiers)
+		periodsText := make([]string, 0, len(periods))
+		for _, period := range periods {
+			periodsText = append(periodsText, fmt.Sprint(period))
+		}
+		periodText = i18n.T(lang, "%v %v на выбор", strings.Join(periodsText, ", "), SuffixDay(lang, int(periods[len(periods)-1])))
+	}
+
 	reliability := (p.Reserve / (jettonData.TotalSupply / (10e+8))) / 0.72 * 100
 	reliability = math.Min(reliability, 100)
 
@@ -137,7 +191,8 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 	//	level = "высокий"
 	//}
 
-	res := fmt.Sprintf(
+	res := i18n.T(
+		lang,
 		`
 <b> 📦 Описание пула %v: </b>
 
@@ -145,11 +200,14 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 <b>Текущая цена токена:</b> %v$
 
 <b>📈 Доходность: </b>
-%v%% в день начисляется на застейканую сумму.
+%v
 
 <b>⏳Срок холда:</b>
-%v %v с возможностью досрочного вывода (но тогда без награды за стейкинг).
+%v с возможностью досрочного вывода.
 
+<b>🚪 Досрочный выход:</b>
+%v
+%v
 <b>💵 Минимальный размер стейка </b>
 %v %v
 
@@ -157,25 +215,28 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 Если цена токена упадет более чем на %v%% к моменту окончания стейкинга, вам будет выплачена компенсация
 
 <b>💸 Максимальная компенсация:</b>
-До 90%% от суммы стейка.
+%v
 
 🔒 Резерв пула:
  •	Заблокировано участниками: %v токенов
  •	Доступно для новых стейков: %v токенов
  •  Общий резерв: %v
-`,
+%v`,
 		jettonInfo.DisplayName,
 		status,
 		RemoveZeroFloat(price),
-		RemoveZeroFloat(p.Reward),
-		p.Period,
-		SuffixDay(int(p.Period)),
+		rewardText,
+		periodText,
+		EarlyExitRules(lang, p),
+		limitsText,
 		RemoveZeroFloat(p.MinStakeAmount),
 		p.JettonName,
 		p.InsuranceCoating,
+		insuranceText,
 		ut,
 		reserve,
 		fullReserve,
+		insuranceReserveText,
 		//emoj,
 		//RemoveZeroFloat(reliability),
 		//level,
@@ -189,9 +250,195 @@ func PoolInfo(p *appModels.Pool, ss *services.StakeService, jettonData *appModel
 	return res
 }
 
-func GenerateOwnerPoolInlineKeyboard(poolId int64, backPoolListButtonId string, isActive, commissionPaid bool, sufData string) *models.InlineKeyboardMarkup {
+// StakePeriods сроки холда, доступные в пуле: базовый срок пула и сроки из тарифов
+func StakePeriods(p *appModels.Pool, tiers []appModels.RewardTier) []uint {
+	res := []uint{p.Period}
+	for _, t := range tiers {
+		if !slices.Contains(res, t.Period) {
+			res = append(res, t.Period)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+// RewardTiersInfo описание ставок пула по срокам и суммам. period == 0 - по всем срокам
+func RewardTiersInfo(lang string, p *appModels.Pool, tiers []appModels.RewardTier, period uint) string {
+	res := ""
+	if period == 0 || period == p.Period {
+		res += i18n.T(lang, " •	%v %v: %v%% в день\n", p.Period, SuffixDay(lang, int(p.Period)), RemoveZeroFloat(p.Reward))
+	}
+	for _, t := range tiers {
+		if period != 0 && t.Period != period {
+			continue
+		}
+		res += fmt.Sprintf(" •	%v %v", t.Period, SuffixDay(lang, int(t.Period)))
+		if t.MinAmount > 0 {
+			res += i18n.T(lang, " от %v %v", RemoveZeroFloat(t.MinAmount), p.JettonName)
+		}
+		res += i18n.T(lang, ": %v%% в день", RemoveZeroFloat(t.Reward))
+		if t.StartsAt.Valid {
+			res += i18n.T(lang, " (с %v)", t.StartsAt.Time.Format("02.01.2006"))
+		}
+		if t.CommissionType != "" || t.CommissionAsset != "" {
+			res += i18n.T(lang, ", комиссия ") + TierCommissionInfo(lang, p, &t)
+		}
+		res += "\n"
+	}
+	return res
+}
+
+// PoolLimits описание лимитов пула для карточки. Пустая строка - лимитов нет
+func PoolLimits(lang string, p *appModels.Pool, total float64, stakers int) string {
+	res := ""
+	if p.MaxTotalStake > 0 {
+		res += i18n.T(lang, " •	Всего в пуле: %v из %v %v\n", RemoveZeroFloat(total), RemoveZeroFloat(p.MaxTotalStake), p.JettonName)
+	}
+	if p.MaxUserStake > 0 {
+		res += i18n.T(lang, " •	На одного участника: до %v %v\n", RemoveZeroFloat(p.MaxUserStake), p.JettonName)
+	}
+	if p.MaxStakers > 0 {
+		res += i18n.T(lang, " •	Участников: %v из %v\n", stakers, p.MaxStakers)
+	}
+	if p.IsWhitelist {
+		res += i18n.T(lang, " •	Только для участников белого списка\n")
+	}
+	return res
+}
+
+// CommissionAssetName название валюты комиссии за стейк
+func CommissionAssetName(p *appModels.Pool, asset string) string {
+	switch asset {
+	case appModels.COMMISSION_ASSET_TON:
+		return "TON"
+	case appModels.COMMISSION_ASSET_STAKE:
+		return p.JettonName
+	default:
+		return os.Getenv("JETTON_NAME_COIN")
+	}
+}
+
+// CommissionPolicyInfo условия комиссии: "2 TON", "0.5% из суммы стейка", "1% от стейка в TON"
+func CommissionPolicyInfo(lang string, p *appModels.Pool, policy appModels.CommissionPolicy) string {
+	value := RemoveZeroFloat(policy.Value)
+	if policy.Type == appModels.COMMISSION_TYPE_PERCENT {
+		if policy.Asset == appModels.COMMISSION_ASSET_STAKE {
+			return i18n.T(lang, "%v%% из суммы стейка", value)
+		}
+		return i18n.T(lang, "%v%% от стейка в %v", value, CommissionAssetName(p, policy.Asset))
+	}
+	res := fmt.Sprintf("%v %v", value, CommissionAssetName(p, policy.Asset))
+	if policy.Asset == appModels.COMMISSION_ASSET_STAKE {
+		res += i18n.T(lang, " из суммы стейка")
+	}
+	return res
+}
+
+// TierCommissionInfo комиссия за стейк по тарифу
+func TierCommissionInfo(lang string, p *appModels.Pool, t *appModels.RewardTier) string {
+	return CommissionPolicyInfo(lang, p, services.TierCommissionPolicy(t))
+}
+
+// StakeCommissionInfo рассчитанная комиссия за стейк для сообщения пользователю
+func StakeCommissionInfo(lang string, p *appModels.Pool, c *appModels.StakeCommission) string {
+	res := fmt.Sprintf("%v %v", RemoveZeroFloat(c.Amount), CommissionAssetName(p, c.Asset))
+	if c.Asset == appModels.COMMISSION_ASSET_STAKE {
+		res += i18n.T(lang, " (удерживается из суммы стейка)")
+	}
+	if c.Discount > 0 {
+		res += i18n.T(lang, ", с учетом скидки %v%% по приглашению", RemoveZeroFloat(c.Discount))
+	}
+	return res
+}
+
+// PoolLimitErrorText причина отказа в стейке по лимитам пула
+func PoolLimitErrorText(lang string, err error, p *appModels.Pool) string {
+	switch {
+	case errors.Is(err, services.ErrPoolMaxTotalStake):
+		return i18n.T(lang, "❌ Пул заполнен: общий лимит %v %v", RemoveZeroFloat(p.MaxTotalStake), p.JettonName)
+	case errors.Is(err, services.ErrPoolMaxUserStake):
+		return i18n.T(lang, "❌ Превышен лимит на одного участника: %v %v", RemoveZeroFloat(p.MaxUserStake), p.JettonName)
+	case errors.Is(err, services.ErrPoolMaxStakers):
+		return i18n.T(lang, "❌ В пуле уже максимальное число участников: %v", p.MaxStakers)
+	case errors.Is(err, services.ErrNotWhitelisted):
+		return i18n.T(lang, "❌ Этот пул доступен только участникам белого списка")
+	case errors.Is(err, services.ErrStakeTerms), errors.Is(err, services.ErrCommissionMismatch):
+		return i18n.T(lang, "❌ Условия стейка не совпадают с текущими условиями пула")
+	case errors.Is(err, services.ErrCommissionNotPaid):
+		return i18n.T(lang, "❌ Комиссия за стейк не поступила")
+	default:
+		return i18n.T(lang, "❌ Не удалось проверить лимиты пула. Повторите попытку позже!")
+	}
+}
+
+// EarlyExitRules описание условий досрочного выхода из пула
+func EarlyExitRules(lang string, p *appModels.Pool) string {
+	res := ""
+	if p.EarlyExitMinDays > 0 {
+		res += i18n.T(lang, " •	Доступен через %v %v после старта стейка\n", p.EarlyExitMinDays, SuffixDay(lang, int(p.EarlyExitMinDays)))
+	} else {
+		res += i18n.T(lang, " •	Доступен в любой момент\n")
+	}
+	if p.EarlyExitPenalty > 0 {
+		res += i18n.T(lang, " •	Штраф: %v%% от выводимой суммы\n", RemoveZeroFloat(p.EarlyExitPenalty))
+	} else {
+		res += i18n.T(lang, " •	Без штрафа\n")
+	}
+	if p.EarlyExitProRata {
+		res += i18n.T(lang, " •	Начисленная награда выплачивается пропорционально сроку\n")
+	} else {
+		res += i18n.T(lang, " •	Награда за стейкинг не выплачивается\n")
+	}
+	if p.EarlyExitPartial {
+		res += i18n.T(lang, " •	Можно вывести часть депозита")
+	} else {
+		res += i18n.T(lang, " •	Выводится только весь депозит")
+	}
+	return res
+}
+
+// EarlyExitQuoteText расчет выплаты при досрочном выходе для показа стейкеру
+func EarlyExitQuoteText(lang string, q *appModels.EarlyExitQuote, jettonName string) string {
+	res := i18n.T(lang, "<b>Выводимая сумма</b>: %v %v", RemoveZeroFloat(q.Amount), jettonName)
+	if q.Penalty > 0 {
+		res += i18n.T(lang, "\n<b>Штраф</b>: -%v %v", RemoveZeroFloat(q.Penalty), jettonName)
+	}
+	if q.Reward > 0 {
+		res += i18n.T(lang, "\n<b>Награда</b>: +%v %v", RemoveZeroFloat(q.Reward), jettonName)
+	}
+	if q.Forfeited > 0 {
+		res += i18n.T(lang, "\n<b>Сгорает награды</b>: %v %v", RemoveZeroFloat(q.Forfeited), jettonName)
+	}
+	res += i18n.T(lang, "\n- Нет компенсации падения цены")
+	res += i18n.T(lang, "\n<b>💎 К выплате</b>: %v %v", RemoveZeroFloat(q.Payout), jettonName)
+	if q.IsPartial {
+		res += i18n.T(lang, "\n<b>Останется в стейке</b>: %v %v", RemoveZeroFloat(q.Remaining), jettonName)
+	}
+	return res
+}
+
+func InsuranceAssetName(lang, asset string) string {
+	switch asset {
+	case appModels.INSURANCE_ASSET_USDT:
+		return "USDT"
+	case appModels.INSURANCE_ASSET_TON:
+		return "TON"
+	default:
+		return i18n.T(lang, "токен пула")
+	}
+}
+
+func GenerateOwnerPoolInlineKeyboard(lang string, poolId int64, backPoolListButtonId string, isActive, commissionPaid bool, insuranceAsset, sufData string) *models.InlineKeyboardMarkup {
 	paidCommision := CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.PaidCommissionId, poolId), buttons.PaidCommission)
 	addReserve := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.AddReserveId, poolId, sufData), buttons.AddReserve)
+	addInsuranceReserve := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.AddInsuranceReserveId, poolId, sufData), buttons.AddInsuranceReserve)
+	insuranceAssetBtn := CreateDefaultButton(
+		fmt.Sprintf("%v:%v:%v", buttons.InsuranceAssetId, poolId, sufData),
+		i18n.T(lang, buttons.InsuranceAsset)+InsuranceAssetName(lang, insuranceAsset),
+	)
+	limits := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.PoolLimitsId, poolId, sufData), buttons.PoolLimits)
+	rewardTiers := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.RewardTiersId, poolId, sufData), buttons.RewardTiers)
+	earlyExit := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.EarlyExitSettingId, poolId, sufData), buttons.EarlyExitSetting)
 	var closePoolText string
 	if isActive {
 		closePoolText = buttons.ClosePool
@@ -202,12 +449,19 @@ func GenerateOwnerPoolInlineKeyboard(poolId int64, backPoolListButtonId string,
 	closePool := CreateDefaultButton(fmt.Sprintf("%v:%v:%v", buttons.ClosePoolId, poolId, sufData), closePoolText)
 	backListPools := CreateDefaultButton(backPoolListButtonId, buttons.BackPoolList)
 	deletePool := CreateDefaultButton(fmt.Sprintf("%v:%v", buttons.DeletePoolId, poolId), buttons.DeletePool)
-	btns := make([]models.InlineKeyboardButton, 0, 5)
+	btns := make([]models.InlineKeyboardButton, 0, 11)
 	if !commissionPaid {
 		btns = append(btns, paidCommision)
 	}
 
 	btns = append(btns, addReserve)
+	if insuranceAsset == appModels.INSURANCE_ASSET_USDT || insuranceAsset == appModels.INSURANCE_ASSET_TON {
+		btns = append(btns, addInsuranceReserve)
+	}
+	btns = append(btns, insuranceAssetBtn)
+	btns = append(btns, rewardTiers)
+	btns = append(btns, limits)
+	btns = append(btns, earlyExit)
 	btns = append(btns, closePool)
 	btns = append(btns, takeTokens)
 	btns = append(btns, deletePool)
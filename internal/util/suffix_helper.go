@@ -3,16 +3,23 @@ package util
 import (
 	"strconv"
 	"strings"
+	"tonclient/internal/i18n"
 )
 
-func SuffixDay(num int) string {
+func SuffixDay(lang string, num int) string {
+	if lang == i18n.EN {
+		return suffixEn(num, "day", "days")
+	}
 	a := "дней"
 	b := "дня"
 	def := "день"
 	return suffix(num, a, b, def)
 }
 
-func SuffixPol(num int) string {
+func SuffixPol(lang string, num int) string {
+	if lang == i18n.EN {
+		return suffixEn(num, "pool", "pools")
+	}
 	a := "пулов"
 	b := "пула"
 	def := "пул"
@@ -39,3 +46,10 @@ func suffix(num int, var1, var2, var3 string) string {
 
 	return var3
 }
+
+func suffixEn(num int, one, many string) string {
+	if num == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,219 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"tonclient/internal/config"
+	"tonclient/internal/services"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// RateLimiter лимиты Bot API: общий на бота и на каждый чат с небольшим запасом подряд
+type RateLimiter struct {
+	mu     sync.Mutex
+	global time.Duration // интервал между любыми сообщениями бота
+	chat   time.Duration // интервал между сообщениями в один чат
+	burst  int
+	next   time.Time
+	chats  map[uint64]time.Time // расчетное время следующего сообщения в чат
+}
+
+func NewRateLimiter(cfg *config.TelegramSenderConfig) *RateLimiter {
+	return &RateLimiter{
+		global: time.Second / time.Duration(max(cfg.GlobalRate, 1)),
+		chat:   cfg.ChatInterval,
+		burst:  max(cfg.ChatBurst, 1),
+		chats:  make(map[uint64]time.Time),
+	}
+}
+
+// Reserve занимает слот отправки в чат и возвращает, сколько ждать до него
+func (l *RateLimiter) Reserve(chatId uint64, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tat := l.chats[chatId]
+	if tat.Before(now) {
+		tat = now
+	}
+	at := tat.Add(-l.chat * time.Duration(l.burst-1))
+	if at.Before(now) {
+		at = now
+	}
+	if at.Before(l.next) {
+		at = l.next
+	}
+	l.next = at.Add(l.global)
+	if tat.Before(at) {
+		tat = at
+	}
+	l.chats[chatId] = tat.Add(l.chat)
+
+	if len(l.chats) > 10000 {
+		for id, t := range l.chats {
+			if t.Before(now) {
+				delete(l.chats, id)
+			}
+		}
+	}
+	return at.Sub(now)
+}
+
+// Pause откладывает сообщения в чат на время retry_after из ответа 429
+func (l *RateLimiter) Pause(chatId uint64, d time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tat := now.Add(d + l.chat*time.Duration(l.burst-1))
+	if tat.After(l.chats[chatId]) {
+		l.chats[chatId] = tat
+	}
+}
+
+type outboxMessage struct {
+	bt     *bot.Bot
+	chatId uint64
+	text   string
+	markup models.ReplyMarkup
+}
+
+var (
+	senderOnce   sync.Once
+	senderCfg    *config.TelegramSenderConfig
+	limiter      *RateLimiter
+	senderMu     sync.RWMutex
+	senderTs     *services.TelegramService
+	senderNs     *services.NotificationService
+	outboxQueues []chan outboxMessage
+)
+
+func initSender() {
+	senderOnce.Do(func() {
+		senderCfg = config.LoadTelegramSenderConfig()
+		limiter = NewRateLimiter(senderCfg)
+	})
+}
+
+// StartSender запускает очередь уведомлений. Сообщения в один чат обрабатывает один воркер,
+// поэтому порядок уведомлений пользователю сохраняется. ns - настройки уведомлений для Notify
+func StartSender(ctx context.Context, ts *services.TelegramService, ns *services.NotificationService) {
+	initSender()
+
+	queues := make([]chan outboxMessage, senderCfg.Workers)
+	for i := range queues {
+		queues[i] = make(chan outboxMessage, senderCfg.QueueSize)
+		go func(queue chan outboxMessage) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case m := <-queue:
+					deliverQueued(m)
+				}
+			}
+		}(queues[i])
+	}
+
+	senderMu.Lock()
+	senderTs = ts
+	senderNs = ns
+	outboxQueues = queues
+	senderMu.Unlock()
+}
+
+// QueueTextMessage ставит уведомление в очередь. Пользователям, заблокировавшим бота, оно не отправляется
+func QueueTextMessage(bt *bot.Bot, chatId uint64, text string) {
+	QueueTextMessageMarkup(bt, chatId, text, nil)
+}
+
+func QueueTextMessageMarkup(bt *bot.Bot, chatId uint64, text string, markup models.ReplyMarkup) {
+	m := outboxMessage{bt: bt, chatId: chatId, text: text, markup: markup}
+
+	senderMu.RLock()
+	queues := outboxQueues
+	senderMu.RUnlock()
+	if len(queues) == 0 {
+		// очередь не запущена, отправляем сразу
+		go deliverQueued(m)
+		return
+	}
+	queues[chatId%uint64(len(queues))] <- m
+}
+
+func deliverQueued(m outboxMessage) {
+	senderMu.RLock()
+	ts := senderTs
+	senderMu.RUnlock()
+	if ts != nil && ts.IsBlocked(m.chatId) {
+		return
+	}
+
+	var err error
+	if m.markup == nil {
+		_, err = SendTextMessage(m.bt, m.chatId, m.text)
+	} else {
+		_, err = SendTextMessageMarkup(m.bt, m.chatId, m.text, m.markup)
+	}
+	if err != nil {
+		log.Error("Failed to deliver notification: ", err)
+	}
+}
+
+// sendLimited отправляет сообщение с учетом лимитов, ждет retry_after при 429 и повторяет
+// временные ошибки. Если пользователь заблокировал бота, он отмечается, чтобы уведомления прекратились
+func sendLimited(chatId uint64, timeout time.Duration, send func(ctx context.Context) (*models.Message, error)) (*models.Message, error) {
+	initSender()
+
+	for attempt := 0; ; attempt++ {
+		time.Sleep(limiter.Reserve(chatId, time.Now()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		message, err := send(ctx)
+		cancel()
+		if err == nil {
+			return message, nil
+		}
+
+		var tooMany *bot.TooManyRequestsError
+		switch {
+		case errors.As(err, &tooMany):
+			limiter.Pause(chatId, time.Duration(tooMany.RetryAfter)*time.Second, time.Now())
+		case errors.Is(err, bot.ErrorForbidden):
+			markBlocked(chatId)
+			return nil, err
+		case !isTransientSendError(err):
+			return nil, err
+		default:
+			time.Sleep(time.Duration(1<<attempt) * 500 * time.Millisecond)
+		}
+		if attempt >= senderCfg.Retries {
+			return nil, err
+		}
+	}
+}
+
+func markBlocked(chatId uint64) {
+	senderMu.RLock()
+	ts := senderTs
+	senderMu.RUnlock()
+	if ts == nil {
+		return
+	}
+	if err := ts.SetBlocked(chatId, true); err != nil {
+		log.Error("Failed to mark blocked user: ", err)
+	}
+}
+
+// isTransientSendError сетевые ошибки и ошибки сервера Telegram, которые имеет смысл повторить
+func isTransientSendError(err error) bool {
+	for _, e := range []error{bot.ErrorBadRequest, bot.ErrorUnauthorized, bot.ErrorForbidden, bot.ErrorNotFound, bot.ErrorConflict} {
+		if errors.Is(err, e) {
+			return false
+		}
+	}
+	return !bot.IsMigrateError(err)
+}
@@ -19,13 +19,12 @@ import (
 var log = config.InitLogger()
 
 func SendTextMessage(bt *bot.Bot, chatId uint64, text string) (*models.Message, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	message, err := bt.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    chatId,
-		Text:      text,
-		ParseMode: "HTML",
+	message, err := sendLimited(chatId, 10*time.Second, func(ctx context.Context) (*models.Message, error) {
+		return bt.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:    chatId,
+			Text:      text,
+			ParseMode: "HTML",
+		})
 	})
 	if err != nil {
 		log.Error("Failed to send message: ", err)
@@ -35,15 +34,16 @@ func SendTextMessage(bt *bot.Bot, chatId uint64, text string) (*models.Message,
 	return message, nil
 }
 
+// SendTextMessageMarkup отправляет сообщение с кнопками, подписи кнопок переводятся на язык чата
 func SendTextMessageMarkup(bt *bot.Bot, chatId uint64, text string, markup models.ReplyMarkup) (*models.Message, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	message, err := bt.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatId,
-		Text:        text,
-		ParseMode:   "HTML",
-		ReplyMarkup: markup,
+	markup = localizeMarkup(chatId, markup)
+	message, err := sendLimited(chatId, 10*time.Second, func(ctx context.Context) (*models.Message, error) {
+		return bt.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatId,
+			Text:        text,
+			ParseMode:   "HTML",
+			ReplyMarkup: markup,
+		})
 	})
 
 	if err != nil {
@@ -53,13 +53,32 @@ func SendTextMessageMarkup(bt *bot.Bot, chatId uint64, text string, markup model
 	return message, nil
 }
 
+// SendPhotoUrlMarkup картинка по ссылке, Telegram загружает ее сам
+func SendPhotoUrlMarkup(bt *bot.Bot, chatId uint64, photoUrl, caption string, markup models.ReplyMarkup) (*models.Message, error) {
+	markup = localizeMarkup(chatId, markup)
+	message, err := sendLimited(chatId, 20*time.Second, func(ctx context.Context) (*models.Message, error) {
+		return bt.SendPhoto(ctx, &bot.SendPhotoParams{
+			ChatID:      chatId,
+			Photo:       &models.InputFileString{Data: photoUrl},
+			Caption:     caption,
+			ParseMode:   "HTML",
+			ReplyMarkup: markup,
+		})
+	})
+	if err != nil {
+		log.Error("Failed to send photo: ", err)
+		return nil, err
+	}
+	return message, nil
+}
+
 func CheckTypeMessage(b *bot.Bot, callback *models.CallbackQuery) error {
 	msgType := callback.Message.Type
 	if msgType == models.MaybeInaccessibleMessageTypeInaccessibleMessage {
 		if _, err := SendTextMessage(
 			b,
 			uint64(callback.From.ID),
-			"❌ Не могу обработать данное сообщение! Скорее всего оно мне не доступно!"); err != nil {
+			T(callback.From.ID, "❌ Не могу обработать данное сообщение! Скорее всего оно мне не доступно!")); err != nil {
 			log.Error(err)
 		}
 		return errors.New("message type inaccessible")
@@ -81,6 +100,7 @@ func DeleteMessage(ctx context.Context, b *bot.Bot, chatId uint64, messageId int
 }
 
 func EditMessageMarkup(ctx context.Context, b *bot.Bot, chatId uint64, messageId int, markup models.ReplyMarkup) error {
+	markup = localizeMarkup(chatId, markup)
 	if _, err := b.EditMessageReplyMarkup(
 		ctx,
 		&bot.EditMessageReplyMarkupParams{
@@ -112,6 +132,7 @@ func EditTextMessage(ctx context.Context, b *bot.Bot, chatId uint64, messageId i
 }
 
 func EditTextMessageMarkup(ctx context.Context, b *bot.Bot, chatId uint64, messageId int, message string, markup models.ReplyMarkup) error {
+	markup = localizeMarkup(chatId, markup)
 	if _, err := b.EditMessageText(
 		ctx,
 		&bot.EditMessageTextParams{
@@ -132,48 +153,44 @@ func ConnectingTonConnect(b *bot.Bot, chatId uint64, tcs *services.TonConnectSer
 	sessionTonConnect, err := tcs.CreateSession()
 	if err != nil {
 		log.Error(err)
-		if _, err := SendTextMessage(b, chatId, "❌ Что-то пошло не так. Попробуйте повторить попытку!"); err != nil {
+		if _, err := SendTextMessage(b, chatId, T(chatId, "❌ Что-то пошло не так. Попробуйте повторить попытку!")); err != nil {
 			log.Error(err)
 		}
 		return nil, err
 	}
 
-	urls, err := tcs.GenerateConnectUrls(sessionTonConnect)
+	key := fmt.Sprint(chatId)
+	links, err := tcs.GenerateConnectUrls(key, sessionTonConnect)
 	if err != nil {
 		log.Error(err)
-		if _, err := SendTextMessage(b, chatId, "❌ Произошла ошибка генерации ссылок, для подключения кошелька. Повторите попытку!"); err != nil {
+		if _, err := SendTextMessage(b, chatId, T(chatId, "❌ Произошла ошибка генерации ссылок, для подключения кошелька. Повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return nil, err
 	}
 
-	btns := make([]models.InlineKeyboardButton, 0, 2)
-	for k, v := range urls {
-		btn2 := CreateUrlInlineButton(
-			fmt.Sprintf("%v: %v", buttons.OpenWallet, k),
-			v,
-		)
-		btns = append(btns, btn2)
-	}
-
-	markup := MenuWithBackButton(buttons.DefCloseId, buttons.DefCloseText, btns...)
-	if _, err := SendTextMessageMarkup(b, chatId, "Выберите кошелек, который хотите подключить: ", markup); err != nil {
+	markup := ConnectWalletsMarkup(links)
+	if _, err := SendTextMessageMarkup(b, chatId, T(chatId, "Выберите кошелек, который хотите подключить: "), markup); err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
-	res, err := tcs.Connect(sessionTonConnect)
+	res, err := tcs.Connect(key, sessionTonConnect)
 	if err != nil {
 		log.Error(err)
-		if _, err := SendTextMessage(b, chatId, "❌ Произошла ошибка подключения. Повторите попытку!"); err != nil {
+		resp := "❌ Произошла ошибка подключения. Повторите попытку!"
+		if services.IsProofError(err) {
+			resp = "❌ Кошелек не подтвердил владение адресом. Повторите подключение!"
+		}
+		if _, err := SendTextMessage(b, chatId, T(chatId, resp)); err != nil {
 			log.Error(err)
 		}
 		return nil, err
 	}
-	err = tcs.SaveSession(fmt.Sprint(chatId), sessionTonConnect)
+	err = tcs.SaveSession(key, sessionTonConnect)
 	if err != nil {
 		log.Error(err)
-		if _, err := SendTextMessage(b, chatId, "❌ Произошла ошибка при подключении, повторите попытку!"); err != nil {
+		if _, err := SendTextMessage(b, chatId, T(chatId, "❌ Произошла ошибка при подключении, повторите попытку!")); err != nil {
 			log.Error(err)
 		}
 		return nil, err
@@ -182,11 +199,32 @@ func ConnectingTonConnect(b *bot.Bot, chatId uint64, tcs *services.TonConnectSer
 	return res, nil
 }
 
+// SendSessionLost сообщает, что сессия TonConnect потеряна или устарела, и предлагает переподключить кошелек.
+// Для прочих ошибок загрузки сессии отправляет fallback. action - текст из каталога на русском
+func SendSessionLost(b *bot.Bot, chatId uint64, err error, action string) {
+	text := T(chatId, "❌ Не удалось получить соединение с TonConnect. Повторите попытку позже!")
+	var markup models.ReplyMarkup
+	if services.IsSessionLost(err) {
+		text = T(chatId, "❌ Соединение с кошельком через TonConnect истекло или было отключено. Переподключите кошелек и ") + T(chatId, action)
+		markup = CreateInlineMarup(1, CreateDefaultButton(buttons.LinkTonConnectId, buttons.LinkTonConnect))
+	}
+
+	if markup == nil {
+		if _, err := SendTextMessage(b, chatId, text); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	if _, err := SendTextMessageMarkup(b, chatId, text, markup); err != nil {
+		log.Error(err)
+	}
+}
+
 func RequestRepeatTonConnect(b *bot.Bot, chatId int64, markup *models.InlineKeyboardMarkup, tcs *services.TonConnectService) error {
 	if _, err := SendTextMessageMarkup(
 		b,
 		uint64(chatId),
-		"❌ Возможно вы отключили TonConnect! Подтвердите подключение снова! А затем нажмите <b>Повторить попытку</b>",
+		T(chatId, "❌ Возможно вы отключили TonConnect! Подтвердите подключение снова! А затем нажмите <b>Повторить попытку</b>"),
 		markup,
 	); err != nil {
 		log.Error(err)
@@ -199,7 +237,7 @@ func RequestRepeatTonConnect(b *bot.Bot, chatId int64, markup *models.InlineKeyb
 	if _, err := SendTextMessageMarkup(
 		b,
 		uint64(chatId),
-		"✅ Кошелек привязан. Нажмите 'повторить попытку' и подтвердите транзакцию по резерву в привязанном кошельке",
+		T(chatId, "✅ Кошелек привязан. Нажмите 'повторить попытку' и подтвердите транзакцию по резерву в привязанном кошельке"),
 		markup); err != nil {
 		log.Error(err)
 		return err
@@ -207,35 +245,29 @@ func RequestRepeatTonConnect(b *bot.Bot, chatId int64, markup *models.InlineKeyb
 	return nil
 }
 
+// SendMessageOwnerAndUserIfBadReserve сообщает стейкеру о нехватке резерва и уведомляет владельца пула
 func SendMessageOwnerAndUserIfBadReserve(
 	chatId, ownerPoolId, poolId uint64,
 	jettonName string,
 	b *bot.Bot,
-	ts *services.TelegramService,
 ) {
 	if _, err := SendTextMessage(
 		b,
 		chatId,
-		"❌ Не хватает резерва пула. Мы отправили владельцу пула уведомление. Попробуйте позже!",
+		T(chatId, "❌ Не хватает резерва пула. Мы отправили владельцу пула уведомление. Попробуйте позже!"),
 	); err != nil {
 		log.Println(err)
 	}
-	ownerPoolTelegram, er := ts.GetByUserId(ownerPoolId)
-	if er != nil {
-		return
-	}
 	idButton := fmt.Sprintf("%v:%v:%v", buttons.PoolDataButton, poolId, callbacksuf.My)
 	btn := CreateDefaultButton(idButton, "Открыть пул")
 	markup := CreateInlineMarup(1, btn)
-	textMessage := fmt.Sprintf("В вашем пуле с токеном %v кончается резерв! Пополните его!", jettonName)
-	if _, err := SendTextMessageMarkup(
+	NotifyMarkup(
 		b,
-		ownerPoolTelegram.TelegramId,
-		textMessage,
+		ownerPoolId,
+		appModel.NOTIFY_POOL_RESERVE,
+		NotificationText(UserLang(ownerPoolId), appModel.NOTIFY_POOL_RESERVE, &appModel.NotificationData{JettonName: jettonName}),
 		markup,
-	); err != nil {
-		log.Println(err)
-	}
+	)
 }
 
 func GetJettonNameFromCallbackData(b *bot.Bot, chatId uint64, data string) (string, error) {
@@ -245,7 +277,7 @@ func GetJettonNameFromCallbackData(b *bot.Bot, chatId uint64, data string) (stri
 		if _, err := SendTextMessage(
 			b,
 			chatId,
-			"❌ Не могу обработать эту кнопку!",
+			T(chatId, "❌ Не могу обработать эту кнопку!"),
 		); err != nil {
 			log.Error(err)
 		}
@@ -264,11 +296,11 @@ func GetCurrentPage(chatId int64, pages map[int64]int) int {
 	return page
 }
 
-func GenerateGroupButtons(groups *[]appModel.GroupElements, idButton string) []models.InlineKeyboardButton {
+func GenerateGroupButtons(chatId uint64, groups *[]appModel.GroupElements, idButton string) []models.InlineKeyboardButton {
 	res := make([]models.InlineKeyboardButton, 0, 5)
 	for _, g := range *groups {
 		idButton := fmt.Sprintf("%v:%v", idButton, g.Name)
-		text := fmt.Sprintf("%v. Стейков: %v", g.Name, g.Count)
+		text := T(chatId, "%v. Стейков: %v", g.Name, g.Count)
 		btn := CreateDefaultButton(idButton, text)
 		res = append(res, btn)
 	}
@@ -276,11 +308,11 @@ func GenerateGroupButtons(groups *[]appModel.GroupElements, idButton string) []m
 	return res
 }
 
-func GenerateStakeListByGroup(stakes []appModel.Stake, jettonName, idButton string) []models.InlineKeyboardButton {
+func GenerateStakeListByGroup(chatId uint64, stakes []appModel.Stake, jettonName, idButton string) []models.InlineKeyboardButton {
 	res := make([]models.InlineKeyboardButton, 0, 5)
 	for _, s := range stakes {
 		idbtn := fmt.Sprintf("%v:%v:%v", idButton, jettonName, s.Id.Int64)
-		text := fmt.Sprintf("Стейк от %v", s.StartDate.Format("02.01.2006 15:04"))
+		text := T(chatId, "Стейк от %v", s.StartDate.Format("02.01.2006 15:04"))
 		if !s.IsActive && s.IsRewardPaid || !s.IsActive && s.IsInsurancePaid {
 			text += " ⚪️"
 		} else if !s.IsActive && !s.IsRewardPaid || !s.IsInsurancePaid && !s.IsActive {
@@ -314,7 +346,22 @@ func SendAgreement(b *bot.Bot, chatId uint64) {
 `
 	btn := CreateDefaultButton(buttons.AcceptUserAgreementId, buttons.AcceptUserAgreement)
 	markup := CreateInlineMarup(1, btn)
-	if _, err := SendTextMessageMarkup(b, chatId, message, markup); err != nil {
+	if _, err := SendTextMessageMarkup(b, chatId, T(chatId, message), markup); err != nil {
 		log.Error(err)
 	}
 }
+
+// DeliveryStatus статус доставки по ошибке отправки, при 429 возвращает паузу перед повтором
+func DeliveryStatus(err error) (string, time.Duration) {
+	if err == nil {
+		return appModel.DELIVERY_DELIVERED, 0
+	}
+	if errors.Is(err, bot.ErrorForbidden) {
+		return appModel.DELIVERY_BLOCKED, 0
+	}
+	var tooMany *bot.TooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return appModel.DELIVERY_PENDING, time.Duration(tooMany.RetryAfter) * time.Second
+	}
+	return appModel.DELIVERY_FAILED, 0
+}
@@ -3,7 +3,9 @@ package util
 import (
 	"math"
 	"strconv"
+	"tonclient/internal/config"
 	"tonclient/internal/dyor"
+	appModels "tonclient/internal/models"
 	"tonclient/internal/tonfi"
 )
 
@@ -36,3 +38,19 @@ func GetCurrentPriceJettonAddr(addr string) float64 {
 
 	return currentPrice
 }
+
+// GetInsuranceAssetPrice возвращает цену в $ актива, которым выплачивается компенсация
+func GetInsuranceAssetPrice(asset string) float64 {
+	switch asset {
+	case appModels.INSURANCE_ASSET_USDT:
+		price := GetCurrentPriceJettonAddr(config.USDT_JETTON_MASTER)
+		if price == 0 {
+			price = 1
+		}
+		return price
+	case appModels.INSURANCE_ASSET_TON:
+		return GetCurrentPriceJettonAddr(config.TON_NATIVE_ADDR)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,6 @@
+alter table stake
+    drop column if exists insurance_asset_price;
+
+alter table pool
+    drop column if exists insurance_reserve,
+    drop column if exists insurance_asset;
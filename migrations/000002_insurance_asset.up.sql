@@ -0,0 +1,6 @@
+alter table pool
+    add column if not exists insurance_asset   varchar(16)    default 'jetton' not null,
+    add column if not exists insurance_reserve numeric(28, 9) default 0        not null;
+
+alter table stake
+    add column if not exists insurance_asset_price numeric(28, 9) default 0 not null;
@@ -0,0 +1,5 @@
+alter table pool
+    drop column if exists early_exit_pro_rata,
+    drop column if exists early_exit_partial,
+    drop column if exists early_exit_min_days,
+    drop column if exists early_exit_penalty;
@@ -0,0 +1,5 @@
+alter table pool
+    add column if not exists early_exit_penalty  double precision default 0     not null check ( early_exit_penalty >= 0 and early_exit_penalty <= 100 ),
+    add column if not exists early_exit_min_days int              default 0     not null check ( early_exit_min_days >= 0 ),
+    add column if not exists early_exit_partial  bool             default false not null,
+    add column if not exists early_exit_pro_rata bool             default false not null;
@@ -0,0 +1,3 @@
+alter table stake
+    drop column if exists rolled_from,
+    drop column if exists auto_rollover;
@@ -0,0 +1,3 @@
+alter table stake
+    add column if not exists auto_rollover bool   default false not null,
+    add column if not exists rolled_from   bigint default null references stake (id) on delete set null;
@@ -0,0 +1,5 @@
+alter table stake
+    drop column if exists period,
+    drop column if exists reward;
+
+drop table if exists pool_reward_tier;
@@ -0,0 +1,21 @@
+create table if not exists pool_reward_tier
+(
+    id         bigserial primary key,
+    pool_id    bigint references pool (id) on delete cascade not null,
+    period     int check ( period > 0 )                      not null,
+    min_amount numeric(28, 9) default 0                      not null check ( min_amount >= 0 ),
+    reward     double precision check ( reward > 0 )         not null,
+    starts_at  timestamp      default null
+);
+
+create index if not exists pool_reward_tier_pool_id_idx on pool_reward_tier (pool_id);
+
+alter table stake
+    add column if not exists reward double precision default 0 not null,
+    add column if not exists period int              default 0 not null;
+
+update stake s
+set reward = p.reward,
+    period = p.period
+from pool p
+where p.id = s.pool_id;
@@ -0,0 +1,7 @@
+drop table if exists pool_whitelist;
+
+alter table pool
+    drop column if exists is_whitelist,
+    drop column if exists max_stakers,
+    drop column if exists max_user_stake,
+    drop column if exists max_total_stake;
@@ -0,0 +1,14 @@
+alter table pool
+    add column if not exists max_total_stake numeric(28, 9) default 0     not null check ( max_total_stake >= 0 ),
+    add column if not exists max_user_stake  numeric(28, 9) default 0     not null check ( max_user_stake >= 0 ),
+    add column if not exists max_stakers     int            default 0     not null check ( max_stakers >= 0 ),
+    add column if not exists is_whitelist    bool           default false not null;
+
+create table if not exists pool_whitelist
+(
+    id      bigserial primary key,
+    pool_id bigint references pool (id) on delete cascade not null,
+    kind    varchar(16)                                   not null,
+    value   varchar(256)                                  not null,
+    unique (pool_id, kind, value)
+);
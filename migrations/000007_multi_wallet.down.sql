@@ -0,0 +1,19 @@
+alter table stake
+    drop column if exists payout_addr,
+    drop column if exists deposit_addr;
+
+drop index if exists wallet_ton_user_default_idx;
+drop index if exists wallet_ton_user_id_idx;
+
+delete
+from wallet_ton
+where not is_default;
+
+alter table wallet_ton
+    drop column if exists created_at,
+    drop column if exists default_at,
+    drop column if exists is_default,
+    drop column if exists label;
+
+alter table wallet_ton
+    add constraint wallet_ton_user_id_key unique (user_id);
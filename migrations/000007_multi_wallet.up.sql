@@ -0,0 +1,19 @@
+alter table wallet_ton
+    drop constraint if exists wallet_ton_user_id_key;
+
+alter table wallet_ton
+    add column if not exists label      varchar(64) default ''    not null,
+    add column if not exists is_default bool        default false not null,
+    add column if not exists default_at timestamp   default null,
+    add column if not exists created_at timestamp   default now();
+
+-- до этой миграции у пользователя был один кошелек, он и становится основным
+update wallet_ton
+set is_default = true;
+
+create index if not exists wallet_ton_user_id_idx on wallet_ton (user_id);
+create unique index if not exists wallet_ton_user_default_idx on wallet_ton (user_id) where is_default;
+
+alter table stake
+    add column if not exists deposit_addr varchar(256) default '' not null,
+    add column if not exists payout_addr  varchar(256) default '' not null;
@@ -0,0 +1,18 @@
+create table if not exists tx_intent
+(
+    id             bigserial primary key,
+    telegram_id    bigint                    not null,
+    operation_type int                       not null,
+    sender_addr    varchar(256) default ''   not null,
+    payload        varchar      default ''   not null,
+    boc            text         default ''   not null,
+    msg_hash       varchar(64)  default ''   not null,
+    tx_hash        varchar(64)  default ''   not null,
+    status         varchar(16)               not null,
+    error          varchar      default ''   not null,
+    created_at     timestamp    default now() not null,
+    updated_at     timestamp    default now() not null
+);
+
+create index if not exists tx_intent_status_idx on tx_intent (status);
+create index if not exists tx_intent_msg_hash_idx on tx_intent (msg_hash);
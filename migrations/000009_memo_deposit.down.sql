@@ -0,0 +1,8 @@
+drop index if exists tx_intent_memo_idx;
+
+alter table tx_intent
+    drop column if exists expires_at,
+    drop column if exists amount_units,
+    drop column if exists treasury_wallet,
+    drop column if exists jetton_master,
+    drop column if exists memo;
@@ -0,0 +1,9 @@
+alter table tx_intent
+    add column if not exists memo            varchar(16)  default '' not null,
+    add column if not exists jetton_master   varchar(256) default '' not null,
+    add column if not exists treasury_wallet varchar(256) default '' not null,
+    add column if not exists amount_units    varchar(78)  default '' not null,
+    add column if not exists expires_at      timestamp    default null;
+
+-- один код на каждый кошелек казны: комиссия и депозит стейка могут идти разными jetton с одним комментарием
+create unique index if not exists tx_intent_memo_idx on tx_intent (memo, treasury_wallet) where memo <> '';
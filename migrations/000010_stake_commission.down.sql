@@ -0,0 +1,8 @@
+alter table stake
+    drop column if exists commission_asset,
+    drop column if exists commission;
+
+alter table pool_reward_tier
+    drop column if exists commission_value,
+    drop column if exists commission_type,
+    drop column if exists commission_asset;
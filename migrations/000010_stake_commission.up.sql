@@ -0,0 +1,9 @@
+-- политика комиссии за стейк для тарифа: пустое значение - берется из env
+alter table pool_reward_tier
+    add column if not exists commission_asset varchar(16)    default '' not null,
+    add column if not exists commission_type  varchar(16)    default '' not null,
+    add column if not exists commission_value numeric(28, 9) default 0  not null check ( commission_value >= 0 );
+
+alter table stake
+    add column if not exists commission       numeric(28, 9) default 0  not null,
+    add column if not exists commission_asset varchar(16)    default '' not null;
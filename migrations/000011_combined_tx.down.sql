@@ -0,0 +1,3 @@
+alter table tx_intent
+    drop column if exists received,
+    drop column if exists parts;
@@ -0,0 +1,4 @@
+-- транзакция из нескольких сообщений (комиссия + депозит): сколько переводов ждем и payload поступивших
+alter table tx_intent
+    add column if not exists parts    int  default 1    not null check ( parts > 0 ),
+    add column if not exists received text default '[]' not null;
@@ -0,0 +1,11 @@
+drop index if exists referral_unpaid_idx;
+drop index if exists referral_stake_level_idx;
+
+alter table referral
+    drop column if exists payout_hash,
+    drop column if exists paid_at,
+    drop column if exists created_at,
+    drop column if exists base_amount,
+    drop column if exists base,
+    drop column if exists level,
+    drop column if exists stake_id;
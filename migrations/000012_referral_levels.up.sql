@@ -0,0 +1,20 @@
+-- начисления реферальной программы: уровень приглашения, стейк и база расчета, выплата пачкой
+alter table referral
+    add column if not exists stake_id    bigint references stake (id) on delete set null,
+    add column if not exists level       int            default 1       not null check ( level > 0 ),
+    add column if not exists base        varchar(16)    default 'stake' not null,
+    add column if not exists base_amount numeric(28, 9) default 0       not null,
+    add column if not exists created_at  timestamp      default now()   not null,
+    add column if not exists paid_at     timestamp      default null,
+    add column if not exists payout_hash varchar(128)   default ''      not null;
+
+-- прежние записи - бонус за первый стейк, отправленный сразу
+update referral
+set stake_id = first_stake_id,
+    paid_at  = now()
+where stake_id is null
+  and reward_given;
+
+-- за один стейк на каждом уровне начисляется один раз
+create unique index if not exists referral_stake_level_idx on referral (stake_id, level);
+create index if not exists referral_unpaid_idx on referral (referrer_user_id) where not reward_given;
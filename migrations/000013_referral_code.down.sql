@@ -0,0 +1,4 @@
+drop index if exists usr_referral_code_idx;
+
+alter table usr
+    drop column if exists referral_code;
@@ -0,0 +1,5 @@
+-- случайный реферальный код пользователя вместо закодированного telegram id
+alter table usr
+    add column if not exists referral_code varchar(16) default null;
+
+create unique index if not exists usr_referral_code_idx on usr (referral_code);
@@ -0,0 +1,4 @@
+drop index if exists referral_idem_key_idx;
+
+alter table referral
+    drop column if exists idem_key;
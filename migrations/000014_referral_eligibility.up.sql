@@ -0,0 +1,22 @@
+-- ключ идемпотентности начисления: stake:<стейк>:<уровень> или user:<приглашенный>:<уровень>
+alter table referral
+    add column if not exists idem_key varchar(64);
+
+-- прежний бонус за первый стейк уже выплачен за приглашенного
+update referral r
+set idem_key = 'user:' || r.referral_user_id || ':' || r.level
+from (select id, row_number() over (partition by referral_user_id, level order by id) as n
+      from referral
+      where first_stake_id is not null
+        and referral_user_id is not null) legacy
+where legacy.id = r.id
+  and legacy.n = 1;
+
+update referral
+set idem_key = case when stake_id is not null then 'stake:' || stake_id || ':' || level else 'legacy:' || id end
+where idem_key is null;
+
+alter table referral
+    alter column idem_key set not null;
+
+create unique index if not exists referral_idem_key_idx on referral (idem_key);
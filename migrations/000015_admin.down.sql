@@ -0,0 +1,7 @@
+drop table if exists failed_payout;
+
+alter table pool
+    drop column if exists admin_paused;
+
+alter table usr
+    drop column if exists role;
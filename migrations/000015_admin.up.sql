@@ -0,0 +1,25 @@
+-- роль пользователя: user или admin
+alter table usr
+    add column if not exists role varchar(16) default 'user' not null;
+
+-- пул приостановлен администратором, владелец не может открыть его сам
+alter table pool
+    add column if not exists admin_paused boolean default false not null;
+
+-- выплаты с кошелька платформы, которые не удалось отправить
+create table if not exists failed_payout
+(
+    id            bigserial primary key,
+    user_id       bigint references usr (id) on delete cascade,
+    jetton_master varchar(256)   default ''       not null, -- пусто - выплата в TON
+    amount        numeric(28, 9)                  not null,
+    reason        text           default ''       not null,
+    error         text           default ''       not null,
+    status        varchar(16)    default 'failed' not null,
+    attempts      int            default 1        not null,
+    payout_hash   varchar(128)   default ''       not null,
+    created_at    timestamp      default now()    not null,
+    resolved_at   timestamp      default null
+);
+
+create index if not exists failed_payout_status_idx on failed_payout (status);
@@ -0,0 +1,2 @@
+drop table if exists announcement_delivery;
+drop table if exists announcement;
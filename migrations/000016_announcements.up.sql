@@ -0,0 +1,33 @@
+-- объявления администраторов для сегментов пользователей
+create table if not exists announcement
+(
+    id           bigserial primary key,
+    author_id    bigint                         not null, -- telegram id администратора
+    text         text                           not null,
+    segment      varchar(32)                    not null,
+    pool_id      bigint references pool (id) on delete set null,
+    status       varchar(16) default 'draft'    not null,
+    scheduled_at timestamp   default null,
+    created_at   timestamp   default now()      not null,
+    started_at   timestamp   default null,
+    finished_at  timestamp   default null,
+    total        int         default 0          not null,
+    delivered    int         default 0          not null,
+    blocked      int         default 0          not null,
+    failed       int         default 0          not null
+);
+
+create index if not exists announcement_status_idx on announcement (status, scheduled_at);
+
+-- доставка объявления каждому получателю
+create table if not exists announcement_delivery
+(
+    announcement_id bigint references announcement (id) on delete cascade,
+    telegram_id     bigint                       not null,
+    status          varchar(16) default 'pending' not null,
+    error           text        default ''        not null,
+    sent_at         timestamp   default null,
+    primary key (announcement_id, telegram_id)
+);
+
+create index if not exists announcement_delivery_pending_idx on announcement_delivery (announcement_id) where status = 'pending';
@@ -0,0 +1,4 @@
+alter table telegram
+    drop column if exists blocked_at;
+alter table telegram
+    drop column if exists is_blocked;
@@ -0,0 +1,5 @@
+-- пользователь заблокировал бота, уведомления ему не отправляются
+alter table telegram
+    add column if not exists is_blocked bool default false not null;
+alter table telegram
+    add column if not exists blocked_at timestamp default null;
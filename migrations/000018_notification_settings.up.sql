@@ -0,0 +1,8 @@
+-- настройки уведомлений пользователя, отсутствие строки - значение по умолчанию для события
+create table if not exists notification_setting
+(
+    user_id bigint references usr (id) on delete cascade not null,
+    event   varchar(32)                                  not null,
+    enabled bool                                         not null,
+    primary key (user_id, event)
+);
@@ -0,0 +1,2 @@
+alter table stake
+    drop column if exists price_alert;
@@ -0,0 +1,3 @@
+-- последний уровень оповещения о падении цены по активному стейку: 0 - нет, 1 - близко к порогу страховки, 2 - порог пройден
+alter table stake
+    add column if not exists price_alert smallint default 0 not null;
@@ -0,0 +1,4 @@
+alter table telegram
+    drop column if exists language;
+alter table telegram
+    drop column if exists language_code;
@@ -0,0 +1,5 @@
+-- language_code из Telegram и язык, выбранный пользователем в настройках (пусто - по language_code)
+alter table telegram
+    add column if not exists language_code varchar(16) default '' not null;
+alter table telegram
+    add column if not exists language varchar(8) default '' not null;